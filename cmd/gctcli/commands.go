@@ -1,86 +1,1801 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gocryptotrader/exchanges/forward"
 	"gocryptotrader/gctrpc"
 
-	"github.com/urfave/cli/v2"
-)
+	"github.com/urfave/cli/v2"
+	"google.golang.org/grpc"
+)
+
+var startTime, endTime, orderingDirection string
+var limit int
+
+var errTransferStreamClosed = errors.New("transfer stream closed before the transfer finished")
+
+var getAccountsCommand = &cli.Command{
+	Name:   "getaccounts",
+	Usage:  "gets GoCryptoTrader accounts, optionally filtered and paginated",
+	Action: getAccounts,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "only get accounts with this owner",
+		},
+		&cli.IntFlag{
+			Name:  "layer",
+			Usage: "only get accounts in this layer",
+		},
+		&cli.StringFlag{
+			Name:  "chain_name",
+			Usage: "only get accounts on this chain",
+		},
+		&cli.StringFlag{
+			Name:  "name",
+			Usage: "only get accounts with this name",
+		},
+		&cli.Int64Flag{
+			Name:  "parent_id",
+			Usage: "only get the children of this account",
+		},
+		&cli.IntFlag{
+			Name:  "limit",
+			Usage: "the maximum number of accounts to return; 0 returns all",
+		},
+		&cli.IntFlag{
+			Name:  "offset",
+			Usage: "the number of accounts to skip, ordered by id",
+		},
+	},
+}
+
+var getTokenPriceCommand = &cli.Command{
+	Name:   "gettokenprice",
+	Usage:  "gets token price information",
+	Action: getTokenPrice,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "token_address",
+			Usage: "the address of the token to get price for",
+		},
+	},
+}
+
+var cryptoCommand = &cli.Command{
+	Name:   "crypto",
+	Usage:  "encrypts the provided plaintext",
+	Action: crypto,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "plaintext",
+			Usage: "the text to be encrypted",
+		},
+		&cli.StringFlag{
+			Name:  "address",
+			Usage: "the account address the ciphertext is bound to; it only decrypts for this account",
+		},
+	},
+}
+
+var migrateAccountCiphersCommand = &cli.Command{
+	Name:   "migrateaccountciphers",
+	Usage:  "re-encrypts every account cipher still using the legacy RSA format with envelope encryption under the configured master key, in place",
+	Action: migrateAccountCiphers,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "dry_run",
+			Usage: "decrypt and re-encrypt every legacy cipher to check it without writing anything",
+		},
+	},
+}
+
+var accountFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "name",
+		Usage: "the account name; when creating several accounts each is suffixed with -1, -2 and so on",
+	},
+	&cli.StringFlag{
+		Name:  "owner",
+		Usage: "the owner of the account",
+	},
+	&cli.IntFlag{
+		Name:  "layer",
+		Usage: "the layer of the account",
+	},
+	&cli.StringFlag{
+		Name:  "chain_name",
+		Usage: "the chain of the account; defaults to solana",
+	},
+	&cli.Int64Flag{
+		Name:  "parent_id",
+		Usage: "the id of the parent account one layer up; owner, chain_name and layer default to the parent's when unset",
+	},
+}
+
+var createAccountsCommand = &cli.Command{
+	Name:   "createaccounts",
+	Usage:  "generates new accounts, randomly or derived from a BIP39 mnemonic along m/44'/501'/i'/0', and stores their keys encrypted",
+	Action: createAccounts,
+	Flags: append([]cli.Flag{
+		&cli.IntFlag{
+			Name:  "count",
+			Usage: "the number of accounts to generate; defaults to 1",
+		},
+		&cli.StringFlag{
+			Name:  "mnemonic",
+			Usage: "the BIP39 mnemonic to derive the accounts from; random keys are generated when neither mnemonic nor mnemonic_file is set",
+		},
+		&cli.StringFlag{
+			Name:  "mnemonic_file",
+			Usage: "a local file holding the BIP39 mnemonic, keeping it out of the shell history",
+		},
+		&cli.StringFlag{
+			Name:  "passphrase",
+			Usage: "the optional BIP39 passphrase of the mnemonic",
+		},
+		&cli.UintFlag{
+			Name:  "start_index",
+			Usage: "the account index i of the first derived account; the following accounts use the next indexes",
+		},
+	}, accountFlags...),
+}
+
+var importAccountCommand = &cli.Command{
+	Name:   "importaccount",
+	Usage:  "imports an existing private key and stores it encrypted",
+	Action: importAccount,
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "private_key",
+			Usage: "the base58 encoded private key",
+		},
+		&cli.StringFlag{
+			Name:  "key_file",
+			Usage: "a local solana-keygen keypair file or a file holding the base58 private key, keeping it out of the shell history",
+		},
+	}, accountFlags...),
+}
+
+var generateMnemonicCommand = &cli.Command{
+	Name:   "generatemnemonic",
+	Usage:  "generates a 24 word BIP39 mnemonic to derive accounts from; the server does not store it",
+	Action: generateMnemonic,
+}
+
+var getAccountTreeCommand = &cli.Command{
+	Name:   "getaccounttree",
+	Usage:  "gets the accounts of an owner as a tree from layer 0 down, listing accounts whose parent link is invalid",
+	Action: getAccountTree,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "the owner of the account tree",
+		},
+	},
+}
+
+var setAccountParentCommand = &cli.Command{
+	Name:   "setaccountparent",
+	Usage:  "links an account to a parent account one layer up with the same owner and chain",
+	Action: setAccountParent,
+	Flags: []cli.Flag{
+		&cli.Int64Flag{
+			Name:  "account_id",
+			Usage: "the id of the account",
+		},
+		&cli.Int64Flag{
+			Name:  "parent_id",
+			Usage: "the id of the parent account; 0 removes the link",
+		},
+	},
+}
+
+var fundChildrenCommand = &cli.Command{
+	Name:   "fundchildren",
+	Usage:  "sends the same amount of SOL from an account to each of its child accounts",
+	Action: fundChildren,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "address",
+			Usage: "the address of the parent account",
+		},
+		&cli.Float64Flag{
+			Name:  "amount",
+			Usage: "the SOL amount to send to each child account",
+		},
+		&cli.BoolFlag{
+			Name:  "dry_run",
+			Usage: "build and simulate every transaction without sending anything",
+		},
+		&cli.BoolFlag{
+			Name:  "partial_pay",
+			Usage: "if the parent balance cannot cover every child, fund as many as it allows in id order instead of refusing the transfer",
+		},
+		&cli.BoolFlag{
+			Name:  "use_lookup_tables",
+			Usage: "send v0 transactions backed by address lookup tables to pack more transfers into each transaction; the tables are created before sending and closed after the job",
+		},
+		&cli.StringFlag{
+			Name:  "memo_mode",
+			Usage: "attach an SPL memo to each transaction (transaction) or after each recipient's transfer (recipient); disabled when empty",
+		},
+		&cli.StringFlag{
+			Name:  "reference",
+			Usage: "the memo reference such as an invoice number; defaults to the job id",
+		},
+	},
+}
+
+var sweepLeavesCommand = &cli.Command{
+	Name:   "sweepleaves",
+	Usage:  "sweeps SOL and tokens from every leaf account of an owner's account tree back into its parent",
+	Action: sweepLeaves,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "the owner of the account tree",
+		},
+		&cli.StringSliceFlag{
+			Name:  "token_mint",
+			Usage: "a token mint to sweep, may be repeated",
+		},
+		&cli.BoolFlag{
+			Name:  "sweep_sol",
+			Usage: "sweep SOL above the reserve as well as tokens",
+		},
+		&cli.Uint64Flag{
+			Name:  "reserve_lamports",
+			Usage: "lamports to leave in every leaf account, never less than the rent-exempt minimum",
+		},
+		&cli.BoolFlag{
+			Name:  "close_token_accounts",
+			Usage: "close emptied token accounts and send their rent to the parent",
+		},
+		&cli.BoolFlag{
+			Name:  "dry_run",
+			Usage: "build and simulate every transaction and report what would be swept without sending anything",
+		},
+	},
+}
+
+var recipientsFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "recipients_file",
+		Usage: "path on the server to a CSV/JSONL recipient list (address,amount,memo,label); defaults to the configured filePath",
+	},
+	&cli.StringFlag{
+		Name:  "local_recipients_file",
+		Usage: "path to a local CSV/JSONL recipient list which is parsed and sent inline",
+	},
+}
+
+var duplicatesFlag = &cli.StringFlag{
+	Name:  "duplicates",
+	Usage: "how repeated recipient addresses are handled: reject the list (reject) or merge them and sum their amounts (sum); defaults to reject",
+}
+
+var progressFlag = &cli.BoolFlag{
+	Name:  "progress",
+	Usage: "stream the progress of every batch with running totals until the transfer settles, ignoring the request timeout; interrupting stops sending new batches and the job can be resumed",
+}
+
+var fanOutFlags = []cli.Flag{
+	&cli.StringSliceFlag{
+		Name:  "source",
+		Usage: "a managed account to send from, may be repeated; the recipients are split in list order across the sources which send concurrently, replacing address",
+	},
+	&cli.StringFlag{
+		Name:  "treasury",
+		Usage: "a managed account which tops each source up to its share of the transfers plus top_up_reserve before sending",
+	},
+	&cli.Float64Flag{
+		Name:  "top_up_reserve",
+		Usage: "the SOL each source should hold for fees and rent on top of its share when topping up from the treasury",
+	},
+}
+
+var validateRecipientsCommand = &cli.Command{
+	Name:   "validaterecipients",
+	Usage:  "checks a recipient list for invalid, duplicate, denylisted, off curve and program addresses and reports every issue before transferring",
+	Action: validateRecipients,
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "kind",
+			Usage: "the transfer kind (sol or token) whose default amount is used when merging duplicates; defaults to sol",
+		},
+		duplicatesFlag,
+	}, recipientsFlags...),
+}
+
+var transferSOLCommand = &cli.Command{
+	Name:   "transfersol",
+	Usage:  "transfer SOL tokens to multiple addresses",
+	Action: transferSOL,
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "address",
+			Usage: "the source address to transfer SOL from",
+		},
+		&cli.BoolFlag{
+			Name:  "dry_run",
+			Usage: "build and simulate every transaction and report fees, rent and compute units without sending anything",
+		},
+		&cli.BoolFlag{
+			Name:  "partial_pay",
+			Usage: "if the source balance cannot cover every recipient, pay as many as it allows in list order instead of refusing the transfer",
+		},
+		&cli.BoolFlag{
+			Name:  "use_lookup_tables",
+			Usage: "send v0 transactions backed by address lookup tables to pack more transfers into each transaction; the tables are created before sending and closed after the job",
+		},
+		&cli.BoolFlag{
+			Name:  "use_durable_nonce",
+			Usage: "sign every transaction with one of the source address's nonce accounts instead of a recent blockhash, so it does not expire",
+		},
+		&cli.BoolFlag{
+			Name:  "sign_only",
+			Usage: "sign every transaction with a durable nonce and return the signed transactions without sending them; requires use_durable_nonce",
+		},
+		&cli.StringFlag{
+			Name:  "memo_mode",
+			Usage: "attach an SPL memo to each transaction (transaction) or after each recipient's transfer (recipient); disabled when empty",
+		},
+		&cli.StringFlag{
+			Name:  "reference",
+			Usage: "the memo reference such as an invoice number; defaults to the job id, and recipients without a memo use the job id and row",
+		},
+		duplicatesFlag,
+		progressFlag,
+	}, append(fanOutFlags, recipientsFlags...)...),
+}
+
+var transferTokenCommand = &cli.Command{
+	Name:   "transfertoken",
+	Usage:  "transfer tokens to multiple addresses",
+	Action: transferToken,
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "address",
+			Usage: "the source address to transfer tokens from",
+		},
+		&cli.StringFlag{
+			Name:  "token_mint",
+			Usage: "the token mint address",
+		},
+		&cli.BoolFlag{
+			Name:  "dry_run",
+			Usage: "build and simulate every transaction and report fees, rent and compute units without sending anything",
+		},
+		&cli.BoolFlag{
+			Name:  "partial_pay",
+			Usage: "if the source balance cannot cover every recipient, pay as many as it allows in list order instead of refusing the transfer",
+		},
+		&cli.BoolFlag{
+			Name:  "use_lookup_tables",
+			Usage: "send v0 transactions backed by address lookup tables to pack more transfers into each transaction; the tables are created before sending and closed after the job",
+		},
+		&cli.BoolFlag{
+			Name:  "use_durable_nonce",
+			Usage: "sign every transaction with one of the source address's nonce accounts instead of a recent blockhash, so it does not expire",
+		},
+		&cli.BoolFlag{
+			Name:  "sign_only",
+			Usage: "sign every transaction with a durable nonce and return the signed transactions without sending them; requires use_durable_nonce",
+		},
+		&cli.StringFlag{
+			Name:  "memo_mode",
+			Usage: "attach an SPL memo to each transaction (transaction) or after each recipient's transfer (recipient); disabled when empty",
+		},
+		&cli.StringFlag{
+			Name:  "reference",
+			Usage: "the memo reference such as an invoice number; defaults to the job id, and recipients without a memo use the job id and row",
+		},
+		duplicatesFlag,
+		progressFlag,
+	}, append(fanOutFlags, recipientsFlags...)...),
+}
+
+var listTransferJobsCommand = &cli.Command{
+	Name:   "listtransferjobs",
+	Usage:  "lists persisted transfer jobs, newest first",
+	Action: listTransferJobs,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "status",
+			Usage: "only list jobs with this status (running, completed, unconfirmed, failed, interrupted)",
+		},
+		&cli.IntFlag{
+			Name:        "limit",
+			Usage:       "the maximum number of jobs to return",
+			Value:       50,
+			Destination: &limit,
+		},
+	},
+}
+
+var getTransferJobCommand = &cli.Command{
+	Name:   "gettransferjob",
+	Usage:  "gets a transfer job with the batch, signature and status of every recipient",
+	Action: getTransferJob,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "id",
+			Usage: "the transfer job id",
+		},
+	},
+}
+
+var resumeTransferJobCommand = &cli.Command{
+	Name:   "resumetransferjob",
+	Usage:  "resumes a failed or interrupted transfer job, only recipients that were not paid are sent again",
+	Action: resumeTransferJob,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "id",
+			Usage: "the transfer job id",
+		},
+		&cli.BoolFlag{
+			Name:  "partial_pay",
+			Usage: "if the source balance cannot cover every remaining recipient, pay as many as it allows in list order",
+		},
+		&cli.BoolFlag{
+			Name:  "use_lookup_tables",
+			Usage: "send v0 transactions backed by address lookup tables to pack more transfers into each transaction; the tables are created before sending and closed after the job",
+		},
+	},
+}
+
+var findTransfersCommand = &cli.Command{
+	Name:   "findtransfers",
+	Usage:  "finds transfers in the job history by transaction signature or memo",
+	Action: findTransfers,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "signature",
+			Usage: "the transaction signature",
+		},
+		&cli.StringFlag{
+			Name:  "memo",
+			Usage: "a recipient memo or a job reference",
+		},
+	},
+}
+
+var sweepAccountsCommand = &cli.Command{
+	Name:   "sweepaccounts",
+	Usage:  "sweeps SOL and tokens from every matching account in the accounts table into one destination",
+	Action: sweepAccounts,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "destination",
+			Usage: "the treasury address to sweep into",
+		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "only sweep accounts with this owner",
+		},
+		&cli.IntFlag{
+			Name:  "layer",
+			Usage: "only sweep accounts in this layer",
+		},
+		&cli.StringFlag{
+			Name:  "chain_name",
+			Usage: "only sweep accounts on this chain",
+		},
+		&cli.StringSliceFlag{
+			Name:  "token_mint",
+			Usage: "a token mint to sweep, may be repeated",
+		},
+		&cli.BoolFlag{
+			Name:  "sweep_sol",
+			Usage: "sweep SOL above the reserve as well as tokens",
+		},
+		&cli.Uint64Flag{
+			Name:  "reserve_lamports",
+			Usage: "lamports to leave in every source account, never less than the rent-exempt minimum",
+		},
+		&cli.BoolFlag{
+			Name:  "close_token_accounts",
+			Usage: "close emptied token accounts and send their rent to the destination",
+		},
+		&cli.BoolFlag{
+			Name:  "dry_run",
+			Usage: "build and simulate every transaction and report what would be swept without sending anything",
+		},
+	},
+}
+
+var reclaimRentCommand = &cli.Command{
+	Name:   "reclaimrent",
+	Usage:  "closes empty token accounts owned by managed accounts and returns their rent to each account",
+	Action: reclaimRent,
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "address",
+			Usage: "an account address to reclaim rent for, may be repeated; defaults to every account matching the filters",
+		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "only reclaim rent for accounts with this owner",
+		},
+		&cli.IntFlag{
+			Name:  "layer",
+			Usage: "only reclaim rent for accounts in this layer",
+		},
+		&cli.StringFlag{
+			Name:  "chain_name",
+			Usage: "only reclaim rent for accounts on this chain",
+		},
+		&cli.StringSliceFlag{
+			Name:  "exclude_mint",
+			Usage: "a token mint whose empty accounts are kept open, may be repeated",
+		},
+		&cli.BoolFlag{
+			Name:  "dry_run",
+			Usage: "build and simulate every transaction and report the rent that would be reclaimed without sending anything",
+		},
+	},
+}
+
+var getSolanaRPCHealthCommand = &cli.Command{
+	Name:   "getsolanarpchealth",
+	Usage:  "gets the health, slot lag and latency of every Solana RPC endpoint in the pool",
+	Action: getSolanaRPCHealth,
+}
+
+var createNonceAccountsCommand = &cli.Command{
+	Name:   "createnonceaccounts",
+	Usage:  "creates durable nonce accounts for an address, the address is the nonce authority and pays the rent",
+	Action: createNonceAccounts,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "address",
+			Usage: "the address that owns and funds the nonce accounts",
+		},
+		&cli.Int64Flag{
+			Name:  "count",
+			Usage: "the number of nonce accounts to create, one is needed per transaction in a job",
+			Value: 1,
+		},
+	},
+}
+
+var listNonceAccountsCommand = &cli.Command{
+	Name:   "listnonceaccounts",
+	Usage:  "lists the durable nonce accounts of an address",
+	Action: listNonceAccounts,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "address",
+			Usage: "the nonce authority address",
+		},
+	},
+}
+
+var closeNonceAccountsCommand = &cli.Command{
+	Name:   "closenonceaccounts",
+	Usage:  "closes durable nonce accounts and returns their rent to the address",
+	Action: closeNonceAccounts,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "address",
+			Usage: "the nonce authority address",
+		},
+		&cli.StringSliceFlag{
+			Name:  "nonce_account",
+			Usage: "a nonce account to close, may be repeated; every nonce account of the address is closed when unset",
+		},
+	},
+}
+
+var submitSignedTransactionsCommand = &cli.Command{
+	Name:   "submitsignedtransactions",
+	Usage:  "sends transactions that were signed ahead of time with sign_only and waits for them to confirm",
+	Action: submitSignedTransactions,
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "transaction",
+			Usage: "a base64 encoded signed transaction, may be repeated",
+		},
+		&cli.StringFlag{
+			Name:  "transactions_file",
+			Usage: "a local file with one base64 encoded signed transaction per line",
+		},
+	},
+}
+
+// transferScheduleFlags defines the schedule fields shared by
+// createtransferschedule and updatetransferschedule
+var transferScheduleFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "name",
+		Usage: "a name describing the schedule",
+	},
+	&cli.StringFlag{
+		Name:  "kind",
+		Usage: "what the schedule transfers, sol or token",
+		Value: "sol",
+	},
+	&cli.StringFlag{
+		Name:  "address",
+		Usage: "the source address every run transfers from",
+	},
+	&cli.StringFlag{
+		Name:  "token_mint",
+		Usage: "the token mint, required when kind is token",
+	},
+	&cli.StringFlag{
+		Name:  "cron",
+		Usage: "a five field cron expression in UTC, e.g. '0 9 * * 1' for Mondays at 09:00, or @daily, @weekly, @monthly; mutually exclusive with interval",
+	},
+	&cli.DurationFlag{
+		Name:  "interval",
+		Usage: "run at a fixed interval of at least one minute, e.g. 168h; mutually exclusive with cron",
+	},
+	&cli.Float64Flag{
+		Name:  "amount",
+		Usage: "the amount paid to recipients that have no amount of their own; defaults to the forwarder default",
+	},
+	&cli.StringFlag{
+		Name:  "catch_up",
+		Usage: "what to do with runs missed while the engine was down: skip them, run once for all of them, or run all of them",
+		Value: "once",
+	},
+	&cli.BoolFlag{
+		Name:  "partial_pay",
+		Usage: "if the source balance cannot cover every recipient, pay as many as it allows in list order",
+	},
+	&cli.BoolFlag{
+		Name:  "use_lookup_tables",
+		Usage: "send v0 transactions backed by address lookup tables to pack more transfers into each transaction",
+	},
+	duplicatesFlag,
+}
+
+var createTransferScheduleCommand = &cli.Command{
+	Name:   "createtransferschedule",
+	Usage:  "creates a scheduled or recurring transfer, each run starts a transfer job",
+	Action: createTransferSchedule,
+	Flags:  append(append([]cli.Flag{}, transferScheduleFlags...), recipientsFlags...),
+}
+
+var updateTransferScheduleCommand = &cli.Command{
+	Name:   "updatetransferschedule",
+	Usage:  "updates a transfer schedule, fields that are not set keep their current value",
+	Action: updateTransferSchedule,
+	Flags: append(append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "id",
+			Usage: "the transfer schedule id",
+		},
+	}, transferScheduleFlags...), recipientsFlags...),
+}
+
+var getTransferScheduleCommand = &cli.Command{
+	Name:   "gettransferschedule",
+	Usage:  "gets a transfer schedule with its recipients and last run",
+	Action: getTransferSchedule,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "id",
+			Usage: "the transfer schedule id",
+		},
+	},
+}
+
+var listTransferSchedulesCommand = &cli.Command{
+	Name:   "listtransferschedules",
+	Usage:  "lists transfer schedules",
+	Action: listTransferSchedules,
+}
+
+var deleteTransferScheduleCommand = &cli.Command{
+	Name:   "deletetransferschedule",
+	Usage:  "deletes a transfer schedule, transfer jobs it already started are kept",
+	Action: deleteTransferSchedule,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "id",
+			Usage: "the transfer schedule id",
+		},
+	},
+}
+
+var pauseTransferScheduleCommand = &cli.Command{
+	Name:   "pausetransferschedule",
+	Usage:  "pauses a transfer schedule so it starts no new runs",
+	Action: pauseTransferSchedule,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "id",
+			Usage: "the transfer schedule id",
+		},
+	},
+}
+
+var resumeTransferScheduleCommand = &cli.Command{
+	Name:   "resumetransferschedule",
+	Usage:  "resumes a paused transfer schedule, runs that fell due while it was paused are not caught up",
+	Action: resumeTransferSchedule,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "id",
+			Usage: "the transfer schedule id",
+		},
+	},
+}
+
+var createVestingPlanCommand = &cli.Command{
+	Name:   "createvestingplan",
+	Usage:  "creates a vesting plan that releases a token allocation to an account after a cliff, in equal parts per period",
+	Action: createVestingPlan,
+	Flags: []cli.Flag{
+		&cli.Int64Flag{
+			Name:  "account_id",
+			Usage: "the id of the beneficiary in the accounts table",
+		},
+		&cli.StringFlag{
+			Name:  "address",
+			Usage: "the source address releases are paid from",
+		},
+		&cli.StringFlag{
+			Name:  "token_mint",
+			Usage: "the token mint of the allocation",
+		},
+		&cli.Float64Flag{
+			Name:  "total",
+			Usage: "the total token allocation",
+		},
+		&cli.StringFlag{
+			Name:  "start",
+			Usage: "when vesting starts, in RFC3339 format e.g. 2026-01-01T00:00:00Z; defaults to now",
+		},
+		&cli.DurationFlag{
+			Name:  "cliff",
+			Usage: "nothing is released until the cliff has passed since the start, periods completed during the cliff are released when it ends, e.g. 8760h",
+		},
+		&cli.DurationFlag{
+			Name:  "period",
+			Usage: "the length of each release period, e.g. 720h",
+		},
+		&cli.Int64Flag{
+			Name:  "periods",
+			Usage: "the number of periods, the full allocation is vested when the last one ends",
+		},
+	},
+}
+
+var getVestingPlanCommand = &cli.Command{
+	Name:   "getvestingplan",
+	Usage:  "gets a vesting plan with its progress and release history",
+	Action: getVestingPlan,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "id",
+			Usage: "the vesting plan id",
+		},
+	},
+}
+
+var listVestingPlansCommand = &cli.Command{
+	Name:   "listvestingplans",
+	Usage:  "lists vesting plans with their progress",
+	Action: listVestingPlans,
+	Flags: []cli.Flag{
+		&cli.Int64Flag{
+			Name:  "account_id",
+			Usage: "only list plans of this beneficiary account",
+		},
+		&cli.StringFlag{
+			Name:  "status",
+			Usage: "only list plans with this status, active or completed",
+		},
+	},
+}
+
+var releaseVestingPlanCommand = &cli.Command{
+	Name:   "releasevestingplan",
+	Usage:  "pays out the vested but unreleased amount of a plan, or of every plan with an amount due if no id is given",
+	Action: releaseVestingPlan,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "id",
+			Usage: "the vesting plan id",
+		},
+	},
+}
+
+var wrapSOLCommand = &cli.Command{
+	Name:   "wrapsol",
+	Usage:  "wraps SOL of an account into its wSOL token account, creating the token account if needed",
+	Action: wrapSOL,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "address",
+			Usage: "the account address to wrap SOL for",
+		},
+		&cli.Float64Flag{
+			Name:  "amount",
+			Usage: "the amount of SOL to wrap",
+		},
+		&cli.BoolFlag{
+			Name:  "dry_run",
+			Usage: "build and simulate the transaction without sending it",
+		},
+	},
+}
+
+var unwrapSOLCommand = &cli.Command{
+	Name:   "unwrapsol",
+	Usage:  "unwraps all wSOL of an account by closing its wSOL token account, the balance and rent are returned as SOL",
+	Action: unwrapSOL,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "address",
+			Usage: "the account address to unwrap wSOL for",
+		},
+		&cli.BoolFlag{
+			Name:  "dry_run",
+			Usage: "build and simulate the transaction without sending it",
+		},
+	},
+}
+
+// getRecipients reads the local recipient list, if one was supplied, so it
+// can be sent inline with the transfer request
+func getRecipients(c *cli.Context) ([]*gctrpc.TransferRecipient, error) {
+	localFile := c.String("local_recipients_file")
+	if localFile == "" {
+		return nil, nil
+	}
+	recipients, err := forward.ReadRecipientsFromFile(localFile)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]*gctrpc.TransferRecipient, len(recipients))
+	for i := range recipients {
+		rows[i] = &gctrpc.TransferRecipient{
+			Address: recipients[i].Address,
+			Amount:  recipients[i].Amount,
+			Memo:    recipients[i].Memo,
+			Label:   recipients[i].Label,
+		}
+	}
+	return rows, nil
+}
+
+func validateRecipients(c *cli.Context) error {
+	recipients, err := getRecipients(c)
+	if err != nil {
+		return err
+	}
+
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.ValidateRecipients(c.Context,
+		&gctrpc.ValidateRecipientsRequest{
+			RecipientsFile: c.String("recipients_file"),
+			Recipients:     recipients,
+			Duplicates:     c.String("duplicates"),
+			Kind:           c.String("kind"),
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+// receiveTransferProgress prints each progress event of a streamed transfer
+// and returns the final event carrying the transfer result
+func receiveTransferProgress(stream grpc.ServerStreamingClient[gctrpc.TransferProgress]) (*gctrpc.TransferProgress, error) {
+	for {
+		p, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errTransferStreamClosed
+			}
+			return nil, err
+		}
+		if p.Event == "done" {
+			return p, nil
+		}
+		colour := whiteText
+		switch p.Event {
+		case "confirmed":
+			colour = greenText
+		case "failed", "expired":
+			colour = redText
+		case "built":
+			colour = grayText
+		}
+		fmt.Printf("%sbatch %d %s%s batches %d (sent %d, confirmed %d, failed %d) recipients %d/%d amount %v/%v",
+			colour, p.Batch.GetIndex(), p.Event, defaultText,
+			p.Batches, p.SentBatches, p.ConfirmedBatches, p.FailedBatches,
+			p.ConfirmedRecipients, p.Recipients, p.ConfirmedAmount, p.Amount)
+		if p.Batch.GetError() != "" {
+			fmt.Printf(" %s%s%s", redText, p.Batch.GetError(), defaultText)
+		}
+		fmt.Println()
+	}
+}
+
+func transferToken(c *cli.Context) error {
+	recipients, err := getRecipients(c)
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("progress") {
+		// a streamed transfer runs until every batch settles
+		ignoreTimeout = true
+	}
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	address := c.String("address")
+	tokenMint := c.String("token_mint")
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	req := &gctrpc.TransferTokenRequest{
+		Address:         address,
+		TokenMint:       tokenMint,
+		RecipientsFile:  c.String("recipients_file"),
+		Recipients:      recipients,
+		DryRun:          c.Bool("dry_run"),
+		PartialPay:      c.Bool("partial_pay"),
+		UseLookupTables: c.Bool("use_lookup_tables"),
+		UseDurableNonce: c.Bool("use_durable_nonce"),
+		SignOnly:        c.Bool("sign_only"),
+		MemoMode:        c.String("memo_mode"),
+		Reference:       c.String("reference"),
+		Duplicates:      c.String("duplicates"),
+		Sources:         c.StringSlice("source"),
+		Treasury:        c.String("treasury"),
+		TopUpReserve:    c.Float64("top_up_reserve"),
+	}
+	if c.Bool("progress") {
+		stream, err := client.TransferTokenStream(c.Context, req)
+		if err != nil {
+			return err
+		}
+		final, err := receiveTransferProgress(stream)
+		if err != nil {
+			return err
+		}
+		jsonOutput(final.TokenResult)
+		return nil
+	}
+	result, err := client.TransferToken(c.Context, req)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func transferSOL(c *cli.Context) error {
+	recipients, err := getRecipients(c)
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("progress") {
+		// a streamed transfer runs until every batch settles
+		ignoreTimeout = true
+	}
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	address := c.String("address")
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	req := &gctrpc.TransferSOLRequest{
+		Address:         address,
+		RecipientsFile:  c.String("recipients_file"),
+		Recipients:      recipients,
+		DryRun:          c.Bool("dry_run"),
+		PartialPay:      c.Bool("partial_pay"),
+		UseLookupTables: c.Bool("use_lookup_tables"),
+		UseDurableNonce: c.Bool("use_durable_nonce"),
+		SignOnly:        c.Bool("sign_only"),
+		MemoMode:        c.String("memo_mode"),
+		Reference:       c.String("reference"),
+		Duplicates:      c.String("duplicates"),
+		Sources:         c.StringSlice("source"),
+		Treasury:        c.String("treasury"),
+		TopUpReserve:    c.Float64("top_up_reserve"),
+	}
+	if c.Bool("progress") {
+		stream, err := client.TransferSOLStream(c.Context, req)
+		if err != nil {
+			return err
+		}
+		final, err := receiveTransferProgress(stream)
+		if err != nil {
+			return err
+		}
+		jsonOutput(final.SolResult)
+		return nil
+	}
+	result, err := client.TransferSOL(c.Context, req)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func getAccounts(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	req := &gctrpc.GetAccountsRequest{
+		Owner:     c.String("owner"),
+		ChainName: c.String("chain_name"),
+		Name:      c.String("name"),
+		ParentId:  c.Int64("parent_id"),
+		Limit:     int32(c.Int("limit")),
+		Offset:    int32(c.Int("offset")),
+	}
+	if c.IsSet("layer") {
+		layer := int32(c.Int("layer"))
+		req.Layer = &layer
+	}
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.GetAccounts(c.Context, req)
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func getTokenPrice(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	tokenAddress := c.String("token_address")
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.GetTokenPrice(c.Context,
+		&gctrpc.GetTokenPriceRequest{
+			TokenAddress: tokenAddress,
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func crypto(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	plaintext := c.String("plaintext")
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.Crypto(c.Context,
+		&gctrpc.CryptoRequest{
+			Plaintext: plaintext,
+			Address:   c.String("address"),
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func migrateAccountCiphers(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.MigrateAccountCiphers(c.Context,
+		&gctrpc.MigrateAccountCiphersRequest{
+			DryRun: c.Bool("dry_run"),
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+// secretFlag returns the value of a flag or, when empty, the trimmed content
+// of the local file named by fileFlag
+func secretFlag(c *cli.Context, flag, fileFlag string) (string, error) {
+	if value := c.String(flag); value != "" || c.String(fileFlag) == "" {
+		return value, nil
+	}
+	data, err := os.ReadFile(c.String(fileFlag))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func createAccounts(c *cli.Context) error {
+	mnemonic, err := secretFlag(c, "mnemonic", "mnemonic_file")
+	if err != nil {
+		return err
+	}
+
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.CreateAccounts(c.Context,
+		&gctrpc.CreateAccountsRequest{
+			Name:       c.String("name"),
+			Owner:      c.String("owner"),
+			Layer:      int32(c.Int("layer")),
+			ChainName:  c.String("chain_name"),
+			ParentId:   c.Int64("parent_id"),
+			Count:      int32(c.Int("count")),
+			Mnemonic:   mnemonic,
+			Passphrase: c.String("passphrase"),
+			StartIndex: uint32(c.Uint("start_index")),
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func importAccount(c *cli.Context) error {
+	privateKey, err := secretFlag(c, "private_key", "key_file")
+	if err != nil {
+		return err
+	}
+
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.ImportAccount(c.Context,
+		&gctrpc.ImportAccountRequest{
+			Name:       c.String("name"),
+			Owner:      c.String("owner"),
+			Layer:      int32(c.Int("layer")),
+			ChainName:  c.String("chain_name"),
+			PrivateKey: privateKey,
+			ParentId:   c.Int64("parent_id"),
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func generateMnemonic(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.GenerateMnemonic(c.Context, &gctrpc.GenerateMnemonicRequest{})
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func getAccountTree(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.GetAccountTree(c.Context,
+		&gctrpc.GetAccountTreeRequest{Owner: c.String("owner")},
+	)
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func setAccountParent(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.SetAccountParent(c.Context,
+		&gctrpc.SetAccountParentRequest{
+			AccountId: c.Int64("account_id"),
+			ParentId:  c.Int64("parent_id"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func fundChildren(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.FundChildren(c.Context,
+		&gctrpc.FundChildrenRequest{
+			Address:         c.String("address"),
+			Amount:          c.Float64("amount"),
+			DryRun:          c.Bool("dry_run"),
+			PartialPay:      c.Bool("partial_pay"),
+			UseLookupTables: c.Bool("use_lookup_tables"),
+			MemoMode:        c.String("memo_mode"),
+			Reference:       c.String("reference"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func sweepLeaves(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.SweepLeaves(c.Context,
+		&gctrpc.SweepLeavesRequest{
+			Owner:              c.String("owner"),
+			TokenMints:         c.StringSlice("token_mint"),
+			SweepSol:           c.Bool("sweep_sol"),
+			ReserveLamports:    c.Uint64("reserve_lamports"),
+			CloseTokenAccounts: c.Bool("close_token_accounts"),
+			DryRun:             c.Bool("dry_run"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func listTransferJobs(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.ListTransferJobs(c.Context,
+		&gctrpc.ListTransferJobsRequest{
+			Status: c.String("status"),
+			Limit:  int32(limit),
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func getTransferJob(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.GetTransferJob(c.Context,
+		&gctrpc.GetTransferJobRequest{
+			Id: c.String("id"),
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func resumeTransferJob(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.ResumeTransferJob(c.Context,
+		&gctrpc.ResumeTransferJobRequest{
+			Id:              c.String("id"),
+			PartialPay:      c.Bool("partial_pay"),
+			UseLookupTables: c.Bool("use_lookup_tables"),
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func findTransfers(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.FindTransfers(c.Context,
+		&gctrpc.FindTransfersRequest{
+			Signature: c.String("signature"),
+			Memo:      c.String("memo"),
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func sweepAccounts(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	req := &gctrpc.SweepAccountsRequest{
+		Destination:        c.String("destination"),
+		Owner:              c.String("owner"),
+		ChainName:          c.String("chain_name"),
+		TokenMints:         c.StringSlice("token_mint"),
+		SweepSol:           c.Bool("sweep_sol"),
+		ReserveLamports:    c.Uint64("reserve_lamports"),
+		CloseTokenAccounts: c.Bool("close_token_accounts"),
+		DryRun:             c.Bool("dry_run"),
+	}
+	if c.IsSet("layer") {
+		layer := int32(c.Int("layer"))
+		req.Layer = &layer
+	}
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.SweepAccounts(c.Context, req)
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func reclaimRent(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	req := &gctrpc.ReclaimRentRequest{
+		Addresses:    c.StringSlice("address"),
+		Owner:        c.String("owner"),
+		ChainName:    c.String("chain_name"),
+		ExcludeMints: c.StringSlice("exclude_mint"),
+		DryRun:       c.Bool("dry_run"),
+	}
+	if c.IsSet("layer") {
+		layer := int32(c.Int("layer"))
+		req.Layer = &layer
+	}
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.ReclaimRent(c.Context, req)
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func getSolanaRPCHealth(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.GetSolanaRPCHealth(c.Context,
+		&gctrpc.GetSolanaRPCHealthRequest{},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func createNonceAccounts(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.CreateNonceAccounts(c.Context,
+		&gctrpc.CreateNonceAccountsRequest{
+			Address: c.String("address"),
+			Count:   c.Int64("count"),
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func listNonceAccounts(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.ListNonceAccounts(c.Context,
+		&gctrpc.ListNonceAccountsRequest{
+			Address: c.String("address"),
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func closeNonceAccounts(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.CloseNonceAccounts(c.Context,
+		&gctrpc.CloseNonceAccountsRequest{
+			Address:       c.String("address"),
+			NonceAccounts: c.StringSlice("nonce_account"),
+		},
+	)
 
-var startTime, endTime, orderingDirection string
-var limit int
+	if err != nil {
+		return err
+	}
 
-var getAccountsCommand = &cli.Command{
-	Name:   "getaccounts",
-	Usage:  "gets GoCryptoTrader accounts",
-	Action: getAccounts,
+	jsonOutput(result)
+	return nil
 }
 
-var getTokenPriceCommand = &cli.Command{
-	Name:   "gettokenprice",
-	Usage:  "gets token price information",
-	Action: getTokenPrice,
-	Flags: []cli.Flag{
-		&cli.StringFlag{
-			Name:  "token_address",
-			Usage: "the address of the token to get price for",
+func submitSignedTransactions(c *cli.Context) error {
+	transactions := c.StringSlice("transaction")
+	if file := c.String("transactions_file"); file != "" {
+		lines, err := forward.ReadAddressesFromFile(file)
+		if err != nil {
+			return err
+		}
+		transactions = append(transactions, lines...)
+	}
+
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.SubmitSignedTransactions(c.Context,
+		&gctrpc.SubmitSignedTransactionsRequest{
+			Transactions: transactions,
 		},
-	},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
 }
 
-var cryptoCommand = &cli.Command{
-	Name:   "crypto",
-	Usage:  "encrypts the provided plaintext",
-	Action: crypto,
-	Flags: []cli.Flag{
-		&cli.StringFlag{
-			Name:  "plaintext",
-			Usage: "the text to be encrypted",
+func createTransferSchedule(c *cli.Context) error {
+	recipients, err := getRecipients(c)
+	if err != nil {
+		return err
+	}
+
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	schedule := &gctrpc.TransferSchedule{Recipients: recipients}
+	applyTransferScheduleFlags(c, schedule, true)
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.CreateTransferSchedule(c.Context,
+		&gctrpc.CreateTransferScheduleRequest{
+			Schedule:       schedule,
+			RecipientsFile: c.String("recipients_file"),
 		},
-	},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
 }
 
-var transferSOLCommand = &cli.Command{
-	Name:   "transfersol",
-	Usage:  "transfer SOL tokens to multiple addresses",
-	Action: transferSOL,
-	Flags: []cli.Flag{
-		&cli.StringFlag{
-			Name:  "address",
-			Usage: "the source address to transfer SOL from",
+func updateTransferSchedule(c *cli.Context) error {
+	recipients, err := getRecipients(c)
+	if err != nil {
+		return err
+	}
+
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	current, err := client.GetTransferSchedule(c.Context,
+		&gctrpc.GetTransferScheduleRequest{
+			Id: c.String("id"),
 		},
-	},
+	)
+	if err != nil {
+		return err
+	}
+
+	schedule := current.Schedule
+	applyTransferScheduleFlags(c, schedule, false)
+	if recipients != nil || c.IsSet("recipients_file") {
+		schedule.Recipients = recipients
+	}
+	result, err := client.UpdateTransferSchedule(c.Context,
+		&gctrpc.UpdateTransferScheduleRequest{
+			Schedule:       schedule,
+			RecipientsFile: c.String("recipients_file"),
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
 }
 
-var transferTokenCommand = &cli.Command{
-	Name:   "transfertoken",
-	Usage:  "transfer tokens to multiple addresses",
-	Action: transferToken,
-	Flags: []cli.Flag{
-		&cli.StringFlag{
-			Name:  "address",
-			Usage: "the source address to transfer tokens from",
+// applyTransferScheduleFlags copies the schedule flags onto schedule, when
+// all is false only flags set on the command line are copied
+func applyTransferScheduleFlags(c *cli.Context, schedule *gctrpc.TransferSchedule, all bool) {
+	if all || c.IsSet("name") {
+		schedule.Name = c.String("name")
+	}
+	if all || c.IsSet("kind") {
+		schedule.Kind = c.String("kind")
+	}
+	if all || c.IsSet("address") {
+		schedule.SourceAddress = c.String("address")
+	}
+	if all || c.IsSet("token_mint") {
+		schedule.TokenMint = c.String("token_mint")
+	}
+	// cron and interval are mutually exclusive, setting one clears the other
+	if all || c.IsSet("cron") || c.IsSet("interval") {
+		schedule.Cron = c.String("cron")
+		schedule.IntervalSeconds = int64(c.Duration("interval") / time.Second)
+	}
+	if all || c.IsSet("amount") {
+		schedule.Amount = c.Float64("amount")
+	}
+	if all || c.IsSet("catch_up") {
+		schedule.CatchUp = c.String("catch_up")
+	}
+	if all || c.IsSet("partial_pay") {
+		schedule.PartialPay = c.Bool("partial_pay")
+	}
+	if all || c.IsSet("use_lookup_tables") {
+		schedule.UseLookupTables = c.Bool("use_lookup_tables")
+	}
+	if all || c.IsSet("duplicates") {
+		schedule.Duplicates = c.String("duplicates")
+	}
+}
+
+func getTransferSchedule(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.GetTransferSchedule(c.Context,
+		&gctrpc.GetTransferScheduleRequest{
+			Id: c.String("id"),
 		},
-		&cli.StringFlag{
-			Name:  "token_mint",
-			Usage: "the token mint address",
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func listTransferSchedules(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.ListTransferSchedules(c.Context,
+		&gctrpc.ListTransferSchedulesRequest{},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func deleteTransferSchedule(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.DeleteTransferSchedule(c.Context,
+		&gctrpc.DeleteTransferScheduleRequest{
+			Id: c.String("id"),
 		},
-	},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
 }
 
-func transferToken(c *cli.Context) error {
+func pauseTransferSchedule(c *cli.Context) error {
 	conn, cancel, err := setupClient(c)
 	if err != nil {
 		return err
 	}
 	defer closeConn(conn, cancel)
 
-	address := c.String("address")
-	tokenMint := c.String("token_mint")
 	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
-	result, err := client.TransferToken(c.Context,
-		&gctrpc.TransferTokenRequest{
-			Address:   address,
-			TokenMint: tokenMint,
+	result, err := client.PauseTransferSchedule(c.Context,
+		&gctrpc.PauseTransferScheduleRequest{
+			Id: c.String("id"),
 		},
 	)
 
@@ -92,18 +1807,17 @@ func transferToken(c *cli.Context) error {
 	return nil
 }
 
-func transferSOL(c *cli.Context) error {
+func resumeTransferSchedule(c *cli.Context) error {
 	conn, cancel, err := setupClient(c)
 	if err != nil {
 		return err
 	}
 	defer closeConn(conn, cancel)
 
-	address := c.String("address")
 	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
-	result, err := client.TransferSOL(c.Context,
-		&gctrpc.TransferSOLRequest{
-			Address: address,
+	result, err := client.ResumeTransferSchedule(c.Context,
+		&gctrpc.ResumeTransferScheduleRequest{
+			Id: c.String("id"),
 		},
 	)
 
@@ -115,7 +1829,46 @@ func transferSOL(c *cli.Context) error {
 	return nil
 }
 
-func getAccounts(c *cli.Context) error {
+func createVestingPlan(c *cli.Context) error {
+	plan := &gctrpc.VestingPlan{
+		AccountId:     c.Int64("account_id"),
+		SourceAddress: c.String("address"),
+		TokenMint:     c.String("token_mint"),
+		TotalAmount:   c.Float64("total"),
+		CliffSeconds:  int64(c.Duration("cliff") / time.Second),
+		PeriodSeconds: int64(c.Duration("period") / time.Second),
+		Periods:       c.Int64("periods"),
+	}
+	if start := c.String("start"); start != "" {
+		t, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return err
+		}
+		plan.StartAt = &gctrpc.Timestamp{Seconds: t.Unix(), Nanos: int32(t.Nanosecond())}
+	}
+
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.CreateVestingPlan(c.Context,
+		&gctrpc.CreateVestingPlanRequest{
+			Plan: plan,
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func getVestingPlan(c *cli.Context) error {
 	conn, cancel, err := setupClient(c)
 	if err != nil {
 		return err
@@ -123,8 +1876,10 @@ func getAccounts(c *cli.Context) error {
 	defer closeConn(conn, cancel)
 
 	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
-	result, err := client.GetAccounts(c.Context,
-		&gctrpc.GetAccountsRequest{},
+	result, err := client.GetVestingPlan(c.Context,
+		&gctrpc.GetVestingPlanRequest{
+			Id: c.String("id"),
+		},
 	)
 
 	if err != nil {
@@ -135,18 +1890,18 @@ func getAccounts(c *cli.Context) error {
 	return nil
 }
 
-func getTokenPrice(c *cli.Context) error {
+func listVestingPlans(c *cli.Context) error {
 	conn, cancel, err := setupClient(c)
 	if err != nil {
 		return err
 	}
 	defer closeConn(conn, cancel)
 
-	tokenAddress := c.String("token_address")
 	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
-	result, err := client.GetTokenPrice(c.Context,
-		&gctrpc.GetTokenPriceRequest{
-			TokenAddress: tokenAddress,
+	result, err := client.ListVestingPlans(c.Context,
+		&gctrpc.ListVestingPlansRequest{
+			AccountId: c.Int64("account_id"),
+			Status:    c.String("status"),
 		},
 	)
 
@@ -158,18 +1913,64 @@ func getTokenPrice(c *cli.Context) error {
 	return nil
 }
 
-func crypto(c *cli.Context) error {
+func releaseVestingPlan(c *cli.Context) error {
 	conn, cancel, err := setupClient(c)
 	if err != nil {
 		return err
 	}
 	defer closeConn(conn, cancel)
 
-	plaintext := c.String("plaintext")
 	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
-	result, err := client.Crypto(c.Context,
-		&gctrpc.CryptoRequest{
-			Plaintext: plaintext,
+	result, err := client.ReleaseVestingPlan(c.Context,
+		&gctrpc.ReleaseVestingPlanRequest{
+			Id: c.String("id"),
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func wrapSOL(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.WrapSOL(c.Context,
+		&gctrpc.WrapSOLRequest{
+			Address: c.String("address"),
+			Amount:  c.Float64("amount"),
+			DryRun:  c.Bool("dry_run"),
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	jsonOutput(result)
+	return nil
+}
+
+func unwrapSOL(c *cli.Context) error {
+	conn, cancel, err := setupClient(c)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn, cancel)
+
+	client := gctrpc.NewGoCryptoTraderServiceClient(conn)
+	result, err := client.UnwrapSOL(c.Context,
+		&gctrpc.UnwrapSOLRequest{
+			Address: c.String("address"),
+			DryRun:  c.Bool("dry_run"),
 		},
 	)
 
@@ -124,10 +124,43 @@ func main() {
 	}
 	app.Commands = []*cli.Command{
 		getAccountsCommand,
+		createAccountsCommand,
+		importAccountCommand,
+		generateMnemonicCommand,
+		getAccountTreeCommand,
+		setAccountParentCommand,
+		fundChildrenCommand,
+		sweepLeavesCommand,
 		getTokenPriceCommand,
 		cryptoCommand,
+		migrateAccountCiphersCommand,
+		validateRecipientsCommand,
 		transferSOLCommand,
 		transferTokenCommand,
+		listTransferJobsCommand,
+		getTransferJobCommand,
+		resumeTransferJobCommand,
+		findTransfersCommand,
+		sweepAccountsCommand,
+		reclaimRentCommand,
+		getSolanaRPCHealthCommand,
+		createNonceAccountsCommand,
+		listNonceAccountsCommand,
+		closeNonceAccountsCommand,
+		submitSignedTransactionsCommand,
+		createTransferScheduleCommand,
+		updateTransferScheduleCommand,
+		getTransferScheduleCommand,
+		listTransferSchedulesCommand,
+		deleteTransferScheduleCommand,
+		pauseTransferScheduleCommand,
+		resumeTransferScheduleCommand,
+		createVestingPlanCommand,
+		getVestingPlanCommand,
+		listVestingPlansCommand,
+		releaseVestingPlanCommand,
+		wrapSOLCommand,
+		unwrapSOLCommand,
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
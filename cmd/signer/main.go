@@ -0,0 +1,77 @@
+// Command signer serves transaction signatures over the local socket set in
+// the signer section of the config. It is the only process which decrypts
+// account keys, the engine sends it the messages to sign and the policy
+// checks run here before any key is touched.
+package main
+
+import (
+	"flag"
+	"log"
+	"sync"
+
+	"gocryptotrader/config"
+	"gocryptotrader/database"
+	"gocryptotrader/engine"
+	"gocryptotrader/exchanges/account"
+	"gocryptotrader/exchanges/signer"
+	gctlog "gocryptotrader/log"
+	"gocryptotrader/signaler"
+)
+
+func main() {
+	configFile := flag.String("config", config.DefaultFilePath(), "config file to load")
+	flag.Parse()
+
+	filePath, err := config.GetAndMigrateDefaultPath(*configFile)
+	if err != nil {
+		log.Fatalf("Unable to find config file. Error: %s\n", err)
+	}
+	cfg := &config.Config{}
+	if err = cfg.ReadConfigFromFile(filePath, true); err != nil {
+		log.Fatalf("Unable to read config file %s. Error: %s\n", filePath, err)
+	}
+	if err = cfg.CheckConfig(); err != nil {
+		log.Fatalf("Invalid config. Error: %s\n", err)
+	}
+	if *cfg.Logging.Enabled {
+		if err = gctlog.SetupGlobalLogger(cfg.Name+" signer", cfg.Logging.AdvancedSettings.StructuredLogging); err != nil {
+			log.Fatalf("Unable to setup global logger. Error: %s\n", err)
+		}
+		if err = gctlog.SetupSubLoggers(cfg.Logging.SubLoggers); err != nil {
+			log.Fatalf("Unable to setup sub loggers. Error: %s\n", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	db, err := engine.SetupDatabaseConnectionManager(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Database manager unable to setup. Error: %s\n", err)
+	}
+	if err = db.Start(&wg); err != nil {
+		log.Fatalf("Database manager unable to start. Error: %s\n", err)
+	}
+	if !database.DB.IsConnected() {
+		log.Fatalln("The keystore requires a database connection")
+	}
+
+	srv, err := signer.NewServer(account.New(cfg), &cfg.Signer)
+	if err != nil {
+		log.Fatalf("Unable to setup signer. Error: %s\n", err)
+	}
+	go func() {
+		interrupt := signaler.WaitForInterrupt()
+		gctlog.Infof(gctlog.Global, "Captured %v, shutdown requested.\n", interrupt)
+		srv.Stop()
+	}()
+	if err = srv.Serve(cfg.Signer.Socket); err != nil {
+		gctlog.Errorf(gctlog.Global, "Signer stopped. Error: %v", err)
+	}
+
+	if err = db.Stop(); err != nil {
+		gctlog.Errorf(gctlog.Global, "Database manager unable to stop. Error: %v", err)
+	}
+	wg.Wait()
+	if err = gctlog.CloseLogger(); err != nil {
+		log.Printf("Failed to close logger. Error: %v\n", err)
+	}
+}
@@ -0,0 +1,180 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchLimit bounds how far ahead Next looks for a matching time, so that
+// impossible expressions such as "0 0 30 2 *" terminate
+const searchLimit = 5
+
+var (
+	// ErrInvalidExpression is returned when a cron expression cannot be parsed
+	ErrInvalidExpression = errors.New("invalid cron expression")
+
+	descriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+
+	fields = []bounds{
+		{name: "minute", min: 0, max: 59},
+		{name: "hour", min: 0, max: 23},
+		{name: "day of month", min: 1, max: 31},
+		{name: "month", min: 1, max: 12},
+		{name: "day of week", min: 0, max: 7},
+	}
+)
+
+// Parse parses a standard five field cron expression (minute, hour, day of
+// month, month and day of week) or one of the @yearly, @monthly, @weekly,
+// @daily and @hourly descriptors. Fields accept *, lists, ranges and steps.
+// Both 0 and 7 mean Sunday.
+func Parse(expr string) (*Schedule, error) {
+	spec := strings.TrimSpace(expr)
+	if d, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = d
+	}
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("%w %q: expected %d fields, got %d", ErrInvalidExpression, expr, len(fields), len(parts))
+	}
+
+	var masks [5]uint64
+	for i := range parts {
+		m, err := parseField(parts[i], fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrInvalidExpression, expr, err)
+		}
+		masks[i] = m
+	}
+	// 7 is an alias for Sunday
+	if masks[4]&(1<<7) != 0 {
+		masks[4] = masks[4]&^(1<<7) | 1
+	}
+	return &Schedule{
+		expr:        expr,
+		minute:      masks[0],
+		hour:        masks[1],
+		dom:         masks[2],
+		month:       masks[3],
+		dow:         masks[4],
+		domWildcard: strings.HasPrefix(parts[2], "*"),
+		dowWildcard: strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+// Next returns the first matching time strictly after t, in t's location.
+// A zero time is returned when nothing matches within the next five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(searchLimit, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches follows the usual cron rule: when both the day of month and
+// the day of week are restricted, a day matching either of them matches
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domWildcard || s.dowWildcard {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func parseField(field string, b bounds) (uint64, error) {
+	var mask uint64
+	for _, part := range strings.Split(field, ",") {
+		m, err := parseRange(part, b)
+		if err != nil {
+			return 0, err
+		}
+		mask |= m
+	}
+	return mask, nil
+}
+
+func parseRange(part string, b bounds) (uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+	step := 1
+	if hasStep {
+		var err error
+		step, err = strconv.Atoi(stepPart)
+		if err != nil || step <= 0 {
+			return 0, fmt.Errorf("%s: invalid step %q", b.name, stepPart)
+		}
+	}
+
+	start, end := b.min, b.max
+	if rangePart != "*" {
+		lo, hi, isRange := strings.Cut(rangePart, "-")
+		var err error
+		if start, err = b.value(lo); err != nil {
+			return 0, err
+		}
+		end = start
+		if isRange {
+			if end, err = b.value(hi); err != nil {
+				return 0, err
+			}
+		} else if hasStep {
+			// "5/15" means every 15 starting at 5
+			end = b.max
+		}
+		if end < start {
+			return 0, fmt.Errorf("%s: range %q is reversed", b.name, rangePart)
+		}
+	}
+
+	var mask uint64
+	for v := start; v <= end; v += step {
+		mask |= 1 << uint(v)
+	}
+	return mask, nil
+}
+
+func (b bounds) value(s string) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid value %q", b.name, s)
+	}
+	if v < b.min || v > b.max {
+		return 0, fmt.Errorf("%s: %d is outside %d-%d", b.name, v, b.min, b.max)
+	}
+	return v, nil
+}
+
+// String returns the expression the schedule was parsed from
+func (s *Schedule) String() string {
+	return s.expr
+}
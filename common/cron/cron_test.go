@@ -0,0 +1,51 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *", "@fortnightly"} {
+		_, err := Parse(expr)
+		assert.ErrorIs(t, err, ErrInvalidExpression, "Parse should reject %q", expr)
+	}
+
+	s, err := Parse("@weekly")
+	require.NoError(t, err)
+	assert.Equal(t, "@weekly", s.String())
+
+	s, err = Parse("0 9 * * 7")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), s.dow, "7 must be an alias for Sunday")
+}
+
+func TestNext(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2026, 10, 17, 10, 30, 15, 0, time.UTC) // Saturday
+	for _, tc := range []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 17, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 17, 10, 45, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2026, 10, 17, 10, 45, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 10, 17, 11, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1", time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+		{"30 10 17 10 *", time.Date(2027, 10, 17, 10, 30, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// both day fields restricted: either one matches
+		{"0 0 20 * 0", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	} {
+		s, err := Parse(tc.expr)
+		require.NoError(t, err, "Parse must not error for %q", tc.expr)
+		assert.Equal(t, tc.want, s.Next(start), "Next should return the right time for %q", tc.expr)
+	}
+}
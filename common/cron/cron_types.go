@@ -0,0 +1,21 @@
+package cron
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	expr   string
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	// domWildcard and dowWildcard record whether the day fields were
+	// unrestricted, which changes how the two are combined
+	domWildcard bool
+	dowWildcard bool
+}
+
+// bounds describes the accepted values of a single cron field
+type bounds struct {
+	name     string
+	min, max int
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	"gocryptotrader/common"
 	"gocryptotrader/common/convert"
@@ -23,6 +24,9 @@ var (
 	errPairsManagerIsNil    = errors.New("currency pairs manager is nil")
 	errDecryptFailed        = errors.New("failed to decrypt config after 3 attempts")
 	errCheckingConfigValues = errors.New("fatal error checking config values")
+
+	errSolanaEndpointURLUnset  = errors.New("solana rpc endpoint url unset")
+	errSolanaEndpointDuplicate = errors.New("duplicate solana rpc endpoint name")
 )
 
 // CheckLoggerConfig checks to see logger values are present and valid in config
@@ -183,6 +187,44 @@ func (c *Config) CheckConfig() error {
 		c.GlobalHTTPTimeout = defaultHTTPTimeout
 	}
 
+	return c.checkSolanaConfig()
+}
+
+// checkSolanaConfig validates the Solana RPC endpoint pool and sets defaults
+func (c *Config) checkSolanaConfig() error {
+	if len(c.Solana.Endpoints) == 0 {
+		log.Warnf(log.ConfigMgr, "No Solana RPC endpoints set, defaulting to %s.\n", DefaultSolanaRPCEndpoint)
+		c.Solana.Endpoints = []SolanaRPCEndpoint{{Name: "default", URL: DefaultSolanaRPCEndpoint, Weight: 1}}
+	}
+	names := make(map[string]struct{}, len(c.Solana.Endpoints))
+	for i := range c.Solana.Endpoints {
+		e := &c.Solana.Endpoints[i]
+		if e.URL == "" {
+			return fmt.Errorf("%w: solana endpoint %d", errSolanaEndpointURLUnset, i)
+		}
+		if e.Name == "" {
+			e.Name = e.URL
+		}
+		if _, ok := names[e.Name]; ok {
+			return fmt.Errorf("%w: %s", errSolanaEndpointDuplicate, e.Name)
+		}
+		names[e.Name] = struct{}{}
+		if e.Weight <= 0 {
+			e.Weight = 1
+		}
+		if e.RateLimit > 0 && e.RateLimitInterval <= 0 {
+			e.RateLimitInterval = time.Second
+		}
+	}
+	if c.Solana.HealthCheckInterval <= 0 {
+		c.Solana.HealthCheckInterval = DefaultSolanaHealthCheckInterval
+	}
+	if c.Solana.MaxSlotLag == 0 {
+		c.Solana.MaxSlotLag = DefaultSolanaMaxSlotLag
+	}
+	if c.Solana.MaxLatency <= 0 {
+		c.Solana.MaxLatency = DefaultSolanaMaxLatency
+	}
 	return nil
 }
 
@@ -48,6 +48,17 @@ const (
 	// DefaultWebsocketTrafficTimeout is the default timeout for websocket
 	// traffic.
 	DefaultWebsocketTrafficTimeout = time.Second * 30
+	// DefaultSolanaRPCEndpoint is used when no Solana RPC endpoints are configured
+	DefaultSolanaRPCEndpoint = "http://xolana.xen.network:8899"
+	// DefaultSolanaHealthCheckInterval is the default interval between Solana
+	// RPC endpoint health checks
+	DefaultSolanaHealthCheckInterval = time.Second * 30
+	// DefaultSolanaMaxSlotLag is the default number of slots a Solana RPC
+	// endpoint may fall behind before it is considered unhealthy
+	DefaultSolanaMaxSlotLag = 50
+	// DefaultSolanaMaxLatency is the default slowest health check response
+	// a Solana RPC endpoint may give before it is considered unhealthy
+	DefaultSolanaMaxLatency = time.Second * 5
 )
 
 // Constants here hold some messages
@@ -90,11 +101,16 @@ type Config struct {
 	EncryptConfig     int                 `json:"encryptConfig"`
 	SolisDbPem        string              `json:"solisDbPem"`
 	SubKey            string              `json:"subKey"`
+	MasterKeyFile     string              `json:"masterKeyFile"`
+	CipherAlgorithm   string              `json:"cipherAlgorithm"`
 	FilePath          string              `json:"filePath"`
+	DenylistFile      string              `json:"denylistFile"`
 	GlobalHTTPTimeout time.Duration       `json:"globalHTTPTimeout"`
 	Database          database.Config     `json:"database"`
 	Logging           log.Config          `json:"logging"`
 	RemoteControl     RemoteControlConfig `json:"remoteControl"`
+	Solana            SolanaConfig        `json:"solana"`
+	Signer            SignerConfig        `json:"signer"`
 	// encryption session values
 	storedSalt            []byte
 	sessionDK             []byte
@@ -213,6 +229,47 @@ type WebsocketRPCConfig struct {
 	AllowInsecureOrigin bool   `json:"allowInsecureOrigin"`
 }
 
+// SolanaConfig stores the pool of Solana RPC endpoints used to read chain
+// state and send transactions
+type SolanaConfig struct {
+	Endpoints           []SolanaRPCEndpoint `json:"endpoints"`
+	HealthCheckInterval time.Duration       `json:"healthCheckInterval"`
+	// MaxSlotLag is the number of slots an endpoint may trail the highest
+	// slot seen across the pool before it is considered unhealthy
+	MaxSlotLag uint64 `json:"maxSlotLag"`
+	// MaxLatency is the slowest health check response an endpoint may give
+	// before it is considered unhealthy
+	MaxLatency time.Duration `json:"maxLatency"`
+}
+
+// SignerConfig stores how transactions are signed. With a socket set the
+// engine asks a separate signer process to sign and never decrypts keys
+// itself, otherwise the accounts table is used as an in-process keystore.
+// The policy settings are enforced by the signer process.
+type SignerConfig struct {
+	Socket string `json:"socket"`
+	// AllowedAccounts limits the accounts the signer signs for, every
+	// account in the keystore when empty
+	AllowedAccounts []string `json:"allowedAccounts"`
+	// AllowedPrograms limits the programs a transaction may invoke, the
+	// programs used by transfers when empty
+	AllowedPrograms []string `json:"allowedPrograms"`
+	// MaxLamportsPerTransaction caps the SOL a single transaction may
+	// transfer out of the signing account, zero disables the cap
+	MaxLamportsPerTransaction uint64 `json:"maxLamportsPerTransaction"`
+}
+
+// SolanaRPCEndpoint defines a single Solana RPC endpoint in the pool
+type SolanaRPCEndpoint struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+	// RateLimit is the number of requests allowed per RateLimitInterval, zero
+	// disables rate limiting for the endpoint
+	RateLimit         int           `json:"rateLimit"`
+	RateLimitInterval time.Duration `json:"rateLimitInterval"`
+}
+
 // RemoteControlConfig stores the RPC services config
 type RemoteControlConfig struct {
 	Username string `json:"username"`
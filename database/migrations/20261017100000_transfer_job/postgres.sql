@@ -0,0 +1,36 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS transfer_job
+(
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind varchar(10) NOT NULL,
+    source_address varchar(64) NOT NULL,
+    token_mint varchar(64) NULL,
+    status varchar(20) NOT NULL,
+    error TEXT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS transfer_job_recipient
+(
+    id BIGSERIAL PRIMARY KEY,
+    job_id uuid NOT NULL REFERENCES transfer_job(id) ON DELETE CASCADE,
+    row_index INTEGER NOT NULL,
+    address varchar(64) NOT NULL,
+    amount DOUBLE PRECISION NOT NULL,
+    memo TEXT NOT NULL DEFAULT '',
+    label TEXT NOT NULL DEFAULT '',
+    batch_index INTEGER NULL,
+    signature varchar(128) NULL,
+    last_valid_block_height BIGINT NOT NULL DEFAULT 0,
+    status varchar(20) NOT NULL,
+    error TEXT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT transfer_job_recipient_row_unique
+        unique(job_id, row_index)
+);
+
+CREATE INDEX transfer_job_recipient_signature_idx ON transfer_job_recipient(signature);
+-- +goose Down
+DROP TABLE transfer_job_recipient;
+DROP TABLE transfer_job;
@@ -0,0 +1,37 @@
+-- +goose Up
+CREATE TABLE transfer_job
+(
+    id text NOT NULL primary key,
+    kind text NOT NULL,
+    source_address text NOT NULL,
+    token_mint text NULL,
+    status text NOT NULL,
+    error text NULL,
+    created_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL default CURRENT_TIMESTAMP
+);
+
+CREATE TABLE transfer_job_recipient
+(
+    id integer NOT NULL primary key autoincrement,
+    job_id text NOT NULL,
+    row_index integer NOT NULL,
+    address text NOT NULL,
+    amount real NOT NULL,
+    memo text NOT NULL default '',
+    label text NOT NULL default '',
+    batch_index integer NULL,
+    signature text NULL,
+    last_valid_block_height integer NOT NULL default 0,
+    status text NOT NULL,
+    error text NULL,
+    updated_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    UNIQUE(job_id, row_index),
+    FOREIGN KEY(job_id) REFERENCES transfer_job(id) ON DELETE CASCADE
+);
+
+CREATE INDEX transfer_job_recipient_signature_idx ON transfer_job_recipient(signature);
+
+-- +goose Down
+DROP TABLE transfer_job_recipient;
+DROP TABLE transfer_job;
@@ -0,0 +1,38 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS transfer_schedule
+(
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL DEFAULT '',
+    kind varchar(10) NOT NULL,
+    source_address varchar(64) NOT NULL,
+    token_mint varchar(64) NULL,
+    cron_expression TEXT NULL,
+    interval_seconds BIGINT NOT NULL DEFAULT 0,
+    amount DOUBLE PRECISION NOT NULL DEFAULT 0,
+    catch_up varchar(10) NOT NULL,
+    partial_pay BOOLEAN NOT NULL DEFAULT false,
+    use_lookup_tables BOOLEAN NOT NULL DEFAULT false,
+    paused BOOLEAN NOT NULL DEFAULT false,
+    next_run_at TIMESTAMPTZ NOT NULL,
+    last_run_at TIMESTAMPTZ NULL,
+    last_job_id uuid NULL,
+    last_error TEXT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS transfer_schedule_recipient
+(
+    id BIGSERIAL PRIMARY KEY,
+    schedule_id uuid NOT NULL REFERENCES transfer_schedule(id) ON DELETE CASCADE,
+    row_index INTEGER NOT NULL,
+    address varchar(64) NOT NULL,
+    amount DOUBLE PRECISION NOT NULL DEFAULT 0,
+    memo TEXT NOT NULL DEFAULT '',
+    label TEXT NOT NULL DEFAULT '',
+    CONSTRAINT transfer_schedule_recipient_row_unique
+        unique(schedule_id, row_index)
+);
+-- +goose Down
+DROP TABLE transfer_schedule_recipient;
+DROP TABLE transfer_schedule;
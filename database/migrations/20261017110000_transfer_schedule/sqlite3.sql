@@ -0,0 +1,39 @@
+-- +goose Up
+CREATE TABLE transfer_schedule
+(
+    id text NOT NULL primary key,
+    name text NOT NULL default '',
+    kind text NOT NULL,
+    source_address text NOT NULL,
+    token_mint text NULL,
+    cron_expression text NULL,
+    interval_seconds integer NOT NULL default 0,
+    amount real NOT NULL default 0,
+    catch_up text NOT NULL,
+    partial_pay boolean NOT NULL default false,
+    use_lookup_tables boolean NOT NULL default false,
+    paused boolean NOT NULL default false,
+    next_run_at timestamp NOT NULL,
+    last_run_at timestamp NULL,
+    last_job_id text NULL,
+    last_error text NULL,
+    created_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL default CURRENT_TIMESTAMP
+);
+
+CREATE TABLE transfer_schedule_recipient
+(
+    id integer NOT NULL primary key autoincrement,
+    schedule_id text NOT NULL,
+    row_index integer NOT NULL,
+    address text NOT NULL,
+    amount real NOT NULL default 0,
+    memo text NOT NULL default '',
+    label text NOT NULL default '',
+    UNIQUE(schedule_id, row_index),
+    FOREIGN KEY(schedule_id) REFERENCES transfer_schedule(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE transfer_schedule_recipient;
+DROP TABLE transfer_schedule;
@@ -0,0 +1,38 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS vesting_plan
+(
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id INTEGER NOT NULL,
+    source_address varchar(64) NOT NULL,
+    token_mint varchar(64) NOT NULL,
+    decimals SMALLINT NOT NULL,
+    total_amount BIGINT NOT NULL,
+    released_amount BIGINT NOT NULL DEFAULT 0,
+    start_at TIMESTAMPTZ NOT NULL,
+    cliff_seconds BIGINT NOT NULL DEFAULT 0,
+    period_seconds BIGINT NOT NULL,
+    periods INTEGER NOT NULL,
+    status varchar(20) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX vesting_plan_account_idx ON vesting_plan(account_id);
+
+CREATE TABLE IF NOT EXISTS vesting_release
+(
+    id BIGSERIAL PRIMARY KEY,
+    plan_id uuid NOT NULL REFERENCES vesting_plan(id) ON DELETE CASCADE,
+    amount BIGINT NOT NULL,
+    signature varchar(128) NULL,
+    last_valid_block_height BIGINT NOT NULL DEFAULT 0,
+    status varchar(20) NOT NULL,
+    error TEXT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX vesting_release_plan_idx ON vesting_release(plan_id);
+-- +goose Down
+DROP TABLE vesting_release;
+DROP TABLE vesting_plan;
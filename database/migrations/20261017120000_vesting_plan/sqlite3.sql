@@ -0,0 +1,40 @@
+-- +goose Up
+CREATE TABLE vesting_plan
+(
+    id text NOT NULL primary key,
+    account_id integer NOT NULL,
+    source_address text NOT NULL,
+    token_mint text NOT NULL,
+    decimals integer NOT NULL,
+    total_amount integer NOT NULL,
+    released_amount integer NOT NULL default 0,
+    start_at timestamp NOT NULL,
+    cliff_seconds integer NOT NULL default 0,
+    period_seconds integer NOT NULL,
+    periods integer NOT NULL,
+    status text NOT NULL,
+    created_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL default CURRENT_TIMESTAMP
+);
+
+CREATE INDEX vesting_plan_account_idx ON vesting_plan(account_id);
+
+CREATE TABLE vesting_release
+(
+    id integer NOT NULL primary key autoincrement,
+    plan_id text NOT NULL,
+    amount integer NOT NULL,
+    signature text NULL,
+    last_valid_block_height integer NOT NULL default 0,
+    status text NOT NULL,
+    error text NULL,
+    created_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    FOREIGN KEY(plan_id) REFERENCES vesting_plan(id) ON DELETE CASCADE
+);
+
+CREATE INDEX vesting_release_plan_idx ON vesting_release(plan_id);
+
+-- +goose Down
+DROP TABLE vesting_release;
+DROP TABLE vesting_plan;
@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE transfer_job ADD COLUMN memo_mode varchar(20) NOT NULL DEFAULT '';
+ALTER TABLE transfer_job ADD COLUMN reference TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX transfer_job_reference_idx ON transfer_job(reference);
+CREATE INDEX transfer_job_recipient_memo_idx ON transfer_job_recipient(memo);
+-- +goose Down
+DROP INDEX transfer_job_recipient_memo_idx;
+DROP INDEX transfer_job_reference_idx;
+
+ALTER TABLE transfer_job DROP COLUMN reference;
+ALTER TABLE transfer_job DROP COLUMN memo_mode;
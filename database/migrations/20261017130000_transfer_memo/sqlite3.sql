@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE transfer_job ADD COLUMN memo_mode text NOT NULL default '';
+ALTER TABLE transfer_job ADD COLUMN reference text NOT NULL default '';
+
+CREATE INDEX transfer_job_reference_idx ON transfer_job(reference);
+CREATE INDEX transfer_job_recipient_memo_idx ON transfer_job_recipient(memo);
+
+-- +goose Down
+DROP INDEX transfer_job_recipient_memo_idx;
+DROP INDEX transfer_job_reference_idx;
+
+ALTER TABLE transfer_job DROP COLUMN reference;
+ALTER TABLE transfer_job DROP COLUMN memo_mode;
@@ -0,0 +1,4 @@
+-- +goose Up
+ALTER TABLE transfer_job_recipient ADD COLUMN source_address varchar(64) NOT NULL DEFAULT '';
+-- +goose Down
+ALTER TABLE transfer_job_recipient DROP COLUMN source_address;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE transfer_job_recipient ADD COLUMN source_address text NOT NULL default '';
+
+-- +goose Down
+ALTER TABLE transfer_job_recipient DROP COLUMN source_address;
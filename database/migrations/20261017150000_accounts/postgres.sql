@@ -0,0 +1,26 @@
+-- +goose Up
+-- accounts 可能早于迁移由外部创建，已存在时保留原表
+CREATE TABLE IF NOT EXISTS accounts
+(
+    id SERIAL PRIMARY KEY,
+    name varchar(255) NOT NULL,
+    address varchar(64) NOT NULL UNIQUE,
+    exchange_address_id varchar(255) NULL,
+    zk_address_id varchar(255) NULL,
+    f4_address_id varchar(255) NULL,
+    ot_address_id varchar(255) NULL,
+    cipher TEXT NULL,
+    layer INTEGER NOT NULL,
+    owner varchar(255) NOT NULL,
+    chain_name varchar(125) NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS accounts_owner_layer_idx ON accounts(owner, layer);
+CREATE INDEX IF NOT EXISTS accounts_chain_name_idx ON accounts(chain_name);
+
+-- +goose Down
+-- 表中保存着账户私钥的密文，回滚时只删除索引
+DROP INDEX IF EXISTS accounts_chain_name_idx;
+DROP INDEX IF EXISTS accounts_owner_layer_idx;
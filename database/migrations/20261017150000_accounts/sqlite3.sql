@@ -0,0 +1,26 @@
+-- +goose Up
+-- accounts 可能早于迁移由外部创建，已存在时保留原表
+CREATE TABLE IF NOT EXISTS accounts
+(
+    id integer NOT NULL primary key,
+    name text NOT NULL,
+    address text NOT NULL UNIQUE,
+    exchange_address_id text NULL,
+    zk_address_id text NULL,
+    f4_address_id text NULL,
+    ot_address_id text NULL,
+    cipher text NULL,
+    layer integer NOT NULL,
+    owner text NOT NULL,
+    chain_name text NULL,
+    created_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL default CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS accounts_owner_layer_idx ON accounts(owner, layer);
+CREATE INDEX IF NOT EXISTS accounts_chain_name_idx ON accounts(chain_name);
+
+-- +goose Down
+-- 表中保存着账户私钥的密文，回滚时只删除索引
+DROP INDEX IF EXISTS accounts_chain_name_idx;
+DROP INDEX IF EXISTS accounts_owner_layer_idx;
@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE accounts ADD COLUMN parent_id INTEGER NULL REFERENCES accounts(id);
+
+CREATE INDEX accounts_parent_id_idx ON accounts(parent_id);
+-- +goose Down
+DROP INDEX accounts_parent_id_idx;
+
+ALTER TABLE accounts DROP COLUMN parent_id;
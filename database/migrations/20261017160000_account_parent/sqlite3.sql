@@ -0,0 +1,35 @@
+-- +goose Up
+ALTER TABLE accounts ADD COLUMN parent_id integer NULL REFERENCES accounts(id);
+
+CREATE INDEX accounts_parent_id_idx ON accounts(parent_id);
+
+-- +goose Down
+-- SQLite 无法删除带外键约束的列，重建不含 parent_id 的表并保留账户数据
+-- +goose StatementBegin
+DROP INDEX accounts_parent_id_idx;
+
+CREATE TABLE accounts_new
+(
+    id integer NOT NULL primary key,
+    name text NOT NULL,
+    address text NOT NULL UNIQUE,
+    exchange_address_id text NULL,
+    zk_address_id text NULL,
+    f4_address_id text NULL,
+    ot_address_id text NULL,
+    cipher text NULL,
+    layer integer NOT NULL,
+    owner text NOT NULL,
+    chain_name text NULL,
+    created_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL default CURRENT_TIMESTAMP
+);
+INSERT INTO accounts_new SELECT id, name, address, exchange_address_id, zk_address_id, f4_address_id, ot_address_id, cipher, layer, owner, chain_name, created_at, updated_at FROM accounts;
+
+DROP TABLE accounts;
+
+ALTER TABLE accounts_new RENAME TO accounts;
+
+CREATE INDEX accounts_owner_layer_idx ON accounts(owner, layer);
+CREATE INDEX accounts_chain_name_idx ON accounts(chain_name);
+-- +goose StatementEnd
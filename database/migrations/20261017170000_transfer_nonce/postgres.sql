@@ -0,0 +1,6 @@
+-- +goose Up
+ALTER TABLE transfer_job_recipient ADD COLUMN nonce_account varchar(64) NOT NULL DEFAULT '';
+ALTER TABLE transfer_job_recipient ADD COLUMN nonce varchar(64) NOT NULL DEFAULT '';
+-- +goose Down
+ALTER TABLE transfer_job_recipient DROP COLUMN nonce;
+ALTER TABLE transfer_job_recipient DROP COLUMN nonce_account;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE transfer_job_recipient ADD COLUMN nonce_account text NOT NULL default '';
+ALTER TABLE transfer_job_recipient ADD COLUMN nonce text NOT NULL default '';
+
+-- +goose Down
+ALTER TABLE transfer_job_recipient DROP COLUMN nonce;
+ALTER TABLE transfer_job_recipient DROP COLUMN nonce_account;
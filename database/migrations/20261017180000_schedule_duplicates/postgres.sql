@@ -0,0 +1,4 @@
+-- +goose Up
+ALTER TABLE transfer_schedule ADD COLUMN duplicates varchar(16) NOT NULL DEFAULT '';
+-- +goose Down
+ALTER TABLE transfer_schedule DROP COLUMN duplicates;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE transfer_schedule ADD COLUMN duplicates text NOT NULL default '';
+
+-- +goose Down
+ALTER TABLE transfer_schedule DROP COLUMN duplicates;
@@ -0,0 +1,4 @@
+-- +goose Up
+ALTER TABLE transfer_job_recipient ALTER COLUMN amount TYPE NUMERIC USING amount::numeric;
+-- +goose Down
+ALTER TABLE transfer_job_recipient ALTER COLUMN amount TYPE DOUBLE PRECISION USING amount::double precision;
@@ -0,0 +1,66 @@
+-- +goose Up
+-- 数量以十进制字符串保存，SQLite 无法修改列类型，重建表
+-- +goose StatementBegin
+CREATE TABLE transfer_job_recipient_new
+(
+    id integer NOT NULL primary key autoincrement,
+    job_id text NOT NULL,
+    row_index integer NOT NULL,
+    address text NOT NULL,
+    amount text NOT NULL,
+    memo text NOT NULL default '',
+    label text NOT NULL default '',
+    batch_index integer NULL,
+    signature text NULL,
+    last_valid_block_height integer NOT NULL default 0,
+    status text NOT NULL,
+    error text NULL,
+    updated_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    source_address text NOT NULL default '',
+    nonce_account text NOT NULL default '',
+    nonce text NOT NULL default '',
+    UNIQUE(job_id, row_index),
+    FOREIGN KEY(job_id) REFERENCES transfer_job(id) ON DELETE CASCADE
+);
+INSERT INTO transfer_job_recipient_new SELECT id, job_id, row_index, address, CAST(amount AS TEXT), memo, label, batch_index, signature, last_valid_block_height, status, error, updated_at, source_address, nonce_account, nonce FROM transfer_job_recipient;
+
+DROP TABLE transfer_job_recipient;
+
+ALTER TABLE transfer_job_recipient_new RENAME TO transfer_job_recipient;
+
+CREATE INDEX transfer_job_recipient_signature_idx ON transfer_job_recipient(signature);
+CREATE INDEX transfer_job_recipient_memo_idx ON transfer_job_recipient(memo);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TABLE transfer_job_recipient_new
+(
+    id integer NOT NULL primary key autoincrement,
+    job_id text NOT NULL,
+    row_index integer NOT NULL,
+    address text NOT NULL,
+    amount real NOT NULL,
+    memo text NOT NULL default '',
+    label text NOT NULL default '',
+    batch_index integer NULL,
+    signature text NULL,
+    last_valid_block_height integer NOT NULL default 0,
+    status text NOT NULL,
+    error text NULL,
+    updated_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    source_address text NOT NULL default '',
+    nonce_account text NOT NULL default '',
+    nonce text NOT NULL default '',
+    UNIQUE(job_id, row_index),
+    FOREIGN KEY(job_id) REFERENCES transfer_job(id) ON DELETE CASCADE
+);
+INSERT INTO transfer_job_recipient_new SELECT id, job_id, row_index, address, CAST(amount AS REAL), memo, label, batch_index, signature, last_valid_block_height, status, error, updated_at, source_address, nonce_account, nonce FROM transfer_job_recipient;
+
+DROP TABLE transfer_job_recipient;
+
+ALTER TABLE transfer_job_recipient_new RENAME TO transfer_job_recipient;
+
+CREATE INDEX transfer_job_recipient_signature_idx ON transfer_job_recipient(signature);
+CREATE INDEX transfer_job_recipient_memo_idx ON transfer_job_recipient(memo);
+-- +goose StatementEnd
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/thrasher-corp/sqlboiler/boil"
+	"github.com/thrasher-corp/sqlboiler/queries"
+	"github.com/thrasher-corp/sqlboiler/queries/qm"
+)
+
+// Account is an object representing the database table.
+type Account struct {
+	ID                int            `boil:"id" json:"id" toml:"id" yaml:"id"`
+	Name              string         `boil:"name" json:"name" toml:"name" yaml:"name"`
+	Address           string         `boil:"address" json:"address" toml:"address" yaml:"address"`
+	ExchangeAddressID sql.NullString `boil:"exchange_address_id" json:"exchange_address_id" toml:"exchange_address_id" yaml:"exchange_address_id"`
+	ZkAddressID       sql.NullString `boil:"zk_address_id" json:"zk_address_id" toml:"zk_address_id" yaml:"zk_address_id"`
+	F4AddressID       sql.NullString `boil:"f4_address_id" json:"f4_address_id" toml:"f4_address_id" yaml:"f4_address_id"`
+	OTAddressID       sql.NullString `boil:"ot_address_id" json:"ot_address_id" toml:"ot_address_id" yaml:"ot_address_id"`
+	Cipher            sql.NullString `boil:"cipher" json:"cipher" toml:"cipher" yaml:"cipher"`
+	Layer             int            `boil:"layer" json:"layer" toml:"layer" yaml:"layer"`
+	Owner             sql.NullString `boil:"owner" json:"owner" toml:"owner" yaml:"owner"`
+	ChainName         sql.NullString `boil:"chain_name" json:"chain_name" toml:"chain_name" yaml:"chain_name"`
+	ParentID          sql.NullInt64  `boil:"parent_id" json:"parent_id,omitempty" toml:"parent_id" yaml:"parent_id,omitempty"`
+	CreatedAt         time.Time      `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	UpdatedAt         time.Time      `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+}
+
+// AccountSlice is an alias for a slice of pointers to Account
+type AccountSlice []*Account
+
+type accountQuery struct {
+	*queries.Query
+}
+
+// Insert a single accounts record using an executor and sets its ID.
+func (o *Account) Insert(ctx context.Context, exec boil.ContextExecutor) error {
+	if o == nil {
+		return errors.New("postgres: no accounts provided for insertion")
+	}
+	now := time.Now().UTC()
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = now
+	}
+	o.UpdatedAt = now
+
+	err := exec.QueryRowContext(ctx,
+		"INSERT INTO \"accounts\" (\"name\",\"address\",\"exchange_address_id\",\"zk_address_id\",\"f4_address_id\",\"ot_address_id\",\"cipher\",\"layer\",\"owner\",\"chain_name\",\"parent_id\",\"created_at\",\"updated_at\") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13) RETURNING \"id\"",
+		o.Name, o.Address, o.ExchangeAddressID, o.ZkAddressID, o.F4AddressID, o.OTAddressID, o.Cipher, o.Layer, o.Owner, o.ChainName, o.ParentID, o.CreatedAt, o.UpdatedAt).Scan(&o.ID)
+	if err != nil {
+		return errors.Wrap(err, "postgres: unable to insert into accounts")
+	}
+	return nil
+}
+
+// Update updates every column of an accounts record except the address and
+// cipher, returning the number of rows affected.
+func (o *Account) Update(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	o.UpdatedAt = time.Now().UTC()
+	result, err := exec.ExecContext(ctx,
+		"UPDATE \"accounts\" SET \"name\"=$1,\"exchange_address_id\"=$2,\"zk_address_id\"=$3,\"f4_address_id\"=$4,\"ot_address_id\"=$5,\"layer\"=$6,\"owner\"=$7,\"chain_name\"=$8,\"parent_id\"=$9,\"updated_at\"=$10 WHERE \"id\"=$11",
+		o.Name, o.ExchangeAddressID, o.ZkAddressID, o.F4AddressID, o.OTAddressID, o.Layer, o.Owner, o.ChainName, o.ParentID, o.UpdatedAt, o.ID)
+	if err != nil {
+		return 0, errors.Wrap(err, "postgres: unable to update accounts")
+	}
+	return result.RowsAffected()
+}
+
+// UpdateAccountCipher replaces the cipher of an accounts record provided it
+// still holds oldCipher, returning the number of rows affected.
+func UpdateAccountCipher(ctx context.Context, exec boil.ContextExecutor, id int, oldCipher, newCipher string) (int64, error) {
+	result, err := exec.ExecContext(ctx,
+		"UPDATE \"accounts\" SET \"cipher\"=$1,\"updated_at\"=$2 WHERE \"id\"=$3 AND \"cipher\"=$4",
+		newCipher, time.Now().UTC(), id, oldCipher)
+	if err != nil {
+		return 0, errors.Wrap(err, "postgres: unable to update accounts cipher")
+	}
+	return result.RowsAffected()
+}
+
+// DeleteAccount deletes an accounts record by ID, returning the number of
+// rows affected.
+func DeleteAccount(ctx context.Context, exec boil.ContextExecutor, id int) (int64, error) {
+	result, err := exec.ExecContext(ctx, "DELETE FROM \"accounts\" WHERE \"id\"=$1", id)
+	if err != nil {
+		return 0, errors.Wrap(err, "postgres: unable to delete from accounts")
+	}
+	return result.RowsAffected()
+}
+
+// Accounts retrieves all the accounts records using an executor
+func Accounts(mods ...qm.QueryMod) accountQuery {
+	mods = append(mods, qm.From("\"accounts\""))
+	return accountQuery{NewQuery(mods...)}
+}
+
+// FindAccount retrieves a single accounts record by ID with an executor.
+func FindAccount(ctx context.Context, exec boil.ContextExecutor, id int) (*Account, error) {
+	return findAccount(ctx, exec, "id", id)
+}
+
+// FindAccountByAddress retrieves a single accounts record by address with an executor.
+func FindAccountByAddress(ctx context.Context, exec boil.ContextExecutor, address string) (*Account, error) {
+	return findAccount(ctx, exec, "address", address)
+}
+
+func findAccount(ctx context.Context, exec boil.ContextExecutor, column string, value interface{}) (*Account, error) {
+	o := &Account{}
+	err := queries.Raw("select * from \"accounts\" where \""+column+"\"=$1", value).Bind(ctx, exec, o)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "postgres: unable to select from accounts")
+	}
+	return o, nil
+}
+
+// All returns all Account records from the query.
+func (q accountQuery) All(ctx context.Context, exec boil.ContextExecutor) (AccountSlice, error) {
+	var o AccountSlice
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres: failed to assign all query results to Account slice")
+	}
+	return o, nil
+}
+
+// Count returns the count of all Account records in the query.
+func (q accountQuery) Count(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	var count int64
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "postgres: failed to count accounts rows")
+	}
+	return count, nil
+}
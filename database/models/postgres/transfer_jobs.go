@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"github.com/thrasher-corp/sqlboiler/boil"
+	"github.com/thrasher-corp/sqlboiler/queries"
+	"github.com/thrasher-corp/sqlboiler/queries/qm"
+)
+
+// TransferJob is an object representing the database table.
+type TransferJob struct {
+	ID            string         `boil:"id" json:"id" toml:"id" yaml:"id"`
+	Kind          string         `boil:"kind" json:"kind" toml:"kind" yaml:"kind"`
+	SourceAddress string         `boil:"source_address" json:"source_address" toml:"source_address" yaml:"source_address"`
+	TokenMint     sql.NullString `boil:"token_mint" json:"token_mint" toml:"token_mint" yaml:"token_mint"`
+	Status        string         `boil:"status" json:"status" toml:"status" yaml:"status"`
+	Error         sql.NullString `boil:"error" json:"error" toml:"error" yaml:"error"`
+	MemoMode      string         `boil:"memo_mode" json:"memo_mode" toml:"memo_mode" yaml:"memo_mode"`
+	Reference     string         `boil:"reference" json:"reference" toml:"reference" yaml:"reference"`
+	CreatedAt     time.Time      `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	UpdatedAt     time.Time      `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+}
+
+// TransferJobRecipient is an object representing the database table.
+type TransferJobRecipient struct {
+	ID                   int64           `boil:"id" json:"id" toml:"id" yaml:"id"`
+	JobID                string          `boil:"job_id" json:"job_id" toml:"job_id" yaml:"job_id"`
+	RowIndex             int             `boil:"row_index" json:"row_index" toml:"row_index" yaml:"row_index"`
+	Address              string          `boil:"address" json:"address" toml:"address" yaml:"address"`
+	Amount               decimal.Decimal `boil:"amount" json:"amount" toml:"amount" yaml:"amount"`
+	Memo                 string          `boil:"memo" json:"memo" toml:"memo" yaml:"memo"`
+	Label                string          `boil:"label" json:"label" toml:"label" yaml:"label"`
+	SourceAddress        string          `boil:"source_address" json:"source_address" toml:"source_address" yaml:"source_address"`
+	BatchIndex           sql.NullInt64   `boil:"batch_index" json:"batch_index" toml:"batch_index" yaml:"batch_index"`
+	Signature            sql.NullString  `boil:"signature" json:"signature" toml:"signature" yaml:"signature"`
+	LastValidBlockHeight int64           `boil:"last_valid_block_height" json:"last_valid_block_height" toml:"last_valid_block_height" yaml:"last_valid_block_height"`
+	NonceAccount         string          `boil:"nonce_account" json:"nonce_account" toml:"nonce_account" yaml:"nonce_account"`
+	Nonce                string          `boil:"nonce" json:"nonce" toml:"nonce" yaml:"nonce"`
+	Status               string          `boil:"status" json:"status" toml:"status" yaml:"status"`
+	Error                sql.NullString  `boil:"error" json:"error" toml:"error" yaml:"error"`
+	UpdatedAt            time.Time       `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+}
+
+// TransferJobSlice is an alias for a slice of pointers to TransferJob
+type TransferJobSlice []*TransferJob
+
+// TransferJobRecipientSlice is an alias for a slice of pointers to TransferJobRecipient
+type TransferJobRecipientSlice []*TransferJobRecipient
+
+type transferJobQuery struct {
+	*queries.Query
+}
+
+type transferJobRecipientQuery struct {
+	*queries.Query
+}
+
+// Insert a single transfer_job record using an executor.
+func (o *TransferJob) Insert(ctx context.Context, exec boil.ContextExecutor) error {
+	if o == nil {
+		return errors.New("postgres: no transfer_job provided for insertion")
+	}
+	now := time.Now().UTC()
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = now
+	}
+	o.UpdatedAt = now
+
+	_, err := exec.ExecContext(ctx,
+		"INSERT INTO \"transfer_job\" (\"id\",\"kind\",\"source_address\",\"token_mint\",\"status\",\"error\",\"memo_mode\",\"reference\",\"created_at\",\"updated_at\") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)",
+		o.ID, o.Kind, o.SourceAddress, o.TokenMint, o.Status, o.Error, o.MemoMode, o.Reference, o.CreatedAt, o.UpdatedAt)
+	if err != nil {
+		return errors.Wrap(err, "postgres: unable to insert into transfer_job")
+	}
+	return nil
+}
+
+// UpdateStatus updates the status and error columns of a transfer_job record.
+func (o *TransferJob) UpdateStatus(ctx context.Context, exec boil.ContextExecutor) error {
+	o.UpdatedAt = time.Now().UTC()
+	_, err := exec.ExecContext(ctx,
+		"UPDATE \"transfer_job\" SET \"status\"=$1,\"error\"=$2,\"updated_at\"=$3 WHERE \"id\"=$4",
+		o.Status, o.Error, o.UpdatedAt, o.ID)
+	if err != nil {
+		return errors.Wrap(err, "postgres: unable to update transfer_job")
+	}
+	return nil
+}
+
+// Insert a single transfer_job_recipient record using an executor.
+func (o *TransferJobRecipient) Insert(ctx context.Context, exec boil.ContextExecutor) error {
+	if o == nil {
+		return errors.New("postgres: no transfer_job_recipient provided for insertion")
+	}
+	o.UpdatedAt = time.Now().UTC()
+
+	err := exec.QueryRowContext(ctx,
+		"INSERT INTO \"transfer_job_recipient\" (\"job_id\",\"row_index\",\"address\",\"amount\",\"memo\",\"label\",\"source_address\",\"batch_index\",\"signature\",\"last_valid_block_height\",\"status\",\"error\",\"updated_at\") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13) RETURNING \"id\"",
+		o.JobID, o.RowIndex, o.Address, o.Amount, o.Memo, o.Label, o.SourceAddress, o.BatchIndex, o.Signature, o.LastValidBlockHeight, o.Status, o.Error, o.UpdatedAt).Scan(&o.ID)
+	if err != nil {
+		return errors.Wrap(err, "postgres: unable to insert into transfer_job_recipient")
+	}
+	return nil
+}
+
+// UpdateProgress updates the batch, signature, nonce and status columns of a transfer_job_recipient record.
+func (o *TransferJobRecipient) UpdateProgress(ctx context.Context, exec boil.ContextExecutor) error {
+	o.UpdatedAt = time.Now().UTC()
+	_, err := exec.ExecContext(ctx,
+		"UPDATE \"transfer_job_recipient\" SET \"batch_index\"=$1,\"signature\"=$2,\"last_valid_block_height\"=$3,\"nonce_account\"=$4,\"nonce\"=$5,\"status\"=$6,\"error\"=$7,\"updated_at\"=$8 WHERE \"id\"=$9",
+		o.BatchIndex, o.Signature, o.LastValidBlockHeight, o.NonceAccount, o.Nonce, o.Status, o.Error, o.UpdatedAt, o.ID)
+	if err != nil {
+		return errors.Wrap(err, "postgres: unable to update transfer_job_recipient")
+	}
+	return nil
+}
+
+// TransferJobs retrieves all the transfer_job records using an executor
+func TransferJobs(mods ...qm.QueryMod) transferJobQuery {
+	mods = append(mods, qm.From("\"transfer_job\""))
+	return transferJobQuery{NewQuery(mods...)}
+}
+
+// TransferJobRecipients retrieves all the transfer_job_recipient records using an executor
+func TransferJobRecipients(mods ...qm.QueryMod) transferJobRecipientQuery {
+	mods = append(mods, qm.From("\"transfer_job_recipient\""))
+	return transferJobRecipientQuery{NewQuery(mods...)}
+}
+
+// FindTransferJob retrieves a single transfer_job record by ID with an executor.
+func FindTransferJob(ctx context.Context, exec boil.ContextExecutor, id string) (*TransferJob, error) {
+	o := &TransferJob{}
+	err := queries.Raw("select * from \"transfer_job\" where \"id\"=$1", id).Bind(ctx, exec, o)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "postgres: unable to select from transfer_job")
+	}
+	return o, nil
+}
+
+// All returns all TransferJob records from the query.
+func (q transferJobQuery) All(ctx context.Context, exec boil.ContextExecutor) (TransferJobSlice, error) {
+	var o TransferJobSlice
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres: failed to assign all query results to TransferJob slice")
+	}
+	return o, nil
+}
+
+// All returns all TransferJobRecipient records from the query.
+func (q transferJobRecipientQuery) All(ctx context.Context, exec boil.ContextExecutor) (TransferJobRecipientSlice, error) {
+	var o TransferJobRecipientSlice
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres: failed to assign all query results to TransferJobRecipient slice")
+	}
+	return o, nil
+}
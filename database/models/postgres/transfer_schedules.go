@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/thrasher-corp/sqlboiler/boil"
+	"github.com/thrasher-corp/sqlboiler/queries"
+	"github.com/thrasher-corp/sqlboiler/queries/qm"
+)
+
+// TransferSchedule is an object representing the database table.
+type TransferSchedule struct {
+	ID              string         `boil:"id" json:"id" toml:"id" yaml:"id"`
+	Name            string         `boil:"name" json:"name" toml:"name" yaml:"name"`
+	Kind            string         `boil:"kind" json:"kind" toml:"kind" yaml:"kind"`
+	SourceAddress   string         `boil:"source_address" json:"source_address" toml:"source_address" yaml:"source_address"`
+	TokenMint       sql.NullString `boil:"token_mint" json:"token_mint" toml:"token_mint" yaml:"token_mint"`
+	CronExpression  sql.NullString `boil:"cron_expression" json:"cron_expression" toml:"cron_expression" yaml:"cron_expression"`
+	IntervalSeconds int64          `boil:"interval_seconds" json:"interval_seconds" toml:"interval_seconds" yaml:"interval_seconds"`
+	Amount          float64        `boil:"amount" json:"amount" toml:"amount" yaml:"amount"`
+	CatchUp         string         `boil:"catch_up" json:"catch_up" toml:"catch_up" yaml:"catch_up"`
+	PartialPay      bool           `boil:"partial_pay" json:"partial_pay" toml:"partial_pay" yaml:"partial_pay"`
+	UseLookupTables bool           `boil:"use_lookup_tables" json:"use_lookup_tables" toml:"use_lookup_tables" yaml:"use_lookup_tables"`
+	Duplicates      string         `boil:"duplicates" json:"duplicates" toml:"duplicates" yaml:"duplicates"`
+	Paused          bool           `boil:"paused" json:"paused" toml:"paused" yaml:"paused"`
+	NextRunAt       time.Time      `boil:"next_run_at" json:"next_run_at" toml:"next_run_at" yaml:"next_run_at"`
+	LastRunAt       sql.NullTime   `boil:"last_run_at" json:"last_run_at" toml:"last_run_at" yaml:"last_run_at"`
+	LastJobID       sql.NullString `boil:"last_job_id" json:"last_job_id" toml:"last_job_id" yaml:"last_job_id"`
+	LastError       sql.NullString `boil:"last_error" json:"last_error" toml:"last_error" yaml:"last_error"`
+	CreatedAt       time.Time      `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	UpdatedAt       time.Time      `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+}
+
+// TransferScheduleRecipient is an object representing the database table.
+type TransferScheduleRecipient struct {
+	ID         int64   `boil:"id" json:"id" toml:"id" yaml:"id"`
+	ScheduleID string  `boil:"schedule_id" json:"schedule_id" toml:"schedule_id" yaml:"schedule_id"`
+	RowIndex   int     `boil:"row_index" json:"row_index" toml:"row_index" yaml:"row_index"`
+	Address    string  `boil:"address" json:"address" toml:"address" yaml:"address"`
+	Amount     float64 `boil:"amount" json:"amount" toml:"amount" yaml:"amount"`
+	Memo       string  `boil:"memo" json:"memo" toml:"memo" yaml:"memo"`
+	Label      string  `boil:"label" json:"label" toml:"label" yaml:"label"`
+}
+
+// TransferScheduleSlice is an alias for a slice of pointers to TransferSchedule
+type TransferScheduleSlice []*TransferSchedule
+
+// TransferScheduleRecipientSlice is an alias for a slice of pointers to TransferScheduleRecipient
+type TransferScheduleRecipientSlice []*TransferScheduleRecipient
+
+type transferScheduleQuery struct {
+	*queries.Query
+}
+
+type transferScheduleRecipientQuery struct {
+	*queries.Query
+}
+
+// Insert a single transfer_schedule record using an executor.
+func (o *TransferSchedule) Insert(ctx context.Context, exec boil.ContextExecutor) error {
+	if o == nil {
+		return errors.New("postgres: no transfer_schedule provided for insertion")
+	}
+	now := time.Now().UTC()
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = now
+	}
+	o.UpdatedAt = now
+
+	_, err := exec.ExecContext(ctx,
+		"INSERT INTO \"transfer_schedule\" (\"id\",\"name\",\"kind\",\"source_address\",\"token_mint\",\"cron_expression\",\"interval_seconds\",\"amount\",\"catch_up\",\"partial_pay\",\"use_lookup_tables\",\"duplicates\",\"paused\",\"next_run_at\",\"last_run_at\",\"last_job_id\",\"last_error\",\"created_at\",\"updated_at\") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19)",
+		o.ID, o.Name, o.Kind, o.SourceAddress, o.TokenMint, o.CronExpression, o.IntervalSeconds, o.Amount, o.CatchUp, o.PartialPay, o.UseLookupTables, o.Duplicates, o.Paused, o.NextRunAt, o.LastRunAt, o.LastJobID, o.LastError, o.CreatedAt, o.UpdatedAt)
+	if err != nil {
+		return errors.Wrap(err, "postgres: unable to insert into transfer_schedule")
+	}
+	return nil
+}
+
+// Update updates the definition columns of a transfer_schedule record.
+func (o *TransferSchedule) Update(ctx context.Context, exec boil.ContextExecutor) error {
+	o.UpdatedAt = time.Now().UTC()
+	_, err := exec.ExecContext(ctx,
+		"UPDATE \"transfer_schedule\" SET \"name\"=$1,\"kind\"=$2,\"source_address\"=$3,\"token_mint\"=$4,\"cron_expression\"=$5,\"interval_seconds\"=$6,\"amount\"=$7,\"catch_up\"=$8,\"partial_pay\"=$9,\"use_lookup_tables\"=$10,\"duplicates\"=$11,\"paused\"=$12,\"next_run_at\"=$13,\"updated_at\"=$14 WHERE \"id\"=$15",
+		o.Name, o.Kind, o.SourceAddress, o.TokenMint, o.CronExpression, o.IntervalSeconds, o.Amount, o.CatchUp, o.PartialPay, o.UseLookupTables, o.Duplicates, o.Paused, o.NextRunAt, o.UpdatedAt, o.ID)
+	if err != nil {
+		return errors.Wrap(err, "postgres: unable to update transfer_schedule")
+	}
+	return nil
+}
+
+// UpdateRun updates the run bookkeeping columns of a transfer_schedule record.
+func (o *TransferSchedule) UpdateRun(ctx context.Context, exec boil.ContextExecutor) error {
+	o.UpdatedAt = time.Now().UTC()
+	_, err := exec.ExecContext(ctx,
+		"UPDATE \"transfer_schedule\" SET \"last_run_at\"=$1,\"next_run_at\"=$2,\"last_job_id\"=$3,\"last_error\"=$4,\"updated_at\"=$5 WHERE \"id\"=$6",
+		o.LastRunAt, o.NextRunAt, o.LastJobID, o.LastError, o.UpdatedAt, o.ID)
+	if err != nil {
+		return errors.Wrap(err, "postgres: unable to update transfer_schedule")
+	}
+	return nil
+}
+
+// Delete deletes a single transfer_schedule record with an executor.
+func (o *TransferSchedule) Delete(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	result, err := exec.ExecContext(ctx, "DELETE FROM \"transfer_schedule\" WHERE \"id\"=$1", o.ID)
+	if err != nil {
+		return 0, errors.Wrap(err, "postgres: unable to delete from transfer_schedule")
+	}
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "postgres: failed to get rows affected by delete for transfer_schedule")
+	}
+	return rowsAff, nil
+}
+
+// Insert a single transfer_schedule_recipient record using an executor.
+func (o *TransferScheduleRecipient) Insert(ctx context.Context, exec boil.ContextExecutor) error {
+	if o == nil {
+		return errors.New("postgres: no transfer_schedule_recipient provided for insertion")
+	}
+
+	err := exec.QueryRowContext(ctx,
+		"INSERT INTO \"transfer_schedule_recipient\" (\"schedule_id\",\"row_index\",\"address\",\"amount\",\"memo\",\"label\") VALUES ($1,$2,$3,$4,$5,$6) RETURNING \"id\"",
+		o.ScheduleID, o.RowIndex, o.Address, o.Amount, o.Memo, o.Label).Scan(&o.ID)
+	if err != nil {
+		return errors.Wrap(err, "postgres: unable to insert into transfer_schedule_recipient")
+	}
+	return nil
+}
+
+// DeleteTransferScheduleRecipients deletes all transfer_schedule_recipient records of a schedule.
+func DeleteTransferScheduleRecipients(ctx context.Context, exec boil.ContextExecutor, scheduleID string) error {
+	_, err := exec.ExecContext(ctx, "DELETE FROM \"transfer_schedule_recipient\" WHERE \"schedule_id\"=$1", scheduleID)
+	if err != nil {
+		return errors.Wrap(err, "postgres: unable to delete from transfer_schedule_recipient")
+	}
+	return nil
+}
+
+// TransferSchedules retrieves all the transfer_schedule records using an executor
+func TransferSchedules(mods ...qm.QueryMod) transferScheduleQuery {
+	mods = append(mods, qm.From("\"transfer_schedule\""))
+	return transferScheduleQuery{NewQuery(mods...)}
+}
+
+// TransferScheduleRecipients retrieves all the transfer_schedule_recipient records using an executor
+func TransferScheduleRecipients(mods ...qm.QueryMod) transferScheduleRecipientQuery {
+	mods = append(mods, qm.From("\"transfer_schedule_recipient\""))
+	return transferScheduleRecipientQuery{NewQuery(mods...)}
+}
+
+// FindTransferSchedule retrieves a single transfer_schedule record by ID with an executor.
+func FindTransferSchedule(ctx context.Context, exec boil.ContextExecutor, id string) (*TransferSchedule, error) {
+	o := &TransferSchedule{}
+	err := queries.Raw("select * from \"transfer_schedule\" where \"id\"=$1", id).Bind(ctx, exec, o)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "postgres: unable to select from transfer_schedule")
+	}
+	return o, nil
+}
+
+// All returns all TransferSchedule records from the query.
+func (q transferScheduleQuery) All(ctx context.Context, exec boil.ContextExecutor) (TransferScheduleSlice, error) {
+	var o TransferScheduleSlice
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres: failed to assign all query results to TransferSchedule slice")
+	}
+	return o, nil
+}
+
+// All returns all TransferScheduleRecipient records from the query.
+func (q transferScheduleRecipientQuery) All(ctx context.Context, exec boil.ContextExecutor) (TransferScheduleRecipientSlice, error) {
+	var o TransferScheduleRecipientSlice
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres: failed to assign all query results to TransferScheduleRecipient slice")
+	}
+	return o, nil
+}
@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/thrasher-corp/sqlboiler/boil"
+	"github.com/thrasher-corp/sqlboiler/queries"
+	"github.com/thrasher-corp/sqlboiler/queries/qm"
+)
+
+// VestingPlan is an object representing the database table.
+type VestingPlan struct {
+	ID             string    `boil:"id" json:"id" toml:"id" yaml:"id"`
+	AccountID      int       `boil:"account_id" json:"account_id" toml:"account_id" yaml:"account_id"`
+	SourceAddress  string    `boil:"source_address" json:"source_address" toml:"source_address" yaml:"source_address"`
+	TokenMint      string    `boil:"token_mint" json:"token_mint" toml:"token_mint" yaml:"token_mint"`
+	Decimals       int       `boil:"decimals" json:"decimals" toml:"decimals" yaml:"decimals"`
+	TotalAmount    int64     `boil:"total_amount" json:"total_amount" toml:"total_amount" yaml:"total_amount"`
+	ReleasedAmount int64     `boil:"released_amount" json:"released_amount" toml:"released_amount" yaml:"released_amount"`
+	StartAt        time.Time `boil:"start_at" json:"start_at" toml:"start_at" yaml:"start_at"`
+	CliffSeconds   int64     `boil:"cliff_seconds" json:"cliff_seconds" toml:"cliff_seconds" yaml:"cliff_seconds"`
+	PeriodSeconds  int64     `boil:"period_seconds" json:"period_seconds" toml:"period_seconds" yaml:"period_seconds"`
+	Periods        int       `boil:"periods" json:"periods" toml:"periods" yaml:"periods"`
+	Status         string    `boil:"status" json:"status" toml:"status" yaml:"status"`
+	CreatedAt      time.Time `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	UpdatedAt      time.Time `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+}
+
+// VestingRelease is an object representing the database table.
+type VestingRelease struct {
+	ID                   int64          `boil:"id" json:"id" toml:"id" yaml:"id"`
+	PlanID               string         `boil:"plan_id" json:"plan_id" toml:"plan_id" yaml:"plan_id"`
+	Amount               int64          `boil:"amount" json:"amount" toml:"amount" yaml:"amount"`
+	Signature            sql.NullString `boil:"signature" json:"signature" toml:"signature" yaml:"signature"`
+	LastValidBlockHeight int64          `boil:"last_valid_block_height" json:"last_valid_block_height" toml:"last_valid_block_height" yaml:"last_valid_block_height"`
+	Status               string         `boil:"status" json:"status" toml:"status" yaml:"status"`
+	Error                sql.NullString `boil:"error" json:"error" toml:"error" yaml:"error"`
+	CreatedAt            time.Time      `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	UpdatedAt            time.Time      `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+}
+
+// VestingPlanSlice is an alias for a slice of pointers to VestingPlan
+type VestingPlanSlice []*VestingPlan
+
+// VestingReleaseSlice is an alias for a slice of pointers to VestingRelease
+type VestingReleaseSlice []*VestingRelease
+
+type vestingPlanQuery struct {
+	*queries.Query
+}
+
+type vestingReleaseQuery struct {
+	*queries.Query
+}
+
+// Insert a single vesting_plan record using an executor.
+func (o *VestingPlan) Insert(ctx context.Context, exec boil.ContextExecutor) error {
+	if o == nil {
+		return errors.New("postgres: no vesting_plan provided for insertion")
+	}
+	now := time.Now().UTC()
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = now
+	}
+	o.UpdatedAt = now
+
+	_, err := exec.ExecContext(ctx,
+		"INSERT INTO \"vesting_plan\" (\"id\",\"account_id\",\"source_address\",\"token_mint\",\"decimals\",\"total_amount\",\"released_amount\",\"start_at\",\"cliff_seconds\",\"period_seconds\",\"periods\",\"status\",\"created_at\",\"updated_at\") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14)",
+		o.ID, o.AccountID, o.SourceAddress, o.TokenMint, o.Decimals, o.TotalAmount, o.ReleasedAmount, o.StartAt, o.CliffSeconds, o.PeriodSeconds, o.Periods, o.Status, o.CreatedAt, o.UpdatedAt)
+	if err != nil {
+		return errors.Wrap(err, "postgres: unable to insert into vesting_plan")
+	}
+	return nil
+}
+
+// UpdateProgress updates the released amount and status of a vesting_plan record.
+func (o *VestingPlan) UpdateProgress(ctx context.Context, exec boil.ContextExecutor) error {
+	o.UpdatedAt = time.Now().UTC()
+	_, err := exec.ExecContext(ctx,
+		"UPDATE \"vesting_plan\" SET \"released_amount\"=$1,\"status\"=$2,\"updated_at\"=$3 WHERE \"id\"=$4",
+		o.ReleasedAmount, o.Status, o.UpdatedAt, o.ID)
+	if err != nil {
+		return errors.Wrap(err, "postgres: unable to update vesting_plan")
+	}
+	return nil
+}
+
+// Insert a single vesting_release record using an executor.
+func (o *VestingRelease) Insert(ctx context.Context, exec boil.ContextExecutor) error {
+	if o == nil {
+		return errors.New("postgres: no vesting_release provided for insertion")
+	}
+	now := time.Now().UTC()
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = now
+	}
+	o.UpdatedAt = now
+
+	err := exec.QueryRowContext(ctx,
+		"INSERT INTO \"vesting_release\" (\"plan_id\",\"amount\",\"signature\",\"last_valid_block_height\",\"status\",\"error\",\"created_at\",\"updated_at\") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING \"id\"",
+		o.PlanID, o.Amount, o.Signature, o.LastValidBlockHeight, o.Status, o.Error, o.CreatedAt, o.UpdatedAt).Scan(&o.ID)
+	if err != nil {
+		return errors.Wrap(err, "postgres: unable to insert into vesting_release")
+	}
+	return nil
+}
+
+// Update updates the amount, signature and status columns of a vesting_release record.
+func (o *VestingRelease) Update(ctx context.Context, exec boil.ContextExecutor) error {
+	o.UpdatedAt = time.Now().UTC()
+	_, err := exec.ExecContext(ctx,
+		"UPDATE \"vesting_release\" SET \"amount\"=$1,\"signature\"=$2,\"last_valid_block_height\"=$3,\"status\"=$4,\"error\"=$5,\"updated_at\"=$6 WHERE \"id\"=$7",
+		o.Amount, o.Signature, o.LastValidBlockHeight, o.Status, o.Error, o.UpdatedAt, o.ID)
+	if err != nil {
+		return errors.Wrap(err, "postgres: unable to update vesting_release")
+	}
+	return nil
+}
+
+// VestingPlans retrieves all the vesting_plan records using an executor
+func VestingPlans(mods ...qm.QueryMod) vestingPlanQuery {
+	mods = append(mods, qm.From("\"vesting_plan\""))
+	return vestingPlanQuery{NewQuery(mods...)}
+}
+
+// VestingReleases retrieves all the vesting_release records using an executor
+func VestingReleases(mods ...qm.QueryMod) vestingReleaseQuery {
+	mods = append(mods, qm.From("\"vesting_release\""))
+	return vestingReleaseQuery{NewQuery(mods...)}
+}
+
+// FindVestingPlan retrieves a single vesting_plan record by ID with an executor.
+func FindVestingPlan(ctx context.Context, exec boil.ContextExecutor, id string) (*VestingPlan, error) {
+	o := &VestingPlan{}
+	err := queries.Raw("select * from \"vesting_plan\" where \"id\"=$1", id).Bind(ctx, exec, o)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "postgres: unable to select from vesting_plan")
+	}
+	return o, nil
+}
+
+// All returns all VestingPlan records from the query.
+func (q vestingPlanQuery) All(ctx context.Context, exec boil.ContextExecutor) (VestingPlanSlice, error) {
+	var o VestingPlanSlice
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres: failed to assign all query results to VestingPlan slice")
+	}
+	return o, nil
+}
+
+// All returns all VestingRelease records from the query.
+func (q vestingReleaseQuery) All(ctx context.Context, exec boil.ContextExecutor) (VestingReleaseSlice, error) {
+	var o VestingReleaseSlice
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres: failed to assign all query results to VestingRelease slice")
+	}
+	return o, nil
+}
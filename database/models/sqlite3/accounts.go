@@ -3,16 +3,12 @@ package sqlite3
 import (
 	"context"
 	"database/sql"
-	"fmt"
-	"reflect"
-	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/thrasher-corp/sqlboiler/boil"
 	"github.com/thrasher-corp/sqlboiler/queries"
 	"github.com/thrasher-corp/sqlboiler/queries/qm"
-	"github.com/thrasher-corp/sqlboiler/strmangle"
 )
 
 // Account is an object representing the database table.
@@ -28,180 +24,124 @@ type Account struct {
 	Layer             int            `boil:"layer" json:"layer" toml:"layer" yaml:"layer"`
 	Owner             sql.NullString `boil:"owner" json:"owner" toml:"owner" yaml:"owner"`
 	ChainName         sql.NullString `boil:"chain_name" json:"chain_name" toml:"chain_name" yaml:"chain_name"`
+	ParentID          sql.NullInt64  `boil:"parent_id" json:"parent_id,omitempty" toml:"parent_id" yaml:"parent_id,omitempty"`
 	CreatedAt         time.Time      `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
 	UpdatedAt         time.Time      `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+}
+
+// AccountSlice is an alias for a slice of pointers to Account
+type AccountSlice []*Account
 
-	R *accountR `boil:"-" json:"-" toml:"-" yaml:"-"`
-	L accountL  `boil:"-" json:"-" toml:"-" yaml:"-"`
+type accountQuery struct {
+	*queries.Query
 }
 
-// Insert a single record using an executor.
-// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
-func (o *Account) Insert(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) error {
+// Insert a single accounts record using an executor and sets its ID.
+func (o *Account) Insert(ctx context.Context, exec boil.ContextExecutor) error {
 	if o == nil {
 		return errors.New("sqlite3: no accounts provided for insertion")
 	}
-
-	// 定义列信息
-	accountAllColumns := []string{"id", "name", "address", "exchange_address_id", "zk_address_id", "f4_address_id", "ot_address_id", "cipher", "layer", "owner", "chain_name", "created_at", "updated_at"}
-	accountColumnsWithDefault := []string{"created_at", "updated_at"}
-	accountColumnsWithoutDefault := []string{"name", "address", "exchange_address_id", "zk_address_id", "f4_address_id", "ot_address_id", "cipher", "layer", "owner", "chain_name"}
-
-	// 获取要插入的列
-	wl, returnColumns := columns.InsertColumnSet(
-		accountAllColumns,
-		accountColumnsWithDefault,
-		accountColumnsWithoutDefault,
-		queries.NonZeroDefaultSet(accountColumnsWithDefault, o),
-	)
-
-	// 构建SQL查询
-	var query string
-	if len(wl) != 0 {
-		query = fmt.Sprintf("INSERT INTO \"accounts\" (\"%s\") VALUES (%s)",
-			strings.Join(wl, "\",\""),
-			strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
-	} else {
-		query = "INSERT INTO \"accounts\" () VALUES ()"
-	}
-
-	// 直接从结构体获取值
-	accountType := reflect.TypeOf(Account{})
-	value := reflect.Indirect(reflect.ValueOf(o))
-
-	// 创建值映射
-	vals := make([]interface{}, len(wl))
-	for i, colName := range wl {
-		field, _ := accountType.FieldByNameFunc(func(fieldName string) bool {
-			field, _ := accountType.FieldByName(fieldName)
-			tag := field.Tag.Get("boil")
-			return tag == colName
-		})
-		vals[i] = value.FieldByIndex(field.Index).Interface()
+	now := time.Now().UTC()
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = now
 	}
+	o.UpdatedAt = now
 
-	// 执行插入
-	result, err := exec.ExecContext(ctx, query, vals...)
+	result, err := exec.ExecContext(ctx,
+		"INSERT INTO \"accounts\" (\"name\",\"address\",\"exchange_address_id\",\"zk_address_id\",\"f4_address_id\",\"ot_address_id\",\"cipher\",\"layer\",\"owner\",\"chain_name\",\"parent_id\",\"created_at\",\"updated_at\") VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?)",
+		o.Name, o.Address, o.ExchangeAddressID, o.ZkAddressID, o.F4AddressID, o.OTAddressID, o.Cipher, o.Layer, o.Owner, o.ChainName, o.ParentID, o.CreatedAt, o.UpdatedAt)
 	if err != nil {
-		return errors.Wrap(err, "sqlite3: unable to insert into account")
+		return errors.Wrap(err, "sqlite3: unable to insert into accounts")
 	}
-
-	// 如果需要获取返回值（自增ID或默认值）
-	if len(returnColumns) > 0 {
-		// 对于自增ID，尝试获取
-		if o.ID == 0 {
-			id, err := result.LastInsertId()
-			if err == nil {
-				o.ID = int(id)
-			}
-		}
-
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Wrap(err, "sqlite3: unable to get the id of the inserted account")
 	}
-
+	o.ID = int(id)
 	return nil
 }
 
-// accountR is where relationships are stored.
-type accountR struct {
+// Update updates every column of an accounts record except the address and
+// cipher, returning the number of rows affected.
+func (o *Account) Update(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	o.UpdatedAt = time.Now().UTC()
+	result, err := exec.ExecContext(ctx,
+		"UPDATE \"accounts\" SET \"name\"=?,\"exchange_address_id\"=?,\"zk_address_id\"=?,\"f4_address_id\"=?,\"ot_address_id\"=?,\"layer\"=?,\"owner\"=?,\"chain_name\"=?,\"parent_id\"=?,\"updated_at\"=? WHERE \"id\"=?",
+		o.Name, o.ExchangeAddressID, o.ZkAddressID, o.F4AddressID, o.OTAddressID, o.Layer, o.Owner, o.ChainName, o.ParentID, o.UpdatedAt, o.ID)
+	if err != nil {
+		return 0, errors.Wrap(err, "sqlite3: unable to update accounts")
+	}
+	return result.RowsAffected()
 }
 
-// NewStruct creates a new relationship struct
-func (*accountR) NewStruct() *accountR {
-	return &accountR{}
+// UpdateAccountCipher replaces the cipher of an accounts record provided it
+// still holds oldCipher, returning the number of rows affected.
+func UpdateAccountCipher(ctx context.Context, exec boil.ContextExecutor, id int, oldCipher, newCipher string) (int64, error) {
+	result, err := exec.ExecContext(ctx,
+		"UPDATE \"accounts\" SET \"cipher\"=?,\"updated_at\"=? WHERE \"id\"=? AND \"cipher\"=?",
+		newCipher, time.Now().UTC(), id, oldCipher)
+	if err != nil {
+		return 0, errors.Wrap(err, "sqlite3: unable to update accounts cipher")
+	}
+	return result.RowsAffected()
 }
 
-// accountL is where Load methods for each relationship are stored.
-type accountL struct{}
-
-// AccountQuery is used to build up a query for Account records
-type accountQuery struct {
-	*queries.Query
+// DeleteAccount deletes an accounts record by ID, returning the number of
+// rows affected.
+func DeleteAccount(ctx context.Context, exec boil.ContextExecutor, id int) (int64, error) {
+	result, err := exec.ExecContext(ctx, "DELETE FROM \"accounts\" WHERE \"id\"=?", id)
+	if err != nil {
+		return 0, errors.Wrap(err, "sqlite3: unable to delete from accounts")
+	}
+	return result.RowsAffected()
 }
 
-// AccountSlice is an alias for a slice of pointers to Account
-type AccountSlice []*Account
-
-// Accounts retrieves all the records using an executor
+// Accounts retrieves all the accounts records using an executor
 func Accounts(mods ...qm.QueryMod) accountQuery {
 	mods = append(mods, qm.From("\"accounts\""))
 	return accountQuery{NewQuery(mods...)}
 }
 
-// FindAccount retrieves a single record by ID with an executor.
-// If selectCols is empty Find will return all columns.
-func FindAccount(ctx context.Context, exec boil.ContextExecutor, iD int, selectCols ...string) (*Account, error) {
-	accountObj := &Account{}
-
-	sel := "*"
-	if len(selectCols) > 0 {
-		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
-	}
-	query := fmt.Sprintf(
-		"select %s from \"accounts\" where \"id\"=$1", sel,
-	)
-
-	q := queries.Raw(query, iD)
-
-	err := q.Bind(ctx, exec, accountObj)
-	if err != nil {
-		if errors.Cause(err) == sql.ErrNoRows {
-			return nil, sql.ErrNoRows
-		}
-		return nil, errors.Wrap(err, "postgres: unable to select from account")
-	}
-
-	return accountObj, nil
+// FindAccount retrieves a single accounts record by ID with an executor.
+func FindAccount(ctx context.Context, exec boil.ContextExecutor, id int) (*Account, error) {
+	return findAccount(ctx, exec, "id", id)
 }
 
-// FindAccountByAddress retrieves a single record by Address with an executor.
-// If selectCols is empty Find will return all columns.
-func FindAccountByAddress(ctx context.Context, exec boil.ContextExecutor, address string, selectCols ...string) (*Account, error) {
-	accountObj := &Account{}
-
-	sel := "*"
-	if len(selectCols) > 0 {
-		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
-	}
-	query := fmt.Sprintf(
-		"select %s from \"accounts\" where \"address\"=$1", sel,
-	)
-
-	q := queries.Raw(query, address)
+// FindAccountByAddress retrieves a single accounts record by address with an executor.
+func FindAccountByAddress(ctx context.Context, exec boil.ContextExecutor, address string) (*Account, error) {
+	return findAccount(ctx, exec, "address", address)
+}
 
-	err := q.Bind(ctx, exec, accountObj)
+func findAccount(ctx context.Context, exec boil.ContextExecutor, column string, value interface{}) (*Account, error) {
+	o := &Account{}
+	err := queries.Raw("select * from \"accounts\" where \""+column+"\"=?", value).Bind(ctx, exec, o)
 	if err != nil {
 		if errors.Cause(err) == sql.ErrNoRows {
-			return nil, fmt.Errorf("未找到地址为 %s 的账户", address)
+			return nil, sql.ErrNoRows
 		}
-		return nil, fmt.Errorf("查询账户失败: %v", err)
+		return nil, errors.Wrap(err, "sqlite3: unable to select from accounts")
 	}
-
-	return accountObj, nil
+	return o, nil
 }
 
 // All returns all Account records from the query.
 func (q accountQuery) All(ctx context.Context, exec boil.ContextExecutor) (AccountSlice, error) {
 	var o AccountSlice
-
 	err := q.Bind(ctx, exec, &o)
 	if err != nil {
-		return nil, errors.Wrap(err, "postgres: failed to assign all query results to Account slice")
+		return nil, errors.Wrap(err, "sqlite3: failed to assign all query results to Account slice")
 	}
-
 	return o, nil
 }
 
-// One returns a single Account record from the query.
-func (q accountQuery) One(ctx context.Context, exec boil.ContextExecutor) (*Account, error) {
-	o := &Account{}
-
-	err := q.Bind(ctx, exec, o)
+// Count returns the count of all Account records in the query.
+func (q accountQuery) Count(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	var count int64
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
 	if err != nil {
-		if errors.Cause(err) == sql.ErrNoRows {
-			return nil, sql.ErrNoRows
-		}
-		return nil, errors.Wrap(err, "postgres: failed to assign one query result to Account")
+		return 0, errors.Wrap(err, "sqlite3: failed to count accounts rows")
 	}
-
-	return o, nil
+	return count, nil
 }
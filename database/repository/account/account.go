@@ -2,57 +2,372 @@ package account
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 
 	"gocryptotrader/database"
+	modelPSQL "gocryptotrader/database/models/postgres"
 	modelSQLite "gocryptotrader/database/models/sqlite3"
+	"gocryptotrader/database/repository"
 
 	"github.com/thrasher-corp/sqlboiler/queries/qm"
 )
 
-// GetAccount returns list of accounts matching query
-func GetAccount(name string, limit int) (interface{}, error) {
+// Insert 在一个事务中写入账户并回填 ID 和时间，任一地址已存在时不写入任何账户
+func Insert(accounts []*Account) error {
 	if database.DB.SQL == nil {
-		return nil, database.ErrDatabaseSupportDisabled
+		return database.ErrDatabaseSupportDisabled
 	}
 
-	var mods []qm.QueryMod
-	if name != "" {
-		mods = append(mods, qm.Where("name = ?", name))
+	ctx := context.TODO()
+	tx, err := database.DB.SQL.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+	for _, a := range accounts {
+		if err = insert(ctx, tx, a); err != nil {
+			break
+		}
+	}
+	if err != nil {
+		if rErr := tx.Rollback(); rErr != nil {
+			return fmt.Errorf("%w, 回滚失败: %v", err, rErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func insert(ctx context.Context, tx *sql.Tx, a *Account) error {
+	var count int64
+	var err error
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		count, err = modelSQLite.Accounts(qm.Where("address = ?", a.Address)).Count(ctx, tx)
+	} else {
+		count, err = modelPSQL.Accounts(qm.Where("address = ?", a.Address)).Count(ctx, tx)
+	}
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %s", ErrDuplicateAddress, a.Address)
 	}
 
-	mods = append(mods, qm.OrderBy("id"))
-	if limit > 0 {
-		mods = append(mods, qm.Limit(limit))
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		row := accountToSQLite(a)
+		if err = row.Insert(ctx, tx); err != nil {
+			return err
+		}
+		a.ID, a.CreatedAt, a.UpdatedAt = row.ID, row.CreatedAt, row.UpdatedAt
+		return nil
+	}
+	row := accountToPostgres(a)
+	if err = row.Insert(ctx, tx); err != nil {
+		return err
+	}
+	a.ID, a.CreatedAt, a.UpdatedAt = row.ID, row.CreatedAt, row.UpdatedAt
+	return nil
+}
+
+// GetByID 按 ID 返回账户
+func GetByID(id int) (*Account, error) {
+	if database.DB.SQL == nil {
+		return nil, database.ErrDatabaseSupportDisabled
 	}
 
 	ctx := context.TODO()
-	return modelSQLite.Accounts(mods...).All(ctx, database.DB.SQL)
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		row, err := modelSQLite.FindAccount(ctx, database.DB.SQL, id)
+		if err != nil {
+			return nil, notFound(id, err)
+		}
+		return accountFromSQLite(row), nil
+	}
+	row, err := modelPSQL.FindAccount(ctx, database.DB.SQL, id)
+	if err != nil {
+		return nil, notFound(id, err)
+	}
+	return accountFromPostgres(row), nil
+}
 
+// GetByAddress 按地址返回账户
+func GetByAddress(address string) (*Account, error) {
+	if database.DB.SQL == nil {
+		return nil, database.ErrDatabaseSupportDisabled
+	}
+
+	ctx := context.TODO()
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		row, err := modelSQLite.FindAccountByAddress(ctx, database.DB.SQL, address)
+		if err != nil {
+			return nil, notFound(address, err)
+		}
+		return accountFromSQLite(row), nil
+	}
+	row, err := modelPSQL.FindAccountByAddress(ctx, database.DB.SQL, address)
+	if err != nil {
+		return nil, notFound(address, err)
+	}
+	return accountFromPostgres(row), nil
 }
 
-// GetAccountByID returns a specific account by ID
-func GetAccountByID(id int) (interface{}, error) {
+// List 返回符合筛选条件的账户，按 ID 排序，f 为 nil 时返回全部账户
+func List(f *Filter) ([]*Account, error) {
 	if database.DB.SQL == nil {
 		return nil, database.ErrDatabaseSupportDisabled
 	}
+	if f == nil {
+		f = &Filter{}
+	}
+	if f.Limit < 0 || f.Offset < 0 {
+		return nil, errInvalidPage
+	}
+
+	dialect := repository.GetSQLDialect()
+	mods := append(filterMods(f), qm.OrderBy("id"))
+	mods = append(mods, pageMods(f, dialect)...)
 
 	ctx := context.TODO()
-	return modelSQLite.FindAccount(ctx, database.DB.SQL, id)
+	if dialect == database.DBSQLite3 {
+		rows, err := modelSQLite.Accounts(mods...).All(ctx, database.DB.SQL)
+		if err != nil {
+			return nil, err
+		}
+		accounts := make([]*Account, len(rows))
+		for i := range rows {
+			accounts[i] = accountFromSQLite(rows[i])
+		}
+		return accounts, nil
+	}
+	rows, err := modelPSQL.Accounts(mods...).All(ctx, database.DB.SQL)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]*Account, len(rows))
+	for i := range rows {
+		accounts[i] = accountFromPostgres(rows[i])
+	}
+	return accounts, nil
+}
 
+// Count 返回符合筛选条件的账户总数，忽略分页参数
+func Count(f *Filter) (int64, error) {
+	if database.DB.SQL == nil {
+		return 0, database.ErrDatabaseSupportDisabled
+	}
+	if f == nil {
+		f = &Filter{}
+	}
+
+	ctx := context.TODO()
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		return modelSQLite.Accounts(filterMods(f)...).Count(ctx, database.DB.SQL)
+	}
+	return modelPSQL.Accounts(filterMods(f)...).Count(ctx, database.DB.SQL)
 }
 
-// GetAccountByAddress returns a specific account by address
-func GetAccountByAddress(address string) (interface{}, error) {
+// Update 按 ID 更新账户的名称、层级、所有者、所属链、上级账户和关联地址，地址和密文不会被修改
+func Update(a *Account) error {
 	if database.DB.SQL == nil {
-		return nil, database.ErrDatabaseSupportDisabled
+		return database.ErrDatabaseSupportDisabled
 	}
 
 	ctx := context.TODO()
-	account, err := modelSQLite.FindAccountByAddress(ctx, database.DB.SQL, address)
+	var rows int64
+	var err error
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		row := accountToSQLite(a)
+		rows, err = row.Update(ctx, database.DB.SQL)
+		a.UpdatedAt = row.UpdatedAt
+	} else {
+		row := accountToPostgres(a)
+		rows, err = row.Update(ctx, database.DB.SQL)
+		a.UpdatedAt = row.UpdatedAt
+	}
 	if err != nil {
-		return nil, fmt.Errorf("获取地址信息失败: %w", err)
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w: %d", ErrAccountNotFound, a.ID)
 	}
+	return nil
+}
+
+// UpdateCipher 替换账户的密文，只有账户仍保存 oldCipher 时才会更新，不会覆盖并发的修改
+func UpdateCipher(id int, oldCipher, newCipher string) error {
+	if database.DB.SQL == nil {
+		return database.ErrDatabaseSupportDisabled
+	}
+
+	ctx := context.TODO()
+	var rows int64
+	var err error
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		rows, err = modelSQLite.UpdateAccountCipher(ctx, database.DB.SQL, id, oldCipher, newCipher)
+	} else {
+		rows, err = modelPSQL.UpdateAccountCipher(ctx, database.DB.SQL, id, oldCipher, newCipher)
+	}
+	if err != nil {
+		return fmt.Errorf("更新账户密文失败: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w: %d", errCipherChanged, id)
+	}
+	return nil
+}
+
+// Delete 按 ID 删除账户，账户私钥的密文会一同删除且无法恢复
+func Delete(id int) error {
+	if database.DB.SQL == nil {
+		return database.ErrDatabaseSupportDisabled
+	}
+
+	ctx := context.TODO()
+	var rows int64
+	var err error
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		rows, err = modelSQLite.DeleteAccount(ctx, database.DB.SQL, id)
+	} else {
+		rows, err = modelPSQL.DeleteAccount(ctx, database.DB.SQL, id)
+	}
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w: %d", ErrAccountNotFound, id)
+	}
+	return nil
+}
+
+// filterMods 返回筛选条件对应的查询条件
+func filterMods(f *Filter) []qm.QueryMod {
+	var mods []qm.QueryMod
+	if f.Owner != "" {
+		mods = append(mods, qm.Where("owner = ?", f.Owner))
+	}
+	if f.Layer != nil {
+		mods = append(mods, qm.Where("layer = ?", *f.Layer))
+	}
+	if f.ChainName != "" {
+		mods = append(mods, qm.Where("chain_name = ?", f.ChainName))
+	}
+	if f.Name != "" {
+		mods = append(mods, qm.Where("name = ?", f.Name))
+	}
+	if f.ParentID != 0 {
+		mods = append(mods, qm.Where("parent_id = ?", f.ParentID))
+	}
+	return mods
+}
+
+// pageMods 返回分页条件，dialect 决定仅有偏移量时如何取消数量限制
+func pageMods(f *Filter, dialect string) []qm.QueryMod {
+	var mods []qm.QueryMod
+	if f.Limit > 0 {
+		mods = append(mods, qm.Limit(f.Limit))
+	}
+	if f.Offset > 0 {
+		if f.Limit == 0 && dialect == database.DBSQLite3 {
+			// SQLite 只支持在 LIMIT 之后使用 OFFSET，-1 表示不限制数量；PostgreSQL 不接受负数 LIMIT
+			mods = append(mods, qm.Limit(-1))
+		}
+		mods = append(mods, qm.Offset(f.Offset))
+	}
+	return mods
+}
+
+func accountToSQLite(a *Account) *modelSQLite.Account {
+	return &modelSQLite.Account{
+		ID:                a.ID,
+		Name:              a.Name,
+		Address:           a.Address,
+		ExchangeAddressID: nullString(a.ExchangeAddressID),
+		ZkAddressID:       nullString(a.ZkAddressID),
+		F4AddressID:       nullString(a.F4AddressID),
+		OTAddressID:       nullString(a.OTAddressID),
+		Cipher:            nullString(a.Cipher),
+		Layer:             a.Layer,
+		Owner:             ownerString(a.Owner),
+		ChainName:         nullString(a.ChainName),
+		ParentID:          nullID(a.ParentID),
+		CreatedAt:         a.CreatedAt,
+	}
+}
+
+func accountToPostgres(a *Account) *modelPSQL.Account {
+	return &modelPSQL.Account{
+		ID:                a.ID,
+		Name:              a.Name,
+		Address:           a.Address,
+		ExchangeAddressID: nullString(a.ExchangeAddressID),
+		ZkAddressID:       nullString(a.ZkAddressID),
+		F4AddressID:       nullString(a.F4AddressID),
+		OTAddressID:       nullString(a.OTAddressID),
+		Cipher:            nullString(a.Cipher),
+		Layer:             a.Layer,
+		Owner:             ownerString(a.Owner),
+		ChainName:         nullString(a.ChainName),
+		ParentID:          nullID(a.ParentID),
+		CreatedAt:         a.CreatedAt,
+	}
+}
+
+func accountFromSQLite(a *modelSQLite.Account) *Account {
+	return &Account{
+		ID:                a.ID,
+		Name:              a.Name,
+		Address:           a.Address,
+		ExchangeAddressID: a.ExchangeAddressID.String,
+		ZkAddressID:       a.ZkAddressID.String,
+		F4AddressID:       a.F4AddressID.String,
+		OTAddressID:       a.OTAddressID.String,
+		Cipher:            a.Cipher.String,
+		Layer:             a.Layer,
+		Owner:             a.Owner.String,
+		ChainName:         a.ChainName.String,
+		ParentID:          int(a.ParentID.Int64),
+		CreatedAt:         a.CreatedAt,
+		UpdatedAt:         a.UpdatedAt,
+	}
+}
+
+func accountFromPostgres(a *modelPSQL.Account) *Account {
+	return &Account{
+		ID:                a.ID,
+		Name:              a.Name,
+		Address:           a.Address,
+		ExchangeAddressID: a.ExchangeAddressID.String,
+		ZkAddressID:       a.ZkAddressID.String,
+		F4AddressID:       a.F4AddressID.String,
+		OTAddressID:       a.OTAddressID.String,
+		Cipher:            a.Cipher.String,
+		Layer:             a.Layer,
+		Owner:             a.Owner.String,
+		ChainName:         a.ChainName.String,
+		ParentID:          int(a.ParentID.Int64),
+		CreatedAt:         a.CreatedAt,
+		UpdatedAt:         a.UpdatedAt,
+	}
+}
+
+func notFound(key interface{}, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %v", ErrAccountNotFound, key)
+	}
+	return err
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// ownerString 返回 owner 列的值。外部创建的旧表中 owner 可能为 NULL，读取时按空字符串处理；
+// 写入时始终写入字符串，以兼容 owner 为 NOT NULL 的表
+func ownerString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: true}
+}
 
-	return account, nil
+func nullID(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
 }
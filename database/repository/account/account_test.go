@@ -0,0 +1,246 @@
+package account
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"gocryptotrader/database"
+	"gocryptotrader/database/drivers"
+	modelPSQL "gocryptotrader/database/models/postgres"
+	modelSQLite "gocryptotrader/database/models/sqlite3"
+	"gocryptotrader/database/repository"
+	"gocryptotrader/database/testhelpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thrasher-corp/goose"
+	"github.com/thrasher-corp/sqlboiler/queries"
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	testhelpers.TempDir, err = os.MkdirTemp("", "gct-temp")
+	if err != nil {
+		fmt.Printf("failed to create temp file: %v", err)
+		os.Exit(1)
+	}
+
+	t := m.Run()
+
+	err = os.RemoveAll(testhelpers.TempDir)
+	if err != nil {
+		fmt.Printf("Failed to remove temp db file: %v", err)
+	}
+
+	os.Exit(t)
+}
+
+func TestAccountLifecycle(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config *database.Config
+	}{
+		{
+			name: "SQLite",
+			config: &database.Config{
+				Driver:            database.DBSQLite3,
+				ConnectionDetails: drivers.ConnectionDetails{Database: "account.db"},
+			},
+		},
+		{
+			name:   "Postgres",
+			config: testhelpers.GetConnectionDetails(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !testhelpers.CheckValidConfig(&tc.config.ConnectionDetails) {
+				t.Skip("database not configured skipping test")
+			}
+			dbConn, err := testhelpers.ConnectToDatabase(tc.config)
+			require.NoError(t, err, "ConnectToDatabase must not error")
+			defer func() {
+				assert.NoError(t, testhelpers.CloseDatabase(dbConn))
+			}()
+			// 清理上次运行留在数据库中的账户
+			_, err = database.DB.SQL.Exec("DELETE FROM accounts")
+			require.NoError(t, err, "clearing accounts must not error")
+			testAccountLifecycle(t)
+		})
+	}
+}
+
+func testAccountLifecycle(t *testing.T) {
+	t.Helper()
+	accounts := []*Account{
+		{Name: "root", Address: "7mXrxq4A6LJKsdHDCvj4PCdF3tvQs8rdUKFWLdEicxXW", Cipher: "c0", Layer: 0, Owner: "alice", ChainName: "solana"},
+		{Name: "hot", Address: "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM", Cipher: "c1", Layer: 1, Owner: "alice", ChainName: "solana"},
+		{Name: "hot", Address: "HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH", Cipher: "c2", Layer: 1, Owner: "alice", ChainName: "solana"},
+		{Name: "cold", Address: "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v", Layer: 1, Owner: "bob", ChainName: "eclipse"},
+	}
+	require.NoError(t, Insert(accounts), "Insert must not error")
+	for _, a := range accounts {
+		require.NotZero(t, a.ID, "Insert must assign an account ID")
+		assert.False(t, a.CreatedAt.IsZero(), "Insert should set the creation time")
+	}
+
+	got, err := GetByAddress(accounts[1].Address)
+	require.NoError(t, err, "GetByAddress must not error")
+	assert.Equal(t, accounts[1].ID, got.ID)
+	assert.Equal(t, "c1", got.Cipher)
+	assert.Equal(t, "alice", got.Owner)
+
+	got, err = GetByID(accounts[3].ID)
+	require.NoError(t, err, "GetByID must not error")
+	assert.Empty(t, got.Cipher, "a NULL cipher should be read as empty")
+	_, err = GetByID(-1)
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+	_, err = GetByAddress("missing")
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+
+	// 任一地址重复时整批回滚
+	err = Insert([]*Account{
+		{Name: "new", Address: "So11111111111111111111111111111111111111112", Owner: "bob"},
+		{Name: "dup", Address: accounts[0].Address, Owner: "bob"},
+	})
+	assert.ErrorIs(t, err, ErrDuplicateAddress)
+	_, err = GetByAddress("So11111111111111111111111111111111111111112")
+	assert.ErrorIs(t, err, ErrAccountNotFound, "a failed Insert must not write any account")
+
+	all, err := List(nil)
+	require.NoError(t, err, "List must not error")
+	assert.Len(t, all, 4)
+
+	layer := 1
+	filtered, err := List(&Filter{Owner: "alice", Layer: &layer, Name: "hot"})
+	require.NoError(t, err, "List must not error")
+	require.Len(t, filtered, 2)
+	assert.Equal(t, accounts[1].ID, filtered[0].ID, "List should order accounts by ID")
+
+	page, err := List(&Filter{Layer: &layer, Limit: 2, Offset: 1})
+	require.NoError(t, err, "List must not error")
+	require.Len(t, page, 2)
+	assert.Equal(t, accounts[2].ID, page[0].ID)
+	assert.Equal(t, accounts[3].ID, page[1].ID)
+
+	page, err = List(&Filter{Offset: 3})
+	require.NoError(t, err, "List must not error with an offset and no limit")
+	require.Len(t, page, 1)
+	assert.Equal(t, accounts[3].ID, page[0].ID)
+
+	_, err = List(&Filter{Limit: -1})
+	assert.ErrorIs(t, err, errInvalidPage)
+
+	total, err := Count(&Filter{Layer: &layer, Limit: 1})
+	require.NoError(t, err, "Count must not error")
+	assert.Equal(t, int64(3), total, "Count should ignore pagination")
+	total, err = Count(&Filter{ChainName: "eclipse"})
+	require.NoError(t, err, "Count must not error")
+	assert.Equal(t, int64(1), total)
+
+	update := *accounts[3]
+	update.Name = "treasury"
+	update.Layer = 2
+	update.Address = "changed"
+	update.Cipher = "changed"
+	require.NoError(t, Update(&update), "Update must not error")
+	got, err = GetByID(update.ID)
+	require.NoError(t, err, "GetByID must not error")
+	assert.Equal(t, "treasury", got.Name)
+	assert.Equal(t, 2, got.Layer)
+	assert.Equal(t, accounts[3].Address, got.Address, "Update must not change the address")
+	assert.Empty(t, got.Cipher, "Update must not change the cipher")
+	assert.ErrorIs(t, Update(&Account{ID: -1, Name: "x", Address: "x"}), ErrAccountNotFound)
+
+	child := *accounts[1]
+	child.ParentID = accounts[0].ID
+	require.NoError(t, Update(&child), "Update must not error")
+	children, err := List(&Filter{ParentID: accounts[0].ID})
+	require.NoError(t, err, "List must not error")
+	require.Len(t, children, 1)
+	assert.Equal(t, accounts[1].ID, children[0].ID)
+	assert.Equal(t, accounts[0].ID, children[0].ParentID)
+	got, err = GetByID(accounts[0].ID)
+	require.NoError(t, err, "GetByID must not error")
+	assert.Zero(t, got.ParentID, "a NULL parent should be read as 0")
+
+	require.NoError(t, UpdateCipher(accounts[0].ID, "c0", "c0-new"), "UpdateCipher must not error")
+	assert.ErrorIs(t, UpdateCipher(accounts[0].ID, "c0", "c0-other"), errCipherChanged, "UpdateCipher must not overwrite a changed cipher")
+	got, err = GetByID(accounts[0].ID)
+	require.NoError(t, err, "GetByID must not error")
+	assert.Equal(t, "c0-new", got.Cipher)
+
+	require.NoError(t, Delete(accounts[2].ID), "Delete must not error")
+	_, err = GetByID(accounts[2].ID)
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+	assert.ErrorIs(t, Delete(accounts[2].ID), ErrAccountNotFound)
+}
+
+func TestPageMods(t *testing.T) {
+	t.Parallel()
+	f := &Filter{Offset: 3}
+	query, _ := queries.BuildQuery(modelSQLite.Accounts(pageMods(f, database.DBSQLite3)...).Query)
+	assert.Contains(t, query, "LIMIT -1 OFFSET 3", "SQLite must use an unbounded limit before an offset")
+	query, _ = queries.BuildQuery(modelPSQL.Accounts(pageMods(f, database.DBPostgreSQL)...).Query)
+	assert.NotContains(t, query, "LIMIT", "PostgreSQL rejects a negative limit")
+	assert.Contains(t, query, "OFFSET 3")
+
+	query, _ = queries.BuildQuery(modelPSQL.Accounts(pageMods(&Filter{Limit: 2, Offset: 1}, database.DBPostgreSQL)...).Query)
+	assert.Contains(t, query, "LIMIT 2 OFFSET 1")
+}
+
+func TestAccountParentMigration(t *testing.T) {
+	dbConn, err := testhelpers.ConnectToDatabase(&database.Config{
+		Driver:            database.DBSQLite3,
+		ConnectionDetails: drivers.ConnectionDetails{Database: "account_migration.db"},
+	})
+	require.NoError(t, err, "ConnectToDatabase must not error")
+	defer func() {
+		assert.NoError(t, testhelpers.CloseDatabase(dbConn))
+	}()
+
+	accounts := []*Account{
+		{Name: "root", Address: "7mXrxq4A6LJKsdHDCvj4PCdF3tvQs8rdUKFWLdEicxXW", Cipher: "c0", Owner: "alice", ChainName: "solana"},
+		{Name: "child", Address: "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM", Layer: 1, Owner: "alice"},
+	}
+	require.NoError(t, Insert(accounts), "Insert must not error")
+	accounts[1].ParentID = accounts[0].ID
+	require.NoError(t, Update(accounts[1]), "Update must not error")
+
+	// 回滚到添加 parent_id 之前，账户数据和原有索引必须保留
+	dialect := repository.GetSQLDialect()
+	require.NoError(t, goose.Run("down-to", database.DB.SQL, dialect, testhelpers.MigrationDir, "20261017150000"), "migrating down must not error")
+	assert.False(t, hasColumn(t, "accounts", "parent_id"), "parent_id must be dropped")
+	var indexes int
+	require.NoError(t, database.DB.SQL.QueryRow("SELECT count(*) FROM sqlite_master WHERE type = 'index' AND name IN ('accounts_owner_layer_idx', 'accounts_chain_name_idx')").Scan(&indexes))
+	assert.Equal(t, 2, indexes, "the account indexes must be recreated")
+	var name, cipher string
+	require.NoError(t, database.DB.SQL.QueryRow("SELECT name, cipher FROM accounts WHERE id = ?", accounts[0].ID).Scan(&name, &cipher))
+	assert.Equal(t, "root", name)
+	assert.Equal(t, "c0", cipher, "rolling back must keep the account ciphers")
+
+	require.NoError(t, goose.Run("up", database.DB.SQL, dialect, testhelpers.MigrationDir), "migrating up must not error")
+	assert.True(t, hasColumn(t, "accounts", "parent_id"))
+	got, err := GetByID(accounts[1].ID)
+	require.NoError(t, err, "GetByID must not error")
+	assert.Equal(t, "child", got.Name)
+	assert.Zero(t, got.ParentID, "parent links are not restored by migrating up again")
+}
+
+// hasColumn 返回 SQLite 表中是否存在指定列
+func hasColumn(t *testing.T, table, column string) bool {
+	t.Helper()
+	rows, err := database.DB.SQL.Query("SELECT name FROM pragma_table_info(?)", table)
+	require.NoError(t, err, "reading table info must not error")
+	defer rows.Close()
+	var found bool
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		found = found || name == column
+	}
+	require.NoError(t, rows.Err())
+	return found
+}
@@ -0,0 +1,45 @@
+package account
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrAccountNotFound 未找到账户
+	ErrAccountNotFound = errors.New("账户不存在")
+	// ErrDuplicateAddress 账户地址已存在
+	ErrDuplicateAddress = errors.New("账户地址已存在")
+
+	errCipherChanged = errors.New("账户不存在或密文已被修改")
+	errInvalidPage   = errors.New("分页参数不能为负数")
+)
+
+// Account 表示 accounts 表中的一个账户
+type Account struct {
+	ID                int
+	Name              string
+	Address           string
+	ExchangeAddressID string
+	ZkAddressID       string
+	F4AddressID       string
+	OTAddressID       string
+	Cipher            string // 私钥或私钥分片的密文
+	Layer             int
+	Owner             string
+	ChainName         string
+	ParentID          int // 上级账户的 ID，为 0 时没有上级账户
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// Filter 筛选账户的条件，空字符串和 nil 表示不按该字段筛选
+type Filter struct {
+	Owner     string
+	Layer     *int
+	ChainName string
+	Name      string
+	ParentID  int // 只返回该账户的下级账户
+	Limit     int // 最多返回的账户数量，为 0 时不限制
+	Offset    int // 按 ID 排序后跳过的账户数量，与 Limit 一起分页
+}
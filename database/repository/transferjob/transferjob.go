@@ -0,0 +1,442 @@
+package transferjob
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"gocryptotrader/database"
+	modelPSQL "gocryptotrader/database/models/postgres"
+	modelSQLite "gocryptotrader/database/models/sqlite3"
+	"gocryptotrader/database/repository"
+
+	"github.com/gofrs/uuid"
+	"github.com/thrasher-corp/sqlboiler/queries/qm"
+)
+
+// Insert 在同一事务中保存任务及其全部接收者行，并回填任务 ID 与接收者行 ID
+func Insert(job *Job) error {
+	if database.DB.SQL == nil {
+		return database.ErrDatabaseSupportDisabled
+	}
+	if job.ID == "" {
+		id, err := uuid.NewV4()
+		if err != nil {
+			return err
+		}
+		job.ID = id.String()
+	}
+
+	ctx := context.TODO()
+	tx, err := database.DB.SQL.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		err = insertSQLite(ctx, tx, job)
+	} else {
+		err = insertPostgres(ctx, tx, job)
+	}
+	if err != nil {
+		if rErr := tx.Rollback(); rErr != nil {
+			return fmt.Errorf("%w, 回滚失败: %v", err, rErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetByID 返回任务及其全部接收者行，接收者按行号排序
+func GetByID(id string) (*Job, error) {
+	if database.DB.SQL == nil {
+		return nil, database.ErrDatabaseSupportDisabled
+	}
+
+	ctx := context.TODO()
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		j, err := modelSQLite.FindTransferJob(ctx, database.DB.SQL, id)
+		if err != nil {
+			return nil, notFound(id, err)
+		}
+		rows, err := modelSQLite.TransferJobRecipients(qm.Where("job_id = ?", id), qm.OrderBy("row_index")).All(ctx, database.DB.SQL)
+		if err != nil {
+			return nil, err
+		}
+		job := jobFromSQLite(j)
+		job.Recipients = make([]Recipient, len(rows))
+		for i := range rows {
+			job.Recipients[i] = recipientFromSQLite(rows[i])
+		}
+		return &job, nil
+	}
+
+	j, err := modelPSQL.FindTransferJob(ctx, database.DB.SQL, id)
+	if err != nil {
+		return nil, notFound(id, err)
+	}
+	rows, err := modelPSQL.TransferJobRecipients(qm.Where("job_id = ?", id), qm.OrderBy("row_index")).All(ctx, database.DB.SQL)
+	if err != nil {
+		return nil, err
+	}
+	job := jobFromPostgres(j)
+	job.Recipients = make([]Recipient, len(rows))
+	for i := range rows {
+		job.Recipients[i] = recipientFromPostgres(rows[i])
+	}
+	return &job, nil
+}
+
+// List 返回任务列表（不含接收者行），按创建时间倒序；status 为空时返回所有状态
+func List(status string, limit int) ([]Job, error) {
+	if database.DB.SQL == nil {
+		return nil, database.ErrDatabaseSupportDisabled
+	}
+
+	var mods []qm.QueryMod
+	if status != "" {
+		mods = append(mods, qm.Where("status = ?", status))
+	}
+	mods = append(mods, qm.OrderBy("created_at desc"))
+	if limit > 0 {
+		mods = append(mods, qm.Limit(limit))
+	}
+
+	ctx := context.TODO()
+	var jobs []Job
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		result, err := modelSQLite.TransferJobs(mods...).All(ctx, database.DB.SQL)
+		if err != nil {
+			return nil, err
+		}
+		for i := range result {
+			jobs = append(jobs, jobFromSQLite(result[i]))
+		}
+		return jobs, nil
+	}
+
+	result, err := modelPSQL.TransferJobs(mods...).All(ctx, database.DB.SQL)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result {
+		jobs = append(jobs, jobFromPostgres(result[i]))
+	}
+	return jobs, nil
+}
+
+// UpdateStatus 更新任务状态及错误信息
+func UpdateStatus(id, status, errMsg string) error {
+	if database.DB.SQL == nil {
+		return database.ErrDatabaseSupportDisabled
+	}
+
+	ctx := context.TODO()
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		j := &modelSQLite.TransferJob{ID: id, Status: status, Error: nullString(errMsg)}
+		return j.UpdateStatus(ctx, database.DB.SQL)
+	}
+	j := &modelPSQL.TransferJob{ID: id, Status: status, Error: nullString(errMsg)}
+	return j.UpdateStatus(ctx, database.DB.SQL)
+}
+
+// UpdateRecipients 在同一事务中更新接收者行的批次、签名、nonce 和状态
+func UpdateRecipients(recipients []Recipient) error {
+	if database.DB.SQL == nil {
+		return database.ErrDatabaseSupportDisabled
+	}
+
+	ctx := context.TODO()
+	tx, err := database.DB.SQL.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+
+	sqlite := repository.GetSQLDialect() == database.DBSQLite3
+	for i := range recipients {
+		r := &recipients[i]
+		if sqlite {
+			row := &modelSQLite.TransferJobRecipient{
+				ID:                   r.ID,
+				BatchIndex:           nullBatchIndex(r.BatchIndex),
+				Signature:            nullString(r.Signature),
+				LastValidBlockHeight: int64(r.LastValidBlockHeight),
+				NonceAccount:         r.NonceAccount,
+				Nonce:                r.Nonce,
+				Status:               r.Status,
+				Error:                nullString(r.Error),
+			}
+			err = row.UpdateProgress(ctx, tx)
+		} else {
+			row := &modelPSQL.TransferJobRecipient{
+				ID:                   r.ID,
+				BatchIndex:           nullBatchIndex(r.BatchIndex),
+				Signature:            nullString(r.Signature),
+				LastValidBlockHeight: int64(r.LastValidBlockHeight),
+				NonceAccount:         r.NonceAccount,
+				Nonce:                r.Nonce,
+				Status:               r.Status,
+				Error:                nullString(r.Error),
+			}
+			err = row.UpdateProgress(ctx, tx)
+		}
+		if err != nil {
+			if rErr := tx.Rollback(); rErr != nil {
+				return fmt.Errorf("%w, 回滚失败: %v", err, rErr)
+			}
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// FindTransfers 按交易签名或备注查找转账历史：signature 匹配接收者行的签名，
+// memo 匹配接收者行的备注或任务的备注引用，两者都设置时需同时满足；结果按任务和行号排序
+func FindTransfers(signature, memo string) ([]Transfer, error) {
+	if database.DB.SQL == nil {
+		return nil, database.ErrDatabaseSupportDisabled
+	}
+	if signature == "" && memo == "" {
+		return nil, errLookupUnset
+	}
+
+	var mods []qm.QueryMod
+	if signature != "" {
+		mods = append(mods, qm.Where("signature = ?", signature))
+	}
+	if memo != "" {
+		mods = append(mods, qm.Where("(memo = ? OR job_id IN (SELECT id FROM transfer_job WHERE reference = ?))", memo, memo))
+	}
+	mods = append(mods, qm.OrderBy("job_id, row_index"))
+
+	ctx := context.TODO()
+	var transfers []Transfer
+	jobs := make(map[string]Job)
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		rows, err := modelSQLite.TransferJobRecipients(mods...).All(ctx, database.DB.SQL)
+		if err != nil {
+			return nil, err
+		}
+		for i := range rows {
+			job, ok := jobs[rows[i].JobID]
+			if !ok {
+				j, err := modelSQLite.FindTransferJob(ctx, database.DB.SQL, rows[i].JobID)
+				if err != nil {
+					return nil, notFound(rows[i].JobID, err)
+				}
+				job = jobFromSQLite(j)
+				jobs[job.ID] = job
+			}
+			transfers = append(transfers, newTransfer(&job, recipientFromSQLite(rows[i])))
+		}
+		return transfers, nil
+	}
+
+	rows, err := modelPSQL.TransferJobRecipients(mods...).All(ctx, database.DB.SQL)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		job, ok := jobs[rows[i].JobID]
+		if !ok {
+			j, err := modelPSQL.FindTransferJob(ctx, database.DB.SQL, rows[i].JobID)
+			if err != nil {
+				return nil, notFound(rows[i].JobID, err)
+			}
+			job = jobFromPostgres(j)
+			jobs[job.ID] = job
+		}
+		transfers = append(transfers, newTransfer(&job, recipientFromPostgres(rows[i])))
+	}
+	return transfers, nil
+}
+
+// Source 返回发送接收者 r 的源账户，任务拆分到多个源账户时每个接收者记录各自的源账户
+func (j *Job) Source(r *Recipient) string {
+	if r.SourceAddress != "" {
+		return r.SourceAddress
+	}
+	return j.SourceAddress
+}
+
+func newTransfer(job *Job, r Recipient) Transfer {
+	return Transfer{
+		JobID:         job.ID,
+		Kind:          job.Kind,
+		SourceAddress: job.Source(&r),
+		TokenMint:     job.TokenMint,
+		Reference:     job.Reference,
+		Recipient:     r,
+	}
+}
+
+func insertSQLite(ctx context.Context, tx *sql.Tx, job *Job) error {
+	j := &modelSQLite.TransferJob{
+		ID:            job.ID,
+		Kind:          job.Kind,
+		SourceAddress: job.SourceAddress,
+		TokenMint:     nullString(job.TokenMint),
+		Status:        job.Status,
+		Error:         nullString(job.Error),
+		MemoMode:      job.MemoMode,
+		Reference:     job.Reference,
+	}
+	if err := j.Insert(ctx, tx); err != nil {
+		return err
+	}
+	job.CreatedAt, job.UpdatedAt = j.CreatedAt, j.UpdatedAt
+
+	for i := range job.Recipients {
+		r := &job.Recipients[i]
+		row := &modelSQLite.TransferJobRecipient{
+			JobID:         job.ID,
+			RowIndex:      r.RowIndex,
+			Address:       r.Address,
+			Amount:        r.Amount,
+			Memo:          r.Memo,
+			Label:         r.Label,
+			SourceAddress: r.SourceAddress,
+			BatchIndex:    nullBatchIndex(r.BatchIndex),
+			Signature:     nullString(r.Signature),
+			Status:        r.Status,
+			Error:         nullString(r.Error),
+		}
+		if err := row.Insert(ctx, tx); err != nil {
+			return err
+		}
+		r.ID, r.UpdatedAt = row.ID, row.UpdatedAt
+	}
+	return nil
+}
+
+func insertPostgres(ctx context.Context, tx *sql.Tx, job *Job) error {
+	j := &modelPSQL.TransferJob{
+		ID:            job.ID,
+		Kind:          job.Kind,
+		SourceAddress: job.SourceAddress,
+		TokenMint:     nullString(job.TokenMint),
+		Status:        job.Status,
+		Error:         nullString(job.Error),
+		MemoMode:      job.MemoMode,
+		Reference:     job.Reference,
+	}
+	if err := j.Insert(ctx, tx); err != nil {
+		return err
+	}
+	job.CreatedAt, job.UpdatedAt = j.CreatedAt, j.UpdatedAt
+
+	for i := range job.Recipients {
+		r := &job.Recipients[i]
+		row := &modelPSQL.TransferJobRecipient{
+			JobID:         job.ID,
+			RowIndex:      r.RowIndex,
+			Address:       r.Address,
+			Amount:        r.Amount,
+			Memo:          r.Memo,
+			Label:         r.Label,
+			SourceAddress: r.SourceAddress,
+			BatchIndex:    nullBatchIndex(r.BatchIndex),
+			Signature:     nullString(r.Signature),
+			Status:        r.Status,
+			Error:         nullString(r.Error),
+		}
+		if err := row.Insert(ctx, tx); err != nil {
+			return err
+		}
+		r.ID, r.UpdatedAt = row.ID, row.UpdatedAt
+	}
+	return nil
+}
+
+func jobFromSQLite(j *modelSQLite.TransferJob) Job {
+	return Job{
+		ID:            j.ID,
+		Kind:          j.Kind,
+		SourceAddress: j.SourceAddress,
+		TokenMint:     j.TokenMint.String,
+		Status:        j.Status,
+		Error:         j.Error.String,
+		MemoMode:      j.MemoMode,
+		Reference:     j.Reference,
+		CreatedAt:     j.CreatedAt,
+		UpdatedAt:     j.UpdatedAt,
+	}
+}
+
+func jobFromPostgres(j *modelPSQL.TransferJob) Job {
+	return Job{
+		ID:            j.ID,
+		Kind:          j.Kind,
+		SourceAddress: j.SourceAddress,
+		TokenMint:     j.TokenMint.String,
+		Status:        j.Status,
+		Error:         j.Error.String,
+		MemoMode:      j.MemoMode,
+		Reference:     j.Reference,
+		CreatedAt:     j.CreatedAt,
+		UpdatedAt:     j.UpdatedAt,
+	}
+}
+
+func recipientFromSQLite(r *modelSQLite.TransferJobRecipient) Recipient {
+	return Recipient{
+		ID:                   r.ID,
+		RowIndex:             r.RowIndex,
+		Address:              r.Address,
+		Amount:               r.Amount,
+		Memo:                 r.Memo,
+		Label:                r.Label,
+		SourceAddress:        r.SourceAddress,
+		BatchIndex:           batchIndex(r.BatchIndex),
+		Signature:            r.Signature.String,
+		LastValidBlockHeight: uint64(r.LastValidBlockHeight),
+		NonceAccount:         r.NonceAccount,
+		Nonce:                r.Nonce,
+		Status:               r.Status,
+		Error:                r.Error.String,
+		UpdatedAt:            r.UpdatedAt,
+	}
+}
+
+func recipientFromPostgres(r *modelPSQL.TransferJobRecipient) Recipient {
+	return Recipient{
+		ID:                   r.ID,
+		RowIndex:             r.RowIndex,
+		Address:              r.Address,
+		Amount:               r.Amount,
+		Memo:                 r.Memo,
+		Label:                r.Label,
+		SourceAddress:        r.SourceAddress,
+		BatchIndex:           batchIndex(r.BatchIndex),
+		Signature:            r.Signature.String,
+		LastValidBlockHeight: uint64(r.LastValidBlockHeight),
+		NonceAccount:         r.NonceAccount,
+		Nonce:                r.Nonce,
+		Status:               r.Status,
+		Error:                r.Error.String,
+		UpdatedAt:            r.UpdatedAt,
+	}
+}
+
+func notFound(id string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	return err
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullBatchIndex(i int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(i), Valid: i >= 0}
+}
+
+func batchIndex(i sql.NullInt64) int {
+	if !i.Valid {
+		return -1
+	}
+	return int(i.Int64)
+}
@@ -0,0 +1,182 @@
+package transferjob
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"gocryptotrader/database"
+	"gocryptotrader/database/drivers"
+	"gocryptotrader/database/repository"
+	"gocryptotrader/database/testhelpers"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thrasher-corp/goose"
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	testhelpers.TempDir, err = os.MkdirTemp("", "gct-temp")
+	if err != nil {
+		fmt.Printf("failed to create temp file: %v", err)
+		os.Exit(1)
+	}
+
+	t := m.Run()
+
+	err = os.RemoveAll(testhelpers.TempDir)
+	if err != nil {
+		fmt.Printf("Failed to remove temp db file: %v", err)
+	}
+
+	os.Exit(t)
+}
+
+func TestTransferJobLifecycle(t *testing.T) {
+	dbConn, err := testhelpers.ConnectToDatabase(&database.Config{
+		Driver:            database.DBSQLite3,
+		ConnectionDetails: drivers.ConnectionDetails{Database: "transferjob.db"},
+	})
+	require.NoError(t, err, "ConnectToDatabase must not error")
+	defer func() {
+		assert.NoError(t, testhelpers.CloseDatabase(dbConn))
+	}()
+
+	job := &Job{
+		Kind:          "sol",
+		SourceAddress: "7mXrxq4A6LJKsdHDCvj4PCdF3tvQs8rdUKFWLdEicxXW",
+		Status:        "running",
+		Recipients: []Recipient{
+			{RowIndex: 0, Address: "9W8kkYe6wE9VWJRiwqQMwC3536mMei7KfG7oWiSNbNBb", Amount: decimal.RequireFromString("12345678901.123456789"), Memo: "m", BatchIndex: -1, Status: "pending"},
+			{RowIndex: 1, Address: "7mXrxq4A6LJKsdHDCvj4PCdF3tvQs8rdUKFWLdEicxXW", Amount: decimal.NewFromInt(2), SourceAddress: "9W8kkYe6wE9VWJRiwqQMwC3536mMei7KfG7oWiSNbNBb", BatchIndex: -1, Status: "pending"},
+		},
+	}
+	require.NoError(t, Insert(job), "Insert must not error")
+	require.NotEmpty(t, job.ID, "Insert must assign a job ID")
+	require.NotZero(t, job.Recipients[1].ID, "Insert must assign recipient IDs")
+
+	job.Recipients[0].BatchIndex = 0
+	job.Recipients[0].Signature = "sig"
+	job.Recipients[0].LastValidBlockHeight = 1234
+	job.Recipients[0].NonceAccount = "nonce-account"
+	job.Recipients[0].Nonce = "nonce-value"
+	job.Recipients[0].Status = "sent"
+	require.NoError(t, UpdateRecipients(job.Recipients[:1]), "UpdateRecipients must not error")
+	require.NoError(t, UpdateStatus(job.ID, "interrupted", "boom"), "UpdateStatus must not error")
+
+	got, err := GetByID(job.ID)
+	require.NoError(t, err, "GetByID must not error")
+	assert.Equal(t, "interrupted", got.Status)
+	assert.Equal(t, "boom", got.Error)
+	require.Len(t, got.Recipients, 2)
+	assert.Equal(t, 0, got.Recipients[0].BatchIndex)
+	assert.Equal(t, "sig", got.Recipients[0].Signature)
+	assert.Equal(t, uint64(1234), got.Recipients[0].LastValidBlockHeight)
+	assert.Equal(t, "nonce-account", got.Recipients[0].NonceAccount)
+	assert.Equal(t, "nonce-value", got.Recipients[0].Nonce)
+	assert.Empty(t, got.Recipients[1].Nonce)
+	assert.Equal(t, "m", got.Recipients[0].Memo)
+	assert.Equal(t, "12345678901.123456789", got.Recipients[0].Amount.String(), "amounts must be stored without floating point rounding")
+	assert.True(t, decimal.NewFromInt(2).Equal(got.Recipients[1].Amount))
+	assert.Equal(t, -1, got.Recipients[1].BatchIndex)
+	assert.Equal(t, job.SourceAddress, got.Source(&got.Recipients[0]), "recipients without a source must use the job source")
+	assert.Equal(t, "9W8kkYe6wE9VWJRiwqQMwC3536mMei7KfG7oWiSNbNBb", got.Source(&got.Recipients[1]))
+
+	jobs, err := List("interrupted", 10)
+	require.NoError(t, err, "List must not error")
+	require.Len(t, jobs, 1)
+	assert.Equal(t, job.ID, jobs[0].ID)
+
+	jobs, err = List("completed", 10)
+	require.NoError(t, err, "List must not error")
+	assert.Empty(t, jobs)
+
+	_, err = GetByID("missing")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestFindTransfers(t *testing.T) {
+	dbConn, err := testhelpers.ConnectToDatabase(&database.Config{
+		Driver:            database.DBSQLite3,
+		ConnectionDetails: drivers.ConnectionDetails{Database: "findtransfers.db"},
+	})
+	require.NoError(t, err, "ConnectToDatabase must not error")
+	defer func() {
+		assert.NoError(t, testhelpers.CloseDatabase(dbConn))
+	}()
+
+	_, err = FindTransfers("", "")
+	assert.ErrorIs(t, err, errLookupUnset)
+
+	job := &Job{
+		Kind:          "token",
+		SourceAddress: "7mXrxq4A6LJKsdHDCvj4PCdF3tvQs8rdUKFWLdEicxXW",
+		TokenMint:     "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+		Status:        "running",
+		MemoMode:      "recipient",
+		Reference:     "payroll-2026-10",
+		Recipients: []Recipient{
+			{RowIndex: 0, Address: "9W8kkYe6wE9VWJRiwqQMwC3536mMei7KfG7oWiSNbNBb", Amount: decimal.NewFromInt(1), Memo: "invoice-1", BatchIndex: 0, Signature: "sig-a", Status: "sent"},
+			{RowIndex: 1, Address: "7mXrxq4A6LJKsdHDCvj4PCdF3tvQs8rdUKFWLdEicxXW", Amount: decimal.NewFromInt(2), Memo: "invoice-2", BatchIndex: 1, Signature: "sig-b", Status: "sent"},
+		},
+	}
+	require.NoError(t, Insert(job), "Insert must not error")
+
+	got, err := GetByID(job.ID)
+	require.NoError(t, err, "GetByID must not error")
+	assert.Equal(t, "recipient", got.MemoMode)
+	assert.Equal(t, "payroll-2026-10", got.Reference)
+
+	transfers, err := FindTransfers("sig-b", "")
+	require.NoError(t, err, "FindTransfers must not error")
+	require.Len(t, transfers, 1)
+	assert.Equal(t, job.ID, transfers[0].JobID)
+	assert.Equal(t, job.TokenMint, transfers[0].TokenMint)
+	assert.Equal(t, "invoice-2", transfers[0].Memo)
+
+	transfers, err = FindTransfers("", "invoice-1")
+	require.NoError(t, err, "FindTransfers must not error")
+	require.Len(t, transfers, 1)
+	assert.Equal(t, "sig-a", transfers[0].Signature)
+
+	transfers, err = FindTransfers("", "payroll-2026-10")
+	require.NoError(t, err, "FindTransfers must not error")
+	assert.Len(t, transfers, 2, "reference must match every row of the job")
+
+	transfers, err = FindTransfers("sig-a", "invoice-2")
+	require.NoError(t, err, "FindTransfers must not error")
+	assert.Empty(t, transfers, "both filters must match")
+}
+
+func TestAmountMigration(t *testing.T) {
+	dbConn, err := testhelpers.ConnectToDatabase(&database.Config{
+		Driver:            database.DBSQLite3,
+		ConnectionDetails: drivers.ConnectionDetails{Database: "amountmigration.db"},
+	})
+	require.NoError(t, err, "ConnectToDatabase must not error")
+	defer func() {
+		assert.NoError(t, testhelpers.CloseDatabase(dbConn))
+	}()
+
+	// 迁移前的数量保存在浮点列中
+	dialect := repository.GetSQLDialect()
+	require.NoError(t, goose.Run("down-to", database.DB.SQL, dialect, testhelpers.MigrationDir, "20261017180000"), "migrating down must not error")
+	_, err = database.DB.SQL.Exec("INSERT INTO transfer_job (id, kind, source_address, status) VALUES ('legacy', 'sol', '7mXrxq4A6LJKsdHDCvj4PCdF3tvQs8rdUKFWLdEicxXW', 'completed')")
+	require.NoError(t, err)
+	_, err = database.DB.SQL.Exec("INSERT INTO transfer_job_recipient (job_id, row_index, address, amount, status) VALUES ('legacy', 0, '9W8kkYe6wE9VWJRiwqQMwC3536mMei7KfG7oWiSNbNBb', 1.25, 'confirmed')")
+	require.NoError(t, err)
+
+	require.NoError(t, goose.Run("up", database.DB.SQL, dialect, testhelpers.MigrationDir), "migrating up must not error")
+	got, err := GetByID("legacy")
+	require.NoError(t, err, "GetByID must not error")
+	require.Len(t, got.Recipients, 1)
+	assert.Equal(t, "1.25", got.Recipients[0].Amount.String(), "existing amounts must be kept")
+
+	require.NoError(t, goose.Run("down-to", database.DB.SQL, dialect, testhelpers.MigrationDir, "20261017180000"), "migrating down must not error")
+	var amount float64
+	require.NoError(t, database.DB.SQL.QueryRow("SELECT amount FROM transfer_job_recipient WHERE job_id = 'legacy'").Scan(&amount))
+	assert.Equal(t, 1.25, amount, "rolling back must restore the floating point amounts")
+	require.NoError(t, goose.Run("up", database.DB.SQL, dialect, testhelpers.MigrationDir), "migrating up must not error")
+}
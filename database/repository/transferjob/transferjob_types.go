@@ -0,0 +1,59 @@
+package transferjob
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrJobNotFound 未找到转账任务
+	ErrJobNotFound = errors.New("转账任务不存在")
+
+	errLookupUnset = errors.New("未设置交易签名或备注")
+)
+
+// Job 表示一个持久化的批量转账任务
+type Job struct {
+	ID            string
+	Kind          string // sol 或 token
+	SourceAddress string
+	TokenMint     string
+	Status        string
+	Error         string
+	MemoMode      string // 附加 SPL Memo 的方式，为空时不附加
+	Reference     string // 备注引用，恢复任务时沿用
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Recipients    []Recipient
+}
+
+// Recipient 表示任务中的一个接收者行及其转账进度
+type Recipient struct {
+	ID                   int64
+	RowIndex             int
+	Address              string
+	Amount               decimal.Decimal // 转账数量（SOL 或代币），按十进制精确保存，发送时才换算为最小单位
+	Memo                 string
+	Label                string
+	SourceAddress        string // 发送该接收者的源账户，为空时使用任务的源账户
+	BatchIndex           int    // 所在批次序号，尚未分配批次时为 -1
+	Signature            string
+	LastValidBlockHeight uint64
+	NonceAccount         string // 使用 durable nonce 签名时的 nonce 账户
+	Nonce                string // 签名时使用的 nonce 值，nonce 推进后交易不会再上链
+	Status               string
+	Error                string
+	UpdatedAt            time.Time
+}
+
+// Transfer 表示转账历史中的一个接收者行及其所属任务，用于按交易签名或备注互相查找
+type Transfer struct {
+	JobID         string
+	Kind          string
+	SourceAddress string
+	TokenMint     string
+	Reference     string
+	Recipient
+}
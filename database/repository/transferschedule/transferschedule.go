@@ -0,0 +1,382 @@
+package transferschedule
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"gocryptotrader/database"
+	modelPSQL "gocryptotrader/database/models/postgres"
+	modelSQLite "gocryptotrader/database/models/sqlite3"
+	"gocryptotrader/database/repository"
+
+	"github.com/gofrs/uuid"
+	"github.com/thrasher-corp/sqlboiler/boil"
+	"github.com/thrasher-corp/sqlboiler/queries/qm"
+)
+
+// Insert 在同一事务中保存计划及其全部接收者，并回填计划 ID 与接收者行 ID
+func Insert(s *Schedule) error {
+	if database.DB.SQL == nil {
+		return database.ErrDatabaseSupportDisabled
+	}
+	if s.ID == "" {
+		id, err := uuid.NewV4()
+		if err != nil {
+			return err
+		}
+		s.ID = id.String()
+	}
+
+	return withTx(func(ctx context.Context, tx *sql.Tx) error {
+		if repository.GetSQLDialect() == database.DBSQLite3 {
+			row := scheduleToSQLite(s)
+			if err := row.Insert(ctx, tx); err != nil {
+				return err
+			}
+			s.CreatedAt, s.UpdatedAt = row.CreatedAt, row.UpdatedAt
+		} else {
+			row := scheduleToPostgres(s)
+			if err := row.Insert(ctx, tx); err != nil {
+				return err
+			}
+			s.CreatedAt, s.UpdatedAt = row.CreatedAt, row.UpdatedAt
+		}
+		return insertRecipients(ctx, tx, s)
+	})
+}
+
+// Update 保存计划定义并替换全部接收者；执行记录字段不受影响
+func Update(s *Schedule) error {
+	if database.DB.SQL == nil {
+		return database.ErrDatabaseSupportDisabled
+	}
+
+	return withTx(func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		if repository.GetSQLDialect() == database.DBSQLite3 {
+			row := scheduleToSQLite(s)
+			if err = row.Update(ctx, tx); err != nil {
+				return err
+			}
+			s.UpdatedAt = row.UpdatedAt
+			err = modelSQLite.DeleteTransferScheduleRecipients(ctx, tx, s.ID)
+		} else {
+			row := scheduleToPostgres(s)
+			if err = row.Update(ctx, tx); err != nil {
+				return err
+			}
+			s.UpdatedAt = row.UpdatedAt
+			err = modelPSQL.DeleteTransferScheduleRecipients(ctx, tx, s.ID)
+		}
+		if err != nil {
+			return err
+		}
+		return insertRecipients(ctx, tx, s)
+	})
+}
+
+// UpdateRun 记录一次执行：执行时间、下次执行时间、生成的转账任务 ID 及错误信息
+func UpdateRun(id string, lastRunAt, nextRunAt time.Time, jobID, errMsg string) error {
+	if database.DB.SQL == nil {
+		return database.ErrDatabaseSupportDisabled
+	}
+
+	ctx := context.TODO()
+	lastRun := sql.NullTime{Time: lastRunAt.UTC(), Valid: !lastRunAt.IsZero()}
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		row := &modelSQLite.TransferSchedule{
+			ID:        id,
+			LastRunAt: lastRun,
+			NextRunAt: nextRunAt.UTC(),
+			LastJobID: nullString(jobID),
+			LastError: nullString(errMsg),
+		}
+		return row.UpdateRun(ctx, database.DB.SQL)
+	}
+	row := &modelPSQL.TransferSchedule{
+		ID:        id,
+		LastRunAt: lastRun,
+		NextRunAt: nextRunAt.UTC(),
+		LastJobID: nullString(jobID),
+		LastError: nullString(errMsg),
+	}
+	return row.UpdateRun(ctx, database.DB.SQL)
+}
+
+// GetByID 返回计划及其全部接收者，接收者按行号排序
+func GetByID(id string) (*Schedule, error) {
+	if database.DB.SQL == nil {
+		return nil, database.ErrDatabaseSupportDisabled
+	}
+
+	ctx := context.TODO()
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		s, err := modelSQLite.FindTransferSchedule(ctx, database.DB.SQL, id)
+		if err != nil {
+			return nil, notFound(id, err)
+		}
+		rows, err := modelSQLite.TransferScheduleRecipients(qm.Where("schedule_id = ?", id), qm.OrderBy("row_index")).All(ctx, database.DB.SQL)
+		if err != nil {
+			return nil, err
+		}
+		schedule := scheduleFromSQLite(s)
+		schedule.Recipients = make([]Recipient, len(rows))
+		for i := range rows {
+			schedule.Recipients[i] = Recipient{
+				ID:       rows[i].ID,
+				RowIndex: rows[i].RowIndex,
+				Address:  rows[i].Address,
+				Amount:   rows[i].Amount,
+				Memo:     rows[i].Memo,
+				Label:    rows[i].Label,
+			}
+		}
+		return &schedule, nil
+	}
+
+	s, err := modelPSQL.FindTransferSchedule(ctx, database.DB.SQL, id)
+	if err != nil {
+		return nil, notFound(id, err)
+	}
+	rows, err := modelPSQL.TransferScheduleRecipients(qm.Where("schedule_id = ?", id), qm.OrderBy("row_index")).All(ctx, database.DB.SQL)
+	if err != nil {
+		return nil, err
+	}
+	schedule := scheduleFromPostgres(s)
+	schedule.Recipients = make([]Recipient, len(rows))
+	for i := range rows {
+		schedule.Recipients[i] = Recipient{
+			ID:       rows[i].ID,
+			RowIndex: rows[i].RowIndex,
+			Address:  rows[i].Address,
+			Amount:   rows[i].Amount,
+			Memo:     rows[i].Memo,
+			Label:    rows[i].Label,
+		}
+	}
+	return &schedule, nil
+}
+
+// List 返回全部计划（不含接收者），按创建时间排序
+func List() ([]Schedule, error) {
+	if database.DB.SQL == nil {
+		return nil, database.ErrDatabaseSupportDisabled
+	}
+
+	ctx := context.TODO()
+	var schedules []Schedule
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		result, err := modelSQLite.TransferSchedules(qm.OrderBy("created_at")).All(ctx, database.DB.SQL)
+		if err != nil {
+			return nil, err
+		}
+		for i := range result {
+			schedules = append(schedules, scheduleFromSQLite(result[i]))
+		}
+		return schedules, nil
+	}
+
+	result, err := modelPSQL.TransferSchedules(qm.OrderBy("created_at")).All(ctx, database.DB.SQL)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result {
+		schedules = append(schedules, scheduleFromPostgres(result[i]))
+	}
+	return schedules, nil
+}
+
+// Delete 删除计划及其全部接收者，已生成的转账任务不受影响
+func Delete(id string) error {
+	if database.DB.SQL == nil {
+		return database.ErrDatabaseSupportDisabled
+	}
+
+	return withTx(func(ctx context.Context, tx *sql.Tx) error {
+		var deleted int64
+		var err error
+		if repository.GetSQLDialect() == database.DBSQLite3 {
+			if err = modelSQLite.DeleteTransferScheduleRecipients(ctx, tx, id); err != nil {
+				return err
+			}
+			deleted, err = (&modelSQLite.TransferSchedule{ID: id}).Delete(ctx, tx)
+		} else {
+			if err = modelPSQL.DeleteTransferScheduleRecipients(ctx, tx, id); err != nil {
+				return err
+			}
+			deleted, err = (&modelPSQL.TransferSchedule{ID: id}).Delete(ctx, tx)
+		}
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return fmt.Errorf("%w: %s", ErrScheduleNotFound, id)
+		}
+		return nil
+	})
+}
+
+// withTx 在事务中执行 fn，fn 返回错误时回滚
+func withTx(fn func(context.Context, *sql.Tx) error) error {
+	ctx := context.TODO()
+	tx, err := database.DB.SQL.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+	if err = fn(ctx, tx); err != nil {
+		if rErr := tx.Rollback(); rErr != nil {
+			return fmt.Errorf("%w, 回滚失败: %v", err, rErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertRecipients(ctx context.Context, exec boil.ContextExecutor, s *Schedule) error {
+	sqlite := repository.GetSQLDialect() == database.DBSQLite3
+	for i := range s.Recipients {
+		r := &s.Recipients[i]
+		r.RowIndex = i
+		if sqlite {
+			row := &modelSQLite.TransferScheduleRecipient{
+				ScheduleID: s.ID,
+				RowIndex:   r.RowIndex,
+				Address:    r.Address,
+				Amount:     r.Amount,
+				Memo:       r.Memo,
+				Label:      r.Label,
+			}
+			if err := row.Insert(ctx, exec); err != nil {
+				return err
+			}
+			r.ID = row.ID
+			continue
+		}
+		row := &modelPSQL.TransferScheduleRecipient{
+			ScheduleID: s.ID,
+			RowIndex:   r.RowIndex,
+			Address:    r.Address,
+			Amount:     r.Amount,
+			Memo:       r.Memo,
+			Label:      r.Label,
+		}
+		if err := row.Insert(ctx, exec); err != nil {
+			return err
+		}
+		r.ID = row.ID
+	}
+	return nil
+}
+
+func scheduleToSQLite(s *Schedule) *modelSQLite.TransferSchedule {
+	return &modelSQLite.TransferSchedule{
+		ID:              s.ID,
+		Name:            s.Name,
+		Kind:            s.Kind,
+		SourceAddress:   s.SourceAddress,
+		TokenMint:       nullString(s.TokenMint),
+		CronExpression:  nullString(s.Cron),
+		IntervalSeconds: int64(s.Interval / time.Second),
+		Amount:          s.Amount,
+		CatchUp:         s.CatchUp,
+		PartialPay:      s.PartialPay,
+		UseLookupTables: s.UseLookupTables,
+		Duplicates:      s.Duplicates,
+		Paused:          s.Paused,
+		NextRunAt:       s.NextRunAt.UTC(),
+		LastRunAt:       sql.NullTime{Time: s.LastRunAt.UTC(), Valid: !s.LastRunAt.IsZero()},
+		LastJobID:       nullString(s.LastJobID),
+		LastError:       nullString(s.LastError),
+	}
+}
+
+func scheduleToPostgres(s *Schedule) *modelPSQL.TransferSchedule {
+	return &modelPSQL.TransferSchedule{
+		ID:              s.ID,
+		Name:            s.Name,
+		Kind:            s.Kind,
+		SourceAddress:   s.SourceAddress,
+		TokenMint:       nullString(s.TokenMint),
+		CronExpression:  nullString(s.Cron),
+		IntervalSeconds: int64(s.Interval / time.Second),
+		Amount:          s.Amount,
+		CatchUp:         s.CatchUp,
+		PartialPay:      s.PartialPay,
+		UseLookupTables: s.UseLookupTables,
+		Duplicates:      s.Duplicates,
+		Paused:          s.Paused,
+		NextRunAt:       s.NextRunAt.UTC(),
+		LastRunAt:       sql.NullTime{Time: s.LastRunAt.UTC(), Valid: !s.LastRunAt.IsZero()},
+		LastJobID:       nullString(s.LastJobID),
+		LastError:       nullString(s.LastError),
+	}
+}
+
+func scheduleFromSQLite(s *modelSQLite.TransferSchedule) Schedule {
+	return Schedule{
+		ID:              s.ID,
+		Name:            s.Name,
+		Kind:            s.Kind,
+		SourceAddress:   s.SourceAddress,
+		TokenMint:       s.TokenMint.String,
+		Cron:            s.CronExpression.String,
+		Interval:        time.Duration(s.IntervalSeconds) * time.Second,
+		Amount:          s.Amount,
+		CatchUp:         s.CatchUp,
+		PartialPay:      s.PartialPay,
+		UseLookupTables: s.UseLookupTables,
+		Duplicates:      s.Duplicates,
+		Paused:          s.Paused,
+		NextRunAt:       s.NextRunAt.UTC(),
+		LastRunAt:       nullTime(s.LastRunAt),
+		LastJobID:       s.LastJobID.String,
+		LastError:       s.LastError.String,
+		CreatedAt:       s.CreatedAt,
+		UpdatedAt:       s.UpdatedAt,
+	}
+}
+
+func scheduleFromPostgres(s *modelPSQL.TransferSchedule) Schedule {
+	return Schedule{
+		ID:              s.ID,
+		Name:            s.Name,
+		Kind:            s.Kind,
+		SourceAddress:   s.SourceAddress,
+		TokenMint:       s.TokenMint.String,
+		Cron:            s.CronExpression.String,
+		Interval:        time.Duration(s.IntervalSeconds) * time.Second,
+		Amount:          s.Amount,
+		CatchUp:         s.CatchUp,
+		PartialPay:      s.PartialPay,
+		UseLookupTables: s.UseLookupTables,
+		Duplicates:      s.Duplicates,
+		Paused:          s.Paused,
+		NextRunAt:       s.NextRunAt.UTC(),
+		LastRunAt:       nullTime(s.LastRunAt),
+		LastJobID:       s.LastJobID.String,
+		LastError:       s.LastError.String,
+		CreatedAt:       s.CreatedAt,
+		UpdatedAt:       s.UpdatedAt,
+	}
+}
+
+func notFound(id string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", ErrScheduleNotFound, id)
+	}
+	return err
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullTime(t sql.NullTime) time.Time {
+	if !t.Valid {
+		return time.Time{}
+	}
+	return t.Time.UTC()
+}
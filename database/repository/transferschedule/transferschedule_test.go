@@ -0,0 +1,105 @@
+package transferschedule
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"gocryptotrader/database"
+	"gocryptotrader/database/drivers"
+	"gocryptotrader/database/testhelpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	testhelpers.TempDir, err = os.MkdirTemp("", "gct-temp")
+	if err != nil {
+		fmt.Printf("failed to create temp file: %v", err)
+		os.Exit(1)
+	}
+
+	t := m.Run()
+
+	err = os.RemoveAll(testhelpers.TempDir)
+	if err != nil {
+		fmt.Printf("Failed to remove temp db file: %v", err)
+	}
+
+	os.Exit(t)
+}
+
+func TestTransferScheduleLifecycle(t *testing.T) {
+	dbConn, err := testhelpers.ConnectToDatabase(&database.Config{
+		Driver:            database.DBSQLite3,
+		ConnectionDetails: drivers.ConnectionDetails{Database: "transferschedule.db"},
+	})
+	require.NoError(t, err, "ConnectToDatabase must not error")
+	defer func() {
+		assert.NoError(t, testhelpers.CloseDatabase(dbConn))
+	}()
+
+	next := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	s := &Schedule{
+		Name:          "weekly contributors",
+		Kind:          "sol",
+		SourceAddress: "7mXrxq4A6LJKsdHDCvj4PCdF3tvQs8rdUKFWLdEicxXW",
+		Cron:          "0 9 * * 1",
+		Amount:        0.5,
+		CatchUp:       "once",
+		Duplicates:    "sum",
+		NextRunAt:     next,
+		Recipients: []Recipient{
+			{Address: "9W8kkYe6wE9VWJRiwqQMwC3536mMei7KfG7oWiSNbNBb", Memo: "m"},
+			{Address: "7mXrxq4A6LJKsdHDCvj4PCdF3tvQs8rdUKFWLdEicxXW", Amount: 2},
+		},
+	}
+	require.NoError(t, Insert(s), "Insert must not error")
+	require.NotEmpty(t, s.ID, "Insert must assign a schedule ID")
+	require.NotZero(t, s.Recipients[1].ID, "Insert must assign recipient IDs")
+
+	got, err := GetByID(s.ID)
+	require.NoError(t, err, "GetByID must not error")
+	assert.Equal(t, "0 9 * * 1", got.Cron)
+	assert.Equal(t, next, got.NextRunAt)
+	assert.Equal(t, "sum", got.Duplicates)
+	assert.True(t, got.LastRunAt.IsZero(), "LastRunAt should be zero before the first run")
+	require.Len(t, got.Recipients, 2)
+	assert.Equal(t, "m", got.Recipients[0].Memo)
+	assert.Equal(t, 2.0, got.Recipients[1].Amount)
+
+	ran := next.Add(time.Second)
+	require.NoError(t, UpdateRun(s.ID, ran, next.AddDate(0, 0, 7), "job", "boom"), "UpdateRun must not error")
+
+	s.Cron = ""
+	s.Interval = time.Hour
+	s.Paused = true
+	s.Duplicates = ""
+	s.Recipients = s.Recipients[1:]
+	require.NoError(t, Update(s), "Update must not error")
+
+	got, err = GetByID(s.ID)
+	require.NoError(t, err, "GetByID must not error")
+	assert.Empty(t, got.Cron)
+	assert.Equal(t, time.Hour, got.Interval)
+	assert.True(t, got.Paused)
+	assert.Empty(t, got.Duplicates)
+	assert.Equal(t, ran, got.LastRunAt, "Update must not overwrite the run record")
+	assert.Equal(t, "job", got.LastJobID)
+	assert.Equal(t, "boom", got.LastError)
+	require.Len(t, got.Recipients, 1, "Update must replace the recipients")
+	assert.Equal(t, 0, got.Recipients[0].RowIndex)
+
+	schedules, err := List()
+	require.NoError(t, err, "List must not error")
+	require.Len(t, schedules, 1)
+	assert.Equal(t, s.ID, schedules[0].ID)
+
+	require.NoError(t, Delete(s.ID), "Delete must not error")
+	_, err = GetByID(s.ID)
+	assert.ErrorIs(t, err, ErrScheduleNotFound)
+	assert.ErrorIs(t, Delete(s.ID), ErrScheduleNotFound)
+}
@@ -0,0 +1,43 @@
+package transferschedule
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrScheduleNotFound 未找到定时转账计划
+var ErrScheduleNotFound = errors.New("定时转账计划不存在")
+
+// Schedule 表示一个持久化的定时或周期性转账计划
+type Schedule struct {
+	ID              string
+	Name            string
+	Kind            string // sol 或 token
+	SourceAddress   string
+	TokenMint       string
+	Cron            string        // cron 表达式，与 Interval 二选一
+	Interval        time.Duration // 固定间隔，与 Cron 二选一
+	Amount          float64       // 未单独指定数量的接收者使用的数量
+	CatchUp         string        // 错过执行时间后的补偿规则
+	PartialPay      bool
+	UseLookupTables bool
+	Duplicates      string // 重复地址的处理方式，每次执行前按此重新校验接收者
+	Paused          bool
+	NextRunAt       time.Time
+	LastRunAt       time.Time // 尚未执行过时为零值
+	LastJobID       string
+	LastError       string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Recipients      []Recipient
+}
+
+// Recipient 表示计划中的一个接收者，Amount 为 0 时使用计划的 Amount
+type Recipient struct {
+	ID       int64
+	RowIndex int
+	Address  string
+	Amount   float64
+	Memo     string
+	Label    string
+}
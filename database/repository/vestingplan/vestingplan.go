@@ -0,0 +1,339 @@
+package vestingplan
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"gocryptotrader/database"
+	modelPSQL "gocryptotrader/database/models/postgres"
+	modelSQLite "gocryptotrader/database/models/sqlite3"
+	"gocryptotrader/database/repository"
+
+	"github.com/gofrs/uuid"
+	"github.com/thrasher-corp/sqlboiler/queries/qm"
+)
+
+// InsertPlan 保存新的归属计划并回填计划 ID
+func InsertPlan(p *Plan) error {
+	if database.DB.SQL == nil {
+		return database.ErrDatabaseSupportDisabled
+	}
+	if p.ID == "" {
+		id, err := uuid.NewV4()
+		if err != nil {
+			return err
+		}
+		p.ID = id.String()
+	}
+	if p.Status == "" {
+		p.Status = StatusActive
+	}
+
+	ctx := context.TODO()
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		row := &modelSQLite.VestingPlan{
+			ID:             p.ID,
+			AccountID:      p.AccountID,
+			SourceAddress:  p.SourceAddress,
+			TokenMint:      p.TokenMint,
+			Decimals:       int(p.Decimals),
+			TotalAmount:    int64(p.TotalAmount),
+			ReleasedAmount: int64(p.ReleasedAmount),
+			StartAt:        p.StartAt.UTC(),
+			CliffSeconds:   int64(p.Cliff / time.Second),
+			PeriodSeconds:  int64(p.Period / time.Second),
+			Periods:        p.Periods,
+			Status:         p.Status,
+		}
+		if err := row.Insert(ctx, database.DB.SQL); err != nil {
+			return err
+		}
+		p.CreatedAt, p.UpdatedAt = row.CreatedAt, row.UpdatedAt
+		return nil
+	}
+	row := &modelPSQL.VestingPlan{
+		ID:             p.ID,
+		AccountID:      p.AccountID,
+		SourceAddress:  p.SourceAddress,
+		TokenMint:      p.TokenMint,
+		Decimals:       int(p.Decimals),
+		TotalAmount:    int64(p.TotalAmount),
+		ReleasedAmount: int64(p.ReleasedAmount),
+		StartAt:        p.StartAt.UTC(),
+		CliffSeconds:   int64(p.Cliff / time.Second),
+		PeriodSeconds:  int64(p.Period / time.Second),
+		Periods:        p.Periods,
+		Status:         p.Status,
+	}
+	if err := row.Insert(ctx, database.DB.SQL); err != nil {
+		return err
+	}
+	p.CreatedAt, p.UpdatedAt = row.CreatedAt, row.UpdatedAt
+	return nil
+}
+
+// GetPlan 返回计划及其全部释放记录，释放记录按创建顺序排序
+func GetPlan(id string) (*Plan, error) {
+	if database.DB.SQL == nil {
+		return nil, database.ErrDatabaseSupportDisabled
+	}
+
+	ctx := context.TODO()
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		p, err := modelSQLite.FindVestingPlan(ctx, database.DB.SQL, id)
+		if err != nil {
+			return nil, notFound(id, err)
+		}
+		rows, err := modelSQLite.VestingReleases(qm.Where("plan_id = ?", id), qm.OrderBy("id")).All(ctx, database.DB.SQL)
+		if err != nil {
+			return nil, err
+		}
+		plan := planFromSQLite(p)
+		plan.Releases = make([]Release, len(rows))
+		for i := range rows {
+			plan.Releases[i] = releaseFromSQLite(rows[i])
+			plan.Releases[i].Decimals = plan.Decimals
+		}
+		return &plan, nil
+	}
+
+	p, err := modelPSQL.FindVestingPlan(ctx, database.DB.SQL, id)
+	if err != nil {
+		return nil, notFound(id, err)
+	}
+	rows, err := modelPSQL.VestingReleases(qm.Where("plan_id = ?", id), qm.OrderBy("id")).All(ctx, database.DB.SQL)
+	if err != nil {
+		return nil, err
+	}
+	plan := planFromPostgres(p)
+	plan.Releases = make([]Release, len(rows))
+	for i := range rows {
+		plan.Releases[i] = releaseFromPostgres(rows[i])
+		plan.Releases[i].Decimals = plan.Decimals
+	}
+	return &plan, nil
+}
+
+// ListPlans 返回计划列表（不含释放记录），按创建时间排序；
+// accountID 为 0 或 status 为空时不按该条件过滤
+func ListPlans(accountID int, status string) ([]Plan, error) {
+	if database.DB.SQL == nil {
+		return nil, database.ErrDatabaseSupportDisabled
+	}
+
+	mods := []qm.QueryMod{qm.OrderBy("created_at")}
+	if accountID != 0 {
+		mods = append(mods, qm.Where("account_id = ?", accountID))
+	}
+	if status != "" {
+		mods = append(mods, qm.Where("status = ?", status))
+	}
+
+	ctx := context.TODO()
+	var plans []Plan
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		result, err := modelSQLite.VestingPlans(mods...).All(ctx, database.DB.SQL)
+		if err != nil {
+			return nil, err
+		}
+		for i := range result {
+			plans = append(plans, planFromSQLite(result[i]))
+		}
+		return plans, nil
+	}
+
+	result, err := modelPSQL.VestingPlans(mods...).All(ctx, database.DB.SQL)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result {
+		plans = append(plans, planFromPostgres(result[i]))
+	}
+	return plans, nil
+}
+
+// InsertRelease 保存新的释放记录并回填记录 ID
+func InsertRelease(r *Release) error {
+	if database.DB.SQL == nil {
+		return database.ErrDatabaseSupportDisabled
+	}
+
+	ctx := context.TODO()
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		row := releaseToSQLite(r)
+		if err := row.Insert(ctx, database.DB.SQL); err != nil {
+			return err
+		}
+		r.ID, r.CreatedAt, r.UpdatedAt = row.ID, row.CreatedAt, row.UpdatedAt
+		return nil
+	}
+	row := releaseToPostgres(r)
+	if err := row.Insert(ctx, database.DB.SQL); err != nil {
+		return err
+	}
+	r.ID, r.CreatedAt, r.UpdatedAt = row.ID, row.CreatedAt, row.UpdatedAt
+	return nil
+}
+
+// UpdateRelease 保存释放记录的数量、签名与状态，不影响计划的已释放数量
+func UpdateRelease(r *Release) error {
+	if database.DB.SQL == nil {
+		return database.ErrDatabaseSupportDisabled
+	}
+
+	ctx := context.TODO()
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		row := releaseToSQLite(r)
+		if err := row.Update(ctx, database.DB.SQL); err != nil {
+			return err
+		}
+		r.UpdatedAt = row.UpdatedAt
+		return nil
+	}
+	row := releaseToPostgres(r)
+	if err := row.Update(ctx, database.DB.SQL); err != nil {
+		return err
+	}
+	r.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+// SettleRelease 在同一事务中保存已确认的释放记录，并将计划的已释放数量与状态
+// 更新为 released 与 status，保证两者不会出现不一致
+func SettleRelease(r *Release, released uint64, status string) error {
+	if database.DB.SQL == nil {
+		return database.ErrDatabaseSupportDisabled
+	}
+
+	ctx := context.TODO()
+	tx, err := database.DB.SQL.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+	if repository.GetSQLDialect() == database.DBSQLite3 {
+		row := releaseToSQLite(r)
+		err = row.Update(ctx, tx)
+		if err == nil {
+			r.UpdatedAt = row.UpdatedAt
+			err = (&modelSQLite.VestingPlan{ID: r.PlanID, ReleasedAmount: int64(released), Status: status}).UpdateProgress(ctx, tx)
+		}
+	} else {
+		row := releaseToPostgres(r)
+		err = row.Update(ctx, tx)
+		if err == nil {
+			r.UpdatedAt = row.UpdatedAt
+			err = (&modelPSQL.VestingPlan{ID: r.PlanID, ReleasedAmount: int64(released), Status: status}).UpdateProgress(ctx, tx)
+		}
+	}
+	if err != nil {
+		if rErr := tx.Rollback(); rErr != nil {
+			return fmt.Errorf("%w, 回滚失败: %v", err, rErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func releaseToSQLite(r *Release) *modelSQLite.VestingRelease {
+	return &modelSQLite.VestingRelease{
+		ID:                   r.ID,
+		PlanID:               r.PlanID,
+		Amount:               int64(r.Amount),
+		Signature:            nullString(r.Signature),
+		LastValidBlockHeight: int64(r.LastValidBlockHeight),
+		Status:               r.Status,
+		Error:                nullString(r.Error),
+	}
+}
+
+func releaseToPostgres(r *Release) *modelPSQL.VestingRelease {
+	return &modelPSQL.VestingRelease{
+		ID:                   r.ID,
+		PlanID:               r.PlanID,
+		Amount:               int64(r.Amount),
+		Signature:            nullString(r.Signature),
+		LastValidBlockHeight: int64(r.LastValidBlockHeight),
+		Status:               r.Status,
+		Error:                nullString(r.Error),
+	}
+}
+
+func releaseFromSQLite(r *modelSQLite.VestingRelease) Release {
+	return Release{
+		ID:                   r.ID,
+		PlanID:               r.PlanID,
+		Amount:               uint64(r.Amount),
+		Signature:            r.Signature.String,
+		LastValidBlockHeight: uint64(r.LastValidBlockHeight),
+		Status:               r.Status,
+		Error:                r.Error.String,
+		CreatedAt:            r.CreatedAt,
+		UpdatedAt:            r.UpdatedAt,
+	}
+}
+
+func releaseFromPostgres(r *modelPSQL.VestingRelease) Release {
+	return Release{
+		ID:                   r.ID,
+		PlanID:               r.PlanID,
+		Amount:               uint64(r.Amount),
+		Signature:            r.Signature.String,
+		LastValidBlockHeight: uint64(r.LastValidBlockHeight),
+		Status:               r.Status,
+		Error:                r.Error.String,
+		CreatedAt:            r.CreatedAt,
+		UpdatedAt:            r.UpdatedAt,
+	}
+}
+
+func planFromSQLite(p *modelSQLite.VestingPlan) Plan {
+	return Plan{
+		ID:             p.ID,
+		AccountID:      p.AccountID,
+		SourceAddress:  p.SourceAddress,
+		TokenMint:      p.TokenMint,
+		Decimals:       uint8(p.Decimals),
+		TotalAmount:    uint64(p.TotalAmount),
+		ReleasedAmount: uint64(p.ReleasedAmount),
+		StartAt:        p.StartAt.UTC(),
+		Cliff:          time.Duration(p.CliffSeconds) * time.Second,
+		Period:         time.Duration(p.PeriodSeconds) * time.Second,
+		Periods:        p.Periods,
+		Status:         p.Status,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}
+}
+
+func planFromPostgres(p *modelPSQL.VestingPlan) Plan {
+	return Plan{
+		ID:             p.ID,
+		AccountID:      p.AccountID,
+		SourceAddress:  p.SourceAddress,
+		TokenMint:      p.TokenMint,
+		Decimals:       uint8(p.Decimals),
+		TotalAmount:    uint64(p.TotalAmount),
+		ReleasedAmount: uint64(p.ReleasedAmount),
+		StartAt:        p.StartAt.UTC(),
+		Cliff:          time.Duration(p.CliffSeconds) * time.Second,
+		Period:         time.Duration(p.PeriodSeconds) * time.Second,
+		Periods:        p.Periods,
+		Status:         p.Status,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}
+}
+
+func notFound(id string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", ErrPlanNotFound, id)
+	}
+	return err
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
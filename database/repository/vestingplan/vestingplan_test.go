@@ -0,0 +1,103 @@
+package vestingplan
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"gocryptotrader/database"
+	"gocryptotrader/database/drivers"
+	"gocryptotrader/database/testhelpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	testhelpers.TempDir, err = os.MkdirTemp("", "gct-temp")
+	if err != nil {
+		fmt.Printf("failed to create temp file: %v", err)
+		os.Exit(1)
+	}
+
+	t := m.Run()
+
+	err = os.RemoveAll(testhelpers.TempDir)
+	if err != nil {
+		fmt.Printf("Failed to remove temp db file: %v", err)
+	}
+
+	os.Exit(t)
+}
+
+func TestVestingPlanLifecycle(t *testing.T) {
+	dbConn, err := testhelpers.ConnectToDatabase(&database.Config{
+		Driver:            database.DBSQLite3,
+		ConnectionDetails: drivers.ConnectionDetails{Database: "vestingplan.db"},
+	})
+	require.NoError(t, err, "ConnectToDatabase must not error")
+	defer func() {
+		assert.NoError(t, testhelpers.CloseDatabase(dbConn))
+	}()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := &Plan{
+		AccountID:     3,
+		SourceAddress: "7mXrxq4A6LJKsdHDCvj4PCdF3tvQs8rdUKFWLdEicxXW",
+		TokenMint:     "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+		Decimals:      9,
+		TotalAmount:   1200000000000000001,
+		StartAt:       start,
+		Cliff:         90 * 24 * time.Hour,
+		Period:        30 * 24 * time.Hour,
+		Periods:       12,
+	}
+	require.NoError(t, InsertPlan(p), "InsertPlan must not error")
+	require.NotEmpty(t, p.ID, "InsertPlan must assign a plan ID")
+	assert.Equal(t, StatusActive, p.Status, "InsertPlan should default the status to active")
+
+	r := &Release{PlanID: p.ID, Amount: 300000000000000001, Status: "pending"}
+	require.NoError(t, InsertRelease(r), "InsertRelease must not error")
+	require.NotZero(t, r.ID, "InsertRelease must assign a release ID")
+
+	r.Signature = "sig"
+	r.LastValidBlockHeight = 42
+	r.Status = "sent"
+	require.NoError(t, UpdateRelease(r), "UpdateRelease must not error")
+
+	got, err := GetPlan(p.ID)
+	require.NoError(t, err, "GetPlan must not error")
+	assert.Equal(t, start, got.StartAt)
+	assert.Equal(t, 90*24*time.Hour, got.Cliff)
+	assert.Equal(t, 12, got.Periods)
+	assert.Equal(t, uint8(9), got.Decimals)
+	assert.Equal(t, uint64(1200000000000000001), got.TotalAmount, "base units must be stored exactly")
+	assert.Zero(t, got.ReleasedAmount, "UpdateRelease must not change the released amount")
+	require.Len(t, got.Releases, 1)
+	assert.Equal(t, "sig", got.Releases[0].Signature)
+	assert.Equal(t, uint64(42), got.Releases[0].LastValidBlockHeight)
+	assert.Equal(t, uint64(300000000000000001), got.Releases[0].Amount)
+	assert.Equal(t, uint8(9), got.Releases[0].Decimals, "releases should carry the plan decimals")
+
+	r.Status = "confirmed"
+	require.NoError(t, SettleRelease(r, 300000000000000001, StatusActive), "SettleRelease must not error")
+
+	got, err = GetPlan(p.ID)
+	require.NoError(t, err, "GetPlan must not error")
+	assert.Equal(t, uint64(300000000000000001), got.ReleasedAmount)
+	assert.Equal(t, "confirmed", got.Releases[0].Status)
+
+	plans, err := ListPlans(3, StatusActive)
+	require.NoError(t, err, "ListPlans must not error")
+	require.Len(t, plans, 1)
+	assert.Equal(t, p.ID, plans[0].ID)
+
+	plans, err = ListPlans(4, "")
+	require.NoError(t, err, "ListPlans must not error")
+	assert.Empty(t, plans, "ListPlans should filter by account")
+
+	_, err = GetPlan("missing")
+	assert.ErrorIs(t, err, ErrPlanNotFound)
+}
@@ -0,0 +1,49 @@
+package vestingplan
+
+import (
+	"errors"
+	"time"
+)
+
+// 归属计划状态
+const (
+	StatusActive    = "active"    // 仍有未释放的数量
+	StatusCompleted = "completed" // 全部数量已释放
+)
+
+// ErrPlanNotFound 未找到归属计划
+var ErrPlanNotFound = errors.New("归属计划不存在")
+
+// Plan 表示一个持久化的归属计划：受益账户在悬崖期后按周期线性获得 TotalAmount，
+// 数量均以代币最小单位保存，按 Decimals 换算为代币数量
+type Plan struct {
+	ID             string
+	AccountID      int // accounts 表中受益账户的 ID
+	SourceAddress  string
+	TokenMint      string
+	Decimals       uint8  // 代币精度
+	TotalAmount    uint64 // 归属总量（最小单位）
+	ReleasedAmount uint64 // 已确认转出的数量（最小单位）
+	StartAt        time.Time
+	Cliff          time.Duration // 悬崖期，结束前不释放任何数量
+	Period         time.Duration // 每个释放周期的长度
+	Periods        int           // 周期数，最后一个周期结束时全部归属
+	Status         string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Releases       []Release
+}
+
+// Release 表示计划的一次释放转账及其进度，状态取值与转账批次状态一致
+type Release struct {
+	ID                   int64
+	PlanID               string
+	Amount               uint64 // 释放数量（最小单位）
+	Decimals             uint8  // 所属计划的代币精度，不单独保存
+	Signature            string
+	LastValidBlockHeight uint64
+	Status               string
+	Error                string
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
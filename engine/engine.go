@@ -3,11 +3,15 @@ package engine
 import (
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"sync"
 
 	"gocryptotrader/config"
 	"gocryptotrader/database"
+	"gocryptotrader/exchanges/forward"
+	"gocryptotrader/exchanges/rpcpool"
+	"gocryptotrader/exchanges/signer"
 	gctlog "gocryptotrader/log"
 	"gocryptotrader/utils"
 )
@@ -15,10 +19,14 @@ import (
 // Engine contains configuration, portfolio manager, exchange & ticker data and is the
 // overarching type across this code base.
 type Engine struct {
-	Config          *config.Config
-	DatabaseManager *DatabaseConnectionManager
-	Settings        Settings
-	ServicesWG      sync.WaitGroup
+	Config            *config.Config
+	DatabaseManager   *DatabaseConnectionManager
+	SolanaRPC         *rpcpool.Pool
+	Signer            forward.Signer
+	TransferJobs      *TransferJobManager
+	TransferSchedules *TransferScheduleManager
+	Settings          Settings
+	ServicesWG        sync.WaitGroup
 }
 
 // Bot is a happy global engine to allow various areas of the application
@@ -221,6 +229,43 @@ func (bot *Engine) Start() error {
 		}
 	}
 
+	if p, err := rpcpool.New(&bot.Config.Solana); err != nil {
+		gctlog.Errorf(gctlog.Global, "Solana RPC pool unable to setup: %v", err)
+	} else {
+		bot.SolanaRPC = p
+		if err := bot.SolanaRPC.Start(); err != nil {
+			gctlog.Errorf(gctlog.Global, "Solana RPC pool unable to start: %v", err)
+		}
+	}
+
+	if s, err := signer.New(bot.Config); err != nil {
+		gctlog.Errorf(gctlog.Global, "Transaction signer unable to setup: %v", err)
+	} else {
+		bot.Signer = s
+	}
+
+	if bot.DatabaseManager.IsConnected() {
+		if t, err := SetupTransferJobManager(bot.Config, bot.DatabaseManager, bot.SolanaRPC, bot.Signer); err != nil {
+			gctlog.Errorf(gctlog.Global, "Transfer job manager unable to setup: %v", err)
+		} else {
+			bot.TransferJobs = t
+			if err := bot.TransferJobs.Start(); err != nil {
+				gctlog.Errorf(gctlog.Global, "Transfer job manager unable to start: %v", err)
+			}
+		}
+	}
+
+	if bot.Settings.EnableTransferScheduleManager && bot.TransferJobs.IsRunning() {
+		if t, err := SetupTransferScheduleManager(bot.TransferJobs, bot.Settings.TransferScheduleManagerDelay); err != nil {
+			gctlog.Errorf(gctlog.Global, "Transfer schedule manager unable to setup: %v", err)
+		} else {
+			bot.TransferSchedules = t
+			if err := bot.TransferSchedules.Start(&bot.ServicesWG); err != nil {
+				gctlog.Errorf(gctlog.Global, "Transfer schedule manager unable to start: %v", err)
+			}
+		}
+	}
+
 	if bot.Settings.EnableGRPC {
 		go StartRPCServer(bot)
 	}
@@ -236,6 +281,26 @@ func (bot *Engine) Stop() {
 	gctlog.Debugln(gctlog.Global, "Engine shutting down..")
 
 	// 在这里可以添加必要的清理代码
+	if bot.TransferSchedules.IsRunning() {
+		if err := bot.TransferSchedules.Stop(); err != nil {
+			gctlog.Errorf(gctlog.Global, "Transfer schedule manager unable to stop. Error: %v", err)
+		}
+	}
+	if bot.TransferJobs.IsRunning() {
+		if err := bot.TransferJobs.Stop(); err != nil {
+			gctlog.Errorf(gctlog.Global, "Transfer job manager unable to stop. Error: %v", err)
+		}
+	}
+	if bot.SolanaRPC.IsRunning() {
+		if err := bot.SolanaRPC.Stop(); err != nil {
+			gctlog.Errorf(gctlog.Global, "Solana RPC pool unable to stop. Error: %v", err)
+		}
+	}
+	if c, ok := bot.Signer.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			gctlog.Errorf(gctlog.Global, "Transaction signer unable to close. Error: %v", err)
+		}
+	}
 	if bot.DatabaseManager.IsRunning() {
 		if err := bot.DatabaseManager.Stop(); err != nil {
 			gctlog.Errorf(gctlog.Global, "Database manager unable to stop. Error: %v", err)
@@ -5,9 +5,14 @@ import (
 	errors "errors"
 	"fmt"
 	"gocryptotrader/common/crypto"
+	"gocryptotrader/database/repository/transferjob"
+	"gocryptotrader/database/repository/transferschedule"
+	"gocryptotrader/database/repository/vestingplan"
 	"gocryptotrader/exchanges/forward"
 	"gocryptotrader/exchanges/request"
+	"gocryptotrader/exchanges/rpcpool"
 	"gocryptotrader/exchanges/token"
+	"gocryptotrader/exchanges/vesting"
 	"gocryptotrader/log"
 	net "net"
 	http "net/http"
@@ -48,6 +53,27 @@ var (
 	errGRPCShutdownSignalIsNil = errors.New("cannot shutdown, gRPC shutdown channel is nil")
 	errInvalidStrategy         = errors.New("invalid strategy")
 	errSpecificPairNotEnabled  = errors.New("specified pair is not enabled")
+
+	errRecipientsSourceConflict = errors.New("recipients_file and recipients cannot both be set")
+	errTransferJobIDUnset       = errors.New("transfer job id unset")
+	errSweepDestinationUnset    = errors.New("sweep destination unset")
+	errNoSweepSources           = errors.New("no accounts match the sweep filter")
+	errNoReclaimSources         = errors.New("no accounts match the reclaim filter")
+	errFanOutRequiresJobs       = errors.New("splitting a transfer across sources requires the transfer job manager and cannot be combined with dry_run or sign_only")
+	errTreasuryWithoutSources   = errors.New("treasury is only used to top up the sources of a split transfer")
+	errNoNonceAccounts          = errors.New("no initialised nonce accounts for address")
+	errInvalidNonceCount        = errors.New("nonce account count must be positive")
+	errNoSignedTransactions     = errors.New("no signed transactions to submit")
+	errTransferScheduleUnset    = errors.New("transfer schedule unset")
+	errTransferScheduleIDUnset  = errors.New("transfer schedule id unset")
+	errVestingPlanUnset         = errors.New("vesting plan unset")
+	errVestingPlanIDUnset       = errors.New("vesting plan id unset")
+	errTransferLookupUnset      = errors.New("signature or memo must be set")
+	errInvalidWrapAmount        = errors.New("wrap amount must be positive")
+	errAccountTreeOwnerUnset    = errors.New("account tree owner unset")
+	errInvalidFundAmount        = errors.New("fund amount must be positive")
+	errNoChildAccounts          = errors.New("account has no child accounts")
+	errNoLeafAccounts           = errors.New("account tree has no leaf accounts")
 )
 
 // RPCServer struct
@@ -191,35 +217,219 @@ func (s *RPCServer) authClient(handler http.Handler) http.Handler {
 	})
 }
 
-// GetAccounts 获取所有账户信息
-func (s *RPCServer) GetAccounts(ctx context.Context, req *gctrpc.GetAccountsRequest) (*gctrpc.GetAccountsResponse, error) {
+// GetAccounts 获取符合筛选条件的账户，可按所有者、层级、所属链和名称筛选并分页，total 为不分页时的账户总数
+func (s *RPCServer) GetAccounts(_ context.Context, req *gctrpc.GetAccountsRequest) (*gctrpc.GetAccountsResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
 
+	filter := &account.Filter{
+		Owner:     req.Owner,
+		ChainName: req.ChainName,
+		Name:      req.Name,
+		ParentID:  int(req.ParentId),
+		Limit:     int(req.Limit),
+		Offset:    int(req.Offset),
+	}
+	if req.Layer != nil {
+		layer := int(*req.Layer)
+		filter.Layer = &layer
+	}
 	accountManager := account.New(s.Config)
-
-	accounts, err := accountManager.Accounts()
+	accounts, err := accountManager.AccountsByFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	total, err := accountManager.CountAccounts(filter)
 	if err != nil {
 		return nil, err
 	}
 
-	response := &gctrpc.GetAccountsResponse{}
+	response := &gctrpc.GetAccountsResponse{Total: total}
 	for _, acc := range accounts {
-		response.Accounts = append(response.Accounts, &gctrpc.Account{
-			Name:              acc.Name,
-			Address:           acc.Address,
-			ExchangeAddressId: acc.ExchangeAddressID,
-			ZkAddressId:       acc.ZkAddressID,
-			F4AddressId:       acc.F4AddressID,
-			OtAddressId:       acc.OTAddressID,
-			Cipher:            acc.Cipher,
-			Layer:             int32(acc.Layer),
-			Owner:             acc.Owner,
-			ChainName:         acc.ChainName,
-		})
+		response.Accounts = append(response.Accounts, accountToRPC(acc))
 	}
 
 	return response, nil
 }
 
+// CreateAccounts 随机生成或从助记词派生新账户，私钥加密后写入 accounts 表
+func (s *RPCServer) CreateAccounts(_ context.Context, req *gctrpc.CreateAccountsRequest) (*gctrpc.CreateAccountsResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+
+	created, err := account.New(s.Config).CreateAccounts(&account.CreateRequest{
+		Name:       req.Name,
+		Owner:      req.Owner,
+		Layer:      int(req.Layer),
+		ChainName:  req.ChainName,
+		Count:      int(req.Count),
+		Mnemonic:   req.Mnemonic,
+		Passphrase: req.Passphrase,
+		StartIndex: req.StartIndex,
+		ParentID:   int(req.ParentId),
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &gctrpc.CreateAccountsResponse{Accounts: make([]*gctrpc.CreatedAccount, len(created))}
+	for i := range created {
+		resp.Accounts[i] = createdAccountToRPC(created[i])
+	}
+	return resp, nil
+}
+
+// ImportAccount 导入已有的私钥，私钥加密后写入 accounts 表
+func (s *RPCServer) ImportAccount(_ context.Context, req *gctrpc.ImportAccountRequest) (*gctrpc.CreatedAccount, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+
+	imported, err := account.New(s.Config).ImportAccount(&account.ImportRequest{
+		Name:       req.Name,
+		Owner:      req.Owner,
+		Layer:      int(req.Layer),
+		ChainName:  req.ChainName,
+		PrivateKey: req.PrivateKey,
+		ParentID:   int(req.ParentId),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return createdAccountToRPC(imported), nil
+}
+
+// GenerateMnemonic 生成用于派生账户的 BIP39 助记词，助记词不会被保存
+func (s *RPCServer) GenerateMnemonic(_ context.Context, _ *gctrpc.GenerateMnemonicRequest) (*gctrpc.GenerateMnemonicResponse, error) {
+	mnemonic, err := account.NewMnemonic()
+	if err != nil {
+		return nil, err
+	}
+	return &gctrpc.GenerateMnemonicResponse{Mnemonic: mnemonic}, nil
+}
+
+// GetAccountTree 返回一个所有者按上下级关系组成的账户树，并列出上下级关联无效的账户
+func (s *RPCServer) GetAccountTree(_ context.Context, req *gctrpc.GetAccountTreeRequest) (*gctrpc.GetAccountTreeResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Owner == "" {
+		return nil, errAccountTreeOwnerUnset
+	}
+
+	tree, err := account.New(s.Config).Tree(req.Owner)
+	if err != nil {
+		return nil, err
+	}
+	resp := &gctrpc.GetAccountTreeResponse{
+		Owner:   tree.Owner,
+		Roots:   make([]*gctrpc.AccountNode, len(tree.Roots)),
+		Invalid: invalidLinksToRPC(tree.Invalid),
+	}
+	for i, root := range tree.Roots {
+		resp.Roots[i] = accountNodeToRPC(root)
+	}
+	return resp, nil
+}
+
+// SetAccountParent 设置账户的上级账户，parent_id 为 0 时解除关联
+func (s *RPCServer) SetAccountParent(_ context.Context, req *gctrpc.SetAccountParentRequest) (*gctrpc.Account, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+
+	a, err := account.New(s.Config).SetParent(int(req.AccountId), int(req.ParentId))
+	if err != nil {
+		return nil, err
+	}
+	resp := accountToRPC(a)
+	resp.Cipher = ""
+	return resp, nil
+}
+
+// FundChildren 从账户向其全部直接下级账户各转入 amount SOL，按普通 SOL 转账执行
+func (s *RPCServer) FundChildren(ctx context.Context, req *gctrpc.FundChildrenRequest) (*gctrpc.TransferSOLResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Amount <= 0 {
+		return nil, errInvalidFundAmount
+	}
+
+	accountManager := account.New(s.Config)
+	parent, err := accountManager.GetAccountByAddress(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	children, err := accountManager.Children(parent.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(children) == 0 {
+		return nil, fmt.Errorf("%w: %s", errNoChildAccounts, parent.Address)
+	}
+
+	recipients := make([]*gctrpc.TransferRecipient, len(children))
+	for i, child := range children {
+		recipients[i] = &gctrpc.TransferRecipient{Address: child.Address, Amount: req.Amount, Label: child.Name}
+	}
+	return s.transferSOL(ctx, &gctrpc.TransferSOLRequest{
+		Address:         parent.Address,
+		Recipients:      recipients,
+		DryRun:          req.DryRun,
+		PartialPay:      req.PartialPay,
+		UseLookupTables: req.UseLookupTables,
+		MemoMode:        req.MemoMode,
+		Reference:       req.Reference,
+	}, nil)
+}
+
+// accountNodeToRPC 转换账户树中的节点及其下级账户，私钥密文不返回给客户端
+func accountNodeToRPC(n *account.Node) *gctrpc.AccountNode {
+	a := accountToRPC(n.Account)
+	a.Cipher = ""
+	node := &gctrpc.AccountNode{Account: a, Children: make([]*gctrpc.AccountNode, len(n.Children))}
+	for i, child := range n.Children {
+		node.Children[i] = accountNodeToRPC(child)
+	}
+	return node
+}
+
+// invalidLinksToRPC 转换上下级关联无效的账户
+func invalidLinksToRPC(invalid []account.LinkError) []*gctrpc.InvalidAccountLink {
+	links := make([]*gctrpc.InvalidAccountLink, len(invalid))
+	for i := range invalid {
+		links[i] = &gctrpc.InvalidAccountLink{Node: accountNodeToRPC(invalid[i].Node), Error: invalid[i].Err.Error()}
+	}
+	return links
+}
+
+// accountToRPC 转换账户信息
+func accountToRPC(acc *account.Account) *gctrpc.Account {
+	return &gctrpc.Account{
+		Id:                int64(acc.ID),
+		Name:              acc.Name,
+		Address:           acc.Address,
+		ExchangeAddressId: acc.ExchangeAddressID,
+		ZkAddressId:       acc.ZkAddressID,
+		F4AddressId:       acc.F4AddressID,
+		OtAddressId:       acc.OTAddressID,
+		Cipher:            acc.Cipher,
+		Layer:             int32(acc.Layer),
+		Owner:             acc.Owner,
+		ChainName:         acc.ChainName,
+		ParentId:          int64(acc.ParentID),
+	}
+}
+
+// createdAccountToRPC 转换新建的账户，私钥密文不返回给客户端
+func createdAccountToRPC(acc *account.CreatedAccount) *gctrpc.CreatedAccount {
+	a := accountToRPC(&acc.Account)
+	a.Cipher = ""
+	return &gctrpc.CreatedAccount{Account: a, DerivationPath: acc.DerivationPath}
+}
+
 // GetTokenPrice 获取代币价格信息
 func (s *RPCServer) GetTokenPrice(ctx context.Context, req *gctrpc.GetTokenPriceRequest) (*gctrpc.GetTokenPriceResponse, error) {
 	if req.TokenAddress == "" {
@@ -246,15 +456,18 @@ func (s *RPCServer) GetTokenPrice(ctx context.Context, req *gctrpc.GetTokenPrice
 	return response, nil
 }
 
-// Crypto 实现加密服务
+// Crypto 实现加密服务，使用信封加密并将密文与账户地址绑定
 func (s *RPCServer) Crypto(ctx context.Context, req *gctrpc.CryptoRequest) (*gctrpc.CryptoResponse, error) {
 	if req.Plaintext == "" {
 		return nil, errors.New("enter a partial private key")
 	}
+	if req.Address == "" {
+		return nil, errors.New("address cannot be empty")
+	}
 
 	accountManager := account.New(s.Config)
 
-	ciphertext, err := accountManager.Crypto(req.Plaintext)
+	ciphertext, err := accountManager.Encrypt(req.Address, req.Plaintext)
 	if err != nil {
 		return nil, err
 	}
@@ -264,59 +477,185 @@ func (s *RPCServer) Crypto(ctx context.Context, req *gctrpc.CryptoRequest) (*gct
 	}, nil
 }
 
-// TransferSOL 实现SOL代币批量转发服务
-func (s *RPCServer) TransferSOL(ctx context.Context, req *gctrpc.TransferSOLRequest) (*gctrpc.TransferSOLResponse, error) {
+// MigrateAccountCiphers 将账户的 RSA 密文原地重新加密为信封密文，dry_run 时只校验不写入
+func (s *RPCServer) MigrateAccountCiphers(_ context.Context, req *gctrpc.MigrateAccountCiphersRequest) (*gctrpc.MigrateAccountCiphersResponse, error) {
 	if req == nil {
 		return nil, errNilRequestData
 	}
 
-	if req.Address == "" {
-		return nil, errors.New("address cannot be empty")
+	report, err := account.New(s.Config).MigrateCiphers(req.DryRun)
+	if err != nil {
+		return nil, err
 	}
 
-	// 获取账户管理器
-	accountManager := account.New(s.Config)
+	resp := &gctrpc.MigrateAccountCiphersResponse{
+		Total:    int64(report.Total),
+		Migrated: int64(report.Migrated),
+		Skipped:  int64(report.Skipped),
+		Failures: make([]*gctrpc.CipherMigrationFailure, len(report.Failures)),
+		DryRun:   report.DryRun,
+	}
+	for i := range report.Failures {
+		resp.Failures[i] = &gctrpc.CipherMigrationFailure{
+			Address: report.Failures[i].Address,
+			Error:   report.Failures[i].Err.Error(),
+		}
+	}
+	return resp, nil
+}
+
+// ValidateRecipients 校验接收者列表并返回校验报告，用于在确认转账前检查地址
+func (s *RPCServer) ValidateRecipients(ctx context.Context, req *gctrpc.ValidateRecipientsRequest) (*gctrpc.RecipientValidation, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Kind == "" {
+		req.Kind = TransferJobKindSOL
+	}
+	if req.Kind != TransferJobKindSOL && req.Kind != TransferJobKindToken {
+		return nil, fmt.Errorf("%w: %s", errUnknownTransferKind, req.Kind)
+	}
 
-	// 获取私钥
-	privateKey, err := accountManager.PrivateKey(req.Address)
+	recipients, err := s.loadRecipients(req.RecipientsFile, req.Recipients)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := validationOptions(s.Config, req.Duplicates, defaultAmount(forward.DefaultConfig(), req.Kind))
+	if err != nil {
+		return nil, err
+	}
+	// 存在阻止转账的问题时同样返回完整的报告
+	validation, err := forward.New(s.Config, s.SolanaRPC).ValidateRecipients(ctx, recipients, opts)
 	if err != nil {
-		return nil, fmt.Errorf("获取私钥失败: %w", err)
+		return nil, err
 	}
+	return recipientValidationToRPC(validation), nil
+}
 
-	// 获取转发管理器
-	forwardManager := forward.New(s.Config)
+// TransferSOL 实现SOL代币批量转发服务
+func (s *RPCServer) TransferSOL(ctx context.Context, req *gctrpc.TransferSOLRequest) (*gctrpc.TransferSOLResponse, error) {
+	return s.transferSOL(ctx, req, nil)
+}
 
-	// 从文件读取目标地址列表
-	addresses, err := forward.ReadAddressesFromFile(s.Config.FilePath)
+// TransferSOLStream 与 TransferSOL 相同，但在转账过程中推送每个批次的进度和累计数量；
+// 客户端断开后不再发送新的批次，已发送的批次继续跟踪确认，任务可以稍后恢复
+func (s *RPCServer) TransferSOLStream(req *gctrpc.TransferSOLRequest, stream gctrpc.GoCryptoTraderService_TransferSOLStreamServer) error {
+	progress := newTransferProgress(stream.Context(), stream.Send)
+	resp, err := s.transferSOL(context.WithoutCancel(stream.Context()), req, progress)
 	if err != nil {
-		return nil, fmt.Errorf("读取地址列表失败: %w", err)
+		return err
+	}
+	return progress.finish(&gctrpc.TransferProgress{SolResult: resp})
+}
+
+// transferSOL 执行SOL批量转发，observer 不为空时接收批次进度
+func (s *RPCServer) transferSOL(ctx context.Context, req *gctrpc.TransferSOLRequest, observer forward.Observer) (*gctrpc.TransferSOLResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+
+	if req.Address == "" && len(req.Sources) == 0 {
+		return nil, errors.New("address cannot be empty")
+	}
+
+	if err := s.checkFanOut(req.Sources, req.Treasury, req.UseDurableNonce, req.DryRun || req.SignOnly); err != nil {
+		return nil, err
 	}
 
-	// 创建转发请求
-	forwardReq := &forward.ForwardRequest{
-		PrivateKeyStr: privateKey,
-		Addresses:     addresses,
-		Config:        forward.DefaultConfig(),
+	// 读取接收者列表
+	recipients, err := s.loadRecipients(req.RecipientsFile, req.Recipients)
+	if err != nil {
+		return nil, err
 	}
 
-	// 执行转发
-	txSignatures, err := forwardManager.TransferSOL(ctx, forwardReq)
+	cfg := transferConfig(req.PartialPay, req.UseLookupTables)
+	cfg.MemoMode = forward.MemoMode(req.MemoMode)
+	cfg.Reference = req.Reference
+	validation, err := s.validateRecipients(ctx, recipients, req.Duplicates, defaultAmount(cfg, TransferJobKindSOL))
 	if err != nil {
 		return nil, err
 	}
+	recipients = validation.Recipients
+	if req.UseDurableNonce {
+		if cfg.NonceAccounts, err = s.nonceAccountsFor(ctx, req.Address); err != nil {
+			return nil, err
+		}
+	}
+
+	var jobID string
+	var topUps []string
+	var result *forward.Result
+	if s.TransferJobs.IsRunning() && !req.DryRun && !req.SignOnly {
+		// 持久化转账任务，进程中断后可以恢复；拆分到多个源账户时先从金库为各源账户充值
+		jobReq := &TransferJobRequest{
+			Kind:          TransferJobKindSOL,
+			SourceAddress: req.Address,
+			Recipients:    recipients,
+			Config:        cfg,
+			Sources:       req.Sources,
+			Treasury:      req.Treasury,
+			TopUpReserve:  req.TopUpReserve,
+			Observer:      observer,
+		}
+		if req.Treasury != "" {
+			if topUps, err = s.TransferJobs.TopUp(ctx, jobReq); err != nil {
+				return nil, err
+			}
+		}
+		jobID, result, err = s.TransferJobs.Submit(ctx, jobReq)
+	} else {
+		// 执行转发
+		result, err = forward.New(s.Config, s.SolanaRPC).TransferSOL(ctx, &forward.ForwardRequest{
+			From:       req.Address,
+			Signer:     s.Signer,
+			Recipients: recipients,
+			Config:     cfg,
+			Observer:   observer,
+			DryRun:     req.DryRun,
+			SignOnly:   req.SignOnly,
+		})
+	}
+	if err != nil {
+		return nil, transferJobError(jobID, err)
+	}
 
 	return &gctrpc.TransferSOLResponse{
-		TxSignatures: txSignatures,
+		TxSignatures: result.Signatures(),
+		JobId:        jobID,
+		Batches:      transferBatchesToRPC(result.Batches),
+		Skipped:      recipientAddresses(result.Skipped),
+		Simulation:   simulationToRPC(result.Simulation),
+		Preflight:    preflightToRPC(result.Preflight),
+		Unpaid:       recipientAddresses(result.Unpaid),
+		LookupTables: result.LookupTables,
+		Validation:   recipientValidationToRPC(validation),
+		TopUpJobIds:  topUps,
 	}, nil
 }
 
 // TransferToken 实现代币批量转发服务
 func (s *RPCServer) TransferToken(ctx context.Context, req *gctrpc.TransferTokenRequest) (*gctrpc.TransferTokenResponse, error) {
+	return s.transferToken(ctx, req, nil)
+}
+
+// TransferTokenStream 与 TransferToken 相同，但在转账过程中推送每个批次的进度和累计数量；
+// 客户端断开后不再发送新的批次，已发送的批次继续跟踪确认，任务可以稍后恢复
+func (s *RPCServer) TransferTokenStream(req *gctrpc.TransferTokenRequest, stream gctrpc.GoCryptoTraderService_TransferTokenStreamServer) error {
+	progress := newTransferProgress(stream.Context(), stream.Send)
+	resp, err := s.transferToken(context.WithoutCancel(stream.Context()), req, progress)
+	if err != nil {
+		return err
+	}
+	return progress.finish(&gctrpc.TransferProgress{TokenResult: resp})
+}
+
+// transferToken 执行代币批量转发，observer 不为空时接收批次进度
+func (s *RPCServer) transferToken(ctx context.Context, req *gctrpc.TransferTokenRequest, observer forward.Observer) (*gctrpc.TransferTokenResponse, error) {
 	if req == nil {
 		return nil, errNilRequestData
 	}
 
-	if req.Address == "" {
+	if req.Address == "" && len(req.Sources) == 0 {
 		return nil, errors.New("address cannot be empty")
 	}
 
@@ -324,39 +663,1137 @@ func (s *RPCServer) TransferToken(ctx context.Context, req *gctrpc.TransferToken
 		return nil, errors.New("token mint cannot be empty")
 	}
 
-	// 获取账户管理器
-	accountManager := account.New(s.Config)
+	if err := s.checkFanOut(req.Sources, req.Treasury, req.UseDurableNonce, req.DryRun || req.SignOnly); err != nil {
+		return nil, err
+	}
+
+	// 读取接收者列表
+	recipients, err := s.loadRecipients(req.RecipientsFile, req.Recipients)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := transferConfig(req.PartialPay, req.UseLookupTables)
+	cfg.MemoMode = forward.MemoMode(req.MemoMode)
+	cfg.Reference = req.Reference
+	validation, err := s.validateRecipients(ctx, recipients, req.Duplicates, defaultAmount(cfg, TransferJobKindToken))
+	if err != nil {
+		return nil, err
+	}
+	recipients = validation.Recipients
+	if req.UseDurableNonce {
+		if cfg.NonceAccounts, err = s.nonceAccountsFor(ctx, req.Address); err != nil {
+			return nil, err
+		}
+	}
 
-	// 获取私钥
-	privateKey, err := accountManager.PrivateKey(req.Address)
+	var jobID string
+	var topUps []string
+	var result *forward.Result
+	if s.TransferJobs.IsRunning() && !req.DryRun && !req.SignOnly {
+		// 持久化转账任务，进程中断后可以恢复；拆分到多个源账户时先从金库为各源账户充值
+		jobReq := &TransferJobRequest{
+			Kind:          TransferJobKindToken,
+			SourceAddress: req.Address,
+			TokenMint:     req.TokenMint,
+			Recipients:    recipients,
+			Config:        cfg,
+			Sources:       req.Sources,
+			Treasury:      req.Treasury,
+			TopUpReserve:  req.TopUpReserve,
+			Observer:      observer,
+		}
+		if req.Treasury != "" {
+			if topUps, err = s.TransferJobs.TopUp(ctx, jobReq); err != nil {
+				return nil, err
+			}
+		}
+		jobID, result, err = s.TransferJobs.Submit(ctx, jobReq)
+	} else {
+		// 执行转发
+		result, err = forward.New(s.Config, s.SolanaRPC).TransferToken(ctx, &forward.TokenForwardRequest{
+			From:       req.Address,
+			Signer:     s.Signer,
+			TokenMint:  req.TokenMint,
+			Recipients: recipients,
+			Config:     cfg,
+			Observer:   observer,
+			DryRun:     req.DryRun,
+			SignOnly:   req.SignOnly,
+		})
+	}
 	if err != nil {
-		return nil, fmt.Errorf("获取私钥失败: %w", err)
+		return nil, transferJobError(jobID, err)
 	}
 
-	// 获取转发管理器
-	forwardManager := forward.New(s.Config)
+	return &gctrpc.TransferTokenResponse{
+		TxSignatures: result.Signatures(),
+		JobId:        jobID,
+		Batches:      transferBatchesToRPC(result.Batches),
+		Skipped:      recipientAddresses(result.Skipped),
+		Simulation:   simulationToRPC(result.Simulation),
+		TransferFee:  result.TransferFee,
+		Preflight:    preflightToRPC(result.Preflight),
+		Unpaid:       recipientAddresses(result.Unpaid),
+		LookupTables: result.LookupTables,
+		Validation:   recipientValidationToRPC(validation),
+		TopUpJobIds:  topUps,
+	}, nil
+}
+
+// ListTransferJobs 返回持久化的转账任务列表
+func (s *RPCServer) ListTransferJobs(_ context.Context, req *gctrpc.ListTransferJobsRequest) (*gctrpc.ListTransferJobsResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
 
-	// 从文件读取目标地址列表
-	addresses, err := forward.ReadAddressesFromFile(s.Config.FilePath)
+	jobs, err := s.TransferJobs.ListJobs(req.Status, int(req.Limit))
 	if err != nil {
-		return nil, fmt.Errorf("读取地址列表失败: %w", err)
+		return nil, err
+	}
+
+	resp := &gctrpc.ListTransferJobsResponse{Jobs: make([]*gctrpc.TransferJob, len(jobs))}
+	for i := range jobs {
+		resp.Jobs[i] = transferJobToRPC(&jobs[i])
 	}
+	return resp, nil
+}
 
-	// 创建转发请求
-	forwardReq := &forward.TokenForwardRequest{
-		PrivateKeyStr: privateKey,
-		TokenMint:     req.TokenMint,
-		Addresses:     addresses,
-		Config:        forward.DefaultConfig(),
+// GetTransferJob 返回转账任务及每个接收者的批次、签名和状态
+func (s *RPCServer) GetTransferJob(_ context.Context, req *gctrpc.GetTransferJobRequest) (*gctrpc.GetTransferJobResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Id == "" {
+		return nil, errTransferJobIDUnset
 	}
 
-	// 执行转发
-	txSignatures, err := forwardManager.TransferToken(ctx, forwardReq)
+	job, err := s.TransferJobs.GetJob(req.Id)
 	if err != nil {
 		return nil, err
 	}
+	return &gctrpc.GetTransferJobResponse{Job: transferJobToRPC(job)}, nil
+}
 
-	return &gctrpc.TransferTokenResponse{
-		TxSignatures: txSignatures,
+// ResumeTransferJob 恢复失败或中断的转账任务，只发送尚未到账的接收者
+func (s *RPCServer) ResumeTransferJob(ctx context.Context, req *gctrpc.ResumeTransferJobRequest) (*gctrpc.ResumeTransferJobResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Id == "" {
+		return nil, errTransferJobIDUnset
+	}
+
+	cfg := transferConfig(req.PartialPay, req.UseLookupTables)
+	result, err := s.TransferJobs.Resume(ctx, req.Id, cfg)
+	if err != nil {
+		return nil, transferJobError(req.Id, err)
+	}
+
+	job, err := s.TransferJobs.GetJob(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &gctrpc.ResumeTransferJobResponse{
+		Job:          transferJobToRPC(job),
+		Batches:      transferBatchesToRPC(result.Batches),
+		Preflight:    preflightToRPC(result.Preflight),
+		Unpaid:       recipientAddresses(result.Unpaid),
+		LookupTables: result.LookupTables,
 	}, nil
 }
+
+// FindTransfers 按交易签名或备注查找转账历史，返回对应的接收者及其所属任务
+func (s *RPCServer) FindTransfers(_ context.Context, req *gctrpc.FindTransfersRequest) (*gctrpc.FindTransfersResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Signature == "" && req.Memo == "" {
+		return nil, errTransferLookupUnset
+	}
+
+	transfers, err := s.TransferJobs.FindTransfers(req.Signature, req.Memo)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &gctrpc.FindTransfersResponse{Transfers: make([]*gctrpc.TransferRecord, len(transfers))}
+	for i := range transfers {
+		t := &transfers[i]
+		resp.Transfers[i] = &gctrpc.TransferRecord{
+			JobId:         t.JobID,
+			Kind:          t.Kind,
+			SourceAddress: t.SourceAddress,
+			TokenMint:     t.TokenMint,
+			Reference:     t.Reference,
+			Recipient:     transferJobRecipientToRPC(&t.Recipient, t.SourceAddress),
+		}
+	}
+	return resp, nil
+}
+
+// SweepAccounts 将账户表中符合筛选条件的账户的 SOL 和代币归集到目标地址
+func (s *RPCServer) SweepAccounts(ctx context.Context, req *gctrpc.SweepAccountsRequest) (*gctrpc.SweepAccountsResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Destination == "" {
+		return nil, errSweepDestinationUnset
+	}
+
+	filter := &account.Filter{Owner: req.Owner, ChainName: req.ChainName}
+	if req.Layer != nil {
+		layer := int(*req.Layer)
+		filter.Layer = &layer
+	}
+	accountManager := account.New(s.Config)
+	accounts, err := accountManager.AccountsByFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	sources := make([]string, 0, len(accounts))
+	for _, acc := range accounts {
+		if acc.Address != req.Destination {
+			sources = append(sources, acc.Address)
+		}
+	}
+	if len(sources) == 0 {
+		return nil, errNoSweepSources
+	}
+
+	result, err := forward.New(s.Config, s.SolanaRPC).Sweep(ctx, &forward.SweepRequest{
+		Sources:            sources,
+		Destination:        req.Destination,
+		TokenMints:         req.TokenMints,
+		SweepSOL:           req.SweepSol,
+		Reserve:            req.ReserveLamports,
+		CloseTokenAccounts: req.CloseTokenAccounts,
+		Signer:             s.Signer,
+		Config:             forward.DefaultConfig(),
+		DryRun:             req.DryRun,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sweepResultToRPC(result), nil
+}
+
+// SweepLeaves 将一个所有者账户树中的每个叶子账户的 SOL 和代币归集到各自的上级账户，
+// 按上级账户分组执行，一组失败不影响其他组；上下级关联无效的账户不会被归集
+func (s *RPCServer) SweepLeaves(ctx context.Context, req *gctrpc.SweepLeavesRequest) (*gctrpc.SweepLeavesResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Owner == "" {
+		return nil, errAccountTreeOwnerUnset
+	}
+
+	tree, err := account.New(s.Config).Tree(req.Owner)
+	if err != nil {
+		return nil, err
+	}
+	leaves := tree.Leaves()
+	if len(leaves) == 0 {
+		return nil, fmt.Errorf("%w: %s", errNoLeafAccounts, req.Owner)
+	}
+	var parents []string
+	sources := make(map[string][]string)
+	for _, leaf := range leaves {
+		parent := leaf.Parent.Account.Address
+		if _, ok := sources[parent]; !ok {
+			parents = append(parents, parent)
+		}
+		sources[parent] = append(sources[parent], leaf.Account.Address)
+	}
+
+	resp := &gctrpc.SweepLeavesResponse{
+		Parents: make([]*gctrpc.LeafSweep, len(parents)),
+		Invalid: invalidLinksToRPC(tree.Invalid),
+	}
+	f := forward.New(s.Config, s.SolanaRPC)
+	for i, parent := range parents {
+		out := &gctrpc.LeafSweep{Parent: parent}
+		resp.Parents[i] = out
+		result, err := f.Sweep(ctx, &forward.SweepRequest{
+			Sources:            sources[parent],
+			Destination:        parent,
+			TokenMints:         req.TokenMints,
+			SweepSOL:           req.SweepSol,
+			Reserve:            req.ReserveLamports,
+			CloseTokenAccounts: req.CloseTokenAccounts,
+			Signer:             s.Signer,
+			Config:             forward.DefaultConfig(),
+			DryRun:             req.DryRun,
+		})
+		if err != nil {
+			out.Error = err.Error()
+			continue
+		}
+		out.Result = sweepResultToRPC(result)
+		resp.TotalLamports += out.Result.TotalLamports
+		resp.TotalReclaimedRent += out.Result.TotalReclaimedRent
+	}
+	return resp, nil
+}
+
+// sweepResultToRPC 转换归集结果，失败的源账户不计入总额
+func sweepResultToRPC(result *forward.SweepResult) *gctrpc.SweepAccountsResponse {
+	resp := &gctrpc.SweepAccountsResponse{Sources: make([]*gctrpc.SweepSource, len(result.Sources))}
+	for i, src := range result.Sources {
+		out := &gctrpc.SweepSource{
+			Address:       src.Address,
+			Lamports:      src.Lamports,
+			ReclaimedRent: src.ReclaimedRent,
+			Rent:          src.Rent,
+			Fees:          src.Fees,
+			Batches:       transferBatchesToRPC(src.Batches),
+			Simulation:    simulationToRPC(src.Simulation),
+		}
+		for _, t := range src.Tokens {
+			out.Tokens = append(out.Tokens, &gctrpc.SweptToken{
+				Mint:    t.Mint,
+				Account: t.Account,
+				Amount:  t.Amount,
+				Closed:  t.Closed,
+			})
+		}
+		resp.Sources[i] = out
+		if src.Err != nil {
+			out.Error = src.Err.Error()
+			continue
+		}
+		resp.TotalLamports += src.Lamports
+		resp.TotalReclaimedRent += src.ReclaimedRent
+	}
+	return resp
+}
+
+// ReclaimRent 关闭账户名下余额为 0 的代币账户，租金退回各自的账户
+// 未指定地址时使用账户表中符合筛选条件的全部账户
+func (s *RPCServer) ReclaimRent(ctx context.Context, req *gctrpc.ReclaimRentRequest) (*gctrpc.ReclaimRentResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+
+	accountManager := account.New(s.Config)
+	sources := req.Addresses
+	if len(sources) == 0 {
+		filter := &account.Filter{Owner: req.Owner, ChainName: req.ChainName}
+		if req.Layer != nil {
+			layer := int(*req.Layer)
+			filter.Layer = &layer
+		}
+		accounts, err := accountManager.AccountsByFilter(filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, acc := range accounts {
+			sources = append(sources, acc.Address)
+		}
+	}
+	if len(sources) == 0 {
+		return nil, errNoReclaimSources
+	}
+
+	result, err := forward.New(s.Config, s.SolanaRPC).Reclaim(ctx, &forward.ReclaimRequest{
+		Sources:      sources,
+		ExcludeMints: req.ExcludeMints,
+		Signer:       s.Signer,
+		Config:       forward.DefaultConfig(),
+		DryRun:       req.DryRun,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &gctrpc.ReclaimRentResponse{Sources: make([]*gctrpc.ReclaimSource, len(result.Sources))}
+	for i, src := range result.Sources {
+		out := &gctrpc.ReclaimSource{
+			Address:    src.Address,
+			Lamports:   src.Lamports,
+			Fees:       src.Fees,
+			Batches:    transferBatchesToRPC(src.Batches),
+			Simulation: simulationToRPC(src.Simulation),
+		}
+		for _, a := range src.Accounts {
+			out.Accounts = append(out.Accounts, &gctrpc.ClosedTokenAccount{
+				Account:    a.Account,
+				Mint:       a.Mint,
+				Lamports:   a.Lamports,
+				BatchIndex: int64(a.Batch),
+				Closed:     a.Closed,
+			})
+		}
+		resp.Sources[i] = out
+		if src.Err != nil {
+			out.Error = src.Err.Error()
+			continue
+		}
+		resp.TotalLamports += src.Lamports
+		resp.TotalFees += src.Fees
+	}
+	return resp, nil
+}
+
+// GetSolanaRPCHealth 返回 Solana RPC 节点池中每个节点的健康状态
+func (s *RPCServer) GetSolanaRPCHealth(_ context.Context, req *gctrpc.GetSolanaRPCHealthRequest) (*gctrpc.GetSolanaRPCHealthResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if s.SolanaRPC == nil {
+		return nil, rpcpool.ErrNilPool
+	}
+
+	health := s.SolanaRPC.Health()
+	resp := &gctrpc.GetSolanaRPCHealthResponse{
+		Running:   s.SolanaRPC.IsRunning(),
+		Endpoints: make([]*gctrpc.SolanaRPCEndpointHealth, len(health)),
+	}
+	for i := range health {
+		h := &health[i]
+		resp.Endpoints[i] = &gctrpc.SolanaRPCEndpointHealth{
+			Name:      h.Name,
+			Url:       h.URL,
+			Weight:    int64(h.Weight),
+			Healthy:   h.Healthy,
+			Slot:      h.Slot,
+			SlotLag:   h.SlotLag,
+			LatencyMs: h.Latency.Milliseconds(),
+			LastError: h.LastError,
+			Requests:  h.Requests,
+			Failures:  h.Failures,
+		}
+		if !h.LastCheck.IsZero() {
+			resp.Endpoints[i].LastCheck = &gctrpc.Timestamp{Seconds: h.LastCheck.Unix(), Nanos: int32(h.LastCheck.Nanosecond())}
+		}
+	}
+	return resp, nil
+}
+
+// loadRecipients 根据请求读取接收者列表
+// 请求中直接携带的接收者优先，其次为请求指定的文件，最后回退到配置中的 FilePath
+func (s *RPCServer) loadRecipients(filePath string, rows []*gctrpc.TransferRecipient) ([]forward.Recipient, error) {
+	if len(rows) > 0 {
+		if filePath != "" {
+			return nil, errRecipientsSourceConflict
+		}
+		recipients := make([]forward.Recipient, len(rows))
+		for i := range rows {
+			recipients[i] = forward.Recipient{
+				Address: rows[i].Address,
+				Amount:  rows[i].Amount,
+				Memo:    rows[i].Memo,
+				Label:   rows[i].Label,
+			}
+		}
+		if err := forward.CheckRecipients(recipients); err != nil {
+			return nil, err
+		}
+		return recipients, nil
+	}
+
+	if filePath == "" {
+		filePath = s.Config.FilePath
+	}
+	recipients, err := forward.ReadRecipientsFromFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取接收者列表失败: %w", err)
+	}
+	return recipients, nil
+}
+
+// checkFanOut 拆分到多个源账户的转账只能作为持久化任务执行，各源账户没有共同的 nonce 账户
+func (s *RPCServer) checkFanOut(sources []string, treasury string, durableNonce, direct bool) error {
+	if len(sources) == 0 {
+		if treasury != "" {
+			return errTreasuryWithoutSources
+		}
+		return nil
+	}
+	if direct || !s.TransferJobs.IsRunning() {
+		return errFanOutRequiresJobs
+	}
+	if durableNonce {
+		return errFanOutNonce
+	}
+	return nil
+}
+
+// validateRecipients 在转账或创建计划前校验接收者列表，存在阻止转账的问题时返回错误，
+// 否则返回的报告中包含合并重复地址后的接收者
+func (s *RPCServer) validateRecipients(ctx context.Context, recipients []forward.Recipient, duplicates string, amount float64) (*forward.ValidationReport, error) {
+	opts, err := validationOptions(s.Config, duplicates, amount)
+	if err != nil {
+		return nil, err
+	}
+	report, err := forward.New(s.Config, s.SolanaRPC).ValidateRecipients(ctx, recipients, opts)
+	if err != nil {
+		return nil, err
+	}
+	return report, report.Err()
+}
+
+// defaultAmount 返回转账类型未指定数量时使用的默认数量
+func defaultAmount(cfg *forward.Config, kind string) float64 {
+	if kind == TransferJobKindToken {
+		return cfg.Amount
+	}
+	return cfg.AmountSOL
+}
+
+// transferJobError 在错误中附带任务 ID，便于之后恢复任务
+func transferJobError(jobID string, err error) error {
+	if jobID == "" {
+		return err
+	}
+	return fmt.Errorf("transfer job %s: %w", jobID, err)
+}
+
+// CreateNonceAccounts 为地址创建 durable nonce 账户，地址同时是 nonce 账户的权限账户
+func (s *RPCServer) CreateNonceAccounts(ctx context.Context, req *gctrpc.CreateNonceAccountsRequest) (*gctrpc.NonceAccountsResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Address == "" {
+		return nil, errors.New("address cannot be empty")
+	}
+	if req.Count <= 0 {
+		return nil, errInvalidNonceCount
+	}
+
+	accounts, err := forward.New(s.Config, s.SolanaRPC).CreateNonceAccounts(ctx, &forward.NonceAccountRequest{
+		From:   req.Address,
+		Signer: s.Signer,
+		Count:  int(req.Count),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nonceAccountsToRPC(accounts), nil
+}
+
+// ListNonceAccounts 返回地址名下的 durable nonce 账户
+func (s *RPCServer) ListNonceAccounts(ctx context.Context, req *gctrpc.ListNonceAccountsRequest) (*gctrpc.NonceAccountsResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Address == "" {
+		return nil, errors.New("address cannot be empty")
+	}
+
+	accounts, err := forward.New(s.Config, s.SolanaRPC).NonceAccounts(ctx, req.Address)
+	if err != nil {
+		return nil, err
+	}
+	return nonceAccountsToRPC(accounts), nil
+}
+
+// CloseNonceAccounts 关闭地址名下的 durable nonce 账户，租金退回该地址；未指定账户时关闭全部
+func (s *RPCServer) CloseNonceAccounts(ctx context.Context, req *gctrpc.CloseNonceAccountsRequest) (*gctrpc.NonceAccountsResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Address == "" {
+		return nil, errors.New("address cannot be empty")
+	}
+
+	accounts, err := forward.New(s.Config, s.SolanaRPC).CloseNonceAccounts(ctx, &forward.NonceAccountRequest{
+		From:      req.Address,
+		Signer:    s.Signer,
+		Addresses: req.NonceAccounts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nonceAccountsToRPC(accounts), nil
+}
+
+// SubmitSignedTransactions 发送使用 durable nonce 预先签名的交易并等待确认
+func (s *RPCServer) SubmitSignedTransactions(ctx context.Context, req *gctrpc.SubmitSignedTransactionsRequest) (*gctrpc.SubmitSignedTransactionsResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if len(req.Transactions) == 0 {
+		return nil, errNoSignedTransactions
+	}
+
+	batches, err := forward.New(s.Config, s.SolanaRPC).SubmitSigned(ctx, req.Transactions, forward.DefaultConfig(), nil)
+	if err != nil {
+		return nil, err
+	}
+	result := &forward.Result{Batches: batches}
+	return &gctrpc.SubmitSignedTransactionsResponse{
+		TxSignatures: result.Signatures(),
+		Batches:      transferBatchesToRPC(batches),
+	}, nil
+}
+
+// CreateTransferSchedule 创建定时或周期性转账计划，到期时由计划管理器创建转账任务
+func (s *RPCServer) CreateTransferSchedule(ctx context.Context, req *gctrpc.CreateTransferScheduleRequest) (*gctrpc.TransferScheduleResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	schedule, err := s.transferScheduleFromRPC(ctx, req.Schedule, req.RecipientsFile)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.TransferSchedules.CreateSchedule(schedule); err != nil {
+		return nil, err
+	}
+	return &gctrpc.TransferScheduleResponse{Schedule: transferScheduleToRPC(schedule)}, nil
+}
+
+// UpdateTransferSchedule 替换计划的定义和接收者，修改执行时间规则后重新计算下次执行时间
+func (s *RPCServer) UpdateTransferSchedule(ctx context.Context, req *gctrpc.UpdateTransferScheduleRequest) (*gctrpc.TransferScheduleResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Schedule != nil && req.Schedule.Id == "" {
+		return nil, errTransferScheduleIDUnset
+	}
+	schedule, err := s.transferScheduleFromRPC(ctx, req.Schedule, req.RecipientsFile)
+	if err != nil {
+		return nil, err
+	}
+	if schedule, err = s.TransferSchedules.UpdateSchedule(schedule); err != nil {
+		return nil, err
+	}
+	return &gctrpc.TransferScheduleResponse{Schedule: transferScheduleToRPC(schedule)}, nil
+}
+
+// GetTransferSchedule 返回计划及其接收者
+func (s *RPCServer) GetTransferSchedule(_ context.Context, req *gctrpc.GetTransferScheduleRequest) (*gctrpc.TransferScheduleResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Id == "" {
+		return nil, errTransferScheduleIDUnset
+	}
+	schedule, err := s.TransferSchedules.GetSchedule(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &gctrpc.TransferScheduleResponse{Schedule: transferScheduleToRPC(schedule)}, nil
+}
+
+// ListTransferSchedules 返回全部计划，不含接收者
+func (s *RPCServer) ListTransferSchedules(_ context.Context, req *gctrpc.ListTransferSchedulesRequest) (*gctrpc.ListTransferSchedulesResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	schedules, err := s.TransferSchedules.ListSchedules()
+	if err != nil {
+		return nil, err
+	}
+	resp := &gctrpc.ListTransferSchedulesResponse{Schedules: make([]*gctrpc.TransferSchedule, len(schedules))}
+	for i := range schedules {
+		resp.Schedules[i] = transferScheduleToRPC(&schedules[i])
+	}
+	return resp, nil
+}
+
+// DeleteTransferSchedule 删除计划，已创建的转账任务保留
+func (s *RPCServer) DeleteTransferSchedule(_ context.Context, req *gctrpc.DeleteTransferScheduleRequest) (*gctrpc.DeleteTransferScheduleResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Id == "" {
+		return nil, errTransferScheduleIDUnset
+	}
+	if err := s.TransferSchedules.DeleteSchedule(req.Id); err != nil {
+		return nil, err
+	}
+	return &gctrpc.DeleteTransferScheduleResponse{Id: req.Id}, nil
+}
+
+// PauseTransferSchedule 暂停计划，暂停期间不会创建新的转账任务
+func (s *RPCServer) PauseTransferSchedule(_ context.Context, req *gctrpc.PauseTransferScheduleRequest) (*gctrpc.TransferScheduleResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Id == "" {
+		return nil, errTransferScheduleIDUnset
+	}
+	schedule, err := s.TransferSchedules.PauseSchedule(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &gctrpc.TransferScheduleResponse{Schedule: transferScheduleToRPC(schedule)}, nil
+}
+
+// ResumeTransferSchedule 恢复暂停的计划，暂停期间错过的执行不会补齐
+func (s *RPCServer) ResumeTransferSchedule(_ context.Context, req *gctrpc.ResumeTransferScheduleRequest) (*gctrpc.TransferScheduleResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Id == "" {
+		return nil, errTransferScheduleIDUnset
+	}
+	schedule, err := s.TransferSchedules.ResumeSchedule(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &gctrpc.TransferScheduleResponse{Schedule: transferScheduleToRPC(schedule)}, nil
+}
+
+// CreateVestingPlan 为 accounts 表中的受益账户创建归属计划，未设置开始时间时从当前时间开始归属
+func (s *RPCServer) CreateVestingPlan(ctx context.Context, req *gctrpc.CreateVestingPlanRequest) (*gctrpc.VestingPlanResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Plan == nil {
+		return nil, errVestingPlanUnset
+	}
+	plan := &vestingplan.Plan{
+		AccountID:     int(req.Plan.AccountId),
+		SourceAddress: req.Plan.SourceAddress,
+		TokenMint:     req.Plan.TokenMint,
+		Cliff:         time.Duration(req.Plan.CliffSeconds) * time.Second,
+		Period:        time.Duration(req.Plan.PeriodSeconds) * time.Second,
+		Periods:       int(req.Plan.Periods),
+	}
+	if req.Plan.StartAt != nil {
+		plan.StartAt = time.Unix(req.Plan.StartAt.Seconds, int64(req.Plan.StartAt.Nanos)).UTC()
+	}
+	if err := vesting.New(s.Config, s.SolanaRPC, s.Signer).CreatePlan(ctx, plan, req.Plan.TotalAmount); err != nil {
+		return nil, err
+	}
+	return &gctrpc.VestingPlanResponse{Plan: vestingPlanToRPC(plan, time.Now())}, nil
+}
+
+// GetVestingPlan 返回计划的归属进度及全部释放记录
+func (s *RPCServer) GetVestingPlan(_ context.Context, req *gctrpc.GetVestingPlanRequest) (*gctrpc.VestingPlanResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Id == "" {
+		return nil, errVestingPlanIDUnset
+	}
+	plan, err := vestingplan.GetPlan(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &gctrpc.VestingPlanResponse{Plan: vestingPlanToRPC(plan, time.Now())}, nil
+}
+
+// ListVestingPlans 返回计划及其归属进度，不含释放记录；可按受益账户和状态筛选
+func (s *RPCServer) ListVestingPlans(_ context.Context, req *gctrpc.ListVestingPlansRequest) (*gctrpc.ListVestingPlansResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	plans, err := vestingplan.ListPlans(int(req.AccountId), req.Status)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	resp := &gctrpc.ListVestingPlansResponse{Plans: make([]*gctrpc.VestingPlan, len(plans))}
+	for i := range plans {
+		resp.Plans[i] = vestingPlanToRPC(&plans[i], now)
+	}
+	return resp, nil
+}
+
+// ReleaseVestingPlan 释放计划当前已归属但尚未释放的数量；未指定计划 ID 时释放所有有可释放数量的计划，
+// 部分计划释放失败时在响应中返回错误信息
+func (s *RPCServer) ReleaseVestingPlan(ctx context.Context, req *gctrpc.ReleaseVestingPlanRequest) (*gctrpc.ReleaseVestingPlanResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	m := vesting.New(s.Config, s.SolanaRPC, s.Signer)
+	cfg := forward.DefaultConfig()
+	if req.Id != "" {
+		release, err := m.Release(ctx, req.Id, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return &gctrpc.ReleaseVestingPlanResponse{Releases: []*gctrpc.VestingRelease{vestingReleaseToRPC(release)}}, nil
+	}
+
+	releases, err := m.ReleaseDue(ctx, cfg)
+	resp := &gctrpc.ReleaseVestingPlanResponse{Releases: make([]*gctrpc.VestingRelease, len(releases))}
+	for i := range releases {
+		resp.Releases[i] = vestingReleaseToRPC(releases[i])
+	}
+	if err != nil {
+		if len(releases) == 0 {
+			return nil, err
+		}
+		resp.Error = err.Error()
+	}
+	return resp, nil
+}
+
+// WrapSOL 将账户中的 SOL 包装为 wSOL，存入账户的 wSOL 关联代币账户
+func (s *RPCServer) WrapSOL(ctx context.Context, req *gctrpc.WrapSOLRequest) (*gctrpc.WrappedSOLResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Address == "" {
+		return nil, errors.New("address cannot be empty")
+	}
+	if req.Amount <= 0 {
+		return nil, errInvalidWrapAmount
+	}
+
+	result, err := forward.New(s.Config, s.SolanaRPC).WrapSOL(ctx, &forward.WrapRequest{
+		From:   req.Address,
+		Signer: s.Signer,
+		Amount: req.Amount,
+		DryRun: req.DryRun,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return wrappedSOLToRPC(result), nil
+}
+
+// UnwrapSOL 关闭账户的 wSOL 关联代币账户，wSOL 和租金全部以 SOL 退回账户
+func (s *RPCServer) UnwrapSOL(ctx context.Context, req *gctrpc.UnwrapSOLRequest) (*gctrpc.WrappedSOLResponse, error) {
+	if req == nil {
+		return nil, errNilRequestData
+	}
+	if req.Address == "" {
+		return nil, errors.New("address cannot be empty")
+	}
+
+	result, err := forward.New(s.Config, s.SolanaRPC).UnwrapSOL(ctx, &forward.WrapRequest{
+		From:   req.Address,
+		Signer: s.Signer,
+		DryRun: req.DryRun,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return wrappedSOLToRPC(result), nil
+}
+
+// transferScheduleFromRPC 将请求中的计划转换为存储结构，接收者的读取和校验规则与转账请求相同，
+// 保存的是按重复地址规则合并后的接收者
+func (s *RPCServer) transferScheduleFromRPC(ctx context.Context, req *gctrpc.TransferSchedule, recipientsFile string) (*transferschedule.Schedule, error) {
+	if req == nil {
+		return nil, errTransferScheduleUnset
+	}
+	recipients, err := s.loadRecipients(recipientsFile, req.Recipients)
+	if err != nil {
+		return nil, err
+	}
+	schedule := &transferschedule.Schedule{
+		ID:              req.Id,
+		Name:            req.Name,
+		Kind:            req.Kind,
+		SourceAddress:   req.SourceAddress,
+		TokenMint:       req.TokenMint,
+		Cron:            req.Cron,
+		Interval:        time.Duration(req.IntervalSeconds) * time.Second,
+		Amount:          req.Amount,
+		CatchUp:         req.CatchUp,
+		PartialPay:      req.PartialPay,
+		UseLookupTables: req.UseLookupTables,
+		Duplicates:      req.Duplicates,
+	}
+	validation, err := s.validateRecipients(ctx, recipients, req.Duplicates, defaultAmount(scheduleJobRequest(schedule).Config, req.Kind))
+	if err != nil {
+		return nil, err
+	}
+	recipients = validation.Recipients
+	schedule.Recipients = make([]transferschedule.Recipient, len(recipients))
+	for i := range recipients {
+		schedule.Recipients[i] = transferschedule.Recipient{
+			Address: recipients[i].Address,
+			Amount:  recipients[i].Amount,
+			Memo:    recipients[i].Memo,
+			Label:   recipients[i].Label,
+		}
+	}
+	return schedule, nil
+}
+
+// nonceAccountsFor 返回地址名下已初始化且以该地址为权限账户的 nonce 账户
+func (s *RPCServer) nonceAccountsFor(ctx context.Context, address string) ([]string, error) {
+	accounts, err := forward.New(s.Config, s.SolanaRPC).NonceAccounts(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	var addresses []string
+	for _, a := range accounts {
+		if a.Nonce != "" && a.Authority == address {
+			addresses = append(addresses, a.Address)
+		}
+	}
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("%w %s", errNoNonceAccounts, address)
+	}
+	return addresses, nil
+}
+
+func nonceAccountsToRPC(accounts []*forward.NonceAccount) *gctrpc.NonceAccountsResponse {
+	resp := &gctrpc.NonceAccountsResponse{NonceAccounts: make([]*gctrpc.NonceAccount, len(accounts))}
+	for i, a := range accounts {
+		resp.NonceAccounts[i] = &gctrpc.NonceAccount{
+			Address:   a.Address,
+			Seed:      a.Seed,
+			Authority: a.Authority,
+			Nonce:     a.Nonce,
+			Lamports:  a.Lamports,
+		}
+	}
+	return resp
+}
+
+func transferBatchesToRPC(batches []*forward.Batch) []*gctrpc.TransferBatch {
+	resp := make([]*gctrpc.TransferBatch, len(batches))
+	for i, b := range batches {
+		resp[i] = &gctrpc.TransferBatch{
+			Index:       int64(b.Index),
+			Signature:   b.Signature,
+			Status:      string(b.Status),
+			Addresses:   make([]string, len(b.Recipients)),
+			Transaction: b.Transaction,
+		}
+		if b.Err != nil {
+			resp[i].Error = b.Err.Error()
+		}
+		for j := range b.Recipients {
+			resp[i].Addresses[j] = b.Recipients[j].Address
+		}
+	}
+	return resp
+}
+
+func recipientValidationToRPC(report *forward.ValidationReport) *gctrpc.RecipientValidation {
+	if report == nil {
+		return nil
+	}
+	resp := &gctrpc.RecipientValidation{
+		Total:      int64(report.Total),
+		Valid:      int64(len(report.Recipients)),
+		Merged:     int64(report.Merged),
+		Issues:     make([]*gctrpc.RecipientIssue, len(report.Issues)),
+		Recipients: make([]*gctrpc.TransferRecipient, len(report.Recipients)),
+	}
+	for i := range report.Issues {
+		resp.Issues[i] = &gctrpc.RecipientIssue{
+			Row:      int64(report.Issues[i].Row),
+			Address:  report.Issues[i].Address,
+			Kind:     string(report.Issues[i].Kind),
+			Blocking: report.Issues[i].Blocking,
+			Detail:   report.Issues[i].Detail,
+		}
+	}
+	for i := range report.Recipients {
+		resp.Recipients[i] = &gctrpc.TransferRecipient{
+			Address: report.Recipients[i].Address,
+			Amount:  report.Recipients[i].Amount,
+			Memo:    report.Recipients[i].Memo,
+			Label:   report.Recipients[i].Label,
+		}
+	}
+	return resp
+}
+
+func recipientAddresses(recipients []forward.Recipient) []string {
+	addresses := make([]string, len(recipients))
+	for i := range recipients {
+		addresses[i] = recipients[i].Address
+	}
+	return addresses
+}
+
+func transferJobToRPC(job *transferjob.Job) *gctrpc.TransferJob {
+	resp := &gctrpc.TransferJob{
+		Id:            job.ID,
+		Kind:          job.Kind,
+		SourceAddress: job.SourceAddress,
+		TokenMint:     job.TokenMint,
+		Status:        job.Status,
+		Error:         job.Error,
+		MemoMode:      job.MemoMode,
+		Reference:     job.Reference,
+		CreatedAt:     &gctrpc.Timestamp{Seconds: job.CreatedAt.Unix(), Nanos: int32(job.CreatedAt.Nanosecond())},
+		UpdatedAt:     &gctrpc.Timestamp{Seconds: job.UpdatedAt.Unix(), Nanos: int32(job.UpdatedAt.Nanosecond())},
+	}
+	if len(job.Recipients) == 0 {
+		return resp
+	}
+	resp.RecipientStatus = make(map[string]int64)
+	resp.Recipients = make([]*gctrpc.TransferJobRecipient, len(job.Recipients))
+	for i := range job.Recipients {
+		resp.RecipientStatus[job.Recipients[i].Status]++
+		resp.Recipients[i] = transferJobRecipientToRPC(&job.Recipients[i], job.Source(&job.Recipients[i]))
+	}
+	return resp
+}
+
+func transferJobRecipientToRPC(r *transferjob.Recipient, source string) *gctrpc.TransferJobRecipient {
+	return &gctrpc.TransferJobRecipient{
+		RowIndex:      int64(r.RowIndex),
+		Address:       r.Address,
+		Amount:        r.Amount.InexactFloat64(),
+		Memo:          r.Memo,
+		Label:         r.Label,
+		BatchIndex:    int64(r.BatchIndex),
+		Signature:     r.Signature,
+		Status:        r.Status,
+		Error:         r.Error,
+		SourceAddress: source,
+	}
+}
+
+func transferScheduleToRPC(schedule *transferschedule.Schedule) *gctrpc.TransferSchedule {
+	resp := &gctrpc.TransferSchedule{
+		Id:              schedule.ID,
+		Name:            schedule.Name,
+		Kind:            schedule.Kind,
+		SourceAddress:   schedule.SourceAddress,
+		TokenMint:       schedule.TokenMint,
+		Cron:            schedule.Cron,
+		IntervalSeconds: int64(schedule.Interval / time.Second),
+		Amount:          schedule.Amount,
+		CatchUp:         schedule.CatchUp,
+		PartialPay:      schedule.PartialPay,
+		UseLookupTables: schedule.UseLookupTables,
+		Duplicates:      schedule.Duplicates,
+		Paused:          schedule.Paused,
+		NextRunAt:       &gctrpc.Timestamp{Seconds: schedule.NextRunAt.Unix(), Nanos: int32(schedule.NextRunAt.Nanosecond())},
+		LastJobId:       schedule.LastJobID,
+		LastError:       schedule.LastError,
+		CreatedAt:       &gctrpc.Timestamp{Seconds: schedule.CreatedAt.Unix(), Nanos: int32(schedule.CreatedAt.Nanosecond())},
+		UpdatedAt:       &gctrpc.Timestamp{Seconds: schedule.UpdatedAt.Unix(), Nanos: int32(schedule.UpdatedAt.Nanosecond())},
+	}
+	if !schedule.LastRunAt.IsZero() {
+		resp.LastRunAt = &gctrpc.Timestamp{Seconds: schedule.LastRunAt.Unix(), Nanos: int32(schedule.LastRunAt.Nanosecond())}
+	}
+	for i := range schedule.Recipients {
+		r := &schedule.Recipients[i]
+		resp.Recipients = append(resp.Recipients, &gctrpc.TransferRecipient{
+			Address: r.Address,
+			Amount:  r.Amount,
+			Memo:    r.Memo,
+			Label:   r.Label,
+		})
+	}
+	return resp
+}
+
+func wrappedSOLToRPC(result *forward.WrapResult) *gctrpc.WrappedSOLResponse {
+	resp := &gctrpc.WrappedSOLResponse{
+		Account:    result.Account,
+		Lamports:   result.Lamports,
+		Preflight:  preflightToRPC(result.Preflight),
+		Simulation: simulationToRPC(result.Simulation),
+	}
+	if result.Batch != nil {
+		resp.Signature = result.Batch.Signature
+		resp.Status = string(result.Batch.Status)
+	}
+	return resp
+}
+
+func vestingPlanToRPC(plan *vestingplan.Plan, now time.Time) *gctrpc.VestingPlan {
+	progress := vesting.GetProgress(plan, now)
+	resp := &gctrpc.VestingPlan{
+		Id:             plan.ID,
+		AccountId:      int64(plan.AccountID),
+		SourceAddress:  plan.SourceAddress,
+		TokenMint:      plan.TokenMint,
+		TotalAmount:    forward.FromBaseUnits(plan.TotalAmount, plan.Decimals),
+		ReleasedAmount: forward.FromBaseUnits(plan.ReleasedAmount, plan.Decimals),
+		StartAt:        &gctrpc.Timestamp{Seconds: plan.StartAt.Unix(), Nanos: int32(plan.StartAt.Nanosecond())},
+		CliffSeconds:   int64(plan.Cliff / time.Second),
+		PeriodSeconds:  int64(plan.Period / time.Second),
+		Periods:        int64(plan.Periods),
+		Status:         plan.Status,
+		Vested:         forward.FromBaseUnits(progress.Vested, plan.Decimals),
+		Releasable:     forward.FromBaseUnits(progress.Releasable, plan.Decimals),
+		CreatedAt:      &gctrpc.Timestamp{Seconds: plan.CreatedAt.Unix(), Nanos: int32(plan.CreatedAt.Nanosecond())},
+		UpdatedAt:      &gctrpc.Timestamp{Seconds: plan.UpdatedAt.Unix(), Nanos: int32(plan.UpdatedAt.Nanosecond())},
+	}
+	if !progress.NextVestingAt.IsZero() {
+		resp.NextVestingAt = &gctrpc.Timestamp{Seconds: progress.NextVestingAt.Unix(), Nanos: int32(progress.NextVestingAt.Nanosecond())}
+	}
+	for i := range plan.Releases {
+		resp.Releases = append(resp.Releases, vestingReleaseToRPC(&plan.Releases[i]))
+	}
+	return resp
+}
+
+func vestingReleaseToRPC(release *vestingplan.Release) *gctrpc.VestingRelease {
+	return &gctrpc.VestingRelease{
+		Id:        release.ID,
+		Amount:    forward.FromBaseUnits(release.Amount, release.Decimals),
+		Signature: release.Signature,
+		Status:    release.Status,
+		Error:     release.Error,
+		CreatedAt: &gctrpc.Timestamp{Seconds: release.CreatedAt.Unix(), Nanos: int32(release.CreatedAt.Nanosecond())},
+		UpdatedAt: &gctrpc.Timestamp{Seconds: release.UpdatedAt.Unix(), Nanos: int32(release.UpdatedAt.Nanosecond())},
+	}
+}
+
+func preflightToRPC(p *forward.Preflight) *gctrpc.Preflight {
+	if p == nil {
+		return nil
+	}
+	return &gctrpc.Preflight{
+		Transfers:        p.Transfers,
+		Fees:             p.Fees,
+		PriorityFees:     p.PriorityFees,
+		Rent:             p.Rent,
+		Lamports:         p.Lamports(),
+		LamportBalance:   p.LamportBalance,
+		LamportShortfall: p.LamportShortfall(),
+		Tokens:           p.Tokens,
+		TokenBalance:     p.TokenBalance,
+		TokenShortfall:   p.TokenShortfall(),
+		Decimals:         uint32(p.Decimals),
+		Wrapped:          p.Wrapped(),
+	}
+}
+
+// transferConfig 返回转账请求使用的转发配置
+// 使用地址查找表时每笔交易的指令数量只受交易大小、计算单元和账户锁定数量限制
+func transferConfig(partialPay, useLookupTables bool) *forward.Config {
+	cfg := forward.DefaultConfig()
+	cfg.PartialPay = partialPay
+	if useLookupTables {
+		cfg.UseLookupTables = true
+		cfg.MaxInstructionsPerTx = forward.MaxInstructionsPerLookupTx
+	}
+	return cfg
+}
+
+func simulationToRPC(report *forward.SimulationReport) *gctrpc.SimulationReport {
+	if report == nil {
+		return nil
+	}
+	resp := &gctrpc.SimulationReport{
+		Transactions:     int64(report.Transactions),
+		Instructions:     int64(report.Instructions),
+		AtaCreations:     int64(report.ATACreations),
+		EstimatedFee:     report.EstimatedFee,
+		EstimatedRent:    report.EstimatedRent,
+		ComputeUnits:     report.ComputeUnits,
+		Failed:           int64(report.Failed),
+		Batches:          make([]*gctrpc.BatchSimulation, len(report.Batches)),
+		ComputeUnitPrice: report.ComputeUnitPrice,
+		LookupTables:     int64(report.LookupTables),
+	}
+	for i := range report.Batches {
+		b := &report.Batches[i]
+		resp.Batches[i] = &gctrpc.BatchSimulation{
+			Index:            int64(b.Index),
+			Addresses:        recipientAddresses(b.Recipients),
+			Instructions:     int64(b.Instructions),
+			AtaCreations:     int64(b.ATACreations),
+			EstimatedFee:     b.EstimatedFee,
+			EstimatedRent:    b.EstimatedRent,
+			ComputeUnits:     b.ComputeUnits,
+			ComputeUnitLimit: b.ComputeUnitLimit,
+			Logs:             b.Logs,
+		}
+		if b.Err != nil {
+			resp.Batches[i].Error = b.Err.Error()
+		}
+	}
+	return resp
+}
@@ -31,6 +31,7 @@ var (
 	errNilExchangeManager           = errors.New("cannot start with nil exchange manager")
 	errNilDatabaseConnectionManager = errors.New("cannot start with nil database connection manager")
 	errNilConfig                    = errors.New("received nil config")
+	errNilSigner                    = errors.New("received nil signer")
 )
 
 // iOrderManager defines a limited scoped order manager
@@ -0,0 +1,644 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"gocryptotrader/config"
+	"gocryptotrader/database"
+	"gocryptotrader/database/repository/transferjob"
+	"gocryptotrader/exchanges/forward"
+	"gocryptotrader/exchanges/rpcpool"
+	"gocryptotrader/log"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// SetupTransferJobManager creates a new transfer job manager
+func SetupTransferJobManager(cfg *config.Config, db iDatabaseConnectionManager, pool *rpcpool.Pool, signer forward.Signer) (*TransferJobManager, error) {
+	if cfg == nil {
+		return nil, errNilConfig
+	}
+	if db == nil {
+		return nil, errNilDatabaseConnectionManager
+	}
+	if pool == nil {
+		return nil, rpcpool.ErrNilPool
+	}
+	if signer == nil {
+		return nil, errNilSigner
+	}
+	return &TransferJobManager{
+		db:      db,
+		forward: forward.New(cfg, pool),
+		signer:  signer,
+		cfg:     cfg,
+		running: make(map[string]struct{}),
+	}, nil
+}
+
+// IsRunning safely checks whether the subsystem is running
+func (m *TransferJobManager) IsRunning() bool {
+	if m == nil {
+		return false
+	}
+	return atomic.LoadInt32(&m.started) == 1
+}
+
+// Start marks jobs left running by a previous process as interrupted and
+// starts accepting jobs
+func (m *TransferJobManager) Start() error {
+	if m == nil {
+		return fmt.Errorf("%s %w", TransferJobManagerName, ErrNilSubsystem)
+	}
+	if db := m.db.GetInstance(); db == nil || !db.IsConnected() {
+		return fmt.Errorf("%s %w", TransferJobManagerName, database.ErrDatabaseNotConnected)
+	}
+	if !atomic.CompareAndSwapInt32(&m.started, 0, 1) {
+		return fmt.Errorf("%s %w", TransferJobManagerName, ErrSubSystemAlreadyStarted)
+	}
+
+	jobs, err := transferjob.List(TransferJobStatusRunning, 0)
+	if err != nil {
+		atomic.StoreInt32(&m.started, 0)
+		return err
+	}
+	for i := range jobs {
+		log.Warnf(log.Global, "Transfer job %s was interrupted, it can be resumed", jobs[i].ID)
+		if err := transferjob.UpdateStatus(jobs[i].ID, TransferJobStatusInterrupted, jobs[i].Error); err != nil {
+			log.Errorf(log.Global, "Unable to mark transfer job %s as interrupted: %v", jobs[i].ID, err)
+		}
+	}
+	log.Debugf(log.Global, "%s %s", TransferJobManagerName, MsgSubSystemStarted)
+	return nil
+}
+
+// Stop stops the subsystem
+func (m *TransferJobManager) Stop() error {
+	if m == nil {
+		return fmt.Errorf("%s %w", TransferJobManagerName, ErrNilSubsystem)
+	}
+	if !atomic.CompareAndSwapInt32(&m.started, 1, 0) {
+		return fmt.Errorf("%s %w", TransferJobManagerName, ErrSubSystemNotStarted)
+	}
+	log.Debugf(log.Global, "%s %s", TransferJobManagerName, MsgSubSystemShutdown)
+	return nil
+}
+
+// validateRecipients checks recipients against their on-chain accounts and the
+// configured denylist, the returned report holds the recipients with
+// duplicates handled according to the duplicates policy
+func (m *TransferJobManager) validateRecipients(ctx context.Context, recipients []forward.Recipient, duplicates string, amount float64) (*forward.ValidationReport, error) {
+	opts, err := validationOptions(m.cfg, duplicates, amount)
+	if err != nil {
+		return nil, err
+	}
+	report, err := m.forward.ValidateRecipients(ctx, recipients, opts)
+	if err != nil {
+		return nil, err
+	}
+	return report, report.Err()
+}
+
+// validationOptions 返回接收者校验选项，禁止名单从配置的 denylistFile 读取
+func validationOptions(cfg *config.Config, duplicates string, amount float64) (*forward.ValidationOptions, error) {
+	opts := &forward.ValidationOptions{
+		Duplicates:    forward.DuplicatePolicy(duplicates),
+		DefaultAmount: amount,
+	}
+	if cfg.DenylistFile != "" {
+		var err error
+		if opts.Denylist, err = forward.ReadDenylistFromFile(cfg.DenylistFile); err != nil {
+			return nil, fmt.Errorf("读取禁止名单失败: %w", err)
+		}
+	}
+	return opts, nil
+}
+
+// Submit persists a new transfer job with all of its recipients and executes it
+func (m *TransferJobManager) Submit(ctx context.Context, req *TransferJobRequest) (string, *forward.Result, error) {
+	if !m.IsRunning() {
+		return "", nil, fmt.Errorf("%s %w", TransferJobManagerName, ErrSubSystemNotStarted)
+	}
+	if req.Kind != TransferJobKindSOL && req.Kind != TransferJobKindToken {
+		return "", nil, fmt.Errorf("%w: %s", errUnknownTransferKind, req.Kind)
+	}
+
+	defaultAmount := req.Config.AmountSOL
+	if req.Kind == TransferJobKindToken {
+		defaultAmount = req.Config.Amount
+	}
+	// 数量在创建任务时确定，恢复时不受默认配置变化的影响
+	recipients := forward.ResolveRecipients(req.Recipients, nil, defaultAmount)
+	sources, err := jobSources(req)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(sources) > 1 && len(req.Config.NonceAccounts) > 0 {
+		return "", nil, errFanOutNonce
+	}
+
+	job := &transferjob.Job{
+		Kind:          req.Kind,
+		SourceAddress: sources[0],
+		TokenMint:     req.TokenMint,
+		Status:        TransferJobStatusRunning,
+		MemoMode:      string(req.Config.MemoMode),
+		Reference:     req.Config.Reference,
+		Recipients:    make([]transferjob.Recipient, len(recipients)),
+	}
+	cfg := req.Config
+	if cfg.MemoMode != forward.MemoNone {
+		// 备注在创建任务时确定并随任务保存，未指定时使用任务 ID 和行号，
+		// 链上的备注可以直接对应到任务中的接收者
+		id, err := uuid.NewV4()
+		if err != nil {
+			return "", nil, err
+		}
+		job.ID = id.String()
+		if job.Reference == "" {
+			job.Reference = job.ID
+		}
+		if cfg.MemoMode == forward.MemoRecipient {
+			for i := range recipients {
+				if recipients[i].Memo == "" {
+					recipients[i].Memo = job.ID + ":" + strconv.Itoa(i)
+				}
+			}
+		}
+		c := *cfg
+		c.Reference = job.Reference
+		cfg = &c
+	}
+	for i := range recipients {
+		job.Recipients[i] = transferjob.Recipient{
+			RowIndex:   i,
+			Address:    recipients[i].Address,
+			Amount:     decimal.NewFromFloat(recipients[i].Amount),
+			Memo:       recipients[i].Memo,
+			Label:      recipients[i].Label,
+			BatchIndex: -1,
+			Status:     string(forward.StatusPending),
+		}
+	}
+	if len(sources) > 1 {
+		// 每个接收者记录发送它的源账户，恢复时仍由同一个源账户发送
+		row := 0
+		for i, share := range forward.SplitRecipients(recipients, len(sources)) {
+			for range share {
+				job.Recipients[row].SourceAddress = sources[i]
+				row++
+			}
+		}
+	}
+	if err := transferjob.Insert(job); err != nil {
+		return "", nil, err
+	}
+	for i := range recipients {
+		recipients[i].ID = job.Recipients[i].ID
+	}
+
+	if err := m.lock(job.ID); err != nil {
+		return job.ID, nil, err
+	}
+	defer m.unlock(job.ID)
+
+	result, err := m.execute(ctx, job, recipients, 0, cfg, req.Observer)
+	return job.ID, result, err
+}
+
+// Resume continues a job that failed or was interrupted. Batches that were
+// signed but have no final status are reconciled against the chain first and
+// only recipients that were never paid are sent again.
+func (m *TransferJobManager) Resume(ctx context.Context, id string, cfg *forward.Config) (*forward.Result, error) {
+	if !m.IsRunning() {
+		return nil, fmt.Errorf("%s %w", TransferJobManagerName, ErrSubSystemNotStarted)
+	}
+	if err := m.lock(id); err != nil {
+		return nil, err
+	}
+	defer m.unlock(id)
+
+	job, err := transferjob.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	// 恢复时沿用任务创建时的备注设置，重发的交易与之前的交易带有相同的备注
+	c := *cfg
+	c.MemoMode = forward.MemoMode(job.MemoMode)
+	c.Reference = job.Reference
+	cfg = &c
+
+	// 重建已签名但未确定结果的批次，查询链上状态
+	inFlight := make(map[int]*forward.Batch)
+	var batches []*forward.Batch
+	nextBatch := 0
+	for i := range job.Recipients {
+		r := &job.Recipients[i]
+		if r.BatchIndex >= nextBatch {
+			nextBatch = r.BatchIndex + 1
+		}
+		if r.Status != string(forward.StatusSent) {
+			continue
+		}
+		b, ok := inFlight[r.BatchIndex]
+		if !ok {
+			b = &forward.Batch{
+				Index:                r.BatchIndex,
+				Signature:            r.Signature,
+				LastValidBlockHeight: r.LastValidBlockHeight,
+				NonceAccount:         r.NonceAccount,
+				Nonce:                r.Nonce,
+				Status:               forward.StatusSent,
+			}
+			inFlight[r.BatchIndex] = b
+			batches = append(batches, b)
+		}
+		b.Recipients = append(b.Recipients, forward.Recipient{ID: r.ID, Address: r.Address, Amount: r.Amount.InexactFloat64()})
+	}
+	if err = m.forward.Reconcile(ctx, cfg, batches); err != nil {
+		return nil, err
+	}
+	observer := &jobObserver{m: m, jobID: id}
+	for _, b := range batches {
+		observer.BatchUpdated(b)
+		if b.Status == forward.StatusSent {
+			return nil, fmt.Errorf("%w: batch %d %s", errTransferJobInFlight, b.Index, b.Signature)
+		}
+	}
+
+	// 重新发送从未成功上链的接收者：未发送、发送失败或 blockhash 已过期
+	settled := make(map[int64]forward.Status, len(job.Recipients))
+	for _, b := range batches {
+		for _, r := range b.Recipients {
+			settled[r.ID] = b.Status
+		}
+	}
+	var recipients []forward.Recipient
+	for i := range job.Recipients {
+		r := &job.Recipients[i]
+		status := forward.Status(r.Status)
+		if s, ok := settled[r.ID]; ok {
+			status = s
+		}
+		if resendable(status) {
+			recipients = append(recipients, forward.Recipient{
+				ID:      r.ID,
+				Address: r.Address,
+				Amount:  r.Amount.InexactFloat64(),
+				Memo:    r.Memo,
+				Label:   r.Label,
+			})
+		}
+	}
+
+	if err = transferjob.UpdateStatus(id, TransferJobStatusRunning, ""); err != nil {
+		return nil, err
+	}
+	return m.execute(ctx, job, recipients, nextBatch, cfg, nil)
+}
+
+// GetJob returns a job along with the status of each of its recipients
+func (m *TransferJobManager) GetJob(id string) (*transferjob.Job, error) {
+	if !m.IsRunning() {
+		return nil, fmt.Errorf("%s %w", TransferJobManagerName, ErrSubSystemNotStarted)
+	}
+	return transferjob.GetByID(id)
+}
+
+// ListJobs returns jobs filtered by status, newest first
+func (m *TransferJobManager) ListJobs(status string, limit int) ([]transferjob.Job, error) {
+	if !m.IsRunning() {
+		return nil, fmt.Errorf("%s %w", TransferJobManagerName, ErrSubSystemNotStarted)
+	}
+	return transferjob.List(status, limit)
+}
+
+// FindTransfers looks up recipients in the transfer history by transaction
+// signature and/or memo. A memo matches either a recipient memo or the
+// reference of the job.
+func (m *TransferJobManager) FindTransfers(signature, memo string) ([]transferjob.Transfer, error) {
+	if !m.IsRunning() {
+		return nil, fmt.Errorf("%s %w", TransferJobManagerName, ErrSubSystemNotStarted)
+	}
+	return transferjob.FindTransfers(signature, memo)
+}
+
+// execute sends the given recipients of a job and records the final job status
+func (m *TransferJobManager) execute(ctx context.Context, job *transferjob.Job, recipients []forward.Recipient, batchOffset int, cfg *forward.Config, next forward.Observer) (*forward.Result, error) {
+	result, err := m.send(ctx, job, recipients, batchOffset, cfg, next)
+
+	status := TransferJobStatusCompleted
+	if err != nil {
+		status = TransferJobStatusFailed
+	}
+	if result != nil {
+		if len(result.Skipped) > 0 {
+			skipped := make([]transferjob.Recipient, len(result.Skipped))
+			for i := range result.Skipped {
+				skipped[i] = transferjob.Recipient{
+					ID:         result.Skipped[i].ID,
+					BatchIndex: -1,
+					Status:     string(forward.StatusSkipped),
+				}
+			}
+			m.dbMtx.Lock()
+			if uErr := transferjob.UpdateRecipients(skipped); uErr != nil {
+				log.Errorf(log.Global, "Unable to record skipped recipients for transfer job %s: %v", job.ID, uErr)
+			}
+			m.dbMtx.Unlock()
+		}
+		for _, b := range result.Batches {
+			if resendable(b.Status) {
+				status = TransferJobStatusFailed
+				break
+			}
+			if b.Status == forward.StatusSent {
+				status = TransferJobStatusUnconfirmed
+			}
+		}
+		if len(result.Unpaid) > 0 {
+			// 部分支付时未支付的接收者保持 pending，补足余额后可以恢复
+			status = TransferJobStatusFailed
+		}
+	} else if len(recipients) > 0 {
+		status = TransferJobStatusFailed
+	}
+
+	var errMsg string
+	if err != nil {
+		errMsg = err.Error()
+	}
+	if uErr := transferjob.UpdateStatus(job.ID, status, errMsg); uErr != nil {
+		log.Errorf(log.Global, "Unable to update transfer job %s status: %v", job.ID, uErr)
+	}
+	return result, err
+}
+
+// send transfers to the recipients from their sources. A job split across
+// several sources sends from each of them concurrently and merges the results.
+func (m *TransferJobManager) send(ctx context.Context, job *transferjob.Job, recipients []forward.Recipient, batchOffset int, cfg *forward.Config, next forward.Observer) (*forward.Result, error) {
+	if len(recipients) == 0 {
+		return &forward.Result{}, nil
+	}
+	sourceOf := make(map[int64]string, len(job.Recipients))
+	for i := range job.Recipients {
+		sourceOf[job.Recipients[i].ID] = job.Source(&job.Recipients[i])
+	}
+	var sources []string
+	groups := make(map[string][]forward.Recipient)
+	for i := range recipients {
+		source, ok := sourceOf[recipients[i].ID]
+		if !ok {
+			source = job.SourceAddress
+		}
+		if _, ok = groups[source]; !ok {
+			sources = append(sources, source)
+		}
+		groups[source] = append(groups[source], recipients[i])
+	}
+	if len(sources) == 1 {
+		return m.sendFrom(ctx, job, sources[0], recipients, batchOffset, cfg, next)
+	}
+
+	// 每个批次至少包含一个接收者，按接收者数量为各源账户预留互不重叠的批次序号
+	results := make([]*forward.Result, len(sources))
+	errs := make([]error, len(sources))
+	var wg sync.WaitGroup
+	offset := batchOffset
+	for i, source := range sources {
+		wg.Add(1)
+		go func(offset int) {
+			defer wg.Done()
+			results[i], errs[i] = m.sendFrom(ctx, job, source, groups[source], offset, cfg, next)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("source %s: %w", source, errs[i])
+			}
+			if results[i] != nil {
+				for _, b := range results[i].Batches {
+					b.Index += offset
+				}
+			}
+		}(offset)
+		offset += len(groups[source])
+	}
+	wg.Wait()
+	return forward.MergeResults(results), errors.Join(errs...)
+}
+
+// sendFrom transfers to the recipients from a single source account
+func (m *TransferJobManager) sendFrom(ctx context.Context, job *transferjob.Job, source string, recipients []forward.Recipient, batchOffset int, cfg *forward.Config, next forward.Observer) (*forward.Result, error) {
+	observer := &jobObserver{m: m, jobID: job.ID, batchOffset: batchOffset, next: next}
+	switch job.Kind {
+	case TransferJobKindSOL:
+		return m.forward.TransferSOL(ctx, &forward.ForwardRequest{
+			From:       source,
+			Signer:     m.signer,
+			Recipients: recipients,
+			Config:     cfg,
+			Observer:   observer,
+		})
+	case TransferJobKindToken:
+		return m.forward.TransferToken(ctx, &forward.TokenForwardRequest{
+			From:       source,
+			Signer:     m.signer,
+			TokenMint:  job.TokenMint,
+			Recipients: recipients,
+			Config:     cfg,
+			Observer:   observer,
+		})
+	}
+	return nil, fmt.Errorf("%w: %s", errUnknownTransferKind, job.Kind)
+}
+
+// TopUp funds the sources of a job that is split across several sources.
+// Each source is sent the difference between its balance and its share of the
+// transfers plus the reserve, through ordinary transfer jobs from the treasury
+// which must confirm before the job itself is submitted. It returns the IDs of
+// the top up jobs, none when every source already holds enough.
+func (m *TransferJobManager) TopUp(ctx context.Context, req *TransferJobRequest) ([]string, error) {
+	if !m.IsRunning() {
+		return nil, fmt.Errorf("%s %w", TransferJobManagerName, ErrSubSystemNotStarted)
+	}
+	if req.Treasury == "" {
+		return nil, errNoTreasury
+	}
+	if req.Kind != TransferJobKindSOL && req.Kind != TransferJobKindToken {
+		return nil, fmt.Errorf("%w: %s", errUnknownTransferKind, req.Kind)
+	}
+	sources, err := jobSources(req)
+	if err != nil {
+		return nil, err
+	}
+	var mint string
+	defaultAmount := req.Config.AmountSOL
+	if req.Kind == TransferJobKindToken {
+		mint = req.TokenMint
+		defaultAmount = req.Config.Amount
+	}
+	balances, err := m.forward.Balances(ctx, sources, mint)
+	if err != nil {
+		return nil, err
+	}
+
+	// 各源账户的份额与 Submit 拆分接收者的方式相同
+	recipients := forward.ResolveRecipients(req.Recipients, nil, defaultAmount)
+	reserve := decimal.NewFromFloat(req.TopUpReserve)
+	var lamports, tokens []forward.Recipient
+	for i, share := range forward.SplitRecipients(recipients, len(sources)) {
+		total := decimal.Zero
+		for j := range share {
+			total = total.Add(decimal.NewFromFloat(share[j].Amount))
+		}
+		needSOL := reserve
+		if req.Kind == TransferJobKindSOL {
+			needSOL = needSOL.Add(total)
+		} else if short := total.Sub(decimal.NewFromFloat(balances[i].Tokens)).RoundCeil(int32(balances[i].Decimals)); short.IsPositive() {
+			tokens = append(tokens, forward.Recipient{Address: sources[i], Amount: short.InexactFloat64(), Label: "top up"})
+		}
+		if short := needSOL.Sub(decimal.New(int64(balances[i].Lamports), -9)).RoundCeil(9); short.IsPositive() {
+			lamports = append(lamports, forward.Recipient{Address: sources[i], Amount: short.InexactFloat64(), Label: "top up"})
+		}
+	}
+
+	// 充值任务不沿用备注、nonce 账户和部分支付的设置，任何一个源账户未到账都不能开始转账
+	cfg := *req.Config
+	cfg.MemoMode, cfg.Reference, cfg.NonceAccounts, cfg.PartialPay = forward.MemoNone, "", nil, false
+	var jobs []string
+	for _, topUp := range []struct {
+		kind       string
+		recipients []forward.Recipient
+	}{
+		{TransferJobKindToken, tokens},
+		{TransferJobKindSOL, lamports},
+	} {
+		if len(topUp.recipients) == 0 {
+			continue
+		}
+		id, result, err := m.Submit(ctx, &TransferJobRequest{
+			Kind:          topUp.kind,
+			SourceAddress: req.Treasury,
+			TokenMint:     mint,
+			Recipients:    topUp.recipients,
+			Config:        &cfg,
+		})
+		if id != "" {
+			jobs = append(jobs, id)
+		}
+		if err != nil {
+			return jobs, fmt.Errorf("top up job %s: %w", id, err)
+		}
+		for _, b := range result.Batches {
+			if b.Status != forward.StatusConfirmed {
+				return jobs, fmt.Errorf("%w: job %s batch %d %s", errTopUpUnconfirmed, id, b.Index, b.Status)
+			}
+		}
+		log.Infof(log.Global, "Topped up %d sources from treasury %s with transfer job %s", len(topUp.recipients), req.Treasury, id)
+	}
+	return jobs, nil
+}
+
+// jobSources returns the sources of a job, rejecting repeated sources
+func jobSources(req *TransferJobRequest) ([]string, error) {
+	if len(req.Sources) == 0 {
+		return []string{req.SourceAddress}, nil
+	}
+	seen := make(map[string]struct{}, len(req.Sources))
+	for _, source := range req.Sources {
+		if _, ok := seen[source]; ok {
+			return nil, fmt.Errorf("%w: %s", errDuplicateSource, source)
+		}
+		seen[source] = struct{}{}
+	}
+	return req.Sources, nil
+}
+
+// resendable reports whether recipients with the given status were never
+// paid and can safely be sent again
+func resendable(status forward.Status) bool {
+	return status == forward.StatusPending || status == forward.StatusFailed || status == forward.StatusExpired
+}
+
+func (m *TransferJobManager) lock(id string) error {
+	m.m.Lock()
+	defer m.m.Unlock()
+	if _, ok := m.running[id]; ok {
+		return fmt.Errorf("%w: %s", errTransferJobRunning, id)
+	}
+	m.running[id] = struct{}{}
+	return nil
+}
+
+func (m *TransferJobManager) unlock(id string) {
+	m.m.Lock()
+	delete(m.running, id)
+	m.m.Unlock()
+}
+
+// BatchesBuilt passes the batches of the job on to the next observer
+func (o *jobObserver) BatchesBuilt(batches []*forward.Batch) {
+	if next, ok := o.next.(forward.BuildObserver); ok {
+		views := make([]*forward.Batch, len(batches))
+		for i := range batches {
+			views[i] = o.view(batches[i])
+		}
+		next.BatchesBuilt(views)
+	}
+}
+
+// BatchSigned persists the signature before the transaction is broadcast. The
+// next observer is asked first so a batch it stops is never recorded as sent.
+func (o *jobObserver) BatchSigned(b *forward.Batch) error {
+	if o.next != nil {
+		if err := o.next.BatchSigned(o.view(b)); err != nil {
+			return err
+		}
+	}
+	return o.record(b)
+}
+
+// BatchUpdated persists the send or confirmation outcome of a batch
+func (o *jobObserver) BatchUpdated(b *forward.Batch) {
+	if err := o.record(b); err != nil {
+		log.Errorf(log.Global, "Unable to record batch %d of transfer job %s: %v", b.Index, o.jobID, err)
+	}
+	if o.next != nil {
+		o.next.BatchUpdated(o.view(b))
+	}
+}
+
+// view returns a copy of the batch numbered within the whole job rather than
+// within a single execution
+func (o *jobObserver) view(b *forward.Batch) *forward.Batch {
+	c := *b
+	c.Index += o.batchOffset
+	return &c
+}
+
+func (o *jobObserver) record(b *forward.Batch) error {
+	var errMsg string
+	if b.Err != nil {
+		errMsg = b.Err.Error()
+	}
+	rows := make([]transferjob.Recipient, len(b.Recipients))
+	for i := range b.Recipients {
+		rows[i] = transferjob.Recipient{
+			ID:                   b.Recipients[i].ID,
+			BatchIndex:           o.batchOffset + b.Index,
+			Signature:            b.Signature,
+			LastValidBlockHeight: b.LastValidBlockHeight,
+			NonceAccount:         b.NonceAccount,
+			Nonce:                b.Nonce,
+			Status:               string(b.Status),
+			Error:                errMsg,
+		}
+	}
+	o.m.dbMtx.Lock()
+	defer o.m.dbMtx.Unlock()
+	return transferjob.UpdateRecipients(rows)
+}
@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"errors"
+	"sync"
+
+	"gocryptotrader/config"
+	"gocryptotrader/exchanges/forward"
+)
+
+// TransferJobManagerName is an exported subsystem name
+const TransferJobManagerName = "transfer_job_manager"
+
+// 转账任务类型
+const (
+	TransferJobKindSOL   = "sol"
+	TransferJobKindToken = "token"
+)
+
+// 转账任务状态
+const (
+	TransferJobStatusRunning     = "running"     // 正在执行
+	TransferJobStatusCompleted   = "completed"   // 所有交易均已确认，或接收者被跳过
+	TransferJobStatusUnconfirmed = "unconfirmed" // 部分交易在超时前未确认，恢复时重新查询链上状态
+	TransferJobStatusFailed      = "failed"      // 部分接收者未成功转账，可以恢复
+	TransferJobStatusInterrupted = "interrupted" // 执行期间进程退出，可以恢复
+)
+
+var (
+	errTransferJobRunning  = errors.New("transfer job is already running")
+	errTransferJobInFlight = errors.New("transfer job has unconfirmed transactions, retry once they confirm or their blockhash expires or nonce advances")
+	errUnknownTransferKind = errors.New("unknown transfer job kind")
+	errDuplicateSource     = errors.New("transfer job source is listed more than once")
+	errFanOutNonce         = errors.New("durable nonce accounts cannot be used when a job is split across several sources")
+	errNoTreasury          = errors.New("no treasury address to top up the sources from")
+	errTopUpUnconfirmed    = errors.New("top up transfers did not confirm")
+)
+
+// TransferJobManager persists batch transfers so they can be inspected and
+// resumed after a restart without paying any recipient twice
+type TransferJobManager struct {
+	started int32
+	db      iDatabaseConnectionManager
+	forward *forward.Manager
+	signer  forward.Signer
+	cfg     *config.Config
+	// dbMtx serialises progress writes coming from concurrent batch sends
+	dbMtx sync.Mutex
+	m     sync.Mutex
+	// running holds the IDs of jobs currently executing in this process
+	running map[string]struct{}
+}
+
+// TransferJobRequest defines a new transfer job
+type TransferJobRequest struct {
+	Kind          string
+	SourceAddress string
+	TokenMint     string
+	Recipients    []forward.Recipient
+	Config        *forward.Config
+	// Sources splits the recipients in list order across several managed
+	// accounts which send concurrently, each signed with its own key.
+	// SourceAddress is the only source when empty.
+	Sources []string
+	// Treasury funds the sources before the job is submitted, see TopUp
+	Treasury string
+	// TopUpReserve is the SOL each source should hold for fees and rent on
+	// top of its share of the transfers
+	TopUpReserve float64
+	// Observer is optionally notified of batch progress once it is recorded
+	Observer forward.Observer
+}
+
+// jobObserver records batch progress of a single job execution
+type jobObserver struct {
+	m           *TransferJobManager
+	jobID       string
+	batchOffset int
+	// next is notified after the job records each change
+	next forward.Observer
+}
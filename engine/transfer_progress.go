@@ -0,0 +1,152 @@
+package engine
+
+import (
+	"context"
+	"sync"
+
+	"gocryptotrader/exchanges/forward"
+	"gocryptotrader/gctrpc"
+
+	"github.com/shopspring/decimal"
+)
+
+// 流式转账的进度事件，批次状态变化时事件名为批次的新状态
+const (
+	TransferProgressBuilt = "built" // 批次已构建，等待发送
+	TransferProgressDone  = "done"  // 转账结束，事件带有转账结果
+)
+
+// transferProgress streams the progress of a transfer to a gRPC client with
+// running totals. Once the client goes away no new batches are sent, while
+// batches already sent are still tracked to their outcome.
+type transferProgress struct {
+	ctx  context.Context
+	send func(*gctrpc.TransferProgress) error
+
+	m       sync.Mutex
+	stopped bool
+	batches map[int]*progressBatch
+	totals  progressTotals
+}
+
+// progressTotals are the running totals over all batches
+type progressTotals struct {
+	batches, sent, confirmed, failed int64
+	recipients, confirmedRecipients  int64
+	amount, confirmedAmount          decimal.Decimal
+}
+
+// progressBatch is the last known state of a single batch
+type progressBatch struct {
+	status     forward.Status
+	recipients int64
+	amount     decimal.Decimal
+}
+
+// newTransferProgress creates a progress observer sending events with send
+// until ctx is done
+func newTransferProgress(ctx context.Context, send func(*gctrpc.TransferProgress) error) *transferProgress {
+	return &transferProgress{
+		ctx:     ctx,
+		send:    send,
+		batches: make(map[int]*progressBatch),
+	}
+}
+
+// BatchesBuilt reports each batch as built before any of them is sent
+func (p *transferProgress) BatchesBuilt(batches []*forward.Batch) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	for _, b := range batches {
+		p.update(b)
+		p.emit(TransferProgressBuilt, b)
+	}
+}
+
+// BatchSigned stops the batch from being sent once the client has gone away
+func (p *transferProgress) BatchSigned(*forward.Batch) error {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.stopped || p.ctx.Err() != nil {
+		p.stopped = true
+		return forward.ErrStopped
+	}
+	return nil
+}
+
+// BatchUpdated reports the new status of a batch
+func (p *transferProgress) BatchUpdated(b *forward.Batch) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.update(b)
+	p.emit(string(b.Status), b)
+}
+
+// finish sends the final event carrying the transfer result
+func (p *transferProgress) finish(event *gctrpc.TransferProgress) error {
+	p.m.Lock()
+	defer p.m.Unlock()
+	event.Event = TransferProgressDone
+	p.fillTotals(event)
+	return p.send(event)
+}
+
+// update moves the batch to its new status in the running totals
+func (p *transferProgress) update(b *forward.Batch) {
+	pb, ok := p.batches[b.Index]
+	if !ok {
+		pb = &progressBatch{recipients: int64(len(b.Recipients))}
+		for i := range b.Recipients {
+			pb.amount = pb.amount.Add(decimal.NewFromFloat(b.Recipients[i].Amount))
+		}
+		p.batches[b.Index] = pb
+		p.totals.batches++
+		p.totals.recipients += pb.recipients
+		p.totals.amount = p.totals.amount.Add(pb.amount)
+	} else {
+		p.count(pb, -1)
+	}
+	pb.status = b.Status
+	p.count(pb, 1)
+}
+
+// count adds or removes a batch from the totals of its status
+func (p *transferProgress) count(pb *progressBatch, delta int64) {
+	switch pb.status {
+	case forward.StatusSent:
+		p.totals.sent += delta
+	case forward.StatusConfirmed:
+		p.totals.confirmed += delta
+		p.totals.confirmedRecipients += delta * pb.recipients
+		p.totals.confirmedAmount = p.totals.confirmedAmount.Add(pb.amount.Mul(decimal.NewFromInt(delta)))
+	case forward.StatusFailed, forward.StatusExpired:
+		p.totals.failed += delta
+	}
+}
+
+// emit sends a batch event, a client that can no longer be reached stops the transfer
+func (p *transferProgress) emit(event string, b *forward.Batch) {
+	if p.stopped {
+		return
+	}
+	resp := &gctrpc.TransferProgress{
+		Event: event,
+		Batch: transferBatchesToRPC([]*forward.Batch{b})[0],
+	}
+	p.fillTotals(resp)
+	if err := p.send(resp); err != nil {
+		p.stopped = true
+	}
+}
+
+// fillTotals copies the running totals into an event
+func (p *transferProgress) fillTotals(event *gctrpc.TransferProgress) {
+	event.Batches = p.totals.batches
+	event.SentBatches = p.totals.sent
+	event.ConfirmedBatches = p.totals.confirmed
+	event.FailedBatches = p.totals.failed
+	event.Recipients = p.totals.recipients
+	event.ConfirmedRecipients = p.totals.confirmedRecipients
+	event.Amount = p.totals.amount.InexactFloat64()
+	event.ConfirmedAmount = p.totals.confirmedAmount.InexactFloat64()
+}
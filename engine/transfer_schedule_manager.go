@@ -0,0 +1,407 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gocryptotrader/common"
+	"gocryptotrader/common/cron"
+	"gocryptotrader/database/repository/transferschedule"
+	"gocryptotrader/exchanges/forward"
+	"gocryptotrader/log"
+)
+
+// SetupTransferScheduleManager creates a new transfer schedule manager
+func SetupTransferScheduleManager(jobs *TransferJobManager, delay time.Duration) (*TransferScheduleManager, error) {
+	if jobs == nil {
+		return nil, errNilTransferJobManager
+	}
+	if delay <= 0 {
+		delay = defaultTransferScheduleDelay
+	}
+	return &TransferScheduleManager{
+		delay:   delay,
+		jobs:    jobs,
+		running: make(map[string]struct{}),
+	}, nil
+}
+
+// IsRunning safely checks whether the subsystem is running
+func (m *TransferScheduleManager) IsRunning() bool {
+	if m == nil {
+		return false
+	}
+	return atomic.LoadInt32(&m.started) == 1
+}
+
+// Start starts checking for due schedules
+func (m *TransferScheduleManager) Start(wg *sync.WaitGroup) error {
+	if wg == nil {
+		return fmt.Errorf("%T %w", wg, common.ErrNilPointer)
+	}
+	if m == nil {
+		return fmt.Errorf("%s %w", TransferScheduleManagerName, ErrNilSubsystem)
+	}
+	if !m.jobs.IsRunning() {
+		return fmt.Errorf("%s %w", TransferJobManagerName, ErrSubSystemNotStarted)
+	}
+	if !atomic.CompareAndSwapInt32(&m.started, 0, 1) {
+		return fmt.Errorf("%s %w", TransferScheduleManagerName, ErrSubSystemAlreadyStarted)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.shutdown = make(chan struct{})
+	m.cancel = cancel
+	wg.Add(1)
+	m.wg.Add(1)
+	go m.run(ctx, wg)
+	log.Debugf(log.Global, "%s %s", TransferScheduleManagerName, MsgSubSystemStarted)
+	return nil
+}
+
+// Stop stops checking for due schedules and cancels runs in progress, their
+// transfer jobs are left failed or interrupted and can be resumed
+func (m *TransferScheduleManager) Stop() error {
+	if m == nil {
+		return fmt.Errorf("%s %w", TransferScheduleManagerName, ErrNilSubsystem)
+	}
+	if !atomic.CompareAndSwapInt32(&m.started, 1, 0) {
+		return fmt.Errorf("%s %w", TransferScheduleManagerName, ErrSubSystemNotStarted)
+	}
+	close(m.shutdown)
+	m.cancel()
+	m.wg.Wait()
+	log.Debugf(log.Global, "%s %s", TransferScheduleManagerName, MsgSubSystemShutdown)
+	return nil
+}
+
+// CreateSchedule validates and stores a new schedule. Its first run is the
+// next cron match, or one interval from now.
+func (m *TransferScheduleManager) CreateSchedule(s *transferschedule.Schedule) error {
+	if !m.IsRunning() {
+		return fmt.Errorf("%s %w", TransferScheduleManagerName, ErrSubSystemNotStarted)
+	}
+	if err := validateSchedule(s); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	next, err := nextRunAfter(s, now, now)
+	if err != nil {
+		return err
+	}
+	s.NextRunAt = next
+	s.LastRunAt, s.LastJobID, s.LastError = time.Time{}, "", ""
+	return transferschedule.Insert(s)
+}
+
+// UpdateSchedule replaces the definition and recipients of a schedule. The
+// next run is recalculated when the cron expression or interval changes.
+func (m *TransferScheduleManager) UpdateSchedule(s *transferschedule.Schedule) (*transferschedule.Schedule, error) {
+	if !m.IsRunning() {
+		return nil, fmt.Errorf("%s %w", TransferScheduleManagerName, ErrSubSystemNotStarted)
+	}
+	if err := validateSchedule(s); err != nil {
+		return nil, err
+	}
+	existing, err := transferschedule.GetByID(s.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing.Name = s.Name
+	existing.Kind = s.Kind
+	existing.SourceAddress = s.SourceAddress
+	existing.TokenMint = s.TokenMint
+	existing.Amount = s.Amount
+	existing.CatchUp = s.CatchUp
+	existing.PartialPay = s.PartialPay
+	existing.UseLookupTables = s.UseLookupTables
+	existing.Duplicates = s.Duplicates
+	existing.Recipients = s.Recipients
+	if existing.Cron != s.Cron || existing.Interval != s.Interval {
+		existing.Cron, existing.Interval = s.Cron, s.Interval
+		now := time.Now().UTC()
+		if existing.NextRunAt, err = nextRunAfter(existing, now, now); err != nil {
+			return nil, err
+		}
+	}
+	if err = transferschedule.Update(existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// GetSchedule returns a schedule along with its recipients
+func (m *TransferScheduleManager) GetSchedule(id string) (*transferschedule.Schedule, error) {
+	if !m.IsRunning() {
+		return nil, fmt.Errorf("%s %w", TransferScheduleManagerName, ErrSubSystemNotStarted)
+	}
+	return transferschedule.GetByID(id)
+}
+
+// ListSchedules returns all schedules without their recipients
+func (m *TransferScheduleManager) ListSchedules() ([]transferschedule.Schedule, error) {
+	if !m.IsRunning() {
+		return nil, fmt.Errorf("%s %w", TransferScheduleManagerName, ErrSubSystemNotStarted)
+	}
+	return transferschedule.List()
+}
+
+// DeleteSchedule removes a schedule, transfer jobs it already started are
+// kept
+func (m *TransferScheduleManager) DeleteSchedule(id string) error {
+	if !m.IsRunning() {
+		return fmt.Errorf("%s %w", TransferScheduleManagerName, ErrSubSystemNotStarted)
+	}
+	return transferschedule.Delete(id)
+}
+
+// PauseSchedule stops a schedule from starting new runs
+func (m *TransferScheduleManager) PauseSchedule(id string) (*transferschedule.Schedule, error) {
+	return m.setPaused(id, true)
+}
+
+// ResumeSchedule lets a paused schedule run again. Runs that fell due while
+// it was paused are not caught up, the next run is the first one from now.
+func (m *TransferScheduleManager) ResumeSchedule(id string) (*transferschedule.Schedule, error) {
+	return m.setPaused(id, false)
+}
+
+func (m *TransferScheduleManager) setPaused(id string, paused bool) (*transferschedule.Schedule, error) {
+	if !m.IsRunning() {
+		return nil, fmt.Errorf("%s %w", TransferScheduleManagerName, ErrSubSystemNotStarted)
+	}
+	s, err := transferschedule.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if s.Paused == paused {
+		return s, nil
+	}
+	s.Paused = paused
+	if now := time.Now().UTC(); !paused && !s.NextRunAt.After(now) {
+		if s.NextRunAt, err = nextRunAfter(s, s.NextRunAt, now); err != nil {
+			return nil, err
+		}
+	}
+	if err = transferschedule.Update(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (m *TransferScheduleManager) run(ctx context.Context, wg *sync.WaitGroup) {
+	t := time.NewTicker(m.delay)
+	defer func() {
+		t.Stop()
+		m.wg.Done()
+		wg.Done()
+	}()
+
+	m.checkSchedules(ctx)
+	for {
+		select {
+		case <-m.shutdown:
+			return
+		case <-t.C:
+			m.checkSchedules(ctx)
+		}
+	}
+}
+
+// checkSchedules starts every due schedule that has no run in progress
+func (m *TransferScheduleManager) checkSchedules(ctx context.Context) {
+	schedules, err := transferschedule.List()
+	if err != nil {
+		log.Errorf(log.Global, "Unable to load transfer schedules: %v", err)
+		return
+	}
+	now := time.Now().UTC()
+	for i := range schedules {
+		s := &schedules[i]
+		if s.Paused || s.NextRunAt.After(now) || !m.tryLock(s.ID) {
+			continue
+		}
+		m.wg.Add(1)
+		go func(id string) {
+			defer m.wg.Done()
+			defer m.unlock(id)
+			if err := m.trigger(ctx, id, now); err != nil {
+				log.Errorf(log.Global, "Transfer schedule %s run failed: %v", id, err)
+			}
+		}(s.ID)
+	}
+}
+
+// trigger runs a due schedule once according to its catch up rule. The next
+// run is stored before the transfer job is submitted so a restart during the
+// run never pays the same occurrence twice, an interrupted job is resumed
+// through the transfer job manager instead.
+func (m *TransferScheduleManager) trigger(ctx context.Context, id string, now time.Time) error {
+	s, err := transferschedule.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if s.Paused || s.NextRunAt.After(now) {
+		// 列出计划之后被暂停或修改
+		return nil
+	}
+
+	due := s.NextRunAt
+	missed := now.Sub(due) > m.delay+missedRunGrace
+	var next time.Time
+	if missed && s.CatchUp != TransferScheduleCatchUpAll {
+		next, err = nextRunAfter(s, due, now)
+	} else {
+		// 准时执行，或者逐次补齐：下次执行时间可能仍已到期，下一轮检查时继续执行
+		next, err = nextRunAfter(s, due, due)
+	}
+	if err != nil {
+		return err
+	}
+
+	if missed && s.CatchUp == TransferScheduleCatchUpSkip {
+		log.Warnf(log.Global, "Transfer schedule %s missed its run at %s, skipping to %s", id, due.Format(time.RFC3339), next.Format(time.RFC3339))
+		return transferschedule.UpdateRun(id, s.LastRunAt, next, s.LastJobID, fmt.Sprintf("missed run at %s was skipped", due.Format(time.RFC3339)))
+	}
+	if err = transferschedule.UpdateRun(id, now, next, "", ""); err != nil {
+		return err
+	}
+
+	log.Infof(log.Global, "Transfer schedule %s running for %s, next run at %s", id, due.Format(time.RFC3339), next.Format(time.RFC3339))
+	req := scheduleJobRequest(s)
+	// 禁止名单和接收者的链上账户可能在创建计划后发生变化，每次执行前重新校验
+	var jobID string
+	validation, err := m.jobs.validateRecipients(ctx, req.Recipients, s.Duplicates, defaultAmount(req.Config, s.Kind))
+	if err == nil {
+		req.Recipients = validation.Recipients
+		jobID, _, err = m.jobs.Submit(ctx, req)
+	}
+	var errMsg string
+	if err != nil {
+		errMsg = err.Error()
+	}
+	if uErr := transferschedule.UpdateRun(id, now, next, jobID, errMsg); uErr != nil {
+		log.Errorf(log.Global, "Unable to record run of transfer schedule %s: %v", id, uErr)
+	}
+	return transferJobError(jobID, err)
+}
+
+func (m *TransferScheduleManager) tryLock(id string) bool {
+	m.m.Lock()
+	defer m.m.Unlock()
+	if _, ok := m.running[id]; ok {
+		return false
+	}
+	m.running[id] = struct{}{}
+	return true
+}
+
+func (m *TransferScheduleManager) unlock(id string) {
+	m.m.Lock()
+	delete(m.running, id)
+	m.m.Unlock()
+}
+
+// validateSchedule checks a schedule definition and defaults its catch up
+// rule
+func validateSchedule(s *transferschedule.Schedule) error {
+	if s.Kind != TransferJobKindSOL && s.Kind != TransferJobKindToken {
+		return fmt.Errorf("%w: %s", errUnknownTransferKind, s.Kind)
+	}
+	if s.SourceAddress == "" {
+		return errScheduleSourceUnset
+	}
+	if s.Kind == TransferJobKindToken && s.TokenMint == "" {
+		return errScheduleTokenMint
+	}
+	if (s.Cron == "") == (s.Interval == 0) {
+		return errScheduleTiming
+	}
+	if s.Cron != "" {
+		if _, err := cron.Parse(s.Cron); err != nil {
+			return err
+		}
+	} else if s.Interval < minScheduleInterval {
+		return errScheduleInterval
+	}
+
+	switch s.CatchUp {
+	case "":
+		s.CatchUp = TransferScheduleCatchUpOnce
+	case TransferScheduleCatchUpSkip, TransferScheduleCatchUpOnce, TransferScheduleCatchUpAll:
+	default:
+		return fmt.Errorf("%w: %s", errUnknownCatchUp, s.CatchUp)
+	}
+
+	if s.Amount < 0 {
+		return fmt.Errorf("%w: amount %v", errInvalidArguments, s.Amount)
+	}
+	if len(s.Recipients) == 0 {
+		return errNoScheduleRecipients
+	}
+	return forward.CheckRecipients(scheduleRecipients(s))
+}
+
+// nextRunAfter returns the first run of a schedule that follows from and is
+// later than after. Interval schedules keep their phase relative to from.
+func nextRunAfter(s *transferschedule.Schedule, from, after time.Time) (time.Time, error) {
+	if s.Cron != "" {
+		c, err := cron.Parse(s.Cron)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if from.After(after) {
+			after = from
+		}
+		next := c.Next(after.UTC())
+		if next.IsZero() {
+			return time.Time{}, fmt.Errorf("%w: %s", errScheduleNeverRuns, s.Cron)
+		}
+		return next, nil
+	}
+
+	if s.Interval < minScheduleInterval {
+		return time.Time{}, errScheduleInterval
+	}
+	next := from.Add(s.Interval)
+	if !next.After(after) {
+		next = from.Add((after.Sub(from)/s.Interval + 1) * s.Interval)
+	}
+	return next.UTC(), nil
+}
+
+// scheduleJobRequest builds the transfer job for one run of a schedule
+func scheduleJobRequest(s *transferschedule.Schedule) *TransferJobRequest {
+	cfg := transferConfig(s.PartialPay, s.UseLookupTables)
+	if s.Amount > 0 {
+		if s.Kind == TransferJobKindToken {
+			cfg.Amount = s.Amount
+		} else {
+			cfg.AmountSOL = s.Amount
+		}
+	}
+	return &TransferJobRequest{
+		Kind:          s.Kind,
+		SourceAddress: s.SourceAddress,
+		TokenMint:     s.TokenMint,
+		Recipients:    scheduleRecipients(s),
+		Config:        cfg,
+	}
+}
+
+func scheduleRecipients(s *transferschedule.Schedule) []forward.Recipient {
+	recipients := make([]forward.Recipient, len(s.Recipients))
+	for i := range s.Recipients {
+		recipients[i] = forward.Recipient{
+			Address: s.Recipients[i].Address,
+			Amount:  s.Recipients[i].Amount,
+			Memo:    s.Recipients[i].Memo,
+			Label:   s.Recipients[i].Label,
+		}
+	}
+	return recipients
+}
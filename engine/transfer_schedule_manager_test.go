@@ -0,0 +1,128 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gocryptotrader/config"
+	"gocryptotrader/database"
+	"gocryptotrader/database/drivers"
+	"gocryptotrader/database/repository/transferschedule"
+	"gocryptotrader/database/testhelpers"
+	"gocryptotrader/exchanges/forward"
+	"gocryptotrader/exchanges/rpcpool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	testhelpers.TempDir, err = os.MkdirTemp("", "gct-temp")
+	if err != nil {
+		fmt.Printf("failed to create temp file: %v", err)
+		os.Exit(1)
+	}
+	testhelpers.MigrationDir = filepath.Join("..", "database", "migrations")
+
+	t := m.Run()
+
+	err = os.RemoveAll(testhelpers.TempDir)
+	if err != nil {
+		fmt.Printf("Failed to remove temp db file: %v", err)
+	}
+
+	os.Exit(t)
+}
+
+// emptyAccountsServer answers getMultipleAccounts as if none of the accounts
+// exist yet
+func emptyAccountsServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method != "getMultipleAccounts" || len(req.Params) == 0 {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		var keys []string
+		if err := json.Unmarshal(req.Params[0], &keys); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  map[string]any{"context": map[string]any{"slot": 1}, "value": make([]any, len(keys))},
+		})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestUpdateScheduleDuplicates(t *testing.T) {
+	dbConn, err := testhelpers.ConnectToDatabase(&database.Config{
+		Driver:            database.DBSQLite3,
+		ConnectionDetails: drivers.ConnectionDetails{Database: "transferschedule.db"},
+	})
+	require.NoError(t, err, "ConnectToDatabase must not error")
+	defer func() {
+		assert.NoError(t, testhelpers.CloseDatabase(dbConn))
+	}()
+
+	pool, err := rpcpool.New(&config.SolanaConfig{Endpoints: []config.SolanaRPCEndpoint{{Name: "mock", URL: emptyAccountsServer(t).URL}}})
+	require.NoError(t, err)
+	cfg := &config.Config{}
+	// 转账任务管理器未启动，校验通过的执行在提交任务时失败
+	jobs := &TransferJobManager{cfg: cfg, forward: forward.New(cfg, pool), running: make(map[string]struct{})}
+	m, err := SetupTransferScheduleManager(jobs, time.Minute)
+	require.NoError(t, err)
+	m.started = 1
+
+	s := &transferschedule.Schedule{
+		Name:          "payroll",
+		Kind:          TransferJobKindSOL,
+		SourceAddress: "7mXrxq4A6LJKsdHDCvj4PCdF3tvQs8rdUKFWLdEicxXW",
+		Interval:      time.Hour,
+		Amount:        0.5,
+		Recipients: []transferschedule.Recipient{
+			{Address: "9W8kkYe6wE9VWJRiwqQMwC3536mMei7KfG7oWiSNbNBb", Amount: 1},
+			{Address: "9W8kkYe6wE9VWJRiwqQMwC3536mMei7KfG7oWiSNbNBb", Amount: 2},
+		},
+	}
+	require.NoError(t, m.CreateSchedule(s), "CreateSchedule must not error")
+
+	got, err := m.GetSchedule(s.ID)
+	require.NoError(t, err, "GetSchedule must not error")
+	err = m.trigger(context.Background(), s.ID, got.NextRunAt)
+	assert.ErrorContains(t, err, string(forward.IssueDuplicate), "duplicate recipients must be rejected by default")
+
+	update := *s
+	update.Recipients = append([]transferschedule.Recipient(nil), s.Recipients...)
+	update.Duplicates = string(forward.DuplicateSum)
+	updated, err := m.UpdateSchedule(&update)
+	require.NoError(t, err, "UpdateSchedule must not error")
+	assert.Equal(t, string(forward.DuplicateSum), updated.Duplicates)
+
+	got, err = m.GetSchedule(s.ID)
+	require.NoError(t, err, "GetSchedule must not error")
+	assert.Equal(t, string(forward.DuplicateSum), got.Duplicates, "UpdateSchedule must store the duplicate policy")
+	// 合并重复地址后校验通过，执行在提交任务时才失败
+	err = m.trigger(context.Background(), s.ID, got.NextRunAt)
+	assert.ErrorIs(t, err, ErrSubSystemNotStarted, "the updated duplicate policy must be used by the next run")
+	got, err = m.GetSchedule(s.ID)
+	require.NoError(t, err, "GetSchedule must not error")
+	assert.NotContains(t, got.LastError, string(forward.IssueDuplicate))
+}
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// TransferScheduleManagerName is an exported subsystem name
+const TransferScheduleManagerName = "transfer_schedule_manager"
+
+const (
+	// defaultTransferScheduleDelay 检查到期计划的默认间隔
+	defaultTransferScheduleDelay = 30 * time.Second
+	// missedRunGrace 计划到期后超过检查间隔加上该时长仍未执行即视为错过
+	missedRunGrace = time.Minute
+	// minScheduleInterval 固定间隔计划的最小间隔
+	minScheduleInterval = time.Minute
+)
+
+// 错过执行时间（例如进程停止期间）后的补偿规则
+const (
+	TransferScheduleCatchUpSkip = "skip" // 丢弃错过的执行，等待下一个执行时间
+	TransferScheduleCatchUpOnce = "once" // 错过的多次执行合并为一次立即执行
+	TransferScheduleCatchUpAll  = "all"  // 依次补齐每一次错过的执行
+)
+
+var (
+	errNilTransferJobManager = errors.New("cannot start with nil transfer job manager")
+	errScheduleTiming        = errors.New("exactly one of cron and interval must be set")
+	errScheduleInterval      = errors.New("schedule interval must be at least one minute")
+	errUnknownCatchUp        = errors.New("unknown schedule catch up rule")
+	errNoScheduleRecipients  = errors.New("schedule has no recipients")
+	errScheduleTokenMint     = errors.New("token schedules require a token mint")
+	errScheduleSourceUnset   = errors.New("schedule source address unset")
+	errScheduleNeverRuns     = errors.New("cron expression has no upcoming run")
+)
+
+// TransferScheduleManager starts transfer jobs for stored schedules when they
+// are due. Each run is a regular transfer job and can be inspected and
+// resumed through the transfer job manager.
+type TransferScheduleManager struct {
+	started  int32
+	delay    time.Duration
+	jobs     *TransferJobManager
+	shutdown chan struct{}
+	cancel   func()
+	wg       sync.WaitGroup
+	m        sync.Mutex
+	// running holds the IDs of schedules with a run in progress
+	running map[string]struct{}
+}
@@ -1,18 +1,25 @@
 package account
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
-	"database/sql"
 	"encoding/base64"
 	"encoding/pem"
+	"errors"
 	"fmt"
-	"io/ioutil"
-	"reflect"
+	"os"
 
 	"gocryptotrader/config"
 	accountsql "gocryptotrader/database/repository/account"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	errKeyMismatch       = errors.New("私钥与账户地址不匹配")
+	errMigrationMismatch = errors.New("重新加密后的密文解密结果与原文不一致")
 )
 
 // Manager 管理账户相关操作
@@ -27,286 +34,219 @@ func New(cfg *config.Config) *Manager {
 
 // Accounts 获取所有账户信息
 func (m *Manager) Accounts() ([]*Account, error) {
-	accounts, err := accountsql.GetAccount("", 0)
+	return m.AccountsByFilter(nil)
+}
+
+// AccountsByFilter 获取符合筛选条件的账户，f 为 nil 时返回所有账户
+func (m *Manager) AccountsByFilter(f *Filter) ([]*Account, error) {
+	accounts, err := accountsql.List(f)
 	if err != nil {
 		return nil, fmt.Errorf("获取账户列表失败: %w", err)
 	}
-
-	// 检查返回类型并进行适当的转换
-	switch v := accounts.(type) {
-	case []*Account:
-		// 如果已经是正确的类型，直接返回
-		return v, nil
-	case interface{}:
-		// 尝试从反射获取切片中的每个元素并转换
-		return convertToAccountSlice(v)
-	default:
-		return nil, fmt.Errorf("无法转换账户数据类型：未知类型 %T", accounts)
-	}
+	return accounts, nil
 }
 
-// convertToAccountSlice 将接口类型转换为[]*Account
-func convertToAccountSlice(data interface{}) ([]*Account, error) {
-	var result []*Account
-
-	sliceValue := reflect.ValueOf(data)
-	if sliceValue.Kind() != reflect.Slice {
-		return nil, fmt.Errorf("无法转换账户数据类型：不是切片类型")
-	}
-
-	// 遍历切片中的每个元素
-	for i := 0; i < sliceValue.Len(); i++ {
-		item := sliceValue.Index(i).Interface()
-
-		// 尝试将每个元素转换为map并创建Account对象
-		itemMap, ok := item.(map[string]interface{})
-		if !ok {
-			// 如果不是map，尝试直接转换
-			accObj, ok := item.(*Account)
-			if !ok {
-				// 如果无法直接转换，尝试使用反射获取字段值
-				accObj = convertStructToAccount(item)
-				if accObj == nil {
-					continue
-				}
-			}
-			result = append(result, accObj)
-			continue
-		}
-
-		// 从map创建Account对象
-		accObj := mapToAccount(itemMap)
-		result = append(result, accObj)
+// CountAccounts 返回符合筛选条件的账户总数，忽略分页参数
+func (m *Manager) CountAccounts(f *Filter) (int64, error) {
+	total, err := accountsql.Count(f)
+	if err != nil {
+		return 0, fmt.Errorf("统计账户数量失败: %w", err)
 	}
-
-	return result, nil
+	return total, nil
 }
 
-// convertStructToAccount 使用反射将结构体转换为Account
-func convertStructToAccount(item interface{}) *Account {
-	itemValue := reflect.ValueOf(item)
-	if itemValue.Kind() == reflect.Ptr {
-		itemValue = itemValue.Elem()
+// GetAccountByAddress 根据地址获取账户信息
+func (m *Manager) GetAccountByAddress(address string) (*Account, error) {
+	account, err := accountsql.GetByAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("获取地址信息失败: %w", err)
 	}
+	return account, nil
+}
 
-	if itemValue.Kind() != reflect.Struct {
-		return nil
+// GetAccountByID 根据 ID 获取账户信息
+func (m *Manager) GetAccountByID(id int) (*Account, error) {
+	account, err := accountsql.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("获取账户信息失败: %w", err)
 	}
+	return account, nil
+}
 
-	// 创建新的Account对象
-	accObj := &Account{}
-
-	// 尝试获取并设置字段值
-	if f := itemValue.FieldByName("ID"); f.IsValid() {
-		accObj.ID = int(f.Int())
-	}
-	if f := itemValue.FieldByName("Name"); f.IsValid() {
-		accObj.Name = f.String()
-	}
-	if f := itemValue.FieldByName("Address"); f.IsValid() {
-		accObj.Address = f.String()
-	}
-	if f := itemValue.FieldByName("ExchangeAddressID"); f.IsValid() {
-		if nullStr, ok := f.Interface().(sql.NullString); ok {
-			if nullStr.Valid {
-				accObj.ExchangeAddressID = nullStr.String
-			}
-		}
-	}
-	if f := itemValue.FieldByName("ZkAddressID"); f.IsValid() {
-		if nullStr, ok := f.Interface().(sql.NullString); ok {
-			if nullStr.Valid {
-				accObj.ZkAddressID = nullStr.String
-			}
-		}
-	}
-	if f := itemValue.FieldByName("F4AddressID"); f.IsValid() {
-		if nullStr, ok := f.Interface().(sql.NullString); ok {
-			if nullStr.Valid {
-				accObj.F4AddressID = nullStr.String
-			}
-		}
-	}
-	if f := itemValue.FieldByName("OTAddressID"); f.IsValid() {
-		if nullStr, ok := f.Interface().(sql.NullString); ok {
-			if nullStr.Valid {
-				accObj.OTAddressID = nullStr.String
-			}
+// UpdateAccount 更新账户的名称、层级、所有者、所属链、上级账户和关联地址，地址和密文不能修改。
+// 更新后账户与其上级账户和下级账户的关联都必须仍然有效
+func (m *Manager) UpdateAccount(a *Account) error {
+	if a.ParentID != 0 {
+		parent, err := m.GetAccountByID(a.ParentID)
+		if err != nil {
+			return err
 		}
-	}
-	if f := itemValue.FieldByName("Cipher"); f.IsValid() {
-		if nullStr, ok := f.Interface().(sql.NullString); ok {
-			if nullStr.Valid {
-				accObj.Cipher = nullStr.String
-			}
+		if err := ValidateLink(parent, a); err != nil {
+			return err
 		}
 	}
-	if f := itemValue.FieldByName("Layer"); f.IsValid() {
-		accObj.Layer = int(f.Int())
-	}
-	if f := itemValue.FieldByName("Owner"); f.IsValid() {
-		if nullStr, ok := f.Interface().(sql.NullString); ok {
-			if nullStr.Valid {
-				accObj.Owner = nullStr.String
-			}
-		}
+	children, err := m.Children(a.ID)
+	if err != nil {
+		return err
 	}
-	if f := itemValue.FieldByName("ChainName"); f.IsValid() {
-		if nullStr, ok := f.Interface().(sql.NullString); ok {
-			if nullStr.Valid {
-				accObj.ChainName = nullStr.String
-			}
+	for _, child := range children {
+		if err := ValidateLink(a, child); err != nil {
+			return fmt.Errorf("下级账户 %s: %w", child.Address, err)
 		}
 	}
-
-	return accObj
-}
-
-// mapToAccount 将map转换为Account
-func mapToAccount(itemMap map[string]interface{}) *Account {
-	accObj := &Account{}
-
-	if v, ok := itemMap["id"].(int); ok {
-		accObj.ID = v
-	}
-	if v, ok := itemMap["name"].(string); ok {
-		accObj.Name = v
-	}
-	if v, ok := itemMap["address"].(string); ok {
-		accObj.Address = v
-	}
-	if v, ok := itemMap["exchange_address_id"].(string); ok {
-		accObj.ExchangeAddressID = v
-	}
-	if v, ok := itemMap["zk_address_id"].(string); ok {
-		accObj.ZkAddressID = v
-	}
-	if v, ok := itemMap["f4_address_id"].(string); ok {
-		accObj.F4AddressID = v
-	}
-	if v, ok := itemMap["ot_address_id"].(string); ok {
-		accObj.OTAddressID = v
-	}
-	if v, ok := itemMap["cipher"].(string); ok {
-		accObj.Cipher = v
+	if err := accountsql.Update(a); err != nil {
+		return fmt.Errorf("更新账户失败: %w", err)
 	}
-	if v, ok := itemMap["layer"].(int); ok {
-		accObj.Layer = v
-	}
-	if v, ok := itemMap["owner"].(string); ok {
-		accObj.Owner = v
-	}
-	if v, ok := itemMap["chain_name"].(string); ok {
-		accObj.ChainName = v
-	}
-
-	return accObj
+	return nil
 }
 
-// GetAccountByAddress 根据地址获取账户信息
-func (m *Manager) GetAccountByAddress(address string) (*Account, error) {
-	account, err := accountsql.GetAccountByAddress(address)
-	if err != nil {
-		return nil, fmt.Errorf("获取地址信息失败: %w", err)
-	}
-
-	// 尝试将interface{}转换为*Account
-	accountObj, ok := account.(*Account)
-	if ok {
-		return accountObj, nil
+// DeleteAccount 删除没有下级账户的账户，账户私钥的密文会一同删除且无法恢复
+func (m *Manager) DeleteAccount(id int) error {
+	if err := checkNoChildren(id); err != nil {
+		return err
 	}
-
-	// 使用反射进行转换
-	result := convertStructToAccount(account)
-	if result == nil {
-		return nil, fmt.Errorf("无法转换账户数据类型: %T", account)
+	if err := accountsql.Delete(id); err != nil {
+		return fmt.Errorf("删除账户失败: %w", err)
 	}
-
-	return result, nil
+	return nil
 }
 
-func (m *Manager) Crypto(ciphertestStr string) (string, error) {
-	// 从配置中获取私钥文件路径
-	privateKeyFile, err := ioutil.ReadFile(m.config.SolisDbPem)
+// Encrypt 使用信封加密保护账户私钥的分片，密文与账户地址绑定
+func (m *Manager) Encrypt(address, plaintext string) (string, error) {
+	masterKey, err := ReadMasterKey(m.config.MasterKeyFile)
 	if err != nil {
-		fmt.Println("Error opening private key file:", err)
 		return "", err
 	}
+	return sealEnvelope(masterKey, m.config.CipherAlgorithm, address, []byte(plaintext))
+}
 
-	block, _ := pem.Decode(privateKeyFile)
-	if block == nil || block.Type != "RSA PRIVATE KEY" {
-		fmt.Println("Invalid private key file")
-		return "", err
+// Decrypt 解密账户的密文，同时支持信封密文和迁移前的 RSA 密文
+func (m *Manager) Decrypt(address, ciphertext string) (string, error) {
+	if !IsEnvelope(ciphertext) {
+		return m.decryptLegacy(ciphertext)
 	}
-
-	parsedPrivateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	masterKey, err := ReadMasterKey(m.config.MasterKeyFile)
 	if err != nil {
-		fmt.Println("Error parsing private key:", err)
 		return "", err
 	}
-	// 待加密的原文
-	plaintext := ciphertestStr
-	// 加密
-	ciphertext, err := rsa.EncryptPKCS1v15(rand.Reader, &parsedPrivateKey.PublicKey, []byte(plaintext))
+	plaintext, err := openEnvelope(masterKey, address, ciphertext)
 	if err != nil {
-		fmt.Println("Error encrypting:", err)
 		return "", err
 	}
-
-	// 将密文转换为字符串形式
-	ciphertextStr := base64.StdEncoding.EncodeToString(ciphertext)
-	return ciphertextStr, nil
-
+	return string(plaintext), nil
 }
 
-func (m *Manager) Decrypt(ciphertextStr string) (string, error) {
-	// 从配置中获取私钥文件路径
-	privateKeyFile, err := ioutil.ReadFile(m.config.SolisDbPem)
+// decryptLegacy 使用 SolisDbPem 中的 RSA 私钥解密迁移前的 PKCS#1 v1.5 密文
+func (m *Manager) decryptLegacy(ciphertextStr string) (string, error) {
+	privateKeyFile, err := os.ReadFile(m.config.SolisDbPem)
 	if err != nil {
-		fmt.Println("Error opening private key file:", err)
-		return "", fmt.Errorf("error opening private key file:", err)
+		return "", fmt.Errorf("error opening private key file: %w", err)
 	}
 
 	block, _ := pem.Decode(privateKeyFile)
 	if block == nil || block.Type != "RSA PRIVATE KEY" {
-		fmt.Println("Invalid private key file")
 		return "", fmt.Errorf("invalid private key file")
 	}
 
 	parsedPrivateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
 	if err != nil {
-		fmt.Println("Error parsing private key:", err)
-		return "", fmt.Errorf("error parsing private key:", err)
+		return "", fmt.Errorf("error parsing private key: %w", err)
 	}
 
 	// 解密
 	decodedCiphertext, err := base64.StdEncoding.DecodeString(ciphertextStr)
 	if err != nil {
-		fmt.Println("Error decoding ciphertext:", err)
-		return "", fmt.Errorf("error decoding ciphertext:", err)
+		return "", fmt.Errorf("error decoding ciphertext: %w", err)
 	}
 	decryptedPlaintext, err := rsa.DecryptPKCS1v15(rand.Reader, parsedPrivateKey, decodedCiphertext)
 	if err != nil {
-		fmt.Println("Error decrypting:", err)
-		return "", fmt.Errorf("error decrypting:", err)
+		return "", fmt.Errorf("error decrypting: %w", err)
 	}
 	return string(decryptedPlaintext), nil
 }
 
-func (m *Manager) PrivateKey(address string) (string, error) {
+// privateKey 返回账户的私钥，结果必须与账户地址匹配。新建和导入的账户保存完整私钥，
+// 旧账户只保存分片，由配置中的 SubKey 和解密后的分片拼接而成。私钥只在签名时短暂存在，不会离开 Manager
+func (m *Manager) privateKey(address string) (solana.PrivateKey, error) {
 	account, err := m.GetAccountByAddress(address)
 	if err != nil {
-		return "", fmt.Errorf("获取账户信息失败: %w", err)
+		return nil, fmt.Errorf("获取账户信息失败: %w", err)
 	}
 
-	if account == nil {
-		return "", fmt.Errorf("未找到地址为 %s 的账户", address)
+	share, err := m.Decrypt(account.Address, account.Cipher)
+	if err != nil {
+		return nil, fmt.Errorf("解密失败: %w", err)
 	}
 
-	Cipher, err := m.Decrypt(account.Cipher)
+	key, err := solana.PrivateKeyFromBase58(share)
+	if err != nil {
+		key, err = solana.PrivateKeyFromBase58(m.config.SubKey + share)
+	}
 	if err != nil {
-		return "", fmt.Errorf("解密失败: %w", err)
+		return nil, fmt.Errorf("%w: %s 的私钥格式错误", errKeyMismatch, address)
+	}
+	if key.PublicKey().String() != account.Address {
+		return nil, fmt.Errorf("%w: %s", errKeyMismatch, address)
 	}
+	return key, nil
+}
 
-	return m.config.SubKey + Cipher, nil
+// Sign 实现 forward.Signer，作为进程内的密钥库使用账户私钥对消息签名，签名后立即清除私钥
+func (m *Manager) Sign(_ context.Context, account solana.PublicKey, message []byte) (solana.Signature, error) {
+	key, err := m.privateKey(account.String())
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	defer clear(key)
+	return key.Sign(message)
+}
+
+// MigrateCiphers 将所有账户的 RSA 密文重新加密为信封密文并原地更新。
+// 每个新密文在写入前都会解密校验；dryRun 时只校验不写入。已迁移的账户会被跳过，可以重复执行
+func (m *Manager) MigrateCiphers(dryRun bool) (*CipherMigration, error) {
+	masterKey, err := ReadMasterKey(m.config.MasterKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := m.Accounts()
+	if err != nil {
+		return nil, err
+	}
+	report := &CipherMigration{Total: len(accounts), DryRun: dryRun}
+	for _, a := range accounts {
+		if a.Cipher == "" || IsEnvelope(a.Cipher) {
+			report.Skipped++
+			continue
+		}
+		if err := m.migrateCipher(masterKey, a, dryRun); err != nil {
+			report.Failures = append(report.Failures, CipherMigrationFailure{Address: a.Address, Err: err})
+			continue
+		}
+		report.Migrated++
+	}
+	return report, nil
+}
+
+// migrateCipher 重新加密单个账户的密文
+func (m *Manager) migrateCipher(masterKey []byte, a *Account, dryRun bool) error {
+	share, err := m.decryptLegacy(a.Cipher)
+	if err != nil {
+		return err
+	}
+	sealed, err := sealEnvelope(masterKey, m.config.CipherAlgorithm, a.Address, []byte(share))
+	if err != nil {
+		return err
+	}
+	opened, err := openEnvelope(masterKey, a.Address, sealed)
+	if err != nil {
+		return err
+	}
+	if string(opened) != share {
+		return errMigrationMismatch
+	}
+	if dryRun {
+		return nil
+	}
+	// 只在密文未被修改时更新，避免覆盖并发写入的新密文
+	return accountsql.UpdateCipher(a.ID, a.Cipher, sealed)
 }
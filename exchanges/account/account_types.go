@@ -1,21 +1,75 @@
 package account
 
 import (
-	"time"
+	accountsql "gocryptotrader/database/repository/account"
 )
 
-type Account struct {
-	ID                int    `gorm:"id"`
-	Name              string `gorm:"name"`
-	Address           string `gorm:"address"`
-	ExchangeAddressID string `gorm:"exchange_address_id"`
-	ZkAddressID       string `gorm:"zk_address_id"`
-	F4AddressID       string `gorm:"f4_address_id"`
-	OTAddressID       string `gorm:"ot_address_id"`
-	Cipher            string `gorm:"cipher"`
-	Layer             int    `gorm:"layer"`
-	Owner             string `gorm:"owner"`
-	ChainName         string `gorm:"chain_name"`
-	CreatedAt         time.Time
-	UpdatedAt         time.Time
+// Account 账户信息，与数据库中的账户一致
+type Account = accountsql.Account
+
+// Filter 筛选账户的条件和分页参数
+type Filter = accountsql.Filter
+
+// CreateRequest 生成新账户的请求，设置助记词时按派生路径依次派生，否则随机生成密钥
+type CreateRequest struct {
+	Name       string // 账户名称，生成多个账户时依次加上 -1、-2 等序号
+	Owner      string // 账户所有者
+	Layer      int    // 账户层级
+	ChainName  string // 所属链，默认为 DefaultChainName
+	Count      int    // 生成数量，默认为 1
+	Mnemonic   string // BIP39 助记词
+	Passphrase string // 助记词的 BIP39 密码
+	StartIndex uint32 // 第一个账户的派生索引，之后的账户索引依次递增
+	ParentID   int    // 上级账户的 ID，设置时未指定的所有者、所属链和层级沿用上级账户
+}
+
+// ImportRequest 导入已有私钥的请求
+type ImportRequest struct {
+	Name       string // 账户名称
+	Owner      string // 账户所有者
+	Layer      int    // 账户层级
+	ChainName  string // 所属链，默认为 DefaultChainName
+	PrivateKey string // base58 编码的私钥，或 solana-keygen 密钥文件中的 JSON 字节数组
+	ParentID   int    // 上级账户的 ID，设置时未指定的所有者、所属链和层级沿用上级账户
+}
+
+// CreatedAccount 新建或导入的账户
+type CreatedAccount struct {
+	Account
+	DerivationPath string // 从助记词派生时的路径
+}
+
+// Tree 一个所有者的账户树，例如第 0 层的金库为第 1 层的分发账户充值，分发账户再为第 2 层的终端钱包充值
+type Tree struct {
+	Owner   string      // 所有者
+	Roots   []*Node     // 第 0 层的根节点
+	Invalid []LinkError // 上下级关联无效的账户，连同其下级账户一起列出
+}
+
+// Node 账户树中的一个账户
+type Node struct {
+	Account  *Account
+	Parent   *Node   // 上级账户，根节点和关联无效的账户为 nil
+	Children []*Node // 下级账户
+}
+
+// LinkError 上下级关联无效的账户及原因
+type LinkError struct {
+	Node *Node
+	Err  error
+}
+
+// CipherMigration 汇总一次密文迁移的结果
+type CipherMigration struct {
+	Total    int                      // 账户总数
+	Migrated int                      // 重新加密的账户数，dryRun 时为校验通过的数量
+	Skipped  int                      // 已是信封密文或没有密文的账户数
+	Failures []CipherMigrationFailure // 迁移失败的账户，保持原密文不变
+	DryRun   bool                     // 为 true 时未写入数据库
+}
+
+// CipherMigrationFailure 单个账户的迁移失败原因
+type CipherMigrationFailure struct {
+	Address string
+	Err     error
 }
@@ -0,0 +1,130 @@
+package account
+
+import (
+	"errors"
+	"fmt"
+
+	accountsql "gocryptotrader/database/repository/account"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// DefaultChainName 未指定所属链时新账户使用的链名称
+const DefaultChainName = "solana"
+
+// MaxCreateCount 单次请求最多生成的账户数量
+const MaxCreateCount = 1000
+
+var (
+	errNameRequired = errors.New("账户名称不能为空")
+	errInvalidCount = fmt.Errorf("生成数量必须在 1 到 %d 之间", MaxCreateCount)
+)
+
+// CreateAccounts 生成 req.Count 个账户，私钥以信封加密后写入 accounts 表。
+// 所有账户在同一个事务中写入，任一地址已存在时不写入任何账户
+func (m *Manager) CreateAccounts(req *CreateRequest) ([]*CreatedAccount, error) {
+	if req.Name == "" {
+		return nil, errNameRequired
+	}
+	count := req.Count
+	if count == 0 {
+		count = 1
+	}
+	if count < 0 || count > MaxCreateCount {
+		return nil, errInvalidCount
+	}
+	if req.Mnemonic != "" && uint64(req.StartIndex)+uint64(count) > hardenedOffset {
+		return nil, fmt.Errorf("%w: %d", errIndexOutOfBounds, uint64(req.StartIndex)+uint64(count)-1)
+	}
+
+	keys := make([]solana.PrivateKey, count)
+	defer func() {
+		for _, key := range keys {
+			clear(key)
+		}
+	}()
+	created := make([]*CreatedAccount, count)
+	for i := range keys {
+		var err error
+		a := &CreatedAccount{Account: Account{
+			Name:      req.Name,
+			Owner:     req.Owner,
+			Layer:     req.Layer,
+			ChainName: req.ChainName,
+			ParentID:  req.ParentID,
+		}}
+		if count > 1 {
+			a.Name = fmt.Sprintf("%s-%d", req.Name, i+1)
+		}
+		if req.Mnemonic != "" {
+			index := req.StartIndex + uint32(i) //nolint:gosec // 已校验不超过 hardenedOffset
+			a.DerivationPath = DerivationPath(index)
+			keys[i], err = DeriveKey(req.Mnemonic, req.Passphrase, index)
+		} else {
+			keys[i], err = solana.NewRandomPrivateKey()
+		}
+		if err != nil {
+			return nil, err
+		}
+		created[i] = a
+	}
+	if err := m.insert(created, keys); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// ImportAccount 导入已有的私钥，私钥以信封加密后写入 accounts 表
+func (m *Manager) ImportAccount(req *ImportRequest) (*CreatedAccount, error) {
+	if req.Name == "" {
+		return nil, errNameRequired
+	}
+	key, err := ParsePrivateKey(req.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(key)
+	a := &CreatedAccount{Account: Account{
+		Name:      req.Name,
+		Owner:     req.Owner,
+		Layer:     req.Layer,
+		ChainName: req.ChainName,
+		ParentID:  req.ParentID,
+	}}
+	if err := m.insert([]*CreatedAccount{a}, []solana.PrivateKey{key}); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// insert 加密账户的完整私钥并在一个事务中写入，成功后设置账户的 ID、地址、密文和时间
+func (m *Manager) insert(accounts []*CreatedAccount, keys []solana.PrivateKey) error {
+	parents := make(map[int]*Account)
+	rows := make([]*Account, len(accounts))
+	for i, a := range accounts {
+		a.Address = keys[i].PublicKey().String()
+		if a.ParentID != 0 {
+			parent, ok := parents[a.ParentID]
+			if !ok {
+				var err error
+				if parent, err = m.GetAccountByID(a.ParentID); err != nil {
+					return err
+				}
+				parents[a.ParentID] = parent
+			}
+			if err := linkParent(parent, &a.Account); err != nil {
+				return err
+			}
+		}
+		if a.ChainName == "" {
+			a.ChainName = DefaultChainName
+		}
+		cipher, err := m.Encrypt(a.Address, keys[i].String())
+		if err != nil {
+			return fmt.Errorf("加密 %s 的私钥失败: %w", a.Address, err)
+		}
+		a.Cipher = cipher
+		rows[i] = &a.Account
+	}
+	return accountsql.Insert(rows)
+}
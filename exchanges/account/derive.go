@@ -0,0 +1,107 @@
+package account
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// mnemonicEntropyBits 生成 24 个单词的助记词
+const mnemonicEntropyBits = 256
+
+// solanaCoinType SLIP-0044 中 Solana 的币种编号
+const solanaCoinType = 501
+
+const hardenedOffset = 0x80000000
+
+var (
+	errInvalidMnemonic  = errors.New("助记词无效")
+	errInvalidKey       = errors.New("私钥格式错误，需要 base58 字符串或 64 字节的 JSON 数组")
+	errKeyPairMismatch  = errors.New("私钥中的公钥与私钥不匹配")
+	errIndexOutOfBounds = errors.New("派生索引超出范围")
+)
+
+// NewMnemonic 生成 24 个单词的 BIP39 助记词
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(mnemonicEntropyBits)
+	if err != nil {
+		return "", fmt.Errorf("生成助记词失败: %w", err)
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// DerivationPath 返回第 index 个账户的 BIP44 派生路径 m/44'/501'/index'/0'
+func DerivationPath(index uint32) string {
+	return fmt.Sprintf("m/44'/%d'/%d'/0'", solanaCoinType, index)
+}
+
+// DeriveKey 按 m/44'/501'/index'/0' 从 BIP39 助记词派生私钥。
+// ed25519 只支持强化派生，派生方式遵循 SLIP-0010，与 solana-keygen 和常用钱包一致
+func DeriveKey(mnemonic, passphrase string, index uint32) (solana.PrivateKey, error) {
+	if index >= hardenedOffset {
+		return nil, fmt.Errorf("%w: %d", errIndexOutOfBounds, index)
+	}
+	seed, err := bip39.NewSeedWithErrorChecking(strings.Join(strings.Fields(mnemonic), " "), passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidMnemonic, err)
+	}
+	defer clear(seed)
+	return deriveEd25519(seed, 44, solanaCoinType, index, 0), nil
+}
+
+// deriveEd25519 按 SLIP-0010 从种子沿强化路径派生 ed25519 私钥
+func deriveEd25519(seed []byte, path ...uint32) solana.PrivateKey {
+	mac := hmac.New(sha512.New, []byte("ed25519 seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := sum[:32], sum[32:]
+
+	data := make([]byte, 1+32+4)
+	for _, index := range path {
+		copy(data[1:], key)
+		binary.BigEndian.PutUint32(data[33:], index|hardenedOffset)
+		mac = hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		next := mac.Sum(nil)
+		clear(sum)
+		sum = next
+		key, chainCode = sum[:32], sum[32:]
+	}
+	defer clear(sum)
+	defer clear(data)
+	return solana.PrivateKey(ed25519.NewKeyFromSeed(key))
+}
+
+// ParsePrivateKey 解析 base58 编码的私钥，或 solana-keygen 密钥文件中的 JSON 字节数组
+func ParsePrivateKey(s string) (solana.PrivateKey, error) {
+	s = strings.TrimSpace(s)
+	var key solana.PrivateKey
+	if strings.HasPrefix(s, "[") {
+		// solana-keygen 的密钥文件是 64 个数字组成的数组
+		if err := json.Unmarshal([]byte(s), &key); err != nil {
+			return nil, errInvalidKey
+		}
+		if err := key.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidKey, err)
+		}
+	} else {
+		var err error
+		if key, err = solana.PrivateKeyFromBase58(s); err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidKey, err)
+		}
+	}
+	// 64 字节私钥的后半部分是公钥，必须与种子计算出的公钥一致
+	if !bytes.Equal(ed25519.NewKeyFromSeed(key[:32]), key) {
+		return nil, errKeyPairMismatch
+	}
+	return key, nil
+}
@@ -0,0 +1,89 @@
+package account
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestDeriveEd25519(t *testing.T) {
+	t.Parallel()
+	// SLIP-0010 ed25519 测试向量 1
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	require.NoError(t, err)
+	key := deriveEd25519(seed)
+	assert.Equal(t, "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7", hex.EncodeToString(key[:32]))
+	key = deriveEd25519(seed, 0)
+	assert.Equal(t, "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3", hex.EncodeToString(key[:32]))
+	assert.Equal(t, "8c8a13df77a28f3445213a0f432fde644acaa215fc72dcdf300d5efaa85d350c", hex.EncodeToString(key[32:]))
+}
+
+func TestDeriveKey(t *testing.T) {
+	t.Parallel()
+	key, err := DeriveKey(testMnemonic, "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "HAgk14JpMQLgt6rVgv7cBQFJWFto5Dqxi472uT3DKpqk", key.PublicKey().String())
+	assert.Equal(t, "m/44'/501'/0'/0'", DerivationPath(0))
+
+	again, err := DeriveKey("  "+strings.ReplaceAll(testMnemonic, " ", "\n")+" ", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, key, again, "whitespace in the mnemonic must not change the key")
+
+	next, err := DeriveKey(testMnemonic, "", 1)
+	require.NoError(t, err)
+	assert.NotEqual(t, key.PublicKey(), next.PublicKey())
+	withPassphrase, err := DeriveKey(testMnemonic, "secret", 0)
+	require.NoError(t, err)
+	assert.NotEqual(t, key.PublicKey(), withPassphrase.PublicKey())
+
+	_, err = DeriveKey(strings.Replace(testMnemonic, "about", "abandon", 1), "", 0)
+	assert.ErrorIs(t, err, errInvalidMnemonic, "a mnemonic with a bad checksum must be rejected")
+	_, err = DeriveKey(testMnemonic, "", hardenedOffset)
+	assert.ErrorIs(t, err, errIndexOutOfBounds)
+
+	mnemonic, err := NewMnemonic()
+	require.NoError(t, err)
+	assert.Len(t, strings.Fields(mnemonic), 24)
+	_, err = DeriveKey(mnemonic, "", 0)
+	assert.NoError(t, err)
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	t.Parallel()
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+
+	got, err := ParsePrivateKey(" " + key.String() + "\n")
+	require.NoError(t, err)
+	assert.Equal(t, key, got)
+
+	// solana-keygen 的密钥文件是数字数组
+	ints := make([]int, len(key))
+	for i := range key {
+		ints[i] = int(key[i])
+	}
+	keypair, err := json.Marshal(ints)
+	require.NoError(t, err)
+	got, err = ParsePrivateKey(string(keypair))
+	require.NoError(t, err)
+	assert.Equal(t, key, got)
+
+	_, err = ParsePrivateKey(key.PublicKey().String())
+	assert.ErrorIs(t, err, errInvalidKey)
+	_, err = ParsePrivateKey("[1,2,3]")
+	assert.ErrorIs(t, err, errInvalidKey)
+
+	other, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	mismatched := append(solana.PrivateKey{}, key[:32]...)
+	mismatched = append(mismatched, other.PublicKey().Bytes()...)
+	_, err = ParsePrivateKey(mismatched.String())
+	assert.ErrorIs(t, err, errKeyPairMismatch)
+}
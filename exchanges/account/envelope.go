@@ -0,0 +1,161 @@
+package account
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// 信封加密使用的算法
+const (
+	CipherAES256GCM         = "aes-256-gcm"
+	CipherXChaCha20Poly1305 = "xchacha20-poly1305"
+)
+
+// envelopeVersion 信封密文的版本前缀，旧的 RSA 密文是不含冒号的 base64 字符串
+const envelopeVersion = "env1"
+
+const masterKeySize = 32
+
+var (
+	errNoMasterKey        = errors.New("未配置主密钥文件")
+	errMalformedMasterKey = errors.New("主密钥必须是 32 字节的 hex 或 base64 编码")
+	errMalformedEnvelope  = errors.New("信封密文格式错误")
+	errUnknownCipher      = errors.New("未知的加密算法")
+	errAddressRequired    = errors.New("加密时必须指定账户地址")
+)
+
+// IsEnvelope 判断密文是否为信封格式，否则为旧的 RSA 密文
+func IsEnvelope(ciphertext string) bool {
+	return strings.HasPrefix(ciphertext, envelopeVersion+":")
+}
+
+// ReadMasterKey 读取主密钥文件，文件内容为 32 字节密钥的 hex 或 base64 编码
+func ReadMasterKey(filePath string) ([]byte, error) {
+	if filePath == "" {
+		return nil, errNoMasterKey
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取主密钥文件失败: %w", err)
+	}
+	encoded := strings.TrimSpace(string(data))
+	if key, err := hex.DecodeString(encoded); err == nil && len(key) == masterKeySize {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(encoded); err == nil && len(key) == masterKeySize {
+		return key, nil
+	}
+	return nil, errMalformedMasterKey
+}
+
+// sealEnvelope 使用随机生成的数据密钥加密明文，数据密钥再由主密钥加密。
+// 两层加密都以算法和账户地址作为关联数据，密文无法被挪用到其他账户或换用其他算法解密。
+// 密文格式为 env1:<算法>:<加密的数据密钥>:<加密的明文>，后两段为 nonce 与密文拼接后的 base64 编码
+func sealEnvelope(masterKey []byte, algorithm, address string, plaintext []byte) (string, error) {
+	if address == "" {
+		return "", errAddressRequired
+	}
+	if algorithm == "" {
+		algorithm = CipherAES256GCM
+	}
+	dataKey := make([]byte, masterKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("生成数据密钥失败: %w", err)
+	}
+	wrapped, err := seal(masterKey, algorithm, dataKey, envelopeAAD(algorithm, address, "key"))
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(dataKey, algorithm, plaintext, envelopeAAD(algorithm, address, "data"))
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{
+		envelopeVersion,
+		algorithm,
+		base64.RawStdEncoding.EncodeToString(wrapped),
+		base64.RawStdEncoding.EncodeToString(sealed),
+	}, ":"), nil
+}
+
+// openEnvelope 解密信封密文，地址与加密时不一致时解密失败
+func openEnvelope(masterKey []byte, address, envelope string) ([]byte, error) {
+	parts := strings.Split(envelope, ":")
+	if len(parts) != 4 || parts[0] != envelopeVersion {
+		return nil, errMalformedEnvelope
+	}
+	algorithm := parts[1]
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errMalformedEnvelope, err)
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errMalformedEnvelope, err)
+	}
+	dataKey, err := open(masterKey, algorithm, wrapped, envelopeAAD(algorithm, address, "key"))
+	if err != nil {
+		return nil, fmt.Errorf("解密数据密钥失败: %w", err)
+	}
+	plaintext, err := open(dataKey, algorithm, sealed, envelopeAAD(algorithm, address, "data"))
+	if err != nil {
+		return nil, fmt.Errorf("解密密文失败: %w", err)
+	}
+	return plaintext, nil
+}
+
+// envelopeAAD 返回加密数据密钥或明文时使用的关联数据
+func envelopeAAD(algorithm, address, layer string) []byte {
+	return []byte(envelopeVersion + "/" + algorithm + "/" + layer + "/" + address)
+}
+
+// seal 使用随机 nonce 加密，返回 nonce 与密文的拼接
+func seal(key []byte, algorithm string, plaintext, aad []byte) ([]byte, error) {
+	aead, err := newAEAD(algorithm, key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("生成 nonce 失败: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// open 解密 seal 的输出
+func open(key []byte, algorithm string, sealed, aad []byte) ([]byte, error) {
+	aead, err := newAEAD(algorithm, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize()+aead.Overhead() {
+		return nil, errMalformedEnvelope
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], aad)
+}
+
+func newAEAD(algorithm string, key []byte) (cipher.AEAD, error) {
+	if len(key) != masterKeySize {
+		return nil, errMalformedMasterKey
+	}
+	switch algorithm {
+	case CipherAES256GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	case CipherXChaCha20Poly1305:
+		return chacha20poly1305.NewX(key)
+	}
+	return nil, fmt.Errorf("%w: %s", errUnknownCipher, algorithm)
+}
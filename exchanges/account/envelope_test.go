@@ -0,0 +1,127 @@
+package account
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gocryptotrader/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAddress      = "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"
+	testOtherAddress = "HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH"
+)
+
+func newMasterKey(t *testing.T) []byte {
+	t.Helper()
+	key := make([]byte, masterKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+func TestEnvelope(t *testing.T) {
+	t.Parallel()
+	masterKey := newMasterKey(t)
+	for _, algorithm := range []string{CipherAES256GCM, CipherXChaCha20Poly1305} {
+		sealed, err := sealEnvelope(masterKey, algorithm, testAddress, []byte("share"))
+		require.NoError(t, err, algorithm)
+		assert.True(t, IsEnvelope(sealed))
+		assert.True(t, strings.HasPrefix(sealed, envelopeVersion+":"+algorithm+":"))
+
+		opened, err := openEnvelope(masterKey, testAddress, sealed)
+		require.NoError(t, err, algorithm)
+		assert.Equal(t, "share", string(opened))
+
+		_, err = openEnvelope(masterKey, testOtherAddress, sealed)
+		assert.Error(t, err, "a cipher must not decrypt for another account")
+		_, err = openEnvelope(newMasterKey(t), testAddress, sealed)
+		assert.Error(t, err, "a cipher must not decrypt with another master key")
+
+		tampered := []byte(sealed)
+		tampered[len(tampered)-2] ^= 1
+		_, err = openEnvelope(masterKey, testAddress, string(tampered))
+		assert.Error(t, err, "a tampered cipher must not decrypt")
+	}
+
+	sealed, err := sealEnvelope(masterKey, "", testAddress, []byte("share"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sealed, envelopeVersion+":"+CipherAES256GCM+":"), "aes-256-gcm is the default")
+
+	// 替换算法名称会改变关联数据，不能降级为其他算法解密
+	downgraded := strings.Replace(sealed, CipherAES256GCM, CipherXChaCha20Poly1305, 1)
+	_, err = openEnvelope(masterKey, testAddress, downgraded)
+	assert.Error(t, err)
+
+	_, err = sealEnvelope(masterKey, "rot13", testAddress, []byte("share"))
+	assert.ErrorIs(t, err, errUnknownCipher)
+	_, err = sealEnvelope(masterKey, "", "", []byte("share"))
+	assert.ErrorIs(t, err, errAddressRequired)
+	_, err = openEnvelope(masterKey, testAddress, "env1:aes-256-gcm:abc")
+	assert.ErrorIs(t, err, errMalformedEnvelope)
+	assert.False(t, IsEnvelope(base64.StdEncoding.EncodeToString([]byte("legacy"))))
+}
+
+func TestReadMasterKey(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	key := newMasterKey(t)
+
+	file := filepath.Join(dir, "master.key")
+	require.NoError(t, os.WriteFile(file, []byte(hex.EncodeToString(key)+"\n"), 0o600))
+	got, err := ReadMasterKey(file)
+	require.NoError(t, err)
+	assert.Equal(t, key, got)
+
+	require.NoError(t, os.WriteFile(file, []byte(base64.StdEncoding.EncodeToString(key)), 0o600))
+	got, err = ReadMasterKey(file)
+	require.NoError(t, err)
+	assert.Equal(t, key, got)
+
+	require.NoError(t, os.WriteFile(file, []byte(hex.EncodeToString(key[:16])), 0o600))
+	_, err = ReadMasterKey(file)
+	assert.ErrorIs(t, err, errMalformedMasterKey)
+
+	_, err = ReadMasterKey("")
+	assert.ErrorIs(t, err, errNoMasterKey)
+}
+
+func TestDecrypt(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	masterKeyFile := filepath.Join(dir, "master.key")
+	require.NoError(t, os.WriteFile(masterKeyFile, []byte(hex.EncodeToString(newMasterKey(t))), 0o600))
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pemFile := filepath.Join(dir, "solis.pem")
+	require.NoError(t, os.WriteFile(pemFile, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(rsaKey),
+	}), 0o600))
+
+	m := New(&config.Config{MasterKeyFile: masterKeyFile, SolisDbPem: pemFile, CipherAlgorithm: CipherXChaCha20Poly1305})
+	sealed, err := m.Encrypt(testAddress, "share")
+	require.NoError(t, err)
+	plaintext, err := m.Decrypt(testAddress, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "share", plaintext)
+
+	// 迁移前的 RSA 密文在过渡期间仍然可以解密
+	legacy, err := rsa.EncryptPKCS1v15(rand.Reader, &rsaKey.PublicKey, []byte("legacy share"))
+	require.NoError(t, err)
+	plaintext, err = m.Decrypt(testAddress, base64.StdEncoding.EncodeToString(legacy))
+	require.NoError(t, err)
+	assert.Equal(t, "legacy share", plaintext)
+}
@@ -0,0 +1,199 @@
+package account
+
+import (
+	"errors"
+	"fmt"
+
+	accountsql "gocryptotrader/database/repository/account"
+)
+
+// RootLayer 账户树根节点所在的层级，例如金库账户
+const RootLayer = 0
+
+var (
+	errOwnerRequired  = errors.New("所有者不能为空")
+	errSelfParent     = errors.New("账户不能作为自己的上级账户")
+	errRootParent     = fmt.Errorf("第 %d 层账户不能有上级账户", RootLayer)
+	errParentRequired = fmt.Errorf("第 %d 层以下的账户必须有上级账户", RootLayer)
+	errParentLayer    = errors.New("上级账户必须位于上一层级")
+	errParentOwner    = errors.New("上级账户的所有者不一致")
+	errParentChain    = errors.New("上级账户的所属链不一致")
+	errParentMissing  = errors.New("上级账户不存在或不属于同一所有者")
+	errHasChildren    = errors.New("账户仍有下级账户")
+)
+
+// ValidateLink 检查 parent 能否作为 child 的上级账户：两者属于同一所有者和同一条链，
+// 且 parent 恰好位于 child 的上一层级
+func ValidateLink(parent, child *Account) error {
+	if parent.ID == child.ID {
+		return errSelfParent
+	}
+	if child.Layer == RootLayer {
+		return errRootParent
+	}
+	if parent.Layer != child.Layer-1 {
+		return fmt.Errorf("%w: 上级账户 %s 位于第 %d 层，下级账户 %s 位于第 %d 层",
+			errParentLayer, parent.Address, parent.Layer, child.Address, child.Layer)
+	}
+	if parent.Owner != child.Owner {
+		return fmt.Errorf("%w: %q 与 %q", errParentOwner, parent.Owner, child.Owner)
+	}
+	if chainName(parent) != chainName(child) {
+		return fmt.Errorf("%w: %q 与 %q", errParentChain, chainName(parent), chainName(child))
+	}
+	return nil
+}
+
+// chainName 返回账户的所属链，未设置时为 DefaultChainName
+func chainName(a *Account) string {
+	if a.ChainName == "" {
+		return DefaultChainName
+	}
+	return a.ChainName
+}
+
+// BuildTree 将一个所有者的账户按上下级关系组成账户树。
+// 关联有效的账户挂在上级账户下；没有上级的第 0 层账户作为根节点；
+// 其余账户连同其下级账户一起列入 Invalid，不出现在从根节点出发的树中
+func BuildTree(owner string, accounts []*Account) *Tree {
+	tree := &Tree{Owner: owner}
+	nodes := make(map[int]*Node, len(accounts))
+	for _, a := range accounts {
+		nodes[a.ID] = &Node{Account: a}
+	}
+	// 按输入顺序挂载，同一上级账户的下级账户保持输入顺序
+	for _, a := range accounts {
+		node := nodes[a.ID]
+		if a.ParentID == 0 {
+			if a.Layer == RootLayer {
+				tree.Roots = append(tree.Roots, node)
+				continue
+			}
+			tree.Invalid = append(tree.Invalid, LinkError{Node: node, Err: errParentRequired})
+			continue
+		}
+		parent, ok := nodes[a.ParentID]
+		if !ok {
+			tree.Invalid = append(tree.Invalid, LinkError{Node: node, Err: fmt.Errorf("%w: %d", errParentMissing, a.ParentID)})
+			continue
+		}
+		if err := ValidateLink(parent.Account, a); err != nil {
+			tree.Invalid = append(tree.Invalid, LinkError{Node: node, Err: err})
+			continue
+		}
+		node.Parent = parent
+		parent.Children = append(parent.Children, node)
+	}
+	return tree
+}
+
+// Find 返回树中指定 ID 的账户节点，包括 Invalid 中的节点，不存在时返回 nil
+func (t *Tree) Find(id int) *Node {
+	var found *Node
+	t.walk(func(n *Node) bool {
+		if n.Account.ID == id {
+			found = n
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// Leaves 返回从根节点可达、有上级账户且没有下级账户的账户节点
+func (t *Tree) Leaves() []*Node {
+	var leaves []*Node
+	for _, root := range t.Roots {
+		root.walk(func(n *Node) bool {
+			if n.Parent != nil && len(n.Children) == 0 {
+				leaves = append(leaves, n)
+			}
+			return true
+		})
+	}
+	return leaves
+}
+
+// walk 先序遍历树中的所有节点，fn 返回 false 时停止遍历
+func (t *Tree) walk(fn func(*Node) bool) {
+	for _, root := range t.Roots {
+		if !root.walk(fn) {
+			return
+		}
+	}
+	for i := range t.Invalid {
+		if !t.Invalid[i].Node.walk(fn) {
+			return
+		}
+	}
+}
+
+// walk 先序遍历以 n 为根的子树，fn 返回 false 时停止遍历
+func (n *Node) walk(fn func(*Node) bool) bool {
+	if !fn(n) {
+		return false
+	}
+	for _, child := range n.Children {
+		if !child.walk(fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Tree 返回一个所有者的账户树，并列出上下级关联无效的账户
+func (m *Manager) Tree(owner string) (*Tree, error) {
+	if owner == "" {
+		return nil, errOwnerRequired
+	}
+	accounts, err := m.AccountsByFilter(&Filter{Owner: owner})
+	if err != nil {
+		return nil, err
+	}
+	return BuildTree(owner, accounts), nil
+}
+
+// Children 返回账户的直接下级账户
+func (m *Manager) Children(id int) ([]*Account, error) {
+	return m.AccountsByFilter(&Filter{ParentID: id})
+}
+
+// SetParent 将 parentID 设为账户的上级账户，parentID 为 0 时解除关联
+func (m *Manager) SetParent(id, parentID int) (*Account, error) {
+	a, err := m.GetAccountByID(id)
+	if err != nil {
+		return nil, err
+	}
+	a.ParentID = parentID
+	if err := m.UpdateAccount(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// linkParent 为新账户关联上级账户：未设置的所有者和所属链沿用上级账户，
+// 未设置层级时位于上级账户的下一层，随后校验关联是否有效
+func linkParent(parent, a *Account) error {
+	if a.Owner == "" {
+		a.Owner = parent.Owner
+	}
+	if a.ChainName == "" {
+		a.ChainName = chainName(parent)
+	}
+	if a.Layer == RootLayer {
+		a.Layer = parent.Layer + 1
+	}
+	return ValidateLink(parent, a)
+}
+
+// checkNoChildren 在账户仍有下级账户时返回错误
+func checkNoChildren(id int) error {
+	total, err := accountsql.Count(&Filter{ParentID: id})
+	if err != nil {
+		return err
+	}
+	if total > 0 {
+		return fmt.Errorf("%w: %d 个", errHasChildren, total)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateLink(t *testing.T) {
+	t.Parallel()
+	parent := &Account{ID: 1, Address: "treasury", Layer: 0, Owner: "alice"}
+	child := &Account{ID: 2, Address: "distributor", Layer: 1, Owner: "alice", ChainName: DefaultChainName}
+	assert.NoError(t, ValidateLink(parent, child), "an empty chain name should default to solana")
+
+	assert.ErrorIs(t, ValidateLink(parent, parent), errSelfParent)
+	assert.ErrorIs(t, ValidateLink(child, &Account{ID: 3, Layer: 0, Owner: "alice"}), errRootParent)
+	assert.ErrorIs(t, ValidateLink(parent, &Account{ID: 3, Layer: 2, Owner: "alice"}), errParentLayer)
+	assert.ErrorIs(t, ValidateLink(parent, &Account{ID: 3, Layer: 1, Owner: "bob"}), errParentOwner)
+	assert.ErrorIs(t, ValidateLink(parent, &Account{ID: 3, Layer: 1, Owner: "alice", ChainName: "eclipse"}), errParentChain)
+}
+
+func TestBuildTree(t *testing.T) {
+	t.Parallel()
+	accounts := []*Account{
+		{ID: 1, Address: "treasury", Layer: 0, Owner: "alice"},
+		{ID: 2, Address: "distributor-1", Layer: 1, Owner: "alice", ParentID: 1},
+		{ID: 3, Address: "distributor-2", Layer: 1, Owner: "alice", ParentID: 1},
+		{ID: 4, Address: "wallet-1", Layer: 2, Owner: "alice", ParentID: 2},
+		{ID: 5, Address: "wallet-2", Layer: 2, Owner: "alice", ParentID: 2},
+		{ID: 6, Address: "unlinked", Layer: 1, Owner: "alice"},
+		{ID: 7, Address: "skipped-layer", Layer: 2, Owner: "alice", ParentID: 1},
+		{ID: 8, Address: "under-skipped", Layer: 3, Owner: "alice", ParentID: 7},
+		{ID: 9, Address: "foreign-parent", Layer: 1, Owner: "alice", ParentID: 100},
+	}
+	tree := BuildTree("alice", accounts)
+	assert.Equal(t, "alice", tree.Owner)
+
+	require.Len(t, tree.Roots, 1)
+	root := tree.Roots[0]
+	assert.Equal(t, 1, root.Account.ID)
+	assert.Nil(t, root.Parent)
+	require.Len(t, root.Children, 2)
+	assert.Equal(t, 2, root.Children[0].Account.ID, "children should keep the input order")
+	assert.Equal(t, 3, root.Children[1].Account.ID)
+	require.Len(t, root.Children[0].Children, 2)
+	assert.Same(t, root.Children[0], root.Children[0].Children[0].Parent)
+
+	require.Len(t, tree.Invalid, 3)
+	assert.Equal(t, 6, tree.Invalid[0].Node.Account.ID)
+	assert.ErrorIs(t, tree.Invalid[0].Err, errParentRequired)
+	assert.Equal(t, 7, tree.Invalid[1].Node.Account.ID)
+	assert.ErrorIs(t, tree.Invalid[1].Err, errParentLayer)
+	require.Len(t, tree.Invalid[1].Node.Children, 1, "an invalid account should keep its valid children")
+	assert.Equal(t, 8, tree.Invalid[1].Node.Children[0].Account.ID)
+	assert.Equal(t, 9, tree.Invalid[2].Node.Account.ID)
+	assert.ErrorIs(t, tree.Invalid[2].Err, errParentMissing)
+
+	var leaves []int
+	for _, n := range tree.Leaves() {
+		leaves = append(leaves, n.Account.ID)
+	}
+	assert.Equal(t, []int{4, 5, 3}, leaves, "leaves should only include accounts reachable from a root")
+
+	assert.Equal(t, "wallet-2", tree.Find(5).Account.Address)
+	assert.Equal(t, "under-skipped", tree.Find(8).Account.Address, "Find should search invalid subtrees")
+	assert.Nil(t, tree.Find(100))
+
+	lone := BuildTree("bob", []*Account{{ID: 1, Layer: 0, Owner: "bob"}})
+	assert.Empty(t, lone.Leaves(), "a root without children is not a leaf")
+}
@@ -0,0 +1,93 @@
+package forward
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// maxAccountsPerQuery getMultipleAccounts 单次请求允许的最大账户数量
+const maxAccountsPerQuery = 100
+
+// accountCache 缓存一次转发任务中查询过的账户是否存在，
+// 每个账户只查询一次，重复的接收者和后续批次直接使用缓存结果
+type accountCache struct {
+	exists map[solana.PublicKey]bool
+}
+
+func newAccountCache() *accountCache {
+	return &accountCache{exists: make(map[solana.PublicKey]bool)}
+}
+
+// load 使用 getMultipleAccounts 分块查询尚未缓存的账户是否存在
+func (c *accountCache) load(ctx context.Context, client *rpc.Client, accounts []solana.PublicKey) error {
+	var pending []solana.PublicKey
+	seen := make(map[solana.PublicKey]struct{}, len(accounts))
+	for _, a := range accounts {
+		if _, ok := c.exists[a]; ok {
+			continue
+		}
+		if _, ok := seen[a]; ok {
+			continue
+		}
+		seen[a] = struct{}{}
+		pending = append(pending, a)
+	}
+
+	for i := 0; i < len(pending); i += maxAccountsPerQuery {
+		end := i + maxAccountsPerQuery
+		if end > len(pending) {
+			end = len(pending)
+		}
+		group := pending[i:end]
+
+		// 只关心账户是否存在，不需要返回账户数据
+		out, err := client.GetMultipleAccountsWithOpts(ctx, group, &rpc.GetMultipleAccountsOpts{
+			Encoding:   solana.EncodingBase64,
+			Commitment: rpc.CommitmentConfirmed,
+			DataSlice:  &rpc.DataSlice{Offset: new(uint64), Length: new(uint64)},
+		})
+		if err != nil {
+			return fmt.Errorf("批量查询接收者代币账户失败: %w", err)
+		}
+		if len(out.Value) != len(group) {
+			return fmt.Errorf("批量查询接收者代币账户返回 %d 个结果，请求 %d 个", len(out.Value), len(group))
+		}
+		for j, a := range group {
+			c.exists[a] = out.Value[j] != nil && !out.Value[j].Owner.IsZero()
+		}
+	}
+	return nil
+}
+
+// has 返回账户是否存在，未查询过的账户视为不存在
+func (c *accountCache) has(account solana.PublicKey) bool {
+	return c.exists[account]
+}
+
+// fetchAccounts 使用 getMultipleAccounts 分块查询账户及其数据，不存在的账户对应位置为 nil
+func fetchAccounts(ctx context.Context, client *rpc.Client, accounts []solana.PublicKey) ([]*rpc.Account, error) {
+	out := make([]*rpc.Account, 0, len(accounts))
+	for i := 0; i < len(accounts); i += maxAccountsPerQuery {
+		end := i + maxAccountsPerQuery
+		if end > len(accounts) {
+			end = len(accounts)
+		}
+		group := accounts[i:end]
+
+		res, err := client.GetMultipleAccountsWithOpts(ctx, group, &rpc.GetMultipleAccountsOpts{
+			Encoding:   solana.EncodingBase64,
+			Commitment: rpc.CommitmentConfirmed,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("批量查询账户失败: %w", err)
+		}
+		if len(res.Value) != len(group) {
+			return nil, fmt.Errorf("批量查询账户返回 %d 个结果，请求 %d 个", len(res.Value), len(group))
+		}
+		out = append(out, res.Value...)
+	}
+	return out, nil
+}
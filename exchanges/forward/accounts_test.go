@@ -0,0 +1,162 @@
+package forward
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rpcHandler 处理一个 JSON-RPC 方法，返回值作为 result 编码，返回错误时响应 JSON-RPC 错误
+type rpcHandler func(params []json.RawMessage) (any, error)
+
+// rpcServer 模拟 Solana 节点，按方法名把请求分发给 handlers，未注册的方法返回 400
+func rpcServer(t *testing.T, handlers map[string]rpcHandler) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		handler, ok := handlers[req.Method]
+		if !ok {
+			http.Error(w, "unexpected method "+req.Method, http.StatusBadRequest)
+			return
+		}
+		resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		if result, err := handler(req.Params); err != nil {
+			resp["error"] = map[string]any{"code": -32002, "message": err.Error()}
+		} else {
+			resp["result"] = result
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+// rpcValue 包装带 context 的 RPC 结果
+func rpcValue(value any) map[string]any {
+	return map[string]any{"context": map[string]any{"slot": 1}, "value": value}
+}
+
+// rpcAccount 返回 getMultipleAccounts 中的一个账户
+func rpcAccount(owner solana.PublicKey, lamports uint64, data []byte) map[string]any {
+	return map[string]any{
+		"data":       []string{base64.StdEncoding.EncodeToString(data), "base64"},
+		"executable": false,
+		"lamports":   lamports,
+		"owner":      owner.String(),
+		"rentEpoch":  0,
+	}
+}
+
+// multipleAccountsServer 模拟 getMultipleAccounts，existing 中的账户视为存在
+func multipleAccountsServer(t *testing.T, existing map[string]bool, calls *int32) *httptest.Server {
+	t.Helper()
+	return rpcServer(t, map[string]rpcHandler{
+		"getMultipleAccounts": func(params []json.RawMessage) (any, error) {
+			atomic.AddInt32(calls, 1)
+			var keys []string
+			if err := json.Unmarshal(params[0], &keys); err != nil || len(keys) > maxAccountsPerQuery {
+				return nil, errors.New("unexpected params")
+			}
+			value := make([]any, len(keys))
+			for i, k := range keys {
+				if existing[k] {
+					value[i] = rpcAccount(solana.TokenProgramID, 2039280, nil)
+				}
+			}
+			return rpcValue(value), nil
+		},
+	})
+}
+
+func TestAccountCacheLoad(t *testing.T) {
+	t.Parallel()
+	var accounts []solana.PublicKey
+	existing := make(map[string]bool)
+	for i := range 250 {
+		a := solana.NewWallet().PublicKey()
+		accounts = append(accounts, a)
+		if i%2 == 0 {
+			existing[a.String()] = true
+		}
+	}
+	var calls int32
+	server := multipleAccountsServer(t, existing, &calls)
+	defer server.Close()
+	client := rpc.New(server.URL)
+
+	cache := newAccountCache()
+	// 重复的账户只查询一次
+	require.NoError(t, cache.load(context.Background(), client, append(accounts, accounts[:10]...)), "load must not error")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls), "accounts must be queried in chunks of maxAccountsPerQuery")
+	for i, a := range accounts {
+		assert.Equal(t, i%2 == 0, cache.has(a), "account %d existence must match the RPC response", i)
+	}
+
+	require.NoError(t, cache.load(context.Background(), client, accounts), "load must not error")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls), "cached accounts must not be queried again")
+	assert.False(t, cache.has(solana.NewWallet().PublicKey()), "unknown accounts must be treated as missing")
+}
+
+func TestTxCapacityMixedLayouts(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	cfg.MaxInstructionsPerTx = 100
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	from := key.PublicKey()
+	mint := &mintInfo{address: solana.NewWallet().PublicKey(), programID: solana.TokenProgramID, decimals: 6}
+	senderATA, err := mint.associatedTokenAddress(from)
+	require.NoError(t, err)
+
+	// 交替装入创建 ATA 和普通转账的接收者，直到交易装满
+	capacity := cfg.newTxCapacity()
+	b := &Batch{}
+	for i := 0; ; i++ {
+		layout := tokenLayout
+		if i%3 == 0 {
+			layout = tokenCreateLayout
+		}
+		if !capacity.reserve(layout) {
+			break
+		}
+		to := solana.NewWallet().PublicKey()
+		ata, err := mint.associatedTokenAddress(to)
+		require.NoError(t, err)
+		transferIx, err := mint.transferInstruction(1, senderATA, ata, from)
+		require.NoError(t, err)
+		cost := recipientCost{computeUnits: tokenTransferUnits}
+		instructions := []solana.Instruction{transferIx}
+		if layout == tokenCreateLayout {
+			createIx, err := mint.createAccountInstruction(from, to)
+			require.NoError(t, err)
+			instructions = []solana.Instruction{createIx, transferIx}
+			cost.computeUnits += createATAUnits
+			cost.createsAccount = true
+		}
+		b.add(Recipient{Address: to.String()}, cost, instructions...)
+	}
+	assert.Greater(t, len(b.Recipients), fillCapacity(cfg, tokenCreateLayout), "mixed batches must hold more recipients than the worst case")
+	assert.LessOrEqual(t, transactionSize(t, cfg, b, key), maxTransactionSize, "mixed batch must fit in a transaction")
+
+	cfg.MaxInstructionsPerTx = 0
+	capacity = cfg.newTxCapacity()
+	assert.True(t, capacity.reserve(tokenCreateLayout), "an empty transaction must always take one recipient")
+	assert.False(t, capacity.reserve(tokenLayout))
+}
@@ -0,0 +1,274 @@
+package forward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"gocryptotrader/log"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// add 将接收者及其指令加入批次
+func (b *Batch) add(recipient Recipient, cost recipientCost, instructions ...solana.Instruction) {
+	cost.instructions = len(instructions)
+	b.instructions = append(b.instructions, instructions...)
+	b.Recipients = append(b.Recipients, recipient)
+	b.costs = append(b.costs, cost)
+	b.computeUnits += cost.computeUnits
+	if cost.createsAccount {
+		b.ataCreations++
+	}
+}
+
+// truncate 只保留批次的前 n 个接收者及其指令，返回被移除的接收者
+func (b *Batch) truncate(n int) []Recipient {
+	removed := append([]Recipient(nil), b.Recipients[n:]...)
+	var instructions int
+	b.computeUnits, b.ataCreations = 0, 0
+	for _, c := range b.costs[:n] {
+		instructions += c.instructions
+		b.computeUnits += c.computeUnits
+		if c.createsAccount {
+			b.ataCreations++
+		}
+	}
+	b.instructions = b.instructions[:instructions]
+	b.Recipients = b.Recipients[:n]
+	b.costs = b.costs[:n]
+	return removed
+}
+
+// execute 对所有批次签名并并发发送，然后跟踪确认结果
+// 每个批次在签名后、发送前通知观察者，使签名在交易广播前即被持久化，
+// 进程中途崩溃后可以根据签名查询链上状态，避免重复转账。
+// 上下文取消后不再发送新的批次。观察者会被多个 goroutine 并发调用。
+func (m *Manager) execute(ctx context.Context, client *rpc.Client, signer accountSigner, batches []*Batch, cfg *Config, observer Observer) error {
+	if len(batches) == 0 {
+		return nil
+	}
+	if err := m.send(ctx, client, signer, batches, cfg, observer); err != nil {
+		return err
+	}
+	if cfg.ConfirmTimeout <= 0 {
+		return nil
+	}
+	return m.track(ctx, client, signer, batches, cfg, observer)
+}
+
+// run 模拟、预先签名或执行转账批次
+// 配置了 NonceAccounts 时每个批次使用一个 nonce 账户签名；signOnly 时只签名不发送。
+// 启用 UseLookupTables 时先创建地址查找表，任务结束后在后台停用并关闭；模拟运行时只规划查找表并按 v0 交易估算
+func (m *Manager) run(ctx context.Context, client *rpc.Client, signer accountSigner, result *Result, cfg *Config, observer Observer, dryRun, signOnly bool) error {
+	if signOnly && len(cfg.NonceAccounts) == 0 {
+		return errSignOnlyWithoutNonce
+	}
+	if signOnly && cfg.UseLookupTables {
+		return errSignOnlyLookupTables
+	}
+	if len(cfg.NonceAccounts) > 0 {
+		if err := assignNonces(ctx, client, signer.PublicKey(), result.Batches, cfg); err != nil {
+			return err
+		}
+	}
+
+	if dryRun {
+		var tables []*lookupTable
+		if cfg.UseLookupTables {
+			var err error
+			if tables, err = planDryRunLookupTables(result.Batches, signer.PublicKey()); err != nil {
+				return err
+			}
+		}
+		report, err := m.simulate(ctx, client, signer, result.Batches, cfg)
+		if err != nil {
+			return err
+		}
+		report.LookupTables = len(tables)
+		report.EstimatedFee += uint64(lookupTableTxs(tables)) * lamportsPerSignature
+		result.Simulation = report
+		return nil
+	}
+	if signOnly {
+		return m.sign(ctx, client, signer, result.Batches, cfg)
+	}
+
+	if o, ok := observer.(BuildObserver); ok {
+		o.BatchesBuilt(result.Batches)
+	}
+	if cfg.UseLookupTables {
+		tables, err := m.prepareLookupTables(ctx, client, signer, result, cfg)
+		defer m.releaseLookupTables(client, signer, tables, cfg)
+		if err != nil {
+			return err
+		}
+	}
+	return m.execute(ctx, client, signer, result.Batches, cfg, observer)
+}
+
+// instructionBatch 将账户管理指令（如查找表、nonce 账户）包装为批次
+func instructionBatch(index int, units uint32, instructions ...solana.Instruction) *Batch {
+	return &Batch{
+		Index:        index,
+		Status:       StatusPending,
+		instructions: instructions,
+		computeUnits: units,
+	}
+}
+
+// managementConfig 返回发送账户管理交易使用的配置，管理交易不使用查找表和 nonce，且必须等待确认
+func managementConfig(cfg *Config) *Config {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+	c := *cfg
+	c.ComputeUnitLimit = 0
+	c.UseLookupTables = false
+	c.NonceAccounts = nil
+	if c.ConfirmTimeout <= 0 {
+		c.ConfirmTimeout = DefaultConfig().ConfirmTimeout
+	}
+	return &c
+}
+
+// executeConfirmed 发送账户管理交易并等待确认，任一交易未确认时返回错误
+func (m *Manager) executeConfirmed(ctx context.Context, client *rpc.Client, signer accountSigner, batches []*Batch, cfg *Config, action string) error {
+	if err := m.execute(ctx, client, signer, batches, cfg, nil); err != nil {
+		return fmt.Errorf("%s失败: %w", action, err)
+	}
+	var errs []error
+	for _, b := range batches {
+		if b.Status != StatusConfirmed {
+			errs = append(errs, fmt.Errorf("交易 %s 状态为 %s: %w", b.Signature, b.Status, b.Err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s失败: %w", action, errors.Join(errs...))
+	}
+	return nil
+}
+
+// send 使用最新的 blockhash 对批次签名并并发发送
+func (m *Manager) send(ctx context.Context, client *rpc.Client, signer accountSigner, batches []*Batch, cfg *Config, observer Observer) error {
+	// 获取最新的 blockhash
+	recent, err := client.GetLatestBlockhash(ctx, rpc.CommitmentFinalized)
+	if err != nil {
+		return fmt.Errorf("获取最新 blockhash 失败: %w", err)
+	}
+
+	// 优先费单价在每次发送时确定，重发时使用最新的费用水平
+	price := cfg.computeUnitPrice(ctx, client, signer.PublicKey())
+
+	// 并发发送交易
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, cfg.ConcurrentTxs)
+	for _, b := range batches {
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(b *Batch) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			sendBatch(ctx, client, signer, b, cfg.budgetInstructions(b, price), recent.Value, observer)
+		}(b)
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// sendBatch 构建、签名并发送单个批次
+func sendBatch(ctx context.Context, client *rpc.Client, signer accountSigner, b *Batch, budget []solana.Instruction, blockhash *rpc.LatestBlockhashResult, observer Observer) {
+	recent, lastValid := blockhash.Blockhash, blockhash.LastValidBlockHeight
+	b.NonceAccount, b.Nonce = "", ""
+	if b.nonce != nil {
+		// 使用 nonce 的交易不会因 blockhash 过期而失效，nonce 推进后才会失效
+		recent, lastValid = b.nonce.value, 0
+		b.NonceAccount, b.Nonce = b.nonce.account.String(), b.nonce.value.String()
+	}
+	tx, err := buildTransaction(ctx, b, budget, recent, signer)
+	if err != nil {
+		b.Status, b.Err = StatusFailed, err
+		log.Errorf(log.Global, "批次 %d %v", b.Index, b.Err)
+		notifyObserver(observer, b)
+		return
+	}
+	submitBatch(ctx, client, b, tx, lastValid, observer)
+}
+
+// submitBatch 发送已签名的批次交易，lastValid 为 0 时批次只在 nonce 推进后被判定为过期
+// 发送前通知观察者持久化签名和 nonce
+func submitBatch(ctx context.Context, client *rpc.Client, b *Batch, tx *solana.Transaction, lastValid uint64, observer Observer) {
+	b.Signature = tx.Signatures[0].String()
+	b.LastValidBlockHeight = lastValid
+	b.Status, b.Err = StatusSent, nil
+	if observer != nil {
+		if err := observer.BatchSigned(b); errors.Is(err, ErrStopped) {
+			b.Status, b.Signature, b.Err = StatusPending, "", err
+			return
+		} else if err != nil {
+			// 签名未能持久化，不发送该批次以免无法追踪
+			b.Status, b.Signature, b.Err = StatusPending, "", fmt.Errorf("记录批次签名失败: %w", err)
+			log.Errorf(log.Global, "批次 %d %v", b.Index, b.Err)
+			return
+		}
+	}
+
+	txSig, err := client.SendTransactionWithOpts(ctx, tx, rpc.TransactionOpts{
+		SkipPreflight:       false,
+		PreflightCommitment: rpc.CommitmentFinalized,
+	})
+	if err != nil {
+		// 节点返回的 RPC 错误说明交易被拒绝、未被广播；其他错误（如网络错误）无法确定交易是否已上链
+		var rpcErr *jsonrpc.RPCError
+		if errors.As(err, &rpcErr) {
+			b.Status = StatusFailed
+		}
+		b.Err = fmt.Errorf("发送交易失败: %w", err)
+		log.Errorf(log.Global, "批次 %d %v", b.Index, b.Err)
+	} else {
+		log.Infof(log.Global, "交易已发送: %s", txSig)
+	}
+	notifyObserver(observer, b)
+}
+
+// buildTransaction 使用给定的 blockhash 构建批次交易并由签名方签名，计算预算指令放在最前面，交易级备注放在最后
+func buildTransaction(ctx context.Context, b *Batch, budget []solana.Instruction, blockhash solana.Hash, signer accountSigner) (*solana.Transaction, error) {
+	from := signer.PublicKey()
+	instructions := make([]solana.Instruction, 0, len(budget)+len(b.instructions)+2)
+	if b.nonce != nil {
+		// AdvanceNonceAccount 必须是交易的第一条指令，交易使用 nonce 值代替 blockhash
+		instructions = append(instructions, advanceNonceInstruction(b.nonce, from))
+		blockhash = b.nonce.value
+	}
+	instructions = append(instructions, budget...)
+	instructions = append(instructions, b.instructions...)
+	if b.Memo != "" {
+		instructions = append(instructions, memoInstruction(b.Memo, from))
+	}
+	opts := []solana.TransactionOption{solana.TransactionPayer(from)}
+	if len(b.lookupTables) > 0 {
+		// 引用地址查找表时构建 v0 交易
+		opts = append(opts, solana.TransactionAddressTables(b.lookupTables))
+	}
+	tx, err := solana.NewTransaction(instructions, blockhash, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("创建交易失败: %w", err)
+	}
+
+	if err = signer.signTransaction(ctx, tx); err != nil {
+		return nil, fmt.Errorf("签名交易失败: %w", err)
+	}
+	return tx, nil
+}
+
+func notifyObserver(observer Observer, b *Batch) {
+	if observer != nil {
+		observer.BatchUpdated(b)
+	}
+}
@@ -0,0 +1,37 @@
+package forward
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stopObserver 在签名后停止发送
+type stopObserver struct {
+	updated int
+}
+
+func (o *stopObserver) BatchSigned(*Batch) error { return ErrStopped }
+
+func (o *stopObserver) BatchUpdated(*Batch) { o.updated++ }
+
+func TestSubmitBatchStopped(t *testing.T) {
+	t.Parallel()
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	b := instructionBatch(0, 0, system.NewTransferInstruction(1, key.PublicKey(), solana.NewWallet().PublicKey()).Build())
+	tx, err := buildTransaction(context.Background(), b, nil, solana.Hash{}, testSigner(key))
+	require.NoError(t, err)
+
+	// 停止后不会访问节点，nil client 不会被使用
+	observer := &stopObserver{}
+	submitBatch(context.Background(), nil, b, tx, 100, observer)
+	assert.Equal(t, StatusPending, b.Status, "a stopped batch must stay pending so it can be resent")
+	assert.Empty(t, b.Signature)
+	assert.ErrorIs(t, b.Err, ErrStopped)
+	assert.Zero(t, observer.updated, "a stopped batch is not reported as updated")
+}
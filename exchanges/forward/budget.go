@@ -0,0 +1,229 @@
+package forward
+
+import (
+	"context"
+	"sort"
+
+	"gocryptotrader/log"
+
+	"github.com/gagliardetto/solana-go"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	// maxTransactionSize 序列化后交易的最大字节数
+	maxTransactionSize = 1232
+	// maxComputeUnitLimit 单笔交易允许的最大计算单元
+	maxComputeUnitLimit = 1_400_000
+	// txFixedSize 交易中与账户和指令数量无关的字节数：签名（含长度）、消息头、账户长度、blockhash、指令长度
+	txFixedSize = 1 + 64 + 3 + 1 + 32 + 1
+	// computeBudgetSize 两条计算预算指令的字节数
+	computeBudgetSize = (1 + 1 + 1 + 5) + (1 + 1 + 1 + 9)
+
+	// 各类指令消耗的计算单元估算值，均取保守值
+	computeBudgetUnits  = 150
+	systemTransferUnits = 300
+	tokenTransferUnits  = 6_500
+	createATAUnits      = 35_000
+	closeAccountUnits   = 3_000
+	advanceNonceUnits   = 500
+)
+
+// txLayout 描述一类转账交易的大小和计算单元构成，用于计算每笔交易可容纳的接收者数量
+type txLayout struct {
+	accounts          int    // 所有接收者共用的账户数量，如付款人、程序和发送者代币账户
+	recipientSize     int    // 每个接收者增加的字节数，包括指令和新增的账户
+	recipientAccounts int    // 每个接收者新增的账户数量，使用地址查找表时这些账户只占一个字节的索引
+	recipientUnits    uint32 // 每个接收者消耗的计算单元估算值
+}
+
+var (
+	// solLayout 付款人、System 程序；每个接收者一条 Transfer 指令及接收者账户
+	solLayout = txLayout{accounts: 2, recipientSize: 17 + 32, recipientAccounts: 1, recipientUnits: systemTransferUnits}
+	// tokenLayout 付款人、Token 程序、发送者代币账户、铸币账户；每个接收者一条 TransferChecked 指令及接收者代币账户
+	tokenLayout = txLayout{accounts: 4, recipientSize: 17 + 32, recipientAccounts: 1, recipientUnits: tokenTransferUnits}
+	// tokenCreateLayout 额外包含 ATA 程序、System 程序和 Rent sysvar；
+	// 每个接收者额外一条创建 ATA 的指令及接收者钱包账户
+	tokenCreateLayout = txLayout{accounts: 7, recipientSize: 11 + 32 + 17 + 32, recipientAccounts: 2, recipientUnits: createATAUnits + tokenTransferUnits}
+
+	// sweepSOLLayout 归集 SOL：付款人、System 程序和目标钱包；一条 Transfer 指令
+	sweepSOLLayout = txLayout{accounts: 3, recipientSize: 17, recipientUnits: systemTransferUnits}
+
+	// reclaimLayout 回收租金：付款人（同时是所有者和租金接收者）、SPL Token 和 Token-2022 两个程序；
+	// 每个代币账户一条 CloseAccount 指令及被关闭的账户
+	reclaimLayout = txLayout{accounts: 3, recipientSize: 7 + 32, recipientAccounts: 1, recipientUnits: closeAccountUnits}
+)
+
+// sweepTokenLayout 归集一个代币账户的交易构成
+// 共用付款人、System 程序、目标钱包以及 SPL Token 和 Token-2022 两个程序；
+// 每个代币账户包含源代币账户，转账时额外包含铸币账户、目标代币账户和一条 TransferChecked 指令，
+// 创建目标代币账户时额外共用 ATA 程序和 Rent sysvar，关闭源代币账户时额外一条 CloseAccount 指令
+func sweepTokenLayout(transfer, create, closeAccount bool) txLayout {
+	l := txLayout{accounts: 5, recipientSize: 32, recipientAccounts: 1}
+	if transfer {
+		l.recipientSize += 17 + 32 + 32
+		l.recipientAccounts += 2
+		l.recipientUnits += tokenTransferUnits
+	}
+	if create {
+		l.accounts += 2
+		l.recipientSize += 11
+		l.recipientUnits += createATAUnits
+	}
+	if closeAccount {
+		l.recipientSize += 7
+		l.recipientUnits += closeAccountUnits
+	}
+	return l
+}
+
+// txCapacity 跟踪正在构建的交易剩余的字节数和计算单元，
+// 用于按接收者实际的指令构成装填批次，例如同一批次中混合创建 ATA 和普通转账的接收者
+type txCapacity struct {
+	bytes      int  // 剩余字节数
+	units      int  // 剩余计算单元
+	recipients int  // 剩余接收者数量
+	keys       int  // 剩余可锁定的账户数量
+	accounts   int  // 已计入的共用账户数量
+	used       int  // 已容纳的接收者数量
+	lookup     bool // 接收者账户是否通过地址查找表引用
+}
+
+// newTxCapacity 返回一笔空交易的容量，已扣除固定部分、计算预算程序及其指令
+// 使用地址查找表时还扣除 v0 消息的版本号和查找表引用
+func (c *Config) newTxCapacity() *txCapacity {
+	limit := c.ComputeUnitLimit
+	if limit == 0 || limit > maxComputeUnitLimit {
+		limit = maxComputeUnitLimit
+	}
+	t := &txCapacity{
+		bytes:      maxTransactionSize - txFixedSize - 32 - computeBudgetSize,
+		units:      int(limit) - 2*computeBudgetUnits,
+		recipients: c.MaxInstructionsPerTx,
+		keys:       maxTxAccountLocks - 1,
+		lookup:     c.UseLookupTables,
+	}
+	if t.lookup {
+		t.bytes -= lookupTableOverhead
+	}
+	if len(c.NonceAccounts) > 0 {
+		// 交易以 AdvanceNonceAccount 指令开头
+		t.bytes -= advanceNonceSize
+		t.units -= advanceNonceUnits
+		t.keys -= 2
+	}
+	if memo := c.transactionMemo(); memo != "" {
+		// 交易末尾附加一条备注，Memo 程序占用一个账户
+		t.bytes -= 32 + memoInstructionSize(memo)
+		t.units -= int(memoUnits(memo))
+		t.keys--
+	}
+	return t
+}
+
+// reserve 为一个按 l 构成的接收者预留空间，容量不足时返回 false
+// 共用账户按已计入的最大数量增量计算；空交易总是至少容纳一个接收者
+func (t *txCapacity) reserve(l txLayout) bool {
+	size := l.recipientSize
+	if t.lookup {
+		// 接收者的账户通过查找表引用，每个账户只占一个字节的索引
+		size -= (32 - 1) * l.recipientAccounts
+	}
+	keys := l.recipientAccounts
+	if l.accounts > t.accounts {
+		size += (l.accounts - t.accounts) * 32
+		keys += l.accounts - t.accounts
+	}
+	if t.used > 0 && (t.recipients < 1 || size > t.bytes || int(l.recipientUnits) > t.units || keys > t.keys) {
+		return false
+	}
+	t.bytes -= size
+	t.units -= int(l.recipientUnits)
+	t.keys -= keys
+	t.recipients--
+	if l.accounts > t.accounts {
+		t.accounts = l.accounts
+	}
+	t.used++
+	return true
+}
+
+// computeUnitLimit 返回批次交易的计算单元上限，未配置 ComputeUnitLimit 时按批次指令估算
+func (c *Config) computeUnitLimit(b *Batch) uint32 {
+	units := b.computeUnits + memoUnits(b.Memo)
+	if b.nonce != nil {
+		units += advanceNonceUnits
+	}
+	return c.unitLimit(units)
+}
+
+// unitLimit 返回指令估算消耗 units 个计算单元的交易的计算单元上限
+func (c *Config) unitLimit(units uint32) uint32 {
+	limit := c.ComputeUnitLimit
+	if limit == 0 {
+		// 估算值再预留 10% 余量
+		limit = (units + 2*computeBudgetUnits) * 11 / 10
+	}
+	if limit > maxComputeUnitLimit {
+		limit = maxComputeUnitLimit
+	}
+	return limit
+}
+
+// budgetInstructions 返回批次交易开头的计算预算指令，price 为 0 时不设置优先费
+func (c *Config) budgetInstructions(b *Batch, price uint64) []solana.Instruction {
+	instructions := []solana.Instruction{computebudget.NewSetComputeUnitLimitInstruction(c.computeUnitLimit(b)).Build()}
+	if price > 0 {
+		instructions = append(instructions, computebudget.NewSetComputeUnitPriceInstruction(price).Build())
+	}
+	return instructions
+}
+
+// computeUnitPrice 返回本次发送使用的优先费单价（micro-lamports/CU）
+// 配置了 PriorityFeePercentile 时根据 getRecentPrioritizationFees 取对应百分位并受 MaxComputeUnitPrice 限制，
+// 查询失败时回退到固定单价 ComputeUnitPrice
+func (c *Config) computeUnitPrice(ctx context.Context, client *rpc.Client, payer solana.PublicKey) uint64 {
+	if c.PriorityFeePercentile <= 0 {
+		return c.ComputeUnitPrice
+	}
+	fees, err := client.GetRecentPrioritizationFees(ctx, solana.PublicKeySlice{payer})
+	if err != nil {
+		log.Warnf(log.Global, "获取近期优先费失败，使用固定优先费 %d: %v", c.ComputeUnitPrice, err)
+		return c.ComputeUnitPrice
+	}
+	price := feePercentile(fees, c.PriorityFeePercentile)
+	if c.MaxComputeUnitPrice > 0 && price > c.MaxComputeUnitPrice {
+		price = c.MaxComputeUnitPrice
+	}
+	return price
+}
+
+// withComputeUnitPrice 返回使用固定优先费单价 price 的配置副本，发送时不再查询近期优先费
+func (c *Config) withComputeUnitPrice(price uint64) *Config {
+	cfg := *c
+	cfg.ComputeUnitPrice = price
+	cfg.PriorityFeePercentile = 0
+	return &cfg
+}
+
+// feePercentile 返回近期优先费的指定百分位（1-100）
+func feePercentile(fees []rpc.PriorizationFeeResult, percentile int) uint64 {
+	if len(fees) == 0 {
+		return 0
+	}
+	if percentile > 100 {
+		percentile = 100
+	}
+	values := make([]uint64, len(fees))
+	for i := range fees {
+		values[i] = fees[i].PrioritizationFee
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	// 向上取整的最近秩
+	idx := (percentile*len(values)+99)/100 - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return values[idx]
+}
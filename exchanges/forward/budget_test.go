@@ -0,0 +1,109 @@
+package forward
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transactionSize builds and signs a transaction for the batch as a real run
+// would and returns its serialised size
+func transactionSize(t *testing.T, cfg *Config, b *Batch, key solana.PrivateKey) int {
+	t.Helper()
+	tx, err := buildTransaction(context.Background(), b, cfg.budgetInstructions(b, 1000), solana.Hash{}, testSigner(key))
+	require.NoError(t, err, "buildTransaction must not error")
+	data, err := tx.MarshalBinary()
+	require.NoError(t, err, "MarshalBinary must not error")
+	return len(data)
+}
+
+// fillCapacity returns how many recipients laid out as l fit in an empty
+// transaction, as batching reserves them one at a time
+func fillCapacity(cfg *Config, l txLayout) int {
+	capacity := cfg.newTxCapacity()
+	n := 0
+	for capacity.reserve(l) {
+		n++
+	}
+	return n
+}
+
+func TestTxCapacityFitsTransaction(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	cfg.MaxInstructionsPerTx = 100
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	from := key.PublicKey()
+	mint := &mintInfo{address: solana.NewWallet().PublicKey(), programID: solana.Token2022ProgramID, decimals: 6}
+
+	n := fillCapacity(cfg, solLayout)
+	b := &Batch{}
+	for range n {
+		b.instructions = append(b.instructions, system.NewTransferInstruction(1, from, solana.NewWallet().PublicKey()).Build())
+		b.computeUnits += systemTransferUnits
+	}
+	assert.LessOrEqual(t, transactionSize(t, cfg, b, key), maxTransactionSize, "SOL batch must fit in a transaction")
+
+	n = fillCapacity(cfg, tokenCreateLayout)
+	b = &Batch{}
+	senderATA, err := mint.associatedTokenAddress(from)
+	require.NoError(t, err)
+	for range n {
+		to := solana.NewWallet().PublicKey()
+		ata, err := mint.associatedTokenAddress(to)
+		require.NoError(t, err)
+		createIx, err := mint.createAccountInstruction(from, to)
+		require.NoError(t, err)
+		transferIx, err := mint.transferInstruction(1, senderATA, ata, from)
+		require.NoError(t, err)
+		b.instructions = append(b.instructions, createIx, transferIx)
+		b.computeUnits += createATAUnits + tokenTransferUnits
+	}
+	assert.LessOrEqual(t, transactionSize(t, cfg, b, key), maxTransactionSize, "token batch with ATA creation must fit in a transaction")
+
+	cfg.MaxInstructionsPerTx = 5
+	assert.Equal(t, 5, fillCapacity(cfg, solLayout), "MaxInstructionsPerTx must remain an upper bound")
+
+	cfg.MaxInstructionsPerTx = 100
+	cfg.ComputeUnitLimit = 100_000
+	assert.Equal(t, 2, fillCapacity(cfg, tokenCreateLayout), "a fixed compute unit limit must cap the batch size")
+}
+
+func TestComputeUnitLimit(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	assert.Equal(t, uint32((tokenTransferUnits+2*computeBudgetUnits)*11/10), cfg.computeUnitLimit(&Batch{computeUnits: tokenTransferUnits}))
+	assert.Equal(t, uint32(maxComputeUnitLimit), cfg.computeUnitLimit(&Batch{computeUnits: 2_000_000}))
+	cfg.ComputeUnitLimit = 50_000
+	assert.Equal(t, uint32(50_000), cfg.computeUnitLimit(&Batch{computeUnits: tokenTransferUnits}))
+
+	assert.Len(t, cfg.budgetInstructions(&Batch{}, 0), 1, "no price instruction should be added without a priority fee")
+	assert.Len(t, cfg.budgetInstructions(&Batch{}, 10), 2)
+}
+
+func TestFeePercentile(t *testing.T) {
+	t.Parallel()
+	assert.Zero(t, feePercentile(nil, 75))
+	fees := []rpc.PriorizationFeeResult{{PrioritizationFee: 40}, {PrioritizationFee: 10}, {PrioritizationFee: 30}, {PrioritizationFee: 20}}
+	assert.Equal(t, uint64(10), feePercentile(fees, 1))
+	assert.Equal(t, uint64(20), feePercentile(fees, 50))
+	assert.Equal(t, uint64(30), feePercentile(fees, 75))
+	assert.Equal(t, uint64(40), feePercentile(fees, 100))
+	assert.Equal(t, uint64(40), feePercentile(fees, 150))
+}
+
+func TestWithComputeUnitPrice(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	fixed := cfg.withComputeUnitPrice(1234)
+	assert.Equal(t, 75, cfg.PriorityFeePercentile, "the original config must not change")
+	assert.Zero(t, fixed.PriorityFeePercentile)
+	// 固定单价不会查询节点，nil client 不会被使用
+	assert.Equal(t, uint64(1234), fixed.computeUnitPrice(context.Background(), nil, solana.PublicKey{}), "the planned price must be the price paid")
+}
@@ -0,0 +1,272 @@
+package forward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gocryptotrader/log"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	// maxSignaturesPerStatusQuery getSignatureStatuses 单次请求允许的最大签名数量
+	maxSignaturesPerStatusQuery = 256
+	// defaultPollInterval 未配置 PollInterval 时查询签名状态的间隔
+	defaultPollInterval = 2 * time.Second
+)
+
+var (
+	errBlockhashExpired = errors.New("交易未上链且 blockhash 已过期")
+	errNonceAdvanced    = errors.New("交易未上链且 nonce 已推进")
+)
+
+// commitmentLevels 确认级别由低到高的顺序
+var commitmentLevels = map[rpc.ConfirmationStatusType]int{
+	rpc.ConfirmationStatusProcessed: 0,
+	rpc.ConfirmationStatusConfirmed: 1,
+	rpc.ConfirmationStatusFinalized: 2,
+}
+
+// commitmentReached 判断交易的确认状态是否已达到要求的确认级别
+func commitmentReached(status rpc.ConfirmationStatusType, want rpc.CommitmentType) bool {
+	got, ok := commitmentLevels[status]
+	if !ok {
+		return false
+	}
+	required, ok := commitmentLevels[rpc.ConfirmationStatusType(want)]
+	if !ok {
+		required = commitmentLevels[rpc.ConfirmationStatusConfirmed]
+	}
+	return got >= required
+}
+
+// track 轮询已发送批次的签名状态，直到全部达到配置的确认级别、链上执行失败或过期
+// 未上链且 blockhash 已过期或 nonce 已推进的批次使用新的 blockhash 或 nonce 重新签名发送，
+// 重发次数超过 MaxResends 或无法重新签名时标记为 expired。超过 ConfirmTimeout 仍未确定结果的批次保持 sent。
+func (m *Manager) track(ctx context.Context, client *rpc.Client, signer accountSigner, batches []*Batch, cfg *Config, observer Observer) error {
+	trackCtx, cancel := context.WithTimeout(ctx, cfg.ConfirmTimeout)
+	defer cancel()
+
+	interval := cfg.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	resends := make(map[*Batch]int)
+	for hasInFlight(batches) {
+		select {
+		case <-trackCtx.Done():
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			log.Warnf(log.Global, "等待交易确认超时，仍有批次未确认")
+			return nil
+		case <-ticker.C:
+		}
+
+		expired, err := checkStatuses(trackCtx, client, batches, cfg.Commitment, observer)
+		if err != nil {
+			// 查询失败时在下一个周期重试
+			log.Warnf(log.Global, "查询交易状态失败: %v", err)
+			continue
+		}
+
+		var resend []*Batch
+		for _, b := range expired {
+			if !canResend(signer, b) {
+				b.Status, b.Err = StatusExpired, expiryError(b)
+				log.Errorf(log.Global, "批次 %d %v，无法重新签名，标记为过期", b.Index, b.Err)
+				notifyObserver(observer, b)
+				continue
+			}
+			if resends[b] >= cfg.MaxResends {
+				b.Status, b.Err = StatusExpired, expiryError(b)
+				log.Errorf(log.Global, "批次 %d 已重发 %d 次仍未上链，标记为过期", b.Index, resends[b])
+				notifyObserver(observer, b)
+				continue
+			}
+			resends[b]++
+			resend = append(resend, b)
+		}
+		if len(resend) == 0 {
+			continue
+		}
+		log.Warnf(log.Global, "%d 个批次的 blockhash 已过期或 nonce 已推进，重新签名发送", len(resend))
+		if err := m.send(trackCtx, client, signer, resend, cfg, observer); err != nil {
+			log.Errorf(log.Global, "重新发送批次失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// canResend 判断过期的批次能否重新签名发送，使用 nonce 的批次还需要可用的新 nonce 值
+func canResend(signer accountSigner, b *Batch) bool {
+	return signer.signer != nil && (b.Nonce == "" || b.nonce != nil)
+}
+
+// expiryError 返回批次过期的原因
+func expiryError(b *Batch) error {
+	if b.Nonce != "" {
+		return errNonceAdvanced
+	}
+	return errBlockhashExpired
+}
+
+// Reconcile 查询状态为 sent 的批次在链上的结果并更新批次状态
+// 达到配置确认级别的批次标记为 confirmed，链上执行失败的标记为 failed；
+// 未上链且 blockhash 已过期或 nonce 已推进的批次标记为 expired，可以安全地重新发送；
+// 其余批次保持 sent，需要稍后再次查询。
+func (m *Manager) Reconcile(ctx context.Context, cfg *Config, batches []*Batch) error {
+	if !hasInFlight(batches) {
+		return nil
+	}
+	rpcClient, err := m.client()
+	if err != nil {
+		return err
+	}
+	expired, err := checkStatuses(ctx, rpcClient, batches, cfg.Commitment, nil)
+	if err != nil {
+		return err
+	}
+	for _, b := range expired {
+		b.Status, b.Err = StatusExpired, expiryError(b)
+	}
+	return nil
+}
+
+// checkStatuses 查询状态为 sent 的批次的签名状态，更新已确认和执行失败的批次，
+// 并返回未上链且 blockhash 已过期或 nonce 已推进的批次，由调用方决定重新发送或标记为过期。
+// 使用 nonce 的过期批次会更新为链上当前的 nonce 值，nonce 账户不可用时清除批次的 nonce
+func checkStatuses(ctx context.Context, client *rpc.Client, batches []*Batch, commitment rpc.CommitmentType, observer Observer) ([]*Batch, error) {
+	var inFlight []*Batch
+	for _, b := range batches {
+		if b.Status == StatusSent && b.Signature != "" {
+			inFlight = append(inFlight, b)
+		}
+	}
+	if len(inFlight) == 0 {
+		return nil, nil
+	}
+
+	// 区块高度需在查询签名状态之前获取，避免把查询之后才上链的交易误判为过期
+	blockHeight, err := client.GetBlockHeight(ctx, rpc.CommitmentConfirmed)
+	if err != nil {
+		return nil, fmt.Errorf("获取区块高度失败: %w", err)
+	}
+	// nonce 同样需在查询签名状态之前获取
+	nonces, err := currentNonces(ctx, client, inFlight)
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []*Batch
+	for i := 0; i < len(inFlight); i += maxSignaturesPerStatusQuery {
+		end := i + maxSignaturesPerStatusQuery
+		if end > len(inFlight) {
+			end = len(inFlight)
+		}
+		group := inFlight[i:end]
+
+		signatures := make([]solana.Signature, len(group))
+		for j := range group {
+			signatures[j], err = solana.SignatureFromBase58(group[j].Signature)
+			if err != nil {
+				return nil, fmt.Errorf("无效的交易签名 %s: %w", group[j].Signature, err)
+			}
+		}
+
+		statuses, err := client.GetSignatureStatuses(ctx, true, signatures...)
+		if err != nil {
+			return nil, fmt.Errorf("查询交易状态失败: %w", err)
+		}
+		for j, b := range group {
+			var status *rpc.SignatureStatusesResult
+			if j < len(statuses.Value) {
+				status = statuses.Value[j]
+			}
+			switch {
+			case status == nil:
+				if b.Nonce != "" {
+					if current := nonces[b.NonceAccount]; current == nil || current.nonce.String() != b.Nonce {
+						refreshNonce(b, current)
+						expired = append(expired, b)
+					}
+				} else if b.LastValidBlockHeight != 0 && blockHeight > b.LastValidBlockHeight {
+					expired = append(expired, b)
+				}
+			case status.Err != nil:
+				b.Status, b.Err = StatusFailed, fmt.Errorf("链上执行失败: %v", status.Err)
+				log.Errorf(log.Global, "批次 %d 交易 %s %v", b.Index, b.Signature, b.Err)
+				notifyObserver(observer, b)
+			case commitmentReached(status.ConfirmationStatus, commitment):
+				b.Status, b.Err = StatusConfirmed, nil
+				log.Infof(log.Global, "交易已确认: %s", b.Signature)
+				notifyObserver(observer, b)
+			}
+		}
+	}
+	return expired, nil
+}
+
+// currentNonces 查询批次使用的 nonce 账户的链上数据，账户不存在或未初始化时对应的值为 nil
+func currentNonces(ctx context.Context, client *rpc.Client, batches []*Batch) (map[string]*nonceData, error) {
+	nonces := make(map[string]*nonceData)
+	var keys []solana.PublicKey
+	for _, b := range batches {
+		if b.Nonce == "" {
+			continue
+		}
+		if _, ok := nonces[b.NonceAccount]; ok {
+			continue
+		}
+		key, err := solana.PublicKeyFromBase58(b.NonceAccount)
+		if err != nil {
+			return nil, fmt.Errorf("无效的 nonce 账户地址 %s: %w", b.NonceAccount, err)
+		}
+		nonces[b.NonceAccount] = nil
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nonces, nil
+	}
+	accounts, err := fetchAccounts(ctx, client, keys)
+	if err != nil {
+		return nil, err
+	}
+	for i, account := range accounts {
+		if account == nil {
+			continue
+		}
+		if data, err := parseNonceAccount(account.Data.GetBinary()); err == nil {
+			nonces[keys[i].String()] = data
+		}
+	}
+	return nonces, nil
+}
+
+// refreshNonce 将批次的 nonce 更新为链上当前的值，重发时使用；nonce 账户不可用时清除批次的 nonce
+func refreshNonce(b *Batch, current *nonceData) {
+	if b.nonce == nil {
+		return
+	}
+	if current == nil {
+		b.nonce = nil
+		return
+	}
+	b.nonce.value = current.nonce
+}
+
+func hasInFlight(batches []*Batch) bool {
+	for _, b := range batches {
+		if b.Status == StatusSent && b.Signature != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,376 @@
+package forward
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"gocryptotrader/config"
+	"gocryptotrader/exchanges/rpcpool"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	confirmedStatus = map[string]any{"slot": 1, "confirmations": nil, "err": nil, "confirmationStatus": "confirmed"}
+	failedStatus    = map[string]any{"slot": 1, "confirmations": nil, "err": map[string]any{"InstructionError": []any{0, map[string]any{"Custom": 1}}}, "confirmationStatus": "confirmed"}
+)
+
+// mockChain 模拟节点上的区块高度、交易状态和 nonce 账户
+type mockChain struct {
+	mu          sync.Mutex
+	height      uint64
+	heightStep  uint64            // 每次查询区块高度后增加的高度
+	statuses    map[string]any    // 签名对应的交易状态，不存在时视为未上链
+	nonces      map[string][]byte // nonce 账户地址对应的账户数据
+	sent        []*solana.Transaction
+	confirmSent bool // 发送的交易立即确认
+}
+
+func newMockChain(height uint64) *mockChain {
+	return &mockChain{height: height, statuses: make(map[string]any), nonces: make(map[string][]byte)}
+}
+
+func (c *mockChain) server(t *testing.T) *httptest.Server {
+	t.Helper()
+	return rpcServer(t, map[string]rpcHandler{
+		"getBlockHeight": func([]json.RawMessage) (any, error) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			height := c.height
+			c.height += c.heightStep
+			return height, nil
+		},
+		"getLatestBlockhash": func([]json.RawMessage) (any, error) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return rpcValue(map[string]any{"blockhash": solana.Hash(solana.NewWallet().PublicKey()).String(), "lastValidBlockHeight": c.height + 10}), nil
+		},
+		"getSignatureStatuses": func(params []json.RawMessage) (any, error) {
+			var signatures []string
+			if err := json.Unmarshal(params[0], &signatures); err != nil {
+				return nil, err
+			}
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			value := make([]any, len(signatures))
+			for i, sig := range signatures {
+				value[i] = c.statuses[sig]
+			}
+			return rpcValue(value), nil
+		},
+		"getMultipleAccounts": func(params []json.RawMessage) (any, error) {
+			var keys []string
+			if err := json.Unmarshal(params[0], &keys); err != nil {
+				return nil, err
+			}
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			value := make([]any, len(keys))
+			for i, k := range keys {
+				if data, ok := c.nonces[k]; ok {
+					value[i] = rpcAccount(solana.SystemProgramID, 1447680, data)
+				}
+			}
+			return rpcValue(value), nil
+		},
+		"sendTransaction": func(params []json.RawMessage) (any, error) {
+			var raw string
+			if err := json.Unmarshal(params[0], &raw); err != nil {
+				return nil, err
+			}
+			tx, err := solana.TransactionFromBase64(raw)
+			if err != nil {
+				return nil, err
+			}
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.sent = append(c.sent, tx)
+			if c.confirmSent {
+				c.statuses[tx.Signatures[0].String()] = confirmedStatus
+			}
+			return tx.Signatures[0].String(), nil
+		},
+	})
+}
+
+func (c *mockChain) sentTransactions() []*solana.Transaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*solana.Transaction(nil), c.sent...)
+}
+
+func (c *mockChain) setStatus(signature string, status any) {
+	c.mu.Lock()
+	c.statuses[signature] = status
+	c.mu.Unlock()
+}
+
+func (c *mockChain) setNonce(account, authority solana.PublicKey, nonce solana.Hash) {
+	c.mu.Lock()
+	c.nonces[account.String()] = nonceAccountData(authority, nonce)
+	c.mu.Unlock()
+}
+
+// sentBatch 返回已签名发送、等待确认的批次
+func sentBatch(t *testing.T, index int, key solana.PrivateKey, lastValid uint64) *Batch {
+	t.Helper()
+	b := transferBatch(index, 1, key.PublicKey())
+	tx, err := buildTransaction(context.Background(), b, nil, solana.Hash{byte(index + 1)}, testSigner(key))
+	require.NoError(t, err)
+	b.Signature, b.LastValidBlockHeight, b.Status = tx.Signatures[0].String(), lastValid, StatusSent
+	return b
+}
+
+// nonceBatch 返回使用 durable nonce 签名发送、等待确认的批次
+func nonceBatch(t *testing.T, index int, key solana.PrivateKey, account solana.PublicKey, nonce solana.Hash) *Batch {
+	t.Helper()
+	b := sentBatch(t, index, key, 0)
+	b.nonce = &nonceState{account: account, value: nonce}
+	b.NonceAccount, b.Nonce = account.String(), nonce.String()
+	return b
+}
+
+// trackConfig 返回快速轮询的确认配置
+func trackConfig(timeout time.Duration, maxResends int) *Config {
+	cfg := DefaultConfig()
+	cfg.PriorityFeePercentile = 0
+	cfg.PollInterval = 5 * time.Millisecond
+	cfg.ConfirmTimeout = timeout
+	cfg.MaxResends = maxResends
+	return cfg
+}
+
+// countObserver 统计批次状态更新
+type countObserver struct {
+	mu      sync.Mutex
+	updated map[int]int
+}
+
+func (o *countObserver) BatchSigned(*Batch) error { return nil }
+
+func (o *countObserver) BatchUpdated(b *Batch) {
+	o.mu.Lock()
+	if o.updated == nil {
+		o.updated = make(map[int]int)
+	}
+	o.updated[b.Index]++
+	o.mu.Unlock()
+}
+
+func TestCommitmentReached(t *testing.T) {
+	t.Parallel()
+	assert.True(t, commitmentReached(rpc.ConfirmationStatusConfirmed, rpc.CommitmentConfirmed))
+	assert.True(t, commitmentReached(rpc.ConfirmationStatusFinalized, rpc.CommitmentConfirmed))
+	assert.False(t, commitmentReached(rpc.ConfirmationStatusProcessed, rpc.CommitmentConfirmed))
+	assert.False(t, commitmentReached(rpc.ConfirmationStatusConfirmed, rpc.CommitmentFinalized))
+	assert.True(t, commitmentReached(rpc.ConfirmationStatusProcessed, rpc.CommitmentProcessed))
+	assert.False(t, commitmentReached("", rpc.CommitmentProcessed), "an unknown status must never count as reached")
+	assert.False(t, commitmentReached(rpc.ConfirmationStatusProcessed, ""), "an unset commitment should default to confirmed")
+}
+
+func TestResultSignatures(t *testing.T) {
+	t.Parallel()
+	r := &Result{Batches: []*Batch{
+		{Signature: "a", Status: StatusConfirmed},
+		{Signature: "b", Status: StatusSent},
+		{Signature: "c", Status: StatusFailed},
+		{Signature: "d", Status: StatusExpired},
+		{Status: StatusPending},
+	}}
+	assert.Equal(t, []string{"a", "b"}, r.Signatures())
+}
+
+func TestTrack(t *testing.T) {
+	t.Parallel()
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	chain := newMockChain(100)
+	server := chain.server(t)
+	defer server.Close()
+
+	confirmed, failed := sentBatch(t, 0, key, 150), sentBatch(t, 1, key, 150)
+	pending := &Batch{Index: 2, Status: StatusPending}
+	chain.setStatus(confirmed.Signature, confirmedStatus)
+	chain.setStatus(failed.Signature, failedStatus)
+
+	observer := &countObserver{}
+	err = (&Manager{}).track(context.Background(), rpc.New(server.URL), testSigner(key), []*Batch{confirmed, failed, pending}, trackConfig(time.Second, 3), observer)
+	require.NoError(t, err, "track must not error")
+	assert.Equal(t, StatusConfirmed, confirmed.Status)
+	assert.Equal(t, StatusFailed, failed.Status)
+	assert.ErrorContains(t, failed.Err, "链上执行失败")
+	assert.Equal(t, StatusPending, pending.Status, "unsent batches must not be tracked")
+	assert.Equal(t, map[int]int{0: 1, 1: 1}, observer.updated, "every settled batch must be reported once")
+	assert.Empty(t, chain.sentTransactions(), "settled batches must not be resent")
+}
+
+func TestTrackResendsExpired(t *testing.T) {
+	t.Parallel()
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+
+	// 区块高度每次查询都超过新 blockhash 的有效高度，交易始终过期
+	chain := newMockChain(200)
+	chain.heightStep = 50
+	server := chain.server(t)
+	defer server.Close()
+
+	b := sentBatch(t, 0, key, 150)
+	first := b.Signature
+	err = (&Manager{}).track(context.Background(), rpc.New(server.URL), testSigner(key), []*Batch{b}, trackConfig(5*time.Second, 2), nil)
+	require.NoError(t, err, "track must not error")
+	assert.Equal(t, StatusExpired, b.Status, "a batch must expire once MaxResends is used up")
+	assert.ErrorIs(t, b.Err, errBlockhashExpired)
+	sent := chain.sentTransactions()
+	require.Len(t, sent, 2, "an expired batch must be resent MaxResends times")
+	assert.NotEqual(t, first, b.Signature, "a resent batch must be signed again")
+	assert.Equal(t, sent[1].Signatures[0].String(), b.Signature)
+
+	// 重发的交易确认后批次标记为 confirmed
+	chain = newMockChain(200)
+	chain.confirmSent = true
+	server = chain.server(t)
+	defer server.Close()
+
+	b = sentBatch(t, 0, key, 150)
+	err = (&Manager{}).track(context.Background(), rpc.New(server.URL), testSigner(key), []*Batch{b}, trackConfig(5*time.Second, 2), nil)
+	require.NoError(t, err, "track must not error")
+	assert.Equal(t, StatusConfirmed, b.Status)
+	assert.Len(t, chain.sentTransactions(), 1)
+
+	// MaxResends 为 0 时过期的批次不会重发
+	b = sentBatch(t, 0, key, 150)
+	err = (&Manager{}).track(context.Background(), rpc.New(server.URL), testSigner(key), []*Batch{b}, trackConfig(5*time.Second, 0), nil)
+	require.NoError(t, err, "track must not error")
+	assert.Equal(t, StatusExpired, b.Status)
+	assert.Len(t, chain.sentTransactions(), 1)
+}
+
+func TestTrackTimeout(t *testing.T) {
+	t.Parallel()
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	chain := newMockChain(100)
+	server := chain.server(t)
+	defer server.Close()
+
+	// blockhash 未过期且交易未上链，超时后批次保持 sent
+	b := sentBatch(t, 0, key, 150)
+	err = (&Manager{}).track(context.Background(), rpc.New(server.URL), testSigner(key), []*Batch{b}, trackConfig(50*time.Millisecond, 3), nil)
+	require.NoError(t, err, "a confirmation timeout must not be an error")
+	assert.Equal(t, StatusSent, b.Status)
+	assert.Empty(t, chain.sentTransactions())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = (&Manager{}).track(ctx, rpc.New(server.URL), testSigner(key), []*Batch{b}, trackConfig(time.Second, 3), nil)
+	assert.ErrorIs(t, err, context.Canceled, "a cancelled context must be returned")
+	assert.Equal(t, StatusSent, b.Status)
+
+	// 查询失败时在下一个周期重试，直到超时
+	failing := rpcServer(t, map[string]rpcHandler{
+		"getBlockHeight": func([]json.RawMessage) (any, error) { return nil, errors.New("node is behind") },
+	})
+	defer failing.Close()
+	err = (&Manager{}).track(context.Background(), rpc.New(failing.URL), testSigner(key), []*Batch{b}, trackConfig(50*time.Millisecond, 3), nil)
+	require.NoError(t, err, "query errors must be retried until the timeout")
+	assert.Equal(t, StatusSent, b.Status)
+}
+
+func TestTrackNonce(t *testing.T) {
+	t.Parallel()
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	account := solana.NewWallet().PublicKey()
+	used, advanced := solana.Hash{1}, solana.Hash{2}
+
+	// 最后有效区块高度为 0 的批次不会因区块高度过期
+	chain := newMockChain(1000)
+	chain.confirmSent = true
+	chain.setNonce(account, key.PublicKey(), used)
+	server := chain.server(t)
+	defer server.Close()
+
+	b := nonceBatch(t, 0, key, account, used)
+	err = (&Manager{}).track(context.Background(), rpc.New(server.URL), testSigner(key), []*Batch{b}, trackConfig(50*time.Millisecond, 3), nil)
+	require.NoError(t, err, "track must not error")
+	assert.Equal(t, StatusSent, b.Status, "a nonce batch must stay sent while its nonce is unused")
+	assert.Empty(t, chain.sentTransactions())
+
+	// nonce 推进后使用新的 nonce 重新签名发送
+	chain.setNonce(account, key.PublicKey(), advanced)
+	err = (&Manager{}).track(context.Background(), rpc.New(server.URL), testSigner(key), []*Batch{b}, trackConfig(5*time.Second, 3), nil)
+	require.NoError(t, err, "track must not error")
+	assert.Equal(t, StatusConfirmed, b.Status)
+	sent := chain.sentTransactions()
+	require.Len(t, sent, 1)
+	assert.Equal(t, advanced, sent[0].Message.RecentBlockhash, "the resend must use the advanced nonce")
+	assert.Equal(t, advanced.String(), b.Nonce)
+	assert.Zero(t, b.LastValidBlockHeight)
+
+	// 没有签名方或 nonce 账户已关闭时无法重新签名，直接标记为过期
+	b = nonceBatch(t, 1, key, account, used)
+	err = (&Manager{}).track(context.Background(), rpc.New(server.URL), accountSigner{}, []*Batch{b}, trackConfig(5*time.Second, 3), nil)
+	require.NoError(t, err, "track must not error")
+	assert.Equal(t, StatusExpired, b.Status)
+	assert.ErrorIs(t, b.Err, errNonceAdvanced)
+
+	closed := solana.NewWallet().PublicKey()
+	b = nonceBatch(t, 2, key, closed, used)
+	err = (&Manager{}).track(context.Background(), rpc.New(server.URL), testSigner(key), []*Batch{b}, trackConfig(5*time.Second, 3), nil)
+	require.NoError(t, err, "track must not error")
+	assert.Equal(t, StatusExpired, b.Status)
+	assert.ErrorIs(t, b.Err, errNonceAdvanced)
+	assert.Len(t, chain.sentTransactions(), 1, "batches that cannot be signed again must not be resent")
+}
+
+func TestReconcile(t *testing.T) {
+	t.Parallel()
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	account := solana.NewWallet().PublicKey()
+	used := solana.Hash{1}
+
+	chain := newMockChain(200)
+	chain.setNonce(account, key.PublicKey(), used)
+	server := chain.server(t)
+	defer server.Close()
+	pool, err := rpcpool.New(&config.SolanaConfig{Endpoints: []config.SolanaRPCEndpoint{{Name: "mock", URL: server.URL}}})
+	require.NoError(t, err)
+	m := New(nil, pool)
+
+	confirmed := sentBatch(t, 0, key, 150)
+	failed := sentBatch(t, 1, key, 150)
+	expired := sentBatch(t, 2, key, 150)
+	inFlight := sentBatch(t, 3, key, 250)
+	nonceUnused := nonceBatch(t, 4, key, account, used)
+	nonceAdvanced := nonceBatch(t, 5, key, account, solana.Hash{2})
+	nonceConfirmed := nonceBatch(t, 6, key, solana.NewWallet().PublicKey(), solana.Hash{3})
+	chain.setStatus(confirmed.Signature, confirmedStatus)
+	chain.setStatus(failed.Signature, failedStatus)
+	chain.setStatus(nonceConfirmed.Signature, confirmedStatus)
+
+	batches := []*Batch{confirmed, failed, expired, inFlight, nonceUnused, nonceAdvanced, nonceConfirmed}
+	require.NoError(t, m.Reconcile(context.Background(), trackConfig(time.Second, 3), batches), "Reconcile must not error")
+	assert.Equal(t, StatusConfirmed, confirmed.Status)
+	assert.Equal(t, StatusFailed, failed.Status)
+	assert.Equal(t, StatusExpired, expired.Status)
+	assert.ErrorIs(t, expired.Err, errBlockhashExpired)
+	assert.Equal(t, StatusSent, inFlight.Status, "a batch whose blockhash is still valid must stay sent")
+	assert.Equal(t, StatusSent, nonceUnused.Status, "a nonce batch must stay sent while its nonce is unused")
+	assert.Equal(t, StatusExpired, nonceAdvanced.Status, "a nonce batch must expire once its nonce advances")
+	assert.ErrorIs(t, nonceAdvanced.Err, errNonceAdvanced)
+	assert.Equal(t, StatusConfirmed, nonceConfirmed.Status, "a landed transaction must be confirmed even though its nonce account moved on")
+	assert.Empty(t, chain.sentTransactions(), "Reconcile must never send transactions")
+
+	assert.NoError(t, m.Reconcile(context.Background(), trackConfig(time.Second, 3), []*Batch{{Status: StatusConfirmed}}), "Reconcile must not query the node without in-flight batches")
+	assert.ErrorIs(t, New(nil, nil).Reconcile(context.Background(), trackConfig(time.Second, 3), []*Batch{inFlight}), rpcpool.ErrNilPool)
+}
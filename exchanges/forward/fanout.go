@@ -0,0 +1,121 @@
+package forward
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Balance 钱包的 SOL 余额及关联代币账户中的代币余额
+type Balance struct {
+	Address  string
+	Lamports uint64
+	Tokens   float64 // 代币余额，未查询代币或关联代币账户不存在时为 0
+	Decimals uint8   // 代币精度，未查询代币时为 0
+}
+
+// Balances 批量查询多个钱包的 SOL 余额，tokenMint 不为空时同时查询各钱包关联代币账户的代币余额
+func (m *Manager) Balances(ctx context.Context, owners []string, tokenMint string) ([]Balance, error) {
+	keys := make([]solana.PublicKey, len(owners))
+	for i := range owners {
+		key, err := solana.PublicKeyFromBase58(owners[i])
+		if err != nil {
+			return nil, fmt.Errorf("无效地址 %s: %w", owners[i], err)
+		}
+		keys[i] = key
+	}
+	rpcClient, err := m.client()
+	if err != nil {
+		return nil, err
+	}
+
+	var mint *mintInfo
+	if tokenMint != "" {
+		mintKey, err := solana.PublicKeyFromBase58(tokenMint)
+		if err != nil {
+			return nil, fmt.Errorf("无效的代币铸币地址: %w", err)
+		}
+		if mint, err = fetchMint(ctx, rpcClient, mintKey); err != nil {
+			return nil, err
+		}
+		for i := range owners {
+			account, err := mint.associatedTokenAddress(keys[i])
+			if err != nil {
+				return nil, fmt.Errorf("查找关联代币账户失败: %w", err)
+			}
+			keys = append(keys, account)
+		}
+	}
+	infos, err := fetchAccounts(ctx, rpcClient, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]Balance, len(owners))
+	for i := range owners {
+		balances[i].Address = owners[i]
+		if infos[i] != nil {
+			balances[i].Lamports = infos[i].Lamports
+		}
+		if mint == nil {
+			continue
+		}
+		balances[i].Decimals = mint.decimals
+		if info := infos[len(owners)+i]; info != nil {
+			amount, _, err := parseTokenAccount(info.Data.GetBinary())
+			if err != nil {
+				return nil, fmt.Errorf("解析 %s 的代币账户失败: %w", owners[i], err)
+			}
+			balances[i].Tokens = FromBaseUnits(amount, mint.decimals)
+		}
+	}
+	return balances, nil
+}
+
+// SplitRecipients 将接收者按列表顺序拆分为 parts 段连续的子列表，各段数量最多相差一个；
+// 接收者少于 parts 时只返回非空的子列表
+func SplitRecipients(recipients []Recipient, parts int) [][]Recipient {
+	parts = min(parts, len(recipients))
+	if parts <= 0 {
+		return nil
+	}
+	out := make([][]Recipient, parts)
+	size, extra := len(recipients)/parts, len(recipients)%parts
+	start := 0
+	for i := range out {
+		end := start + size
+		if i < extra {
+			end++
+		}
+		out[i] = recipients[start:end:end]
+		start = end
+	}
+	return out
+}
+
+// MergeResults 将多个源账户的转账结果合并为一个结果，批次按结果顺序排列，
+// 调用方需保证各结果的批次序号互不重复；资金检查结果为各源账户的合计
+func MergeResults(results []*Result) *Result {
+	merged := &Result{}
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		merged.Batches = append(merged.Batches, r.Batches...)
+		merged.Skipped = append(merged.Skipped, r.Skipped...)
+		merged.Unpaid = append(merged.Unpaid, r.Unpaid...)
+		merged.LookupTables = append(merged.LookupTables, r.LookupTables...)
+		merged.TransferFee += r.TransferFee
+		if r.Preflight == nil {
+			continue
+		}
+		if merged.Preflight == nil {
+			merged.Preflight = &Preflight{Decimals: r.Preflight.Decimals, wrap: r.Preflight.wrap}
+		}
+		merged.Preflight.add(r.Preflight)
+		merged.Preflight.LamportBalance += r.Preflight.LamportBalance
+		merged.Preflight.TokenBalance += r.Preflight.TokenBalance
+	}
+	return merged
+}
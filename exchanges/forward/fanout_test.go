@@ -0,0 +1,55 @@
+package forward
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitRecipients(t *testing.T) {
+	t.Parallel()
+	recipients := make([]Recipient, 7)
+	for i := range recipients {
+		recipients[i].ID = int64(i)
+	}
+
+	parts := SplitRecipients(recipients, 3)
+	require.Len(t, parts, 3)
+	assert.Len(t, parts[0], 3)
+	assert.Len(t, parts[1], 2)
+	assert.Len(t, parts[2], 2)
+	assert.Equal(t, int64(3), parts[1][0].ID, "recipients must stay in list order")
+	assert.Equal(t, int64(6), parts[2][1].ID)
+
+	assert.Len(t, SplitRecipients(recipients[:2], 5), 2, "empty parts must not be returned")
+	assert.Empty(t, SplitRecipients(nil, 3))
+	assert.Empty(t, SplitRecipients(recipients, 0))
+}
+
+func TestMergeResults(t *testing.T) {
+	t.Parallel()
+	a := &Result{
+		Batches:     []*Batch{{Index: 0}},
+		Unpaid:      []Recipient{{Address: "a"}},
+		TransferFee: 1,
+		Preflight:   &Preflight{Transfers: 10, Fees: 5000, LamportBalance: 100},
+	}
+	b := &Result{
+		Batches:     []*Batch{{Index: 7}, {Index: 8}},
+		Skipped:     []Recipient{{Address: "b"}},
+		TransferFee: 2,
+		Preflight:   &Preflight{Transfers: 20, Fees: 10000, LamportBalance: 50},
+	}
+
+	merged := MergeResults([]*Result{a, nil, b})
+	require.Len(t, merged.Batches, 3)
+	assert.Equal(t, 7, merged.Batches[1].Index)
+	assert.Len(t, merged.Unpaid, 1)
+	assert.Len(t, merged.Skipped, 1)
+	assert.Equal(t, 3.0, merged.TransferFee)
+	require.NotNil(t, merged.Preflight)
+	assert.Equal(t, uint64(30), merged.Preflight.Transfers)
+	assert.Equal(t, uint64(15000), merged.Preflight.Fees)
+	assert.Equal(t, uint64(150), merged.Preflight.LamportBalance)
+}
@@ -4,131 +4,116 @@ import (
 	"bufio"
 	"context"
 	"fmt"
-	"math"
 	"os"
-	"sync"
+	"strings"
 
 	"gocryptotrader/config"
+	"gocryptotrader/exchanges/rpcpool"
 	"gocryptotrader/log"
 
 	"github.com/gagliardetto/solana-go"
-	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
 	"github.com/gagliardetto/solana-go/programs/system"
-	"github.com/gagliardetto/solana-go/programs/token"
 	"github.com/gagliardetto/solana-go/rpc"
 )
 
 // Manager 管理SOL转发相关操作
 type Manager struct {
 	config *config.Config
+	pool   *rpcpool.Pool
 }
 
-// New 创建一个新的SOL转发管理器
-func New(cfg *config.Config) *Manager {
-	return &Manager{config: cfg}
+// New 创建一个新的SOL转发管理器，所有链上请求都通过 RPC 节点池发送
+func New(cfg *config.Config, pool *rpcpool.Pool) *Manager {
+	return &Manager{config: cfg, pool: pool}
+}
+
+// client 返回由 RPC 节点池提供服务的客户端
+func (m *Manager) client() (*rpc.Client, error) {
+	if m.pool == nil {
+		return nil, rpcpool.ErrNilPool
+	}
+	return m.pool.Client(), nil
 }
 
 // TransferSOL 将 SOL 发送到多个地址
-func (m *Manager) TransferSOL(ctx context.Context, req *ForwardRequest) ([]string, error) {
-	// 解析私钥
-	privateKey, err := solana.PrivateKeyFromBase58(req.PrivateKeyStr)
+func (m *Manager) TransferSOL(ctx context.Context, req *ForwardRequest) (*Result, error) {
+	// 发送者的交易由签名方签名
+	signer, err := newAccountSigner(req.Signer, req.From)
 	if err != nil {
-		return nil, fmt.Errorf("无效的私钥: %w", err)
+		return nil, err
 	}
-	from := privateKey.PublicKey()
-
-	// 将 SOL 金额转换为 lamports（1 SOL = 10^9 lamports）
-	amountLamport := uint64(req.Config.AmountSOL * 1e9)
+	from := signer.PublicKey()
 
-	// 创建 RPC 客户端
-	rpcClient := rpc.New(req.Config.RPCEndpoint)
+	// 合并接收者列表，未指定数量的接收者使用默认 SOL 数量
+	recipients := ResolveRecipients(req.Recipients, req.Addresses, req.Config.AmountSOL)
 
-	// 获取最新的 blockhash
-	recent, err := rpcClient.GetLatestBlockhash(ctx, rpc.CommitmentFinalized)
+	// 从 RPC 节点池获取客户端
+	rpcClient, err := m.client()
 	if err != nil {
-		return nil, fmt.Errorf("获取最新 blockhash 失败: %w", err)
+		return nil, err
 	}
 
-	// 将地址分组，每组生成一笔交易
-	var txs []*solana.Transaction
-	for i := 0; i < len(req.Addresses); i += req.Config.MaxInstructionsPerTx {
-		end := i + req.Config.MaxInstructionsPerTx
-		if end > len(req.Addresses) {
-			end = len(req.Addresses)
-		}
-		group := req.Addresses[i:end]
+	if err = req.Config.checkMemos(recipients); err != nil {
+		return nil, err
+	}
 
-		var instructions []solana.Instruction
-		for _, toStr := range group {
-			to, err := solana.PublicKeyFromBase58(toStr)
-			if err != nil {
-				log.Warnf(log.Global, "无效地址: %s，已跳过", toStr)
-				continue
-			}
-			ix := system.NewTransferInstruction(amountLamport, from, to).Build()
-			instructions = append(instructions, ix)
+	// 按交易大小和计算单元装填批次，每个批次生成一笔交易；接收者备注的长度不同，每个接收者占用的空间也不同
+	result := &Result{}
+	var (
+		batch    *Batch
+		capacity *txCapacity
+	)
+	for _, recipient := range recipients {
+		to, err := solana.PublicKeyFromBase58(recipient.Address)
+		if err != nil {
+			log.Warnf(log.Global, "无效地址: %s，已跳过", recipient.Address)
+			result.Skipped = append(result.Skipped, recipient)
+			continue
 		}
 
-		tx, err := solana.NewTransaction(instructions, recent.Value.Blockhash, solana.TransactionPayer(from))
+		// 将 SOL 数量转换为 lamports（1 SOL = 10^9 lamports）
+		amountLamport, err := recipientUnits(&recipient, 9)
 		if err != nil {
-			log.Errorf(log.Global, "创建交易失败: %v", err)
+			log.Warnf(log.Global, "%s: %v，已跳过", recipient.Address, err)
+			result.Skipped = append(result.Skipped, recipient)
 			continue
 		}
-		txs = append(txs, tx)
-	}
 
-	// 并发发送交易
-	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, req.Config.ConcurrentTxs)
-	var mu sync.Mutex
-	var txSignatures []string
-
-	for _, tx := range txs {
-		wg.Add(1)
-		semaphore <- struct{}{}
-		go func(tx *solana.Transaction) {
-			defer wg.Done()
-			defer func() { <-semaphore }()
-
-			_, err = tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
-				if key.Equals(from) {
-					return &privateKey
-				}
-				return nil
-			})
-			if err != nil {
-				log.Errorf(log.Global, "签名交易失败: %v", err)
-				return
-			}
+		memo := req.Config.recipientMemo(&recipient)
+		layout := solLayout.withMemo(req.Config, memo)
+		if batch == nil || !capacity.reserve(layout) {
+			batch = &Batch{Index: len(result.Batches), Status: StatusPending, Memo: req.Config.transactionMemo()}
+			result.Batches = append(result.Batches, batch)
+			capacity = req.Config.newTxCapacity()
+			capacity.reserve(layout)
+		}
 
-			txSig, err := rpcClient.SendTransactionWithOpts(ctx, tx, rpc.TransactionOpts{
-				SkipPreflight:       false,
-				PreflightCommitment: rpc.CommitmentFinalized,
-			})
-			if err != nil {
-				log.Errorf(log.Global, "发送交易失败: %v", err)
-				return
-			}
-			log.Infof(log.Global, "交易已发送: %s", txSig)
+		cost := recipientCost{computeUnits: systemTransferUnits, lamports: amountLamport}
+		instructions := []solana.Instruction{system.NewTransferInstruction(amountLamport, from, to).Build()}
+		if memo != "" {
+			instructions = append(instructions, memoInstruction(memo, from))
+			cost.computeUnits += memoUnits(memo)
+		}
+		batch.add(recipient, cost, instructions...)
+	}
 
-			mu.Lock()
-			txSignatures = append(txSignatures, txSig.String())
-			mu.Unlock()
-		}(tx)
+	// 发送前检查余额是否足以支付转账、交易费和优先费
+	if err = m.preflight(ctx, rpcClient, from, nil, result, req.Config, req.DryRun); err != nil {
+		return result, err
 	}
 
-	wg.Wait()
-	return txSignatures, nil
+	err = m.run(ctx, rpcClient, signer, result, req.Config, req.Observer, req.DryRun, req.SignOnly)
+	return result, err
 }
 
 // TransferToken 将代币发送到多个地址
-func (m *Manager) TransferToken(ctx context.Context, req *TokenForwardRequest) ([]string, error) {
-	// 解析私钥
-	privateKey, err := solana.PrivateKeyFromBase58(req.PrivateKeyStr)
+func (m *Manager) TransferToken(ctx context.Context, req *TokenForwardRequest) (*Result, error) {
+	// 发送者的交易由签名方签名
+	signer, err := newAccountSigner(req.Signer, req.From)
 	if err != nil {
-		return nil, fmt.Errorf("无效的私钥: %w", err)
+		return nil, err
 	}
-	from := privateKey.PublicKey()
+	from := signer.PublicKey()
 
 	// 解析代币铸币地址
 	tokenMint, err := solana.PublicKeyFromBase58(req.TokenMint)
@@ -136,134 +121,181 @@ func (m *Manager) TransferToken(ctx context.Context, req *TokenForwardRequest) (
 		return nil, fmt.Errorf("无效的代币铸币地址: %w", err)
 	}
 
-	// 创建 RPC 客户端
-	rpcClient := rpc.New(req.Config.RPCEndpoint)
+	// 从 RPC 节点池获取客户端
+	rpcClient, err := m.client()
+	if err != nil {
+		return nil, err
+	}
 
-	// 获取代币精度信息
-	tokenInfo, err := rpcClient.GetTokenSupply(ctx, tokenMint, rpc.CommitmentFinalized)
+	// 根据铸币账户的所有者识别 SPL Token 或 Token-2022，并获取精度和转账手续费配置
+	mint, err := fetchMint(ctx, rpcClient, tokenMint)
 	if err != nil {
-		return nil, fmt.Errorf("获取代币信息失败: %w", err)
+		return nil, err
+	}
+	var epoch uint64
+	if mint.hasTransferFee() {
+		epochInfo, err := rpcClient.GetEpochInfo(ctx, rpc.CommitmentFinalized)
+		if err != nil {
+			return nil, fmt.Errorf("获取当前纪元失败: %w", err)
+		}
+		epoch = epochInfo.Epoch
+	}
+
+	// 合并接收者列表，未指定数量的接收者使用默认代币数量
+	recipients := ResolveRecipients(req.Recipients, req.Addresses, req.Config.Amount)
+	if err = req.Config.checkMemos(recipients); err != nil {
+		return nil, err
 	}
 
-	// 计算转账金额（根据代币精度）
-	decimals := tokenInfo.Value.Decimals
-	amountRaw := uint64(req.Config.Amount * math.Pow10(int(decimals)))
 	// 获取发送者的代币账户
-	senderTokenAccount, _, err := solana.FindAssociatedTokenAddress(from, tokenMint)
+	senderTokenAccount, err := mint.associatedTokenAddress(from)
 	if err != nil {
 		return nil, fmt.Errorf("查找发送者代币账户失败: %w", err)
 	}
-
-	// 获取最新的 blockhash
-	recent, err := rpcClient.GetLatestBlockhash(ctx, rpc.CommitmentFinalized)
-	if err != nil {
-		return nil, fmt.Errorf("获取最新 blockhash 失败: %w", err)
+	// 代币为 wSOL 时，发送者 wSOL 余额不足的部分在各批次开头从 SOL 包装，每个批次需要预留包装指令的空间
+	wrap := mint.isNative()
+	newCapacity := func() *txCapacity {
+		capacity := req.Config.newTxCapacity()
+		if wrap {
+			capacity.reserveWrap()
+		}
+		return capacity
 	}
 
-	// 将地址分组，每组生成一笔交易
-	var txs []*solana.Transaction
-	for i := 0; i < len(req.Addresses); i += req.Config.MaxInstructionsPerTx {
-		end := i + req.Config.MaxInstructionsPerTx
-		if end > len(req.Addresses) {
-			end = len(req.Addresses)
+	// 解析接收者地址并推导 ATA
+	result := &Result{}
+	targets := make([]tokenTarget, 0, len(recipients))
+	accounts := make([]solana.PublicKey, 0, len(recipients))
+	for _, recipient := range recipients {
+		toStr := recipient.Address
+		to, err := solana.PublicKeyFromBase58(toStr)
+		if err != nil {
+			log.Warnf(log.Global, "无效地址: %s，已跳过", toStr)
+			result.Skipped = append(result.Skipped, recipient)
+			continue
 		}
-		group := req.Addresses[i:end]
 
-		var instructions []solana.Instruction
+		// 按铸币所属的代币程序推导 ATA
+		recipientTokenAccount, err := mint.associatedTokenAddress(to)
+		if err != nil {
+			log.Warnf(log.Global, "查找接收者代币账户失败: %v，已跳过", toStr)
+			result.Skipped = append(result.Skipped, recipient)
+			continue
+		}
+		targets = append(targets, tokenTarget{recipient: recipient, wallet: to, account: recipientTokenAccount})
+		accounts = append(accounts, recipientTokenAccount)
+	}
 
-		for _, toStr := range group {
-			to, err := solana.PublicKeyFromBase58(toStr)
-			if err != nil {
-				log.Warnf(log.Global, "无效地址: %s，已跳过", toStr)
+	// 分块批量查询接收者 ATA 是否存在
+	if wrap {
+		accounts = append(accounts, senderTokenAccount)
+	}
+	existing := newAccountCache()
+	if err = existing.load(ctx, rpcClient, accounts); err != nil {
+		return nil, err
+	}
+
+	// 按每个接收者实际的指令构成装填批次，批次中可以同时包含创建 ATA 和普通转账的接收者
+	var (
+		batch    *Batch
+		capacity *txCapacity
+		// created 当前批次中已经创建的 ATA，同一批次中重复的接收者只需创建一次
+		created map[solana.PublicKey]struct{}
+	)
+	for _, target := range targets {
+		recipient := target.recipient
+		toStr := recipient.Address
+
+		// 创建 TransferChecked 指令（根据代币精度换算数量）
+		amount, err := recipientUnits(&recipient, mint.decimals)
+		if err != nil {
+			log.Warnf(log.Global, "%s: %v，已跳过", toStr, err)
+			result.Skipped = append(result.Skipped, recipient)
+			continue
+		}
+		transferIx, err := mint.transferInstruction(amount, senderTokenAccount, target.account, from)
+		if err != nil {
+			log.Warnf(log.Global, "构建转账指令失败: %s: %v，已跳过", toStr, err)
+			result.Skipped = append(result.Skipped, recipient)
+			continue
+		}
+
+		// ATA 不存在时预先构建创建指令，是否需要放入批次在确定批次后判断
+		var createIx solana.Instruction
+		if !existing.has(target.account) {
+			if !req.Config.CreateAccountIfNotExist {
+				log.Warnf(log.Global, "接收者代币账户不存在且未配置自动创建: %s，已跳过", toStr)
+				result.Skipped = append(result.Skipped, recipient)
 				continue
 			}
-
-			// 使用标准 ATA
-			recipientTokenAccount, _, err := solana.FindAssociatedTokenAddress(to, tokenMint)
+			createIx, err = mint.createAccountInstruction(from, target.wallet)
 			if err != nil {
-				log.Warnf(log.Global, "查找接收者代币账户失败: %v，已跳过", toStr)
+				log.Warnf(log.Global, "构建创建代币账户指令失败: %s: %v，已跳过", toStr, err)
+				result.Skipped = append(result.Skipped, recipient)
 				continue
 			}
-			// 检查账户是否存在
-			accountInfo, err := rpcClient.GetAccountInfo(ctx, recipientTokenAccount)
-			if err != nil || accountInfo.Value == nil || accountInfo.Value.Owner.IsZero() {
-				if req.Config.CreateAccountIfNotExist {
-					createIx := associatedtokenaccount.NewCreateInstruction(from, to, tokenMint).Build()
-					instructions = append(instructions, createIx)
-				} else {
-					log.Warnf(log.Global, "接收者代币账户不存在且未配置自动创建: %s，已跳过", toStr)
-					continue
-				}
+		}
+		// 同一批次中已创建的 ATA 不再重复创建
+		needsCreate := func() bool {
+			_, ok := created[target.account]
+			return createIx != nil && !ok
+		}
+		memo := req.Config.recipientMemo(&recipient)
+		layoutFor := func() txLayout {
+			if needsCreate() {
+				return tokenCreateLayout.withMemo(req.Config, memo)
 			}
+			return tokenLayout.withMemo(req.Config, memo)
+		}
 
-			// 创建转账指令
-			transferIx := token.NewTransferInstruction(
-				amountRaw,
-				senderTokenAccount,
-				recipientTokenAccount,
-				from,
-				[]solana.PublicKey{},
-			).Build()
-			instructions = append(instructions, transferIx)
+		if batch == nil || !capacity.reserve(layoutFor()) {
+			// 当前交易已满，开始新的批次；新批次中需要重新判断是否创建 ATA
+			batch = &Batch{Index: len(result.Batches), Status: StatusPending, Memo: req.Config.transactionMemo()}
+			result.Batches = append(result.Batches, batch)
+			capacity = newCapacity()
+			created = make(map[solana.PublicKey]struct{})
+			capacity.reserve(layoutFor())
 		}
 
-		if len(instructions) > 0 {
-			tx, err := solana.NewTransaction(instructions, recent.Value.Blockhash, solana.TransactionPayer(from))
-			if err != nil {
-				log.Errorf(log.Global, "创建交易失败: %v", err)
-				continue
-			}
-			fmt.Println("Required signers:", tx.Signatures) // 打印签名者
+		cost := recipientCost{computeUnits: tokenTransferUnits, tokens: amount}
+		instructions := []solana.Instruction{transferIx}
+		if needsCreate() {
+			instructions = []solana.Instruction{createIx, transferIx}
+			cost.computeUnits += createATAUnits
+			cost.createsAccount = true
+			batch.accountSize = mint.accountSize()
+			created[target.account] = struct{}{}
+		}
+		if memo != "" {
+			instructions = append(instructions, memoInstruction(memo, from))
+			cost.computeUnits += memoUnits(memo)
+		}
 
-			txs = append(txs, tx)
+		// 转账手续费由代币程序扣留在接收者账户中，接收者实际到账数量为转账数量减去手续费
+		if fee := mint.withheldFee(amount, epoch); fee > 0 {
+			recipient.Fee = FromBaseUnits(fee, mint.decimals)
+			result.TransferFee += recipient.Fee
 		}
+		batch.add(recipient, cost, instructions...)
 	}
 
-	// 并发发送交易
-	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, req.Config.ConcurrentTxs)
-	var mu sync.Mutex
-	var txSignatures []string
-
-	for _, tx := range txs {
-		wg.Add(1)
-		semaphore <- struct{}{}
-		go func(tx *solana.Transaction) {
-			defer wg.Done()
-			defer func() { <-semaphore }()
-
-			_, err = tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
-				if key.Equals(from) {
-					return &privateKey
-				}
-				return nil
-			})
-			if err != nil {
-				log.Errorf(log.Global, "签名交易失败: %v", err)
-				return
-			}
-
-			txSig, err := rpcClient.SendTransactionWithOpts(ctx, tx, rpc.TransactionOpts{
-				SkipPreflight:       false,
-				PreflightCommitment: rpc.CommitmentFinalized,
-			})
-			if err != nil {
-				log.Errorf(log.Global, "发送交易失败: %v", err)
-				return
-			}
-			log.Infof(log.Global, "交易已发送: %s", txSig)
-
-			mu.Lock()
-			txSignatures = append(txSignatures, txSig.String())
-			mu.Unlock()
-		}(tx)
+	// 发送前检查余额是否足以支付代币、交易费、优先费和 ATA 租金
+	source := &tokenSource{mint: mint, account: senderTokenAccount, exists: existing.has(senderTokenAccount)}
+	if err = m.preflight(ctx, rpcClient, from, source, result, req.Config, req.DryRun); err != nil {
+		return result, err
+	}
+	if wrap {
+		if err = wrapBatches(result.Batches, from, senderTokenAccount, result.Preflight.TokenBalance); err != nil {
+			return result, err
+		}
 	}
 
-	wg.Wait()
-	return txSignatures, nil
+	err = m.run(ctx, rpcClient, signer, result, req.Config, req.Observer, req.DryRun, req.SignOnly)
+	return result, err
 }
 
-// ReadAddressesFromFile 从文件中读取目标地址列表
+// ReadAddressesFromFile 从文件中读取目标地址列表，每行一个，忽略空行和 # 开头的注释行；
+// 只读取内容而不校验地址，地址在转账前由 ValidateRecipients 校验
 func ReadAddressesFromFile(filePath string) ([]string, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -274,8 +306,8 @@ func ReadAddressesFromFile(filePath string) ([]string, error) {
 	var addresses []string
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
-		addr := scanner.Text()
-		if addr != "" {
+		addr := strings.TrimSpace(scanner.Text())
+		if addr != "" && !strings.HasPrefix(addr, "#") {
 			addresses = append(addresses, addr)
 		}
 	}
@@ -1,39 +1,373 @@
 package forward
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+var (
+	// ErrMalformedRecipients 接收者列表中存在格式错误的行
+	ErrMalformedRecipients = errors.New("接收者列表存在格式错误的行")
+	// ErrStopped 观察者在 BatchSigned 中返回该错误表示停止发送新的批次，
+	// 该批次保持 pending，已发送的批次继续跟踪确认
+	ErrStopped = errors.New("已停止发送新的批次")
+)
+
 // Config 定义 SOL 转发的配置参数
 type Config struct {
-	RPCEndpoint             string  // Solana RPC 端点
 	MaxInstructionsPerTx    int     // 每笔交易的最大指令数
 	ConcurrentTxs           int     // 并发交易数量
 	AmountSOL               float64 // 每笔转账的 SOL 数量
 	Amount                  float64 // 每笔转账的代币数量
 	CreateAccountIfNotExist bool    // 如果接收者没有关联代币账户，是否创建
+
+	Commitment     rpc.CommitmentType // 交易视为成功所需的确认级别
+	ConfirmTimeout time.Duration      // 等待交易确认的最长时间，为 0 时不跟踪确认
+	PollInterval   time.Duration      // 查询签名状态的间隔
+	MaxResends     int                // blockhash 过期后重新签名发送的最大次数
+
+	ComputeUnitLimit      uint32 // 每笔交易的计算单元上限，为 0 时按批次指令估算
+	ComputeUnitPrice      uint64 // 固定优先费单价（micro-lamports/CU），为 0 时不设置优先费
+	PriorityFeePercentile int    // 大于 0 时按 getRecentPrioritizationFees 的该百分位（1-100）动态取价
+	MaxComputeUnitPrice   uint64 // 动态优先费单价上限，为 0 时不限制
+
+	PartialPay bool // 余额不足时按列表顺序支付余额足以覆盖的接收者，而不是拒绝整个任务
+
+	// UseLookupTables 使用 v0 交易和地址查找表，接收者账户只占一个字节的索引，每笔交易可以容纳更多转账；
+	// 任务开始前创建并扩展查找表，任务结束后停用并在冷却期后关闭。应同时调大 MaxInstructionsPerTx
+	UseLookupTables bool
+
+	// NonceAccounts 使用 durable nonce 签名交易的 nonce 账户，每个批次使用一个，权限账户必须是发送者；
+	// 交易以 AdvanceNonceAccount 指令开头并使用 nonce 值代替 blockhash，不会因 blockhash 过期而失效
+	NonceAccounts []string
+
+	// MemoMode 在交易中附加 SPL Memo 指令的方式，为空时不附加备注
+	MemoMode MemoMode
+	// Reference 备注引用，例如发票号或任务 ID；按交易附加备注时作为每笔交易的备注，
+	// 按接收者附加备注时作为未设置备注的接收者的备注
+	Reference string
 }
 
+// MemoMode 表示附加 SPL Memo 指令的方式
+type MemoMode string
+
+// 备注方式
+const (
+	MemoNone        MemoMode = ""            // 不附加备注
+	MemoTransaction MemoMode = "transaction" // 每笔交易附加一条内容为 Reference 的备注
+	MemoRecipient   MemoMode = "recipient"   // 每个接收者的转账指令后附加一条该接收者的备注
+)
+
 // DefaultConfig 返回默认配置
 func DefaultConfig() *Config {
 	return &Config{
-		RPCEndpoint:             "http://xolana.xen.network:8899",
 		MaxInstructionsPerTx:    10,
 		ConcurrentTxs:           5,
 		AmountSOL:               0.01,
 		Amount:                  100.0,
 		CreateAccountIfNotExist: true,
+		Commitment:              rpc.CommitmentConfirmed,
+		ConfirmTimeout:          90 * time.Second,
+		PollInterval:            defaultPollInterval,
+		MaxResends:              3,
+		PriorityFeePercentile:   75,
+		MaxComputeUnitPrice:     1_000_000,
+	}
+}
+
+// Status 表示接收者或批次的转账状态
+type Status string
+
+// 转账状态
+const (
+	StatusPending   Status = "pending"   // 尚未发送，可以安全地重新发送
+	StatusSent      Status = "sent"      // 已签名并提交，链上结果未知
+	StatusConfirmed Status = "confirmed" // 已在链上确认
+	StatusFailed    Status = "failed"    // 发送被拒绝或链上执行失败，资金未转出
+	StatusExpired   Status = "expired"   // blockhash 过期或 nonce 推进且交易未上链，资金未转出
+	StatusSkipped   Status = "skipped"   // 接收者无效，未生成转账指令
+)
+
+// Recipient 定义单个接收者及其转账数量
+type Recipient struct {
+	ID      int64   `json:"-"`               // 持久化任务中的行 ID，未持久化时为 0
+	Address string  `json:"address"`         // 接收者地址
+	Amount  float64 `json:"amount"`          // 转账数量（SOL 或代币），为 0 表示未指定，使用配置中的默认数量
+	Memo    string  `json:"memo,omitempty"`  // 可选备注
+	Label   string  `json:"label,omitempty"` // 可选标签
+	Fee     float64 `json:"fee,omitempty"`   // Token-2022 转账手续费，由代币程序从转账数量中扣留，接收者实际到账 Amount-Fee
+	Units   uint64  `json:"-"`               // 按最小单位指定的转账数量，不为 0 时优先于 Amount，避免大额数量经浮点数换算丢失精度
+}
+
+// RowError 描述接收者列表中某一行的错误
+type RowError struct {
+	Line int    // 行号，从 1 开始
+	Raw  string // 原始内容
+	Err  error  // 错误原因
+}
+
+// RecipientsError 汇总接收者列表中所有格式错误的行
+type RecipientsError struct {
+	Rows []RowError
+}
+
+// Error 实现 error 接口
+func (e *RecipientsError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%v: 共 %d 行", ErrMalformedRecipients, len(e.Rows))
+	for i := range e.Rows {
+		fmt.Fprintf(&sb, "; 第 %d 行 %q: %v", e.Rows[i].Line, e.Rows[i].Raw, e.Rows[i].Err)
+	}
+	return sb.String()
+}
+
+// Unwrap 使 errors.Is 可以匹配 ErrMalformedRecipients
+func (e *RecipientsError) Unwrap() error {
+	return ErrMalformedRecipients
+}
+
+// Batch 表示一笔交易及其包含的接收者
+type Batch struct {
+	Index                int         // 批次序号
+	Recipients           []Recipient // 本批次的接收者
+	Signature            string      // 交易签名，签名后即确定
+	LastValidBlockHeight uint64      // 交易所用 blockhash 的最后有效区块高度，使用 durable nonce 时为 0
+	NonceAccount         string      // 使用 durable nonce 签名时的 nonce 账户
+	Nonce                string      // 签名时使用的 nonce 值，链上 nonce 推进后交易不会再上链
+	Status               Status      // 批次状态
+	Err                  error       // 失败原因
+	Transaction          string      // base64 编码的已签名交易，仅在预先签名时设置
+	Memo                 string      // 交易级备注，仅在按交易附加备注时设置
+
+	instructions []solana.Instruction
+	ataCreations int
+	accountSize  uint64 // 创建的关联代币账户的数据长度，用于估算租金
+	computeUnits uint32 // 批次指令的计算单元估算值
+	// costs 与 Recipients 一一对应，记录每个接收者的指令数量和所需资金
+	costs []recipientCost
+	// lookupTables 交易引用的地址查找表及其中的地址，为空时构建 legacy 交易
+	lookupTables map[solana.PublicKey]solana.PublicKeySlice
+	// nonce 交易使用的 nonce 账户，为 nil 时使用最新的 blockhash
+	nonce *nonceState
+}
+
+// recipientCost 单个接收者在批次中的指令数量和所需资金
+type recipientCost struct {
+	instructions   int
+	computeUnits   uint32
+	lamports       uint64 // SOL 转账数量
+	tokens         uint64 // 代币转账数量（原始数量）
+	createsAccount bool   // 是否为接收者创建关联代币账户
+}
+
+// tokenTarget 已解析地址并推导出 ATA 的代币接收者
+type tokenTarget struct {
+	recipient Recipient
+	wallet    solana.PublicKey // 接收者钱包地址
+	account   solana.PublicKey // 接收者的关联代币账户
+}
+
+// Observer 接收批次状态变化的通知，用于持久化任务进度
+type Observer interface {
+	// BatchSigned 在交易签名后、发送前调用，返回错误时该批次不会被发送
+	BatchSigned(*Batch) error
+	// BatchUpdated 在批次发送结果或链上状态变化后调用
+	BatchUpdated(*Batch)
+}
+
+// BuildObserver 是观察者可选实现的接口，在批次构建完成、开始发送前接收全部批次，
+// 可用于展示转账进度
+type BuildObserver interface {
+	BatchesBuilt([]*Batch)
+}
+
+// Result 汇总一次转发的结果
+type Result struct {
+	Batches      []*Batch          // 所有批次
+	Skipped      []Recipient       // 因地址无效被跳过的接收者
+	Simulation   *SimulationReport // 模拟运行的报告，仅在 DryRun 时设置
+	TransferFee  float64           // Token-2022 转账手续费合计（代币数量），未启用转账手续费时为 0
+	Preflight    *Preflight        // 发送前的资金检查结果
+	Unpaid       []Recipient       // 启用 PartialPay 时因余额不足未发送的接收者
+	LookupTables []string          // 启用 UseLookupTables 时本次任务创建的地址查找表
+}
+
+// SimulationReport 汇总模拟运行的结果，金额单位均为 lamports
+type SimulationReport struct {
+	Transactions     int               // 交易数量
+	Instructions     int               // 指令总数
+	ATACreations     int               // 需要创建的关联代币账户数量
+	EstimatedFee     uint64            // 预计交易费用
+	EstimatedRent    uint64            // 创建关联代币账户预计支付的租金
+	ComputeUnits     uint64            // 模拟消耗的计算单元总数
+	Failed           int               // 模拟失败的交易数量
+	ComputeUnitPrice uint64            // 使用的优先费单价（micro-lamports/CU）
+	LookupTables     int               // 需要创建的地址查找表数量，其交易费计入 EstimatedFee
+	Batches          []BatchSimulation // 每笔交易的模拟结果
+}
+
+// BatchSimulation 单笔交易的模拟结果
+type BatchSimulation struct {
+	Index            int
+	Recipients       []Recipient
+	Instructions     int
+	ATACreations     int
+	EstimatedFee     uint64
+	EstimatedRent    uint64
+	ComputeUnits     uint64
+	ComputeUnitLimit uint32
+	Logs             []string
+	Err              error
+}
+
+// Signatures 返回所有已发送且未失败或过期的批次的交易签名
+func (r *Result) Signatures() []string {
+	var signatures []string
+	for _, b := range r.Batches {
+		if b.Status == StatusSent || b.Status == StatusConfirmed {
+			signatures = append(signatures, b.Signature)
+		}
 	}
+	return signatures
 }
 
 // ForwardRequest 定义转发请求的结构
 type ForwardRequest struct {
-	PrivateKeyStr string   // 发送者私钥
-	Addresses     []string // 接收者地址列表
-	Config        *Config  // 转发配置
+	From       string      // 发送者地址
+	Signer     Signer      // 发送者的签名方
+	Addresses  []string    // 接收者地址列表，使用 Config.AmountSOL 作为转账数量
+	Recipients []Recipient // 带独立数量的接收者列表，设置后优先于 Addresses
+	Config     *Config     // 转发配置
+	Observer   Observer    // 可选的批次状态观察者
+	DryRun     bool        // 只构建并模拟交易，不发送
+	SignOnly   bool        // 只使用 nonce 账户签名交易，不发送；签名后的交易保存在 Batch.Transaction
 }
 
 // TokenForwardRequest 定义代币转发请求的结构
 type TokenForwardRequest struct {
-	PrivateKeyStr string   // 发送者私钥
-	TokenMint     string   // 代币铸币账户地址
-	Addresses     []string // 接收者地址列表
-	IsToken2022   bool     // 是否为Token-2022类型代币
-	Config        *Config  // 转发配置
+	From       string      // 发送者地址
+	Signer     Signer      // 发送者的签名方
+	TokenMint  string      // 代币铸币账户地址
+	Addresses  []string    // 接收者地址列表，使用 Config.Amount 作为转账数量
+	Recipients []Recipient // 带独立数量的接收者列表，设置后优先于 Addresses
+	Config     *Config     // 转发配置
+	Observer   Observer    // 可选的批次状态观察者
+	DryRun     bool        // 只构建并模拟交易，不发送
+	SignOnly   bool        // 只使用 nonce 账户签名交易，不发送；签名后的交易保存在 Batch.Transaction
+}
+
+// NonceAccount 描述一个 durable nonce 账户
+type NonceAccount struct {
+	Address              string // nonce 账户地址
+	Seed                 string // 由权限钱包推导地址使用的种子，非本系统创建的账户为空
+	Authority            string // 权限账户
+	Nonce                string // 当前存储的 nonce 值，交易使用它代替 blockhash
+	Lamports             uint64 // 账户余额
+	LamportsPerSignature uint64 // 存储 nonce 时的每签名费用
+}
+
+// NonceAccountRequest 定义创建或关闭 nonce 账户的请求
+type NonceAccountRequest struct {
+	From      string   // 权限钱包地址，同时支付租金和交易费
+	Signer    Signer   // 权限钱包的签名方
+	Count     int      // 创建的 nonce 账户数量
+	Addresses []string // 关闭的 nonce 账户，为空时关闭钱包名下的全部 nonce 账户
+	Config    *Config  // 转发配置，为 nil 时使用默认配置
+}
+
+// SweepRequest 定义归集请求：将多个源账户中的 SOL 和代币归集到同一个目标地址
+// 每个源账户自行签名并支付交易费，创建目标代币账户的租金也由源账户支付
+type SweepRequest struct {
+	Sources            []string // 源账户地址
+	Destination        string   // 归集目标地址
+	TokenMints         []string // 需要归集的代币铸币地址
+	SweepSOL           bool     // 是否归集 SOL
+	Reserve            uint64   // 归集 SOL 后源账户保留的 lamports，不低于 0 字节账户的免租金额
+	CloseTokenAccounts bool     // 归集后关闭已清空的源代币账户，租金退回目标地址
+	Signer             Signer   // 源账户的签名方
+	Config             *Config  // 转发配置
+	DryRun             bool     // 只构建并模拟交易，不发送
+}
+
+// SweepResult 汇总一次归集的结果
+type SweepResult struct {
+	Sources []*SweepSource // 每个源账户的归集结果，顺序与请求一致
+}
+
+// SweepSource 单个源账户的归集结果，SOL 金额单位为 lamports
+type SweepSource struct {
+	Address       string            // 源账户地址
+	Lamports      uint64            // 归集的 SOL
+	Tokens        []SweptToken      // 归集的代币账户
+	ReclaimedRent uint64            // 关闭代币账户回收的租金，退回目标地址
+	Rent          uint64            // 创建目标代币账户预计支付的租金
+	Fees          uint64            // 交易费和优先费
+	Batches       []*Batch          // 源账户的交易，没有可归集的资产时为空
+	Simulation    *SimulationReport // 模拟运行的报告，仅在 DryRun 时设置
+	Err           error             // 归集失败的原因，失败不影响其他源账户
+}
+
+// SweptToken 单个源代币账户的归集结果
+type SweptToken struct {
+	Mint    string  // 铸币地址
+	Account string  // 源代币账户
+	Amount  float64 // 归集的代币数量
+	Closed  bool    // 是否关闭源代币账户
+}
+
+// WrapRequest 定义包装或解除包装 SOL 的请求
+type WrapRequest struct {
+	From   string  // 钱包地址，同时支付交易费
+	Signer Signer  // 钱包的签名方
+	Amount float64 // 包装的 SOL 数量；解除包装时关闭 wSOL 账户，取回全部余额
+	Config *Config // 转发配置，为 nil 时使用默认配置
+	DryRun bool    // 只构建并模拟交易，不发送
+}
+
+// WrapResult 汇总一次包装或解除包装 SOL 的结果
+type WrapResult struct {
+	Account    string            // 钱包的 wSOL 关联代币账户
+	Lamports   uint64            // 包装的 lamports；解除包装时为退回的 lamports，包括账户租金
+	Preflight  *Preflight        // 包装前的资金检查结果，解除包装时为 nil
+	Batch      *Batch            // 包装或解除包装的交易
+	Simulation *SimulationReport // 模拟运行的报告，仅在 DryRun 时设置
+}
+
+// ReclaimRequest 定义回收租金的请求：关闭多个钱包名下余额为 0 的代币账户，租金退回各自的钱包
+type ReclaimRequest struct {
+	Sources      []string // 钱包地址
+	ExcludeMints []string // 不关闭这些铸币的代币账户
+	Signer       Signer   // 钱包的签名方
+	Config       *Config  // 转发配置
+	DryRun       bool     // 只构建并模拟交易，不发送
+}
+
+// ReclaimResult 汇总一次回收租金的结果
+type ReclaimResult struct {
+	Sources []*ReclaimSource // 每个钱包的回收结果，顺序与请求一致
+}
+
+// ReclaimSource 单个钱包的回收结果，金额单位为 lamports
+type ReclaimSource struct {
+	Address    string            // 钱包地址
+	Accounts   []ClosedAccount   // 可以关闭的代币账户
+	Lamports   uint64            // 回收的租金；模拟运行时为预计回收的租金
+	Fees       uint64            // 交易费和优先费
+	Batches    []*Batch          // 钱包的交易，没有可关闭的账户时为空
+	Simulation *SimulationReport // 模拟运行的报告，仅在 DryRun 时设置
+	Err        error             // 回收失败的原因，失败不影响其他钱包
+}
+
+// ClosedAccount 一个被关闭的代币账户
+type ClosedAccount struct {
+	Account  string // 代币账户地址
+	Mint     string // 铸币地址
+	Lamports uint64 // 账户持有的租金
+	Batch    int    // 所在批次的序号
+	Closed   bool   // 所在交易是否已确认
 }
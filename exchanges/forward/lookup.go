@@ -0,0 +1,423 @@
+package forward
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"gocryptotrader/log"
+
+	"github.com/gagliardetto/solana-go"
+	addresslookuptable "github.com/gagliardetto/solana-go/programs/address-lookup-table"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// addressLookupTableProgramID 地址查找表程序
+var addressLookupTableProgramID = solana.MustPublicKeyFromBase58("AddressLookupTab1e1111111111111111111111111")
+
+// 地址查找表程序的指令编号
+const (
+	lookupTableCreate     uint32 = 0
+	lookupTableExtend     uint32 = 2
+	lookupTableDeactivate uint32 = 3
+	lookupTableClose      uint32 = 4
+)
+
+const (
+	// maxLookupTableAddresses 单个查找表最多容纳的地址数量
+	maxLookupTableAddresses = addresslookuptable.LOOKUP_TABLE_MAX_ADDRESSES
+	// maxLookupTablesPerTx 每笔交易引用的查找表数量上限；批次的地址连续写入查找表，最多跨越相邻的两张表
+	maxLookupTablesPerTx = 2
+	// lookupTableOverhead v0 消息的版本号、查找表数量以及每张查找表的地址和两组索引长度
+	lookupTableOverhead = 1 + 1 + maxLookupTablesPerTx*(32+1+1)
+	// maxTxAccountLocks 单笔交易可以锁定的账户数量上限，包括通过查找表引用的账户
+	maxTxAccountLocks = 64
+	// maxAddressesPerExtend 单笔扩展交易写入的地址数量：扣除付款人、查找表、System 程序、查找表程序和计算预算程序
+	// 五个账户，计算预算指令，以及扩展指令的程序索引、账户、数据长度、指令编号和地址数量
+	maxAddressesPerExtend = (maxTransactionSize - txFixedSize - 5*32 - computeBudgetSize - (1 + 1 + 4 + 2 + 4 + 8)) / 32
+	// lookupTableUnits 查找表管理指令的计算单元估算值
+	lookupTableUnits = 30_000
+	// lookupTableCooldown 查找表停用后需要等待的 slot 数量，停用 slot 移出 SlotHashes 后才能关闭
+	lookupTableCooldown = 513
+	// recentSlotRange 创建查找表时可用的 recent slot 范围，recent slot 必须仍在 SlotHashes 中
+	recentSlotRange = 400
+	// lookupTableCloseTimeout 后台等待冷却期并关闭查找表的最长时间
+	lookupTableCloseTimeout = 10 * time.Minute
+)
+
+// MaxInstructionsPerLookupTx 使用地址查找表时建议的 MaxInstructionsPerTx，
+// 每笔交易的接收者数量由交易大小、计算单元和账户锁定数量决定
+const MaxInstructionsPerLookupTx = maxTxAccountLocks
+
+var (
+	errTooManyLookupTables = errors.New("地址查找表数量超过可用的 recent slot 数量")
+	errLookupTableMissing  = errors.New("地址查找表中缺少批次引用的地址")
+)
+
+// lookupTable 转发任务使用的地址查找表
+type lookupTable struct {
+	address   solana.PublicKey
+	addresses solana.PublicKeySlice // 查找表中的地址，创建后按链上顺序更新
+	batches   []*Batch              // 引用该查找表的批次
+	created   bool                  // 创建交易是否已确认，已创建的查找表在任务结束后需要关闭
+}
+
+// use 记录批次引用了该查找表
+func (t *lookupTable) use(b *Batch) {
+	if len(t.batches) == 0 || t.batches[len(t.batches)-1] != b {
+		t.batches = append(t.batches, b)
+	}
+}
+
+// lookupKeys 返回批次指令中可以通过查找表引用的账户
+// 签名者和被调用的程序必须出现在交易的静态账户中，不能写入查找表
+func lookupKeys(b *Batch) []solana.PublicKey {
+	programs := make(map[solana.PublicKey]struct{})
+	for _, ix := range b.instructions {
+		programs[ix.ProgramID()] = struct{}{}
+	}
+	seen := make(map[solana.PublicKey]struct{})
+	var keys []solana.PublicKey
+	for _, ix := range b.instructions {
+		for _, account := range ix.Accounts() {
+			if account.IsSigner {
+				continue
+			}
+			if _, ok := programs[account.PublicKey]; ok {
+				continue
+			}
+			if _, ok := seen[account.PublicKey]; ok {
+				continue
+			}
+			seen[account.PublicKey] = struct{}{}
+			keys = append(keys, account.PublicKey)
+		}
+	}
+	return keys
+}
+
+// planLookupTables 按批次顺序将每个批次引用的账户分配到查找表
+// 同一批次的账户连续写入当前查找表，写满后开始新的查找表，因此每个批次最多引用相邻的两张查找表
+func planLookupTables(batches []*Batch) []*lookupTable {
+	var (
+		tables  []*lookupTable
+		current *lookupTable
+		index   map[solana.PublicKey]struct{}
+	)
+	for _, b := range batches {
+		for _, key := range lookupKeys(b) {
+			if current != nil {
+				if _, ok := index[key]; ok {
+					current.use(b)
+					continue
+				}
+			}
+			if current == nil || len(current.addresses) == maxLookupTableAddresses {
+				current = &lookupTable{}
+				tables = append(tables, current)
+				index = make(map[solana.PublicKey]struct{})
+			}
+			current.addresses = append(current.addresses, key)
+			index[key] = struct{}{}
+			current.use(b)
+		}
+	}
+	return tables
+}
+
+// assignLookupTables 为每个批次设置其引用的查找表
+func assignLookupTables(tables []*lookupTable) {
+	for _, t := range tables {
+		for _, b := range t.batches {
+			if b.lookupTables == nil {
+				b.lookupTables = make(map[solana.PublicKey]solana.PublicKeySlice, maxLookupTablesPerTx)
+			}
+			b.lookupTables[t.address] = t.addresses
+		}
+	}
+}
+
+// lookupTableTxs 返回创建、扩展、停用和关闭查找表所需的交易数量
+func lookupTableTxs(tables []*lookupTable) int {
+	n := 0
+	for _, t := range tables {
+		n += 3 + (len(t.addresses)+maxAddressesPerExtend-1)/maxAddressesPerExtend
+	}
+	return n
+}
+
+// lookupTableAddress 按权限账户和 recent slot 推导查找表地址
+func lookupTableAddress(authority solana.PublicKey, recentSlot uint64) (solana.PublicKey, uint8, error) {
+	return solana.FindProgramAddress([][]byte{
+		authority[:],
+		binary.LittleEndian.AppendUint64(nil, recentSlot),
+	}, addressLookupTableProgramID)
+}
+
+// createLookupTableInstruction 构建创建查找表的指令，返回指令和查找表地址
+func createLookupTableInstruction(authority, payer solana.PublicKey, recentSlot uint64) (solana.Instruction, solana.PublicKey, error) {
+	table, bump, err := lookupTableAddress(authority, recentSlot)
+	if err != nil {
+		return nil, solana.PublicKey{}, fmt.Errorf("推导地址查找表地址失败: %w", err)
+	}
+	data := binary.LittleEndian.AppendUint32(nil, lookupTableCreate)
+	data = binary.LittleEndian.AppendUint64(data, recentSlot)
+	data = append(data, bump)
+	return solana.NewInstruction(addressLookupTableProgramID, solana.AccountMetaSlice{
+		solana.Meta(table).WRITE(),
+		solana.Meta(authority).SIGNER(),
+		solana.Meta(payer).WRITE().SIGNER(),
+		solana.Meta(solana.SystemProgramID),
+	}, data), table, nil
+}
+
+// extendLookupTableInstruction 构建向查找表追加地址的指令，扩容所需的租金由 payer 支付
+func extendLookupTableInstruction(table, authority, payer solana.PublicKey, addresses []solana.PublicKey) solana.Instruction {
+	data := binary.LittleEndian.AppendUint32(nil, lookupTableExtend)
+	data = binary.LittleEndian.AppendUint64(data, uint64(len(addresses)))
+	for _, address := range addresses {
+		data = append(data, address[:]...)
+	}
+	return solana.NewInstruction(addressLookupTableProgramID, solana.AccountMetaSlice{
+		solana.Meta(table).WRITE(),
+		solana.Meta(authority).SIGNER(),
+		solana.Meta(payer).WRITE().SIGNER(),
+		solana.Meta(solana.SystemProgramID),
+	}, data)
+}
+
+// deactivateLookupTableInstruction 构建停用查找表的指令
+func deactivateLookupTableInstruction(table, authority solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(addressLookupTableProgramID, solana.AccountMetaSlice{
+		solana.Meta(table).WRITE(),
+		solana.Meta(authority).SIGNER(),
+	}, binary.LittleEndian.AppendUint32(nil, lookupTableDeactivate))
+}
+
+// closeLookupTableInstruction 构建关闭查找表的指令，租金退回 recipient
+func closeLookupTableInstruction(table, authority, recipient solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(addressLookupTableProgramID, solana.AccountMetaSlice{
+		solana.Meta(table).WRITE(),
+		solana.Meta(authority).SIGNER(),
+		solana.Meta(recipient).WRITE(),
+	}, binary.LittleEndian.AppendUint32(nil, lookupTableClose))
+}
+
+// recentSlots 返回最近已确定的 n 个有区块的 slot，由新到旧排列
+// 创建查找表的 recent slot 必须仍在 SlotHashes 中，跳过的 slot 不能使用
+func recentSlots(ctx context.Context, client *rpc.Client, n int) ([]uint64, error) {
+	slot, err := client.GetSlot(ctx, rpc.CommitmentFinalized)
+	if err != nil {
+		return nil, fmt.Errorf("获取当前 slot 失败: %w", err)
+	}
+	start := uint64(0)
+	if slot > recentSlotRange {
+		start = slot - recentSlotRange
+	}
+	blocks, err := client.GetBlocks(ctx, start, &slot, rpc.CommitmentFinalized)
+	if err != nil {
+		return nil, fmt.Errorf("获取最近的区块失败: %w", err)
+	}
+	if len(blocks) < n {
+		return nil, fmt.Errorf("%w: 需要 %d 张，可用 %d 个", errTooManyLookupTables, n, len(blocks))
+	}
+	slots := make([]uint64, 0, n)
+	for i := len(blocks) - 1; i >= len(blocks)-n; i-- {
+		slots = append(slots, blocks[i])
+	}
+	return slots, nil
+}
+
+// checkLookupTableFunds 检查余额在支付转账之外是否还足以支付查找表的租金和管理交易费
+// 查找表的租金在关闭后退回，但任务执行期间需要占用
+func checkLookupTableFunds(ctx context.Context, client *rpc.Client, tables []*lookupTable, p *Preflight) error {
+	var rent uint64
+	for _, t := range tables {
+		lamports, err := client.GetMinimumBalanceForRentExemption(ctx, addresslookuptable.LOOKUP_TABLE_META_SIZE+uint64(32*len(t.addresses)), rpc.CommitmentFinalized)
+		if err != nil {
+			return fmt.Errorf("获取地址查找表租金失败: %w", err)
+		}
+		rent += lamports
+	}
+	fees := uint64(lookupTableTxs(tables)) * lamportsPerSignature
+	if p != nil && p.Lamports()+rent+fees > p.LamportBalance {
+		return fmt.Errorf("%w: 地址查找表需要租金 %d lamports、交易费 %d lamports，余额 %d，转账需要 %d",
+			ErrInsufficientFunds, rent, fees, p.LamportBalance, p.Lamports())
+	}
+	return nil
+}
+
+// prepareLookupTables 为批次创建并扩展地址查找表，等待查找表可用后为每个批次设置引用的查找表
+// 同一查找表的多笔扩展交易并发发送，地址在链上的顺序可能与计划不同，因此扩展完成后重新读取查找表内容。
+// 返回的查找表在任务结束后需要通过 releaseLookupTables 关闭，出错时也会返回已创建的查找表。
+func (m *Manager) prepareLookupTables(ctx context.Context, client *rpc.Client, signer accountSigner, result *Result, cfg *Config) ([]*lookupTable, error) {
+	tables := planLookupTables(result.Batches)
+	if len(tables) == 0 {
+		return nil, nil
+	}
+	if err := checkLookupTableFunds(ctx, client, tables, result.Preflight); err != nil {
+		return nil, err
+	}
+	slots, err := recentSlots(ctx, client, len(tables))
+	if err != nil {
+		return nil, err
+	}
+
+	authority := signer.PublicKey()
+	mcfg := managementConfig(cfg)
+
+	// 创建查找表，每张查找表使用不同的 recent slot 推导地址
+	create := make([]*Batch, len(tables))
+	for i, t := range tables {
+		ix, address, err := createLookupTableInstruction(authority, authority, slots[i])
+		if err != nil {
+			return nil, err
+		}
+		t.address = address
+		create[i] = instructionBatch(i, lookupTableUnits, ix)
+	}
+	err = m.executeConfirmed(ctx, client, signer, create, mcfg, "创建地址查找表")
+	for i, t := range tables {
+		t.created = create[i].Status == StatusConfirmed
+	}
+	if err != nil {
+		return tables, err
+	}
+	for _, t := range tables {
+		result.LookupTables = append(result.LookupTables, t.address.String())
+	}
+
+	// 扩展查找表
+	var extend []*Batch
+	for _, t := range tables {
+		for i := 0; i < len(t.addresses); i += maxAddressesPerExtend {
+			end := min(i+maxAddressesPerExtend, len(t.addresses))
+			ix := extendLookupTableInstruction(t.address, authority, authority, t.addresses[i:end])
+			extend = append(extend, instructionBatch(len(extend), lookupTableUnits, ix))
+		}
+	}
+	if err = m.executeConfirmed(ctx, client, signer, extend, mcfg, "扩展地址查找表"); err != nil {
+		return tables, err
+	}
+
+	// 读取链上的查找表内容，确认包含全部计划的地址
+	var lastExtended uint64
+	for _, t := range tables {
+		state, err := addresslookuptable.GetAddressLookupTableStateWithOpts(ctx, client, t.address, &rpc.GetAccountInfoOpts{Commitment: mcfg.Commitment})
+		if err != nil {
+			return tables, fmt.Errorf("读取地址查找表 %s 失败: %w", t.address, err)
+		}
+		present := make(map[solana.PublicKey]struct{}, len(state.Addresses))
+		for _, address := range state.Addresses {
+			present[address] = struct{}{}
+		}
+		for _, address := range t.addresses {
+			if _, ok := present[address]; !ok {
+				return tables, fmt.Errorf("%w: 查找表 %s 缺少 %s", errLookupTableMissing, t.address, address)
+			}
+		}
+		t.addresses = state.Addresses
+		lastExtended = max(lastExtended, state.LastExtendedSlot)
+	}
+
+	// 扩展的地址在扩展所在 slot 之后才能使用
+	if err = waitForSlot(ctx, client, lastExtended+1, mcfg.PollInterval); err != nil {
+		return tables, err
+	}
+	assignLookupTables(tables)
+	log.Infof(log.Global, "已创建 %d 张地址查找表", len(tables))
+	return tables, nil
+}
+
+// waitForSlot 等待节点处理到 slot
+func waitForSlot(ctx context.Context, client *rpc.Client, slot uint64, interval time.Duration) error {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		current, err := client.GetSlot(ctx, rpc.CommitmentProcessed)
+		if err == nil && current >= slot {
+			return nil
+		}
+		if err != nil {
+			log.Warnf(log.Global, "获取当前 slot 失败: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// releaseLookupTables 在后台停用任务创建的地址查找表，冷却期结束后关闭查找表并将租金退回发送者
+// 任务的上下文可能已经取消，因此使用独立的上下文；关闭失败时记录查找表地址，可以稍后手动关闭
+func (m *Manager) releaseLookupTables(client *rpc.Client, signer accountSigner, tables []*lookupTable, cfg *Config) {
+	var created []*lookupTable
+	for _, t := range tables {
+		if t.created {
+			created = append(created, t)
+		}
+	}
+	if len(created) == 0 {
+		return
+	}
+	mcfg := managementConfig(cfg)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), lookupTableCloseTimeout)
+		defer cancel()
+		if err := m.closeLookupTables(ctx, client, signer, created, mcfg); err != nil {
+			for _, t := range created {
+				log.Errorf(log.Global, "关闭地址查找表 %s 失败，需要手动关闭: %v", t.address, err)
+			}
+		}
+	}()
+}
+
+// closeLookupTables 停用查找表，等待冷却期后关闭
+func (m *Manager) closeLookupTables(ctx context.Context, client *rpc.Client, signer accountSigner, tables []*lookupTable, cfg *Config) error {
+	authority := signer.PublicKey()
+	deactivate := make([]*Batch, len(tables))
+	for i, t := range tables {
+		deactivate[i] = instructionBatch(i, lookupTableUnits, deactivateLookupTableInstruction(t.address, authority))
+	}
+	if err := m.executeConfirmed(ctx, client, signer, deactivate, cfg, "停用地址查找表"); err != nil {
+		return err
+	}
+
+	slot, err := client.GetSlot(ctx, rpc.CommitmentProcessed)
+	if err != nil {
+		return fmt.Errorf("获取当前 slot 失败: %w", err)
+	}
+	if err = waitForSlot(ctx, client, slot+lookupTableCooldown, cfg.PollInterval); err != nil {
+		return err
+	}
+
+	closing := make([]*Batch, len(tables))
+	for i, t := range tables {
+		closing[i] = instructionBatch(i, lookupTableUnits, closeLookupTableInstruction(t.address, authority, authority))
+	}
+	if err = m.executeConfirmed(ctx, client, signer, closing, cfg, "关闭地址查找表"); err != nil {
+		return err
+	}
+	log.Infof(log.Global, "已关闭 %d 张地址查找表", len(tables))
+	return nil
+}
+
+// planDryRunLookupTables 模拟运行时规划查找表并使用推导出的占位地址，使批次按 v0 交易估算大小和费用
+func planDryRunLookupTables(batches []*Batch, authority solana.PublicKey) ([]*lookupTable, error) {
+	tables := planLookupTables(batches)
+	for i, t := range tables {
+		address, _, err := lookupTableAddress(authority, uint64(i))
+		if err != nil {
+			return nil, fmt.Errorf("推导地址查找表地址失败: %w", err)
+		}
+		t.address = address
+	}
+	assignLookupTables(tables)
+	return tables, nil
+}
@@ -0,0 +1,138 @@
+package forward
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupTableInstructions(t *testing.T) {
+	t.Parallel()
+	authority := solana.NewWallet().PublicKey()
+
+	ix, table, err := createLookupTableInstruction(authority, authority, 1234)
+	require.NoError(t, err)
+	expected, bump, err := lookupTableAddress(authority, 1234)
+	require.NoError(t, err)
+	assert.Equal(t, expected, table)
+	assert.Equal(t, addressLookupTableProgramID, ix.ProgramID())
+	data, err := ix.Data()
+	require.NoError(t, err)
+	require.Len(t, data, 4+8+1)
+	assert.Equal(t, lookupTableCreate, binary.LittleEndian.Uint32(data))
+	assert.Equal(t, uint64(1234), binary.LittleEndian.Uint64(data[4:]))
+	assert.Equal(t, bump, data[12])
+	accounts := ix.Accounts()
+	require.Len(t, accounts, 4)
+	assert.True(t, accounts[0].IsWritable, "the table must be writable")
+	assert.True(t, accounts[2].IsSigner, "the payer must sign")
+
+	addresses := []solana.PublicKey{solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()}
+	data, err = extendLookupTableInstruction(table, authority, authority, addresses).Data()
+	require.NoError(t, err)
+	require.Len(t, data, 4+8+2*32)
+	assert.Equal(t, lookupTableExtend, binary.LittleEndian.Uint32(data))
+	assert.Equal(t, uint64(2), binary.LittleEndian.Uint64(data[4:]))
+	assert.Equal(t, addresses[1][:], data[4+8+32:])
+
+	data, err = deactivateLookupTableInstruction(table, authority).Data()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{3, 0, 0, 0}, data)
+	ix = closeLookupTableInstruction(table, authority, authority)
+	data, err = ix.Data()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{4, 0, 0, 0}, data)
+	assert.Len(t, ix.Accounts(), 3)
+}
+
+func TestExtendLookupTableFitsTransaction(t *testing.T) {
+	t.Parallel()
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	authority := key.PublicKey()
+	addresses := make([]solana.PublicKey, maxAddressesPerExtend)
+	for i := range addresses {
+		addresses[i] = solana.NewWallet().PublicKey()
+	}
+	_, table, err := createLookupTableInstruction(authority, authority, 1)
+	require.NoError(t, err)
+	b := instructionBatch(0, lookupTableUnits, extendLookupTableInstruction(table, authority, authority, addresses))
+	assert.LessOrEqual(t, transactionSize(t, DefaultConfig(), b, key), maxTransactionSize, "a full extend transaction must fit")
+}
+
+func TestLookupKeys(t *testing.T) {
+	t.Parallel()
+	from := solana.NewWallet().PublicKey()
+	to := solana.NewWallet().PublicKey()
+	b := &Batch{}
+	b.add(Recipient{}, recipientCost{}, system.NewTransferInstruction(1, from, to).Build())
+	b.add(Recipient{}, recipientCost{}, system.NewTransferInstruction(1, from, to).Build())
+	assert.Equal(t, []solana.PublicKey{to}, lookupKeys(b), "signers and duplicates must not be looked up")
+}
+
+func TestLookupTablesFitTransaction(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	cfg.MaxInstructionsPerTx = 100
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	from := key.PublicKey()
+	mint := &mintInfo{address: solana.NewWallet().PublicKey(), programID: solana.Token2022ProgramID, decimals: 6}
+	senderATA, err := mint.associatedTokenAddress(from)
+	require.NoError(t, err)
+
+	legacySOL := fillCapacity(cfg, solLayout)
+	legacyToken := fillCapacity(cfg, tokenCreateLayout)
+	cfg.UseLookupTables = true
+	perSOL := fillCapacity(cfg, solLayout)
+	perToken := fillCapacity(cfg, tokenCreateLayout)
+	assert.Greater(t, perSOL, legacySOL, "lookup tables must fit more SOL transfers per transaction")
+	assert.Greater(t, perToken, legacyToken, "lookup tables must fit more token transfers per transaction")
+
+	var batches []*Batch
+	for i := range 8 {
+		b := &Batch{Index: len(batches)}
+		if i%2 == 0 {
+			for range perSOL {
+				ix := system.NewTransferInstruction(1, from, solana.NewWallet().PublicKey()).Build()
+				b.add(Recipient{}, recipientCost{computeUnits: systemTransferUnits}, ix)
+			}
+		} else {
+			for range perToken {
+				to := solana.NewWallet().PublicKey()
+				ata, err := mint.associatedTokenAddress(to)
+				require.NoError(t, err)
+				createIx, err := mint.createAccountInstruction(from, to)
+				require.NoError(t, err)
+				transferIx, err := mint.transferInstruction(1, senderATA, ata, from)
+				require.NoError(t, err)
+				b.add(Recipient{}, recipientCost{computeUnits: createATAUnits + tokenTransferUnits}, createIx, transferIx)
+			}
+		}
+		batches = append(batches, b)
+	}
+
+	tables, err := planDryRunLookupTables(batches, from)
+	require.NoError(t, err)
+	require.Greater(t, len(tables), 1, "the batches must span several tables")
+	for _, table := range tables {
+		assert.LessOrEqual(t, len(table.addresses), maxLookupTableAddresses)
+	}
+	for _, b := range batches {
+		require.NotEmpty(t, b.lookupTables)
+		assert.LessOrEqual(t, len(b.lookupTables), maxLookupTablesPerTx, "batch %d must reference at most two tables", b.Index)
+
+		tx, err := buildTransaction(context.Background(), b, cfg.budgetInstructions(b, 1000), solana.Hash{}, testSigner(key))
+		require.NoError(t, err)
+		assert.Equal(t, solana.MessageVersionV0, tx.Message.GetVersion())
+		data, err := tx.MarshalBinary()
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(data), maxTransactionSize, "batch %d must fit in a transaction", b.Index)
+		assert.LessOrEqual(t, len(tx.Message.AccountKeys)+tx.Message.NumLookups(), maxTxAccountLocks, "batch %d must not exceed the account lock limit", b.Index)
+	}
+}
@@ -0,0 +1,116 @@
+package forward
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const (
+	// maxMemoLength 单条备注的最大字节数，避免备注占用过多交易空间
+	maxMemoLength = 256
+	// memoBaseUnits Memo 指令的基础计算单元估算值，实际消耗随备注长度增加
+	memoBaseUnits = 5_000
+	// memoUnitsPerByte 备注每个字节增加的计算单元估算值（UTF-8 校验和日志输出）
+	memoUnitsPerByte = 80
+)
+
+var (
+	errUnknownMemoMode    = errors.New("未知的备注方式")
+	errMemoReferenceUnset = errors.New("按交易附加备注时必须设置备注引用")
+	errMemoTooLong        = fmt.Errorf("备注超过 %d 字节", maxMemoLength)
+	errMemoNotUTF8        = errors.New("备注不是有效的 UTF-8 文本")
+)
+
+// checkMemos 校验备注方式以及备注引用和每个接收者的备注
+func (c *Config) checkMemos(recipients []Recipient) error {
+	switch c.MemoMode {
+	case MemoNone:
+		return nil
+	case MemoTransaction:
+		if c.Reference == "" {
+			return errMemoReferenceUnset
+		}
+		return checkMemo(c.Reference)
+	case MemoRecipient:
+		if err := checkMemo(c.Reference); err != nil {
+			return err
+		}
+		for i := range recipients {
+			if err := checkMemo(recipients[i].Memo); err != nil {
+				return fmt.Errorf("接收者 %s: %w", recipients[i].Address, err)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s", errUnknownMemoMode, c.MemoMode)
+}
+
+func checkMemo(memo string) error {
+	if len(memo) > maxMemoLength {
+		return errMemoTooLong
+	}
+	if !utf8.ValidString(memo) {
+		return errMemoNotUTF8
+	}
+	return nil
+}
+
+// transactionMemo 返回每笔交易附加的备注，未按交易附加备注时为空
+func (c *Config) transactionMemo() string {
+	if c.MemoMode == MemoTransaction {
+		return c.Reference
+	}
+	return ""
+}
+
+// recipientMemo 返回接收者转账指令后附加的备注，接收者未设置备注时使用备注引用；
+// 未按接收者附加备注时为空
+func (c *Config) recipientMemo(r *Recipient) string {
+	if c.MemoMode != MemoRecipient {
+		return ""
+	}
+	if r.Memo != "" {
+		return r.Memo
+	}
+	return c.Reference
+}
+
+// withMemo 返回附加接收者备注后的交易构成：共用账户增加 Memo 程序，
+// 备注非空时每个接收者增加一条 Memo 指令
+// 按接收者附加备注时，即使某个接收者没有备注也计入 Memo 程序，保证同一批次混合有无备注的接收者时不会低估交易大小
+func (l txLayout) withMemo(cfg *Config, memo string) txLayout {
+	if cfg.MemoMode != MemoRecipient {
+		return l
+	}
+	l.accounts++
+	if memo != "" {
+		l.recipientSize += memoInstructionSize(memo)
+		l.recipientUnits += memoUnits(memo)
+	}
+	return l
+}
+
+// memoInstruction 构建由付款人签名的 SPL Memo 指令，指令数据即备注原文
+func memoInstruction(memo string, signer solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(solana.MemoProgramID, solana.AccountMetaSlice{solana.Meta(signer).SIGNER()}, []byte(memo))
+}
+
+// memoInstructionSize 返回 Memo 指令序列化后的字节数：程序索引、账户数量、签名者索引、数据长度和备注原文
+func memoInstructionSize(memo string) int {
+	size := 1 + 1 + 1 + 1 + len(memo)
+	if len(memo) >= 0x80 {
+		size++
+	}
+	return size
+}
+
+// memoUnits 返回 Memo 指令的计算单元估算值
+func memoUnits(memo string) uint32 {
+	if memo == "" {
+		return 0
+	}
+	return memoBaseUnits + memoUnitsPerByte*uint32(len(memo))
+}
@@ -0,0 +1,97 @@
+package forward
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckMemos(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	recipients := []Recipient{{Address: testAddress1, Memo: "invoice-1"}, {Address: testAddress2}}
+	assert.NoError(t, cfg.checkMemos(recipients), "checkMemos should not error without a memo mode")
+
+	cfg.MemoMode = MemoTransaction
+	assert.ErrorIs(t, cfg.checkMemos(recipients), errMemoReferenceUnset)
+	cfg.Reference = "payout-2026-10"
+	assert.NoError(t, cfg.checkMemos(recipients))
+
+	cfg.MemoMode = MemoRecipient
+	recipients[1].Memo = strings.Repeat("a", maxMemoLength+1)
+	assert.ErrorIs(t, cfg.checkMemos(recipients), errMemoTooLong)
+	recipients[1].Memo = "\xff"
+	assert.ErrorIs(t, cfg.checkMemos(recipients), errMemoNotUTF8)
+
+	cfg.MemoMode = "bogus"
+	assert.ErrorIs(t, cfg.checkMemos(recipients), errUnknownMemoMode)
+}
+
+func TestMemoText(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	cfg.Reference = "ref"
+	r := &Recipient{Memo: "invoice-1"}
+	assert.Empty(t, cfg.transactionMemo(), "no memo should be added without a memo mode")
+	assert.Empty(t, cfg.recipientMemo(r), "no memo should be added without a memo mode")
+
+	cfg.MemoMode = MemoTransaction
+	assert.Equal(t, "ref", cfg.transactionMemo())
+	assert.Empty(t, cfg.recipientMemo(r), "transaction memos must not add recipient memos")
+
+	cfg.MemoMode = MemoRecipient
+	assert.Empty(t, cfg.transactionMemo(), "recipient memos must not add a transaction memo")
+	assert.Equal(t, "invoice-1", cfg.recipientMemo(r))
+	assert.Equal(t, "ref", cfg.recipientMemo(&Recipient{}), "recipients without a memo should use the reference")
+}
+
+func TestMemoBatchFitsTransaction(t *testing.T) {
+	t.Parallel()
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	from := key.PublicKey()
+
+	plain := DefaultConfig()
+	plain.MaxInstructionsPerTx = 100
+	for _, mode := range []MemoMode{MemoTransaction, MemoRecipient} {
+		cfg := DefaultConfig()
+		cfg.MaxInstructionsPerTx = 100
+		cfg.MemoMode = mode
+		cfg.Reference = strings.Repeat("r", 200)
+
+		capacity := cfg.newTxCapacity()
+		b := &Batch{Memo: cfg.transactionMemo()}
+		for {
+			memo := cfg.recipientMemo(&Recipient{})
+			if !capacity.reserve(solLayout.withMemo(cfg, memo)) {
+				break
+			}
+			b.instructions = append(b.instructions, system.NewTransferInstruction(1, from, solana.NewWallet().PublicKey()).Build())
+			if memo != "" {
+				b.instructions = append(b.instructions, memoInstruction(memo, from))
+			}
+		}
+		assert.LessOrEqual(t, transactionSize(t, cfg, b, key), maxTransactionSize, "%s memo batch must fit in a transaction", mode)
+		assert.Less(t, capacity.used, fillCapacity(plain, solLayout), "%s memos must reduce the recipients per transaction", mode)
+	}
+}
+
+func TestMemoInstruction(t *testing.T) {
+	t.Parallel()
+	signer := solana.NewWallet().PublicKey()
+	ix := memoInstruction("invoice-1", signer)
+	assert.Equal(t, solana.MemoProgramID, ix.ProgramID())
+	data, err := ix.Data()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("invoice-1"), data, "memo data must be the raw memo text")
+	require.Len(t, ix.Accounts(), 1)
+	assert.True(t, ix.Accounts()[0].IsSigner, "the memo must be signed by the payer")
+
+	assert.Equal(t, 4+9, memoInstructionSize("invoice-1"))
+	assert.Equal(t, 5+200, memoInstructionSize(strings.Repeat("a", 200)), "long memos need a two byte length prefix")
+	assert.Zero(t, memoUnits(""))
+}
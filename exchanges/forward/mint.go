@@ -0,0 +1,240 @@
+package forward
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// mintSize 铸币账户基础数据长度
+	mintSize = 82
+	// mintDecimalsOffset 基础数据中精度字段的偏移
+	mintDecimalsOffset = 44
+	// accountTypeOffset Token-2022 扩展账户中账户类型字段的偏移，
+	// 铸币账户会填充到与代币账户相同的长度，使两者的扩展数据起始位置一致
+	accountTypeOffset = tokenAccountSize
+	// accountTypeMint 账户类型字段中表示铸币账户的值
+	accountTypeMint = 1
+
+	// extensionTransferFeeConfig 铸币账户的转账手续费扩展
+	extensionTransferFeeConfig = 1
+	// transferFeeConfigSize 转账手续费扩展的数据长度：两个权限、已扣留数量、新旧两组费率
+	transferFeeConfigSize = 32 + 32 + 8 + 2*transferFeeSize
+	// transferFeeSize 单组费率的长度：生效纪元、最大手续费、费率（基点）
+	transferFeeSize = 8 + 8 + 2
+
+	// token2022AccountExtensionSize Token-2022 关联代币账户比基础代币账户多出的长度：
+	// 账户类型和 ImmutableOwner 扩展
+	token2022AccountExtensionSize = 1 + 4
+	// transferFeeAmountSize 使用转账手续费的铸币，其代币账户额外包含 TransferFeeAmount 扩展
+	transferFeeAmountSize = 4 + 8
+
+	// createIdempotentInstruction 关联代币账户程序 CreateIdempotent 指令的编号
+	createIdempotentInstruction = 1
+)
+
+var (
+	errNotMintAccount   = errors.New("账户不是代币铸币账户")
+	errMalformedMint    = errors.New("铸币账户数据格式错误")
+	errUnsupportedOwner = errors.New("铸币账户不属于 SPL Token 或 Token-2022 程序")
+)
+
+// transferFee 一组转账手续费率
+type transferFee struct {
+	epoch       uint64 // 生效纪元
+	maximumFee  uint64 // 单笔转账的最大手续费（原始数量）
+	basisPoints uint16 // 费率，单位为万分之一
+}
+
+// mintInfo 转账所需的铸币信息
+type mintInfo struct {
+	address   solana.PublicKey
+	programID solana.PublicKey // 铸币所属的代币程序
+	decimals  uint8
+	// 转账手续费扩展，仅 Token-2022 铸币可能设置
+	olderFee, newerFee *transferFee
+}
+
+// fetchMint 查询铸币账户，根据账户所有者识别 SPL Token 或 Token-2022，并解析精度和转账手续费扩展
+func fetchMint(ctx context.Context, client *rpc.Client, mint solana.PublicKey) (*mintInfo, error) {
+	out, err := client.GetAccountInfoWithOpts(ctx, mint, &rpc.GetAccountInfoOpts{
+		Encoding:   solana.EncodingBase64,
+		Commitment: rpc.CommitmentFinalized,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取铸币账户失败: %w", err)
+	}
+	if out == nil || out.Value == nil {
+		return nil, fmt.Errorf("%w: %s", errNotMintAccount, mint)
+	}
+	return parseMint(mint, out.Value.Owner, out.Value.Data.GetBinary())
+}
+
+// TokenDecimals 返回代币的精度，转账数量超出该精度的部分会被截断
+func (m *Manager) TokenDecimals(ctx context.Context, tokenMint string) (uint8, error) {
+	mint, err := solana.PublicKeyFromBase58(tokenMint)
+	if err != nil {
+		return 0, fmt.Errorf("无效的代币铸币地址: %w", err)
+	}
+	rpcClient, err := m.client()
+	if err != nil {
+		return 0, err
+	}
+	info, err := fetchMint(ctx, rpcClient, mint)
+	if err != nil {
+		return 0, err
+	}
+	return info.decimals, nil
+}
+
+// parseMint 解析铸币账户数据
+func parseMint(mint, owner solana.PublicKey, data []byte) (*mintInfo, error) {
+	if !owner.Equals(solana.TokenProgramID) && !owner.Equals(solana.Token2022ProgramID) {
+		return nil, fmt.Errorf("%w: %s 的所有者为 %s", errUnsupportedOwner, mint, owner)
+	}
+	if len(data) < mintSize {
+		return nil, fmt.Errorf("%w: %s", errNotMintAccount, mint)
+	}
+	info := &mintInfo{address: mint, programID: owner, decimals: data[mintDecimalsOffset]}
+	if !owner.Equals(solana.Token2022ProgramID) || len(data) == mintSize {
+		return info, nil
+	}
+
+	// Token-2022 扩展数据：账户类型之后为 TLV 格式（类型 u16、长度 u16、数据）
+	if len(data) <= accountTypeOffset || data[accountTypeOffset] != accountTypeMint {
+		return nil, fmt.Errorf("%w: %s", errNotMintAccount, mint)
+	}
+	for tlv := data[accountTypeOffset+1:]; len(tlv) >= 4; {
+		extType := binary.LittleEndian.Uint16(tlv)
+		length := int(binary.LittleEndian.Uint16(tlv[2:]))
+		if extType == 0 {
+			// 未初始化的填充数据
+			break
+		}
+		if len(tlv) < 4+length {
+			return nil, fmt.Errorf("%w: %s 扩展 %d 长度越界", errMalformedMint, mint, extType)
+		}
+		if extType == extensionTransferFeeConfig {
+			if length < transferFeeConfigSize {
+				return nil, fmt.Errorf("%w: %s 转账手续费扩展长度 %d", errMalformedMint, mint, length)
+			}
+			fees := tlv[4+32+32+8:]
+			info.olderFee = parseTransferFee(fees)
+			info.newerFee = parseTransferFee(fees[transferFeeSize:])
+		}
+		tlv = tlv[4+length:]
+	}
+	return info, nil
+}
+
+func parseTransferFee(data []byte) *transferFee {
+	return &transferFee{
+		epoch:       binary.LittleEndian.Uint64(data),
+		maximumFee:  binary.LittleEndian.Uint64(data[8:]),
+		basisPoints: binary.LittleEndian.Uint16(data[16:]),
+	}
+}
+
+// isToken2022 铸币是否属于 Token-2022 程序
+func (m *mintInfo) isToken2022() bool {
+	return m.programID.Equals(solana.Token2022ProgramID)
+}
+
+// hasTransferFee 铸币是否启用了转账手续费扩展
+func (m *mintInfo) hasTransferFee() bool {
+	return m.newerFee != nil
+}
+
+// accountSize 该铸币的关联代币账户数据长度，用于估算创建账户的租金
+func (m *mintInfo) accountSize() uint64 {
+	if !m.isToken2022() {
+		return tokenAccountSize
+	}
+	size := uint64(tokenAccountSize + token2022AccountExtensionSize)
+	if m.hasTransferFee() {
+		size += transferFeeAmountSize
+	}
+	return size
+}
+
+// withheldFee 计算在指定纪元转账 amount（原始数量）时被扣留在接收者账户中的手续费
+// 与链上规则一致：按费率向上取整，且不超过最大手续费
+func (m *mintInfo) withheldFee(amount, epoch uint64) uint64 {
+	if !m.hasTransferFee() {
+		return 0
+	}
+	fee := m.olderFee
+	if epoch >= m.newerFee.epoch {
+		fee = m.newerFee
+	}
+	if fee.basisPoints == 0 || amount == 0 {
+		return 0
+	}
+	n := new(big.Int).Mul(new(big.Int).SetUint64(amount), big.NewInt(int64(fee.basisPoints)))
+	n.Add(n, big.NewInt(9999))
+	n.Quo(n, big.NewInt(10000))
+	if !n.IsUint64() || n.Uint64() > fee.maximumFee {
+		return fee.maximumFee
+	}
+	return n.Uint64()
+}
+
+// associatedTokenAddress 按铸币所属的代币程序推导关联代币账户地址
+func (m *mintInfo) associatedTokenAddress(wallet solana.PublicKey) (solana.PublicKey, error) {
+	addr, _, err := solana.FindProgramAddress([][]byte{
+		wallet[:],
+		m.programID[:],
+		m.address[:],
+	}, solana.SPLAssociatedTokenAccountProgramID)
+	return addr, err
+}
+
+// createAccountInstruction 为 wallet 创建关联代币账户的指令，账户归属铸币所属的代币程序
+// 使用 CreateIdempotent，账户已存在时不会失败，同一钱包出现在并发发送的多个批次中时各批次均可独立执行
+func (m *mintInfo) createAccountInstruction(payer, wallet solana.PublicKey) (solana.Instruction, error) {
+	ix := associatedtokenaccount.NewCreateInstruction(payer, wallet, m.address).Build()
+	accounts := ix.Accounts()
+	ata, err := m.associatedTokenAddress(wallet)
+	if err != nil {
+		return nil, err
+	}
+	// 库中的指令固定使用 SPL Token 程序，替换为铸币实际所属的程序及对应的账户地址
+	accounts[1].PublicKey = ata
+	accounts[5].PublicKey = m.programID
+	return solana.NewInstruction(ix.ProgramID(), accounts, []byte{createIdempotentInstruction}), nil
+}
+
+// transferInstruction 构建 TransferChecked 指令，由代币程序校验精度和铸币
+func (m *mintInfo) transferInstruction(amount uint64, source, destination, owner solana.PublicKey) (solana.Instruction, error) {
+	ix := token.NewTransferCheckedInstruction(amount, m.decimals, source, m.address, destination, owner, nil).Build()
+	data, err := ix.Data()
+	if err != nil {
+		return nil, err
+	}
+	return solana.NewInstruction(m.programID, ix.Accounts(), data), nil
+}
+
+// closeAccountInstruction 构建关闭代币账户的指令，账户中的租金退回 destination
+// 代币账户余额必须为 0，Token-2022 账户中还不能有已扣留的转账手续费
+func (m *mintInfo) closeAccountInstruction(account, destination, owner solana.PublicKey) (solana.Instruction, error) {
+	ix := token.NewCloseAccountInstruction(account, destination, owner, nil).Build()
+	data, err := ix.Data()
+	if err != nil {
+		return nil, err
+	}
+	return solana.NewInstruction(m.programID, ix.Accounts(), data), nil
+}
+
+// FromBaseUnits 按精度将原始数量转换为代币数量
+func FromBaseUnits(amount uint64, decimals uint8) float64 {
+	return decimal.NewFromBigInt(new(big.Int).SetUint64(amount), -int32(decimals)).InexactFloat64()
+}
@@ -0,0 +1,109 @@
+package forward
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// token2022MintData 构造带转账手续费扩展的 Token-2022 铸币账户数据
+func token2022MintData(decimals uint8, older, newer transferFee) []byte {
+	data := make([]byte, accountTypeOffset+1, accountTypeOffset+1+4+transferFeeConfigSize)
+	data[mintDecimalsOffset] = decimals
+	data[accountTypeOffset] = accountTypeMint
+	data = binary.LittleEndian.AppendUint16(data, extensionTransferFeeConfig)
+	data = binary.LittleEndian.AppendUint16(data, transferFeeConfigSize)
+	data = append(data, make([]byte, 32+32+8)...)
+	for _, fee := range []transferFee{older, newer} {
+		data = binary.LittleEndian.AppendUint64(data, fee.epoch)
+		data = binary.LittleEndian.AppendUint64(data, fee.maximumFee)
+		data = binary.LittleEndian.AppendUint16(data, fee.basisPoints)
+	}
+	return data
+}
+
+func TestParseMint(t *testing.T) {
+	t.Parallel()
+	address := solana.NewWallet().PublicKey()
+
+	legacy := make([]byte, mintSize)
+	legacy[mintDecimalsOffset] = 9
+	m, err := parseMint(address, solana.TokenProgramID, legacy)
+	require.NoError(t, err, "parseMint must not error for a legacy mint")
+	assert.False(t, m.isToken2022())
+	assert.Equal(t, uint8(9), m.decimals)
+	assert.False(t, m.hasTransferFee())
+	assert.Equal(t, uint64(tokenAccountSize), m.accountSize())
+
+	m, err = parseMint(address, solana.Token2022ProgramID, legacy)
+	require.NoError(t, err, "parseMint must not error for a Token-2022 mint without extensions")
+	assert.True(t, m.isToken2022())
+	assert.False(t, m.hasTransferFee())
+
+	data := token2022MintData(6, transferFee{epoch: 0, maximumFee: 1000, basisPoints: 50}, transferFee{epoch: 100, maximumFee: 5000, basisPoints: 100})
+	m, err = parseMint(address, solana.Token2022ProgramID, data)
+	require.NoError(t, err, "parseMint must not error for a Token-2022 mint with a transfer fee")
+	assert.Equal(t, uint8(6), m.decimals)
+	require.True(t, m.hasTransferFee())
+	assert.Equal(t, uint64(tokenAccountSize+token2022AccountExtensionSize+transferFeeAmountSize), m.accountSize())
+
+	_, err = parseMint(address, solana.SystemProgramID, legacy)
+	assert.ErrorIs(t, err, errUnsupportedOwner)
+	_, err = parseMint(address, solana.TokenProgramID, legacy[:40])
+	assert.ErrorIs(t, err, errNotMintAccount)
+	_, err = parseMint(address, solana.Token2022ProgramID, data[:len(data)-10])
+	assert.ErrorIs(t, err, errMalformedMint)
+}
+
+func TestWithheldFee(t *testing.T) {
+	t.Parallel()
+	m := &mintInfo{
+		olderFee: &transferFee{epoch: 0, maximumFee: 1000, basisPoints: 50},
+		newerFee: &transferFee{epoch: 100, maximumFee: 5000, basisPoints: 100},
+	}
+	assert.Equal(t, uint64(50), m.withheldFee(10_000, 99), "older fee must apply before the newer fee epoch")
+	assert.Equal(t, uint64(100), m.withheldFee(10_000, 100), "newer fee must apply from its epoch")
+	assert.Equal(t, uint64(1), m.withheldFee(1, 100), "fee must round up")
+	assert.Equal(t, uint64(5000), m.withheldFee(10_000_000, 100), "fee must be capped at the maximum fee")
+	assert.Zero(t, m.withheldFee(0, 100))
+	assert.Zero(t, (&mintInfo{}).withheldFee(10_000, 100), "mints without a transfer fee must not withhold anything")
+	assert.Equal(t, 0.0001, FromBaseUnits(100, 6))
+}
+
+func TestMintInstructions(t *testing.T) {
+	t.Parallel()
+	payer := solana.NewWallet().PublicKey()
+	wallet := solana.NewWallet().PublicKey()
+
+	legacy := &mintInfo{address: solana.NewWallet().PublicKey(), programID: solana.TokenProgramID, decimals: 6}
+	ata, err := legacy.associatedTokenAddress(wallet)
+	require.NoError(t, err)
+	expected, _, err := solana.FindAssociatedTokenAddress(wallet, legacy.address)
+	require.NoError(t, err)
+	assert.Equal(t, expected, ata, "legacy mints must derive the standard ATA")
+
+	token2022 := &mintInfo{address: legacy.address, programID: solana.Token2022ProgramID, decimals: 6}
+	ata, err = token2022.associatedTokenAddress(wallet)
+	require.NoError(t, err)
+	assert.NotEqual(t, expected, ata, "Token-2022 mints must derive the ATA with the Token-2022 program")
+
+	ix, err := token2022.createAccountInstruction(payer, wallet)
+	require.NoError(t, err)
+	assert.Equal(t, solana.SPLAssociatedTokenAccountProgramID, ix.ProgramID())
+	assert.Equal(t, ata, ix.Accounts()[1].PublicKey)
+	assert.Equal(t, solana.Token2022ProgramID, ix.Accounts()[5].PublicKey)
+	data, err := ix.Data()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{createIdempotentInstruction}, data, "ATA creation must be idempotent")
+
+	ix, err = token2022.transferInstruction(100, payer, ata, wallet)
+	require.NoError(t, err)
+	assert.Equal(t, solana.Token2022ProgramID, ix.ProgramID())
+	assert.Equal(t, token2022.address, ix.Accounts()[1].PublicKey, "TransferChecked must include the mint")
+	data, err = ix.Data()
+	require.NoError(t, err)
+	assert.Equal(t, uint8(6), data[len(data)-1], "TransferChecked must carry the mint decimals")
+}
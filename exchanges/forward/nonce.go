@@ -0,0 +1,376 @@
+package forward
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"gocryptotrader/log"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	// nonceAccountSize nonce 账户的数据长度：版本、状态、权限账户、nonce 值和每签名费用
+	nonceAccountSize = 4 + 4 + 32 + 32 + 8
+	// nonceInitialized nonce 账户已初始化的状态值
+	nonceInitialized = 1
+	// nonceSeedPrefix nonce 账户由权限钱包和种子推导地址，不需要保存额外的私钥
+	nonceSeedPrefix = "nonce-"
+	// nonceScanChunk 查找钱包的 nonce 账户时每次查询的种子数量，整组种子都未使用时停止查找
+	nonceScanChunk = 100
+	// advanceNonceSize AdvanceNonceAccount 指令及其 nonce 账户和 RecentBlockhashes sysvar 的字节数
+	advanceNonceSize = (1 + 1 + 3 + 1 + 4) + 2*32
+	// nonceManagementUnits 创建或关闭 nonce 账户的计算单元估算值
+	nonceManagementUnits = 5_000
+)
+
+var (
+	errNotEnoughNonceAccounts = errors.New("nonce 账户数量少于批次数量")
+	errNonceAuthority         = errors.New("nonce 账户的权限账户不是发送者")
+	errNonceUninitialized     = errors.New("nonce 账户未初始化")
+	errMalformedNonceAccount  = errors.New("nonce 账户数据格式错误")
+	errNonceAccountNotFound   = errors.New("nonce 账户不存在")
+	errSignOnlyWithoutNonce   = errors.New("预先签名的交易需要配置 nonce 账户")
+	errSignOnlyLookupTables   = errors.New("预先签名的交易不能使用地址查找表")
+	errInvalidNonceCount      = errors.New("创建的 nonce 账户数量必须大于 0")
+)
+
+// nonceState 批次交易使用的 nonce 账户及其当前的 nonce 值
+type nonceState struct {
+	account solana.PublicKey
+	value   solana.Hash
+}
+
+// nonceData nonce 账户中的数据
+type nonceData struct {
+	authority            solana.PublicKey
+	nonce                solana.Hash
+	lamportsPerSignature uint64
+}
+
+// parseNonceAccount 解析 nonce 账户数据，未初始化的账户返回 errNonceUninitialized
+func parseNonceAccount(data []byte) (*nonceData, error) {
+	if len(data) < nonceAccountSize {
+		return nil, fmt.Errorf("%w: 长度 %d", errMalformedNonceAccount, len(data))
+	}
+	if binary.LittleEndian.Uint32(data[4:]) != nonceInitialized {
+		return nil, errNonceUninitialized
+	}
+	return &nonceData{
+		authority:            solana.PublicKeyFromBytes(data[8:40]),
+		nonce:                solana.HashFromBytes(data[40:72]),
+		lamportsPerSignature: binary.LittleEndian.Uint64(data[72:]),
+	}, nil
+}
+
+// nonceSeed 返回第 i 个 nonce 账户的种子
+func nonceSeed(i int) string {
+	return nonceSeedPrefix + strconv.Itoa(i)
+}
+
+// nonceAddress 由权限钱包和种子推导 nonce 账户地址
+func nonceAddress(authority solana.PublicKey, seed string) (solana.PublicKey, error) {
+	return solana.CreateWithSeed(authority, seed, solana.SystemProgramID)
+}
+
+// advanceNonceInstruction 构建推进 nonce 的指令，使用 nonce 的交易必须以该指令开头
+func advanceNonceInstruction(n *nonceState, authority solana.PublicKey) solana.Instruction {
+	return system.NewAdvanceNonceAccountInstruction(n.account, solana.SysVarRecentBlockHashesPubkey, authority).Build()
+}
+
+// findNonceAccounts 按种子顺序查找钱包名下的 nonce 账户，返回已存在的账户和已使用的种子序号
+func findNonceAccounts(ctx context.Context, client *rpc.Client, authority solana.PublicKey) ([]*NonceAccount, map[int]struct{}, error) {
+	var found []*NonceAccount
+	used := make(map[int]struct{})
+	for start := 0; ; start += nonceScanChunk {
+		addresses := make([]solana.PublicKey, nonceScanChunk)
+		for i := range addresses {
+			address, err := nonceAddress(authority, nonceSeed(start+i))
+			if err != nil {
+				return nil, nil, fmt.Errorf("推导 nonce 账户地址失败: %w", err)
+			}
+			addresses[i] = address
+		}
+		accounts, err := fetchAccounts(ctx, client, addresses)
+		if err != nil {
+			return nil, nil, err
+		}
+		exists := false
+		for i, account := range accounts {
+			if account == nil {
+				continue
+			}
+			exists = true
+			used[start+i] = struct{}{}
+			n := &NonceAccount{
+				Address:   addresses[i].String(),
+				Seed:      nonceSeed(start + i),
+				Authority: authority.String(),
+				Lamports:  account.Lamports,
+			}
+			if data, err := parseNonceAccount(account.Data.GetBinary()); err == nil {
+				n.Authority = data.authority.String()
+				n.Nonce = data.nonce.String()
+				n.LamportsPerSignature = data.lamportsPerSignature
+			}
+			found = append(found, n)
+		}
+		if !exists {
+			return found, used, nil
+		}
+	}
+}
+
+// NonceAccounts 返回钱包名下由种子推导的全部 nonce 账户
+func (m *Manager) NonceAccounts(ctx context.Context, authority string) ([]*NonceAccount, error) {
+	wallet, err := solana.PublicKeyFromBase58(authority)
+	if err != nil {
+		return nil, fmt.Errorf("无效的钱包地址: %w", err)
+	}
+	rpcClient, err := m.client()
+	if err != nil {
+		return nil, err
+	}
+	accounts, _, err := findNonceAccounts(ctx, rpcClient, wallet)
+	return accounts, err
+}
+
+// CreateNonceAccounts 为钱包创建 Count 个 nonce 账户，钱包同时是权限账户并支付租金和交易费
+// 新账户使用尚未占用的最小种子序号，创建完成后返回钱包名下的全部 nonce 账户
+func (m *Manager) CreateNonceAccounts(ctx context.Context, req *NonceAccountRequest) ([]*NonceAccount, error) {
+	if req.Count <= 0 {
+		return nil, errInvalidNonceCount
+	}
+	signer, err := newAccountSigner(req.Signer, req.From)
+	if err != nil {
+		return nil, err
+	}
+	authority := signer.PublicKey()
+	rpcClient, err := m.client()
+	if err != nil {
+		return nil, err
+	}
+
+	_, used, err := findNonceAccounts(ctx, rpcClient, authority)
+	if err != nil {
+		return nil, err
+	}
+	rent, err := rpcClient.GetMinimumBalanceForRentExemption(ctx, nonceAccountSize, rpc.CommitmentFinalized)
+	if err != nil {
+		return nil, fmt.Errorf("获取 nonce 账户租金失败: %w", err)
+	}
+
+	batches := make([]*Batch, 0, req.Count)
+	for i := 0; len(batches) < req.Count; i++ {
+		if _, ok := used[i]; ok {
+			continue
+		}
+		seed := nonceSeed(i)
+		address, err := nonceAddress(authority, seed)
+		if err != nil {
+			return nil, fmt.Errorf("推导 nonce 账户地址失败: %w", err)
+		}
+		batches = append(batches, instructionBatch(len(batches), nonceManagementUnits,
+			system.NewCreateAccountWithSeedInstruction(authority, seed, rent, nonceAccountSize, solana.SystemProgramID, authority, address, authority).Build(),
+			system.NewInitializeNonceAccountInstruction(authority, address, solana.SysVarRecentBlockHashesPubkey, solana.SysVarRentPubkey).Build(),
+		))
+	}
+	if err = m.executeConfirmed(ctx, rpcClient, signer, batches, managementConfig(req.Config), "创建 nonce 账户"); err != nil {
+		return nil, err
+	}
+	log.Infof(log.Global, "已为 %s 创建 %d 个 nonce 账户", authority, len(batches))
+
+	accounts, _, err := findNonceAccounts(ctx, rpcClient, authority)
+	return accounts, err
+}
+
+// CloseNonceAccounts 提取 nonce 账户中的全部 lamports 到权限钱包，账户随之关闭
+// Addresses 为空时关闭钱包名下的全部 nonce 账户，返回被关闭的账户
+func (m *Manager) CloseNonceAccounts(ctx context.Context, req *NonceAccountRequest) ([]*NonceAccount, error) {
+	signer, err := newAccountSigner(req.Signer, req.From)
+	if err != nil {
+		return nil, err
+	}
+	authority := signer.PublicKey()
+	rpcClient, err := m.client()
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []*NonceAccount
+	if len(req.Addresses) == 0 {
+		if accounts, _, err = findNonceAccounts(ctx, rpcClient, authority); err != nil {
+			return nil, err
+		}
+	} else {
+		if accounts, err = loadNonceAccounts(ctx, rpcClient, req.Addresses); err != nil {
+			return nil, err
+		}
+	}
+
+	batches := make([]*Batch, 0, len(accounts))
+	for _, n := range accounts {
+		if n.Authority != authority.String() {
+			return nil, fmt.Errorf("%w: %s", errNonceAuthority, n.Address)
+		}
+		address := solana.MustPublicKeyFromBase58(n.Address)
+		batches = append(batches, instructionBatch(len(batches), nonceManagementUnits,
+			system.NewWithdrawNonceAccountInstruction(n.Lamports, address, authority, solana.SysVarRecentBlockHashesPubkey, solana.SysVarRentPubkey, authority).Build(),
+		))
+	}
+	if err = m.executeConfirmed(ctx, rpcClient, signer, batches, managementConfig(req.Config), "关闭 nonce 账户"); err != nil {
+		return nil, err
+	}
+	log.Infof(log.Global, "已关闭 %s 的 %d 个 nonce 账户", authority, len(batches))
+	return accounts, nil
+}
+
+// loadNonceAccounts 查询指定的 nonce 账户
+func loadNonceAccounts(ctx context.Context, client *rpc.Client, addresses []string) ([]*NonceAccount, error) {
+	keys := make([]solana.PublicKey, len(addresses))
+	for i, address := range addresses {
+		key, err := solana.PublicKeyFromBase58(address)
+		if err != nil {
+			return nil, fmt.Errorf("无效的 nonce 账户地址 %s: %w", address, err)
+		}
+		keys[i] = key
+	}
+	accounts, err := fetchAccounts(ctx, client, keys)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*NonceAccount, len(keys))
+	for i, account := range accounts {
+		if account == nil {
+			return nil, fmt.Errorf("%w: %s", errNonceAccountNotFound, keys[i])
+		}
+		data, err := parseNonceAccount(account.Data.GetBinary())
+		if err != nil {
+			return nil, fmt.Errorf("nonce 账户 %s: %w", keys[i], err)
+		}
+		result[i] = &NonceAccount{
+			Address:              keys[i].String(),
+			Authority:            data.authority.String(),
+			Nonce:                data.nonce.String(),
+			Lamports:             account.Lamports,
+			LamportsPerSignature: data.lamportsPerSignature,
+		}
+	}
+	return result, nil
+}
+
+// assignNonces 为每个批次分配一个 nonce 账户，交易使用账户中当前的 nonce 值代替 blockhash
+// 每个 nonce 值只能被一笔交易使用，因此 nonce 账户数量不能少于批次数量；nonce 账户的权限账户必须是发送者
+func assignNonces(ctx context.Context, client *rpc.Client, from solana.PublicKey, batches []*Batch, cfg *Config) error {
+	if len(cfg.NonceAccounts) < len(batches) {
+		return fmt.Errorf("%w: 批次 %d 个，nonce 账户 %d 个", errNotEnoughNonceAccounts, len(batches), len(cfg.NonceAccounts))
+	}
+	accounts, err := loadNonceAccounts(ctx, client, cfg.NonceAccounts[:len(batches)])
+	if err != nil {
+		return err
+	}
+	for i, b := range batches {
+		if accounts[i].Authority != from.String() {
+			return fmt.Errorf("%w: %s", errNonceAuthority, accounts[i].Address)
+		}
+		b.nonce = &nonceState{
+			account: solana.MustPublicKeyFromBase58(accounts[i].Address),
+			value:   solana.MustHashFromBase58(accounts[i].Nonce),
+		}
+	}
+	return nil
+}
+
+// sign 使用 durable nonce 对批次签名但不发送，签名后的交易保存在 Batch.Transaction，可稍后通过 SubmitSigned 发送
+// 优先费单价在签名时确定
+func (m *Manager) sign(ctx context.Context, client *rpc.Client, signer accountSigner, batches []*Batch, cfg *Config) error {
+	price := cfg.computeUnitPrice(ctx, client, signer.PublicKey())
+	for _, b := range batches {
+		if b.nonce == nil {
+			return errSignOnlyWithoutNonce
+		}
+		tx, err := buildTransaction(ctx, b, cfg.budgetInstructions(b, price), b.nonce.value, signer)
+		if err != nil {
+			return fmt.Errorf("批次 %d %w", b.Index, err)
+		}
+		if b.Transaction, err = tx.ToBase64(); err != nil {
+			return fmt.Errorf("批次 %d 序列化交易失败: %w", b.Index, err)
+		}
+		b.Signature = tx.Signatures[0].String()
+		b.NonceAccount, b.Nonce = b.nonce.account.String(), b.nonce.value.String()
+	}
+	return nil
+}
+
+// transactionNonce 返回交易开头的 AdvanceNonceAccount 指令使用的 nonce 账户和 nonce 值，
+// 交易未使用 durable nonce 时返回空字符串
+func transactionNonce(tx *solana.Transaction) (account, nonce string) {
+	if len(tx.Message.Instructions) == 0 {
+		return "", ""
+	}
+	ix := tx.Message.Instructions[0]
+	program, err := tx.Message.Program(ix.ProgramIDIndex)
+	if err != nil || !program.Equals(solana.SystemProgramID) || len(ix.Accounts) == 0 ||
+		len(ix.Data) < 4 || binary.LittleEndian.Uint32(ix.Data) != system.Instruction_AdvanceNonceAccount {
+		return "", ""
+	}
+	keys, err := tx.Message.AccountMetaList()
+	if err != nil || int(ix.Accounts[0]) >= len(keys) {
+		return "", ""
+	}
+	return keys[ix.Accounts[0]].PublicKey.String(), tx.Message.RecentBlockhash.String()
+}
+
+// SubmitSigned 发送预先签名的交易并跟踪确认结果，交易通常由 SignOnly 使用 durable nonce 签名
+// 交易已经签名，不需要签名方。nonce 推进后仍未上链的交易标记为 expired，无法重新签名；
+// 超过 ConfirmTimeout 仍未确认的批次保持 sent
+func (m *Manager) SubmitSigned(ctx context.Context, transactions []string, cfg *Config, observer Observer) ([]*Batch, error) {
+	batches := make([]*Batch, len(transactions))
+	signed := make([]*solana.Transaction, len(transactions))
+	for i, raw := range transactions {
+		tx, err := solana.TransactionFromBase64(raw)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 笔交易无法解析: %w", i+1, err)
+		}
+		if len(tx.Signatures) == 0 {
+			return nil, fmt.Errorf("第 %d 笔交易未签名", i+1)
+		}
+		signed[i] = tx
+		batches[i] = &Batch{Index: i, Status: StatusPending, Transaction: raw}
+		batches[i].NonceAccount, batches[i].Nonce = transactionNonce(tx)
+	}
+
+	rpcClient, err := m.client()
+	if err != nil {
+		return nil, err
+	}
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, max(cfg.ConcurrentTxs, 1))
+	for i, b := range batches {
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(b *Batch, tx *solana.Transaction) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			submitBatch(ctx, rpcClient, b, tx, 0, observer)
+		}(b, signed[i])
+	}
+	wg.Wait()
+	if err = ctx.Err(); err != nil {
+		return batches, err
+	}
+	if cfg.ConfirmTimeout <= 0 {
+		return batches, nil
+	}
+	// 没有签名方时过期的批次不会重新签名，直接标记为 expired
+	return batches, m.track(ctx, rpcClient, accountSigner{}, batches, cfg, observer)
+}
@@ -0,0 +1,93 @@
+package forward
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nonceAccountData 返回已初始化的 nonce 账户数据
+func nonceAccountData(authority solana.PublicKey, nonce solana.Hash) []byte {
+	data := binary.LittleEndian.AppendUint32(nil, 1)
+	data = binary.LittleEndian.AppendUint32(data, nonceInitialized)
+	data = append(data, authority[:]...)
+	data = append(data, nonce[:]...)
+	return binary.LittleEndian.AppendUint64(data, 5000)
+}
+
+func TestParseNonceAccount(t *testing.T) {
+	t.Parallel()
+	_, err := parseNonceAccount(make([]byte, 10))
+	assert.ErrorIs(t, err, errMalformedNonceAccount)
+	_, err = parseNonceAccount(make([]byte, nonceAccountSize))
+	assert.ErrorIs(t, err, errNonceUninitialized)
+
+	authority := solana.NewWallet().PublicKey()
+	nonce := solana.Hash(solana.NewWallet().PublicKey())
+	n, err := parseNonceAccount(nonceAccountData(authority, nonce))
+	require.NoError(t, err)
+	assert.Equal(t, authority, n.authority)
+	assert.Equal(t, nonce, n.nonce)
+	assert.Equal(t, uint64(5000), n.lamportsPerSignature)
+}
+
+func TestNonceAddress(t *testing.T) {
+	t.Parallel()
+	authority := solana.NewWallet().PublicKey()
+	a, err := nonceAddress(authority, nonceSeed(0))
+	require.NoError(t, err)
+	b, err := nonceAddress(authority, nonceSeed(0))
+	require.NoError(t, err)
+	c, err := nonceAddress(authority, nonceSeed(1))
+	require.NoError(t, err)
+	assert.Equal(t, a, b, "nonce addresses must be derivable from the wallet")
+	assert.NotEqual(t, a, c)
+}
+
+func TestNonceTransaction(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	cfg.MaxInstructionsPerTx = 100
+	legacy := fillCapacity(cfg, solLayout)
+	cfg.NonceAccounts = []string{solana.NewWallet().PublicKey().String()}
+	n := fillCapacity(cfg, solLayout)
+	assert.Less(t, n, legacy, "the AdvanceNonceAccount instruction must take up space")
+
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	from := key.PublicKey()
+	b := &Batch{nonce: &nonceState{
+		account: solana.MustPublicKeyFromBase58(cfg.NonceAccounts[0]),
+		value:   solana.Hash(solana.NewWallet().PublicKey()),
+	}}
+	for range n {
+		ix := system.NewTransferInstruction(1, from, solana.NewWallet().PublicKey()).Build()
+		b.add(Recipient{}, recipientCost{computeUnits: systemTransferUnits}, ix)
+	}
+	assert.Equal(t, cfg.unitLimit(b.computeUnits+advanceNonceUnits), cfg.computeUnitLimit(b))
+
+	tx, err := buildTransaction(context.Background(), b, cfg.budgetInstructions(b, 1000), solana.Hash{}, testSigner(key))
+	require.NoError(t, err)
+	assert.Equal(t, b.nonce.value, tx.Message.RecentBlockhash, "the nonce must replace the blockhash")
+	program, err := tx.Message.Program(tx.Message.Instructions[0].ProgramIDIndex)
+	require.NoError(t, err)
+	assert.Equal(t, solana.SystemProgramID, program, "AdvanceNonceAccount must be the first instruction")
+	account, nonce := transactionNonce(tx)
+	assert.Equal(t, b.nonce.account.String(), account, "the nonce account must be read back from the transaction")
+	assert.Equal(t, b.nonce.value.String(), nonce)
+	data, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(data), maxTransactionSize)
+
+	b.nonce = nil
+	tx, err = buildTransaction(context.Background(), b, nil, solana.Hash{1}, testSigner(key))
+	require.NoError(t, err)
+	account, nonce = transactionNonce(tx)
+	assert.Empty(t, account, "a blockhash transaction has no nonce")
+	assert.Empty(t, nonce)
+}
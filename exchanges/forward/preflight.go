@@ -0,0 +1,249 @@
+package forward
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gocryptotrader/log"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	// tokenAmountOffset 代币账户数据中余额字段的偏移，SPL Token 与 Token-2022 相同
+	tokenAmountOffset = 64
+	// microLamportsPerLamport 优先费单价的单位换算
+	microLamportsPerLamport = 1_000_000
+)
+
+// ErrInsufficientFunds 发送者余额不足以支付全部接收者
+var ErrInsufficientFunds = errors.New("发送者余额不足")
+
+// Preflight 发送前的资金检查结果，SOL 金额单位为 lamports，代币为原始数量
+type Preflight struct {
+	Transfers      uint64 // SOL 转账合计
+	Fees           uint64 // 交易基础费用
+	PriorityFees   uint64 // 优先费
+	Rent           uint64 // 创建关联代币账户的租金
+	LamportBalance uint64 // 发送者 SOL 余额
+	Tokens         uint64 // 代币转账合计
+	TokenBalance   uint64 // 发送者代币余额
+	Decimals       uint8  // 代币精度
+
+	// wrap 代币为 wSOL，代币余额不足的部分从 SOL 余额中包装
+	wrap bool
+}
+
+// Wrapped 返回需要从 SOL 包装为 wSOL 的 lamports，代币不是 wSOL 时为 0
+func (p *Preflight) Wrapped() uint64 {
+	if !p.wrap || p.Tokens <= p.TokenBalance {
+		return 0
+	}
+	return p.Tokens - p.TokenBalance
+}
+
+// Lamports 返回所需的 SOL 合计
+func (p *Preflight) Lamports() uint64 {
+	return p.Transfers + p.Fees + p.PriorityFees + p.Rent + p.Wrapped()
+}
+
+// LamportShortfall 返回 SOL 的缺口，余额充足时为 0
+func (p *Preflight) LamportShortfall() uint64 {
+	if p.Lamports() <= p.LamportBalance {
+		return 0
+	}
+	return p.Lamports() - p.LamportBalance
+}
+
+// TokenShortfall 返回代币的缺口，余额充足时为 0
+func (p *Preflight) TokenShortfall() uint64 {
+	if p.wrap || p.Tokens <= p.TokenBalance {
+		return 0
+	}
+	return p.Tokens - p.TokenBalance
+}
+
+// Sufficient 余额是否足以支付全部接收者
+func (p *Preflight) Sufficient() bool {
+	return p.LamportShortfall() == 0 && p.TokenShortfall() == 0
+}
+
+// covers 在已计入的费用之外，余额是否还足以支付 cost
+func (p *Preflight) covers(cost *Preflight) bool {
+	total := *p
+	total.add(cost)
+	return total.Sufficient()
+}
+
+func (p *Preflight) add(cost *Preflight) {
+	p.Transfers += cost.Transfers
+	p.Fees += cost.Fees
+	p.PriorityFees += cost.PriorityFees
+	p.Rent += cost.Rent
+	p.Tokens += cost.Tokens
+}
+
+// InsufficientFundsError 描述余额不足时的资金缺口明细
+type InsufficientFundsError struct {
+	Preflight *Preflight
+}
+
+// Error 实现 error 接口
+func (e *InsufficientFundsError) Error() string {
+	p := e.Preflight
+	var sb strings.Builder
+	sb.WriteString(ErrInsufficientFunds.Error())
+	if shortfall := p.LamportShortfall(); shortfall > 0 {
+		fmt.Fprintf(&sb, "; SOL 需要 %d lamports（转账 %d，交易费 %d，优先费 %d，租金 %d",
+			p.Lamports(), p.Transfers, p.Fees, p.PriorityFees, p.Rent)
+		if p.wrap {
+			fmt.Fprintf(&sb, "，包装 wSOL %d", p.Wrapped())
+		}
+		fmt.Fprintf(&sb, "），余额 %d，缺少 %d", p.LamportBalance, shortfall)
+	}
+	if shortfall := p.TokenShortfall(); shortfall > 0 {
+		fmt.Fprintf(&sb, "; 代币需要 %v，余额 %v，缺少 %v",
+			FromBaseUnits(p.Tokens, p.Decimals), FromBaseUnits(p.TokenBalance, p.Decimals), FromBaseUnits(shortfall, p.Decimals))
+	}
+	return sb.String()
+}
+
+// Unwrap 使 errors.Is 可以匹配 ErrInsufficientFunds
+func (e *InsufficientFundsError) Unwrap() error {
+	return ErrInsufficientFunds
+}
+
+// tokenSource 代币转账的发送方，SOL 转账时为 nil
+type tokenSource struct {
+	mint    *mintInfo
+	account solana.PublicKey // 发送者的代币账户
+	exists  bool             // 发送者的代币账户是否存在，仅在代币为 wSOL 时使用
+}
+
+// preflight 在发送前计算全部批次所需的 SOL（转账、交易费、优先费和 ATA 租金）及代币数量，并与发送者余额比较
+// 配置了 PartialPay 时按列表顺序保留余额足以支付的接收者，其余接收者移入 Result.Unpaid；
+// 否则余额不足时返回 InsufficientFundsError。模拟运行时只记录检查结果，不返回错误。
+func (m *Manager) preflight(ctx context.Context, client *rpc.Client, from solana.PublicKey, source *tokenSource, result *Result, cfg *Config, dryRun bool) error {
+	p := &Preflight{}
+	balance, err := client.GetBalance(ctx, from, rpc.CommitmentFinalized)
+	if err != nil {
+		return fmt.Errorf("获取发送者余额失败: %w", err)
+	}
+	p.LamportBalance = balance.Value
+	if source != nil {
+		p.Decimals = source.mint.decimals
+		p.TokenBalance, err = tokenBalance(ctx, client, source.account)
+		if err != nil {
+			return err
+		}
+		if source.mint.isNative() {
+			// wSOL 余额不足的部分在批次中从 SOL 包装，wSOL 账户不存在时由第一笔包装交易创建
+			p.wrap = true
+			if !source.exists {
+				if p.Rent, err = client.GetMinimumBalanceForRentExemption(ctx, tokenAccountSize, rpc.CommitmentFinalized); err != nil {
+					return fmt.Errorf("获取代币账户租金失败: %w", err)
+				}
+			}
+		}
+	}
+
+	rent, err := rentExemptions(ctx, client, result.Batches)
+	if err != nil {
+		return err
+	}
+	price := cfg.computeUnitPrice(ctx, client, from)
+
+	result.Preflight = p
+	allocateFunds(p, result, rent, price, cfg)
+	if !dryRun && !p.Sufficient() {
+		return &InsufficientFundsError{Preflight: p}
+	}
+	return nil
+}
+
+// allocateFunds 按列表顺序将各批次的费用计入 p
+// 配置了 PartialPay 时遇到余额无法覆盖的接收者即停止，截断所在批次并将其后的接收者移入 result.Unpaid
+func allocateFunds(p *Preflight, result *Result, rent map[uint64]uint64, price uint64, cfg *Config) {
+	for i, b := range result.Batches {
+		// cost 为本批次已计入的接收者的费用，fits 为其中余额足以支付的部分
+		var cost, fits Preflight
+		var units uint32
+		for k := range b.costs {
+			c := &b.costs[k]
+			units += c.computeUnits
+			cost.Transfers += c.lamports
+			cost.Tokens += c.tokens
+			if c.createsAccount {
+				cost.Rent += rent[b.accountSize]
+			}
+			cost.Fees = lamportsPerSignature
+			cost.PriorityFees = priorityFee(price, cfg.unitLimit(units))
+			if cfg.PartialPay && !p.covers(&cost) {
+				p.add(&fits)
+				result.Unpaid = b.truncate(k)
+				kept := result.Batches[:i]
+				if k > 0 {
+					kept = result.Batches[:i+1]
+				}
+				for _, rest := range result.Batches[i+1:] {
+					result.Unpaid = append(result.Unpaid, rest.Recipients...)
+				}
+				for j := range result.Unpaid {
+					result.TransferFee -= result.Unpaid[j].Fee
+				}
+				result.Batches = kept
+				log.Warnf(log.Global, "发送者余额不足，仅支付前 %d 个批次中的接收者，%d 个接收者未支付", len(kept), len(result.Unpaid))
+				return
+			}
+			fits = cost
+		}
+		p.add(&cost)
+	}
+}
+
+// priorityFee 按优先费单价和计算单元上限计算优先费（lamports），向上取整
+func priorityFee(price uint64, limit uint32) uint64 {
+	return (price*uint64(limit) + microLamportsPerLamport - 1) / microLamportsPerLamport
+}
+
+// rentExemptions 查询批次中需要创建的账户的免租金额，按账户长度缓存
+func rentExemptions(ctx context.Context, client *rpc.Client, batches []*Batch) (map[uint64]uint64, error) {
+	rent := make(map[uint64]uint64)
+	for _, b := range batches {
+		if b.ataCreations == 0 {
+			continue
+		}
+		if _, ok := rent[b.accountSize]; ok {
+			continue
+		}
+		lamports, err := client.GetMinimumBalanceForRentExemption(ctx, b.accountSize, rpc.CommitmentFinalized)
+		if err != nil {
+			return nil, fmt.Errorf("获取代币账户租金失败: %w", err)
+		}
+		rent[b.accountSize] = lamports
+	}
+	return rent, nil
+}
+
+// tokenBalance 查询代币账户余额（原始数量），账户不存在时余额为 0
+func tokenBalance(ctx context.Context, client *rpc.Client, account solana.PublicKey) (uint64, error) {
+	out, err := client.GetAccountInfoWithOpts(ctx, account, &rpc.GetAccountInfoOpts{
+		Encoding:   solana.EncodingBase64,
+		Commitment: rpc.CommitmentFinalized,
+	})
+	if err != nil {
+		if errors.Is(err, rpc.ErrNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("获取发送者代币账户失败: %w", err)
+	}
+	data := out.Value.Data.GetBinary()
+	if len(data) < tokenAmountOffset+8 {
+		return 0, fmt.Errorf("发送者代币账户 %s 数据格式错误", account)
+	}
+	return binary.LittleEndian.Uint64(data[tokenAmountOffset:]), nil
+}
@@ -0,0 +1,125 @@
+package forward
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// solBatches 构造 SOL 转账批次，每个接收者转账 lamports
+func solBatches(perTx int, lamports ...uint64) []*Batch {
+	from := solana.NewWallet().PublicKey()
+	var batches []*Batch
+	for i, amount := range lamports {
+		if i%perTx == 0 {
+			batches = append(batches, &Batch{Index: len(batches), Status: StatusPending})
+		}
+		b := batches[len(batches)-1]
+		to := solana.NewWallet().PublicKey()
+		b.add(Recipient{Address: to.String()}, recipientCost{computeUnits: systemTransferUnits, lamports: amount},
+			system.NewTransferInstruction(amount, from, to).Build())
+	}
+	return batches
+}
+
+func TestAllocateFunds(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+
+	result := &Result{Batches: solBatches(2, 1000, 2000, 3000)}
+	p := &Preflight{LamportBalance: 100_000}
+	allocateFunds(p, result, nil, 0, cfg)
+	assert.True(t, p.Sufficient())
+	assert.Equal(t, uint64(6000), p.Transfers)
+	assert.Equal(t, uint64(2*lamportsPerSignature), p.Fees)
+	assert.Len(t, result.Batches, 2)
+	assert.Empty(t, result.Unpaid)
+
+	result = &Result{Batches: solBatches(2, 1000, 2000, 3000)}
+	p = &Preflight{LamportBalance: 5000}
+	allocateFunds(p, result, nil, 0, cfg)
+	assert.False(t, p.Sufficient())
+	assert.Equal(t, uint64(6000+2*lamportsPerSignature-5000), p.LamportShortfall())
+	assert.Len(t, result.Batches, 2, "batches must be kept when partial payment is disabled")
+
+	err := &InsufficientFundsError{Preflight: p}
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	assert.Contains(t, err.Error(), "缺少 11000")
+}
+
+func TestAllocateFundsPartialPay(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	cfg.PartialPay = true
+
+	// 第一个批次全部支付，第二个批次的第一个接收者超出余额
+	result := &Result{Batches: solBatches(2, 1000, 2000, 3000, 4000), TransferFee: 4}
+	for _, b := range result.Batches {
+		for i := range b.Recipients {
+			b.Recipients[i].Fee = 1
+		}
+	}
+	p := &Preflight{LamportBalance: 1000 + 2000 + lamportsPerSignature + 1000}
+	allocateFunds(p, result, nil, 0, cfg)
+	require.Len(t, result.Batches, 1, "batches that cannot be paid must be dropped")
+	assert.Len(t, result.Unpaid, 2)
+	assert.Equal(t, float64(2), result.TransferFee, "unpaid recipients must not count towards the transfer fee")
+	assert.True(t, p.Sufficient())
+
+	// 余额在批次中间耗尽时截断该批次
+	result = &Result{Batches: solBatches(3, 1000, 2000, 3000, 4000)}
+	p = &Preflight{LamportBalance: 1000 + 2000 + lamportsPerSignature}
+	allocateFunds(p, result, nil, 0, cfg)
+	require.Len(t, result.Batches, 1)
+	assert.Len(t, result.Batches[0].Recipients, 2)
+	assert.Len(t, result.Batches[0].instructions, 2)
+	assert.Equal(t, uint32(2*systemTransferUnits), result.Batches[0].computeUnits)
+	require.Len(t, result.Unpaid, 2)
+	assert.Equal(t, uint64(3000+lamportsPerSignature), p.Lamports())
+	assert.True(t, p.Sufficient())
+
+	// 余额连一个接收者都无法支付
+	result = &Result{Batches: solBatches(3, 1000, 2000)}
+	p = &Preflight{LamportBalance: 10}
+	allocateFunds(p, result, nil, 0, cfg)
+	assert.Empty(t, result.Batches)
+	assert.Len(t, result.Unpaid, 2)
+	assert.Zero(t, p.Lamports())
+}
+
+func TestAllocateFundsTokens(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	cfg.PartialPay = true
+	rent := map[uint64]uint64{tokenAccountSize: 2_000_000}
+
+	b := &Batch{accountSize: tokenAccountSize}
+	b.add(Recipient{Address: "a"}, recipientCost{computeUnits: createATAUnits + tokenTransferUnits, tokens: 50, createsAccount: true}, nil, nil)
+	b.add(Recipient{Address: "b"}, recipientCost{computeUnits: tokenTransferUnits, tokens: 60}, nil)
+	assert.Equal(t, 1, b.ataCreations)
+
+	result := &Result{Batches: []*Batch{b}}
+	p := &Preflight{LamportBalance: 10_000_000, TokenBalance: 100}
+	allocateFunds(p, result, rent, 1000, cfg)
+	require.Len(t, result.Unpaid, 1, "recipients beyond the token balance must be left unpaid")
+	assert.Equal(t, "b", result.Unpaid[0].Address)
+	assert.Equal(t, uint64(50), p.Tokens)
+	assert.Equal(t, uint64(2_000_000), p.Rent)
+	assert.Equal(t, priorityFee(1000, cfg.unitLimit(createATAUnits+tokenTransferUnits)), p.PriorityFees)
+	assert.Len(t, b.instructions, 2)
+
+	p = &Preflight{TokenBalance: 10, Decimals: 1, Tokens: 25}
+	err := &InsufficientFundsError{Preflight: p}
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	assert.Contains(t, err.Error(), "缺少 1.5")
+}
+
+func TestPriorityFee(t *testing.T) {
+	t.Parallel()
+	assert.Zero(t, priorityFee(0, 200_000))
+	assert.Equal(t, uint64(1), priorityFee(1, 1), "priority fees must round up")
+	assert.Equal(t, uint64(200), priorityFee(1000, 200_000))
+}
@@ -0,0 +1,278 @@
+package forward
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	errEmptyAddress   = errors.New("地址为空")
+	errInvalidAmount  = errors.New("无效的转账数量")
+	errAmountOverflow = errors.New("转账数量超出范围")
+	errUnknownColumn  = errors.New("未知的列名")
+	errTooManyColumns = errors.New("列数过多")
+)
+
+// maxBaseUnits 链上转账指令可以表示的最大数量
+var maxBaseUnits = decimal.NewFromUint64(math.MaxUint64)
+
+// recipientColumns 定义 CSV 中无表头时各列的默认顺序
+var recipientColumns = []string{"address", "amount", "memo", "label"}
+
+// ReadRecipientsFromFile 从 CSV 或 JSONL 文件中读取接收者列表
+// 扩展名为 .jsonl/.ndjson 时按 JSONL 解析，否则按 CSV 解析。
+// CSV 每行格式为 address[,amount[,memo[,label]]]，首行可为表头；
+// 仅包含地址的旧格式文件同样兼容，数量留空时使用配置中的默认值。
+// 任意一行格式错误时返回 *RecipientsError，其中列出所有错误行。
+func ReadRecipientsFromFile(filePath string) ([]Recipient, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".jsonl", ".ndjson":
+		return ParseRecipientsJSONL(file)
+	default:
+		return ParseRecipientsCSV(file)
+	}
+}
+
+// ParseRecipientsCSV 解析 CSV 格式的接收者列表
+func ParseRecipientsCSV(r io.Reader) ([]Recipient, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	var (
+		recipients []Recipient
+		rowErrs    []RowError
+		columns    = recipientColumns
+		seenRow    bool
+	)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("读取文件出错: %w", err)
+			}
+			rowErrs = append(rowErrs, RowError{Line: parseErr.Line, Err: parseErr.Err})
+			continue
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		first := !seenRow
+		seenRow = true
+		if first && isHeader(record) {
+			if columns, err = parseHeader(record); err != nil {
+				return nil, fmt.Errorf("解析表头失败: %w", err)
+			}
+			continue
+		}
+
+		recipient, err := recordToRecipient(columns, record)
+		if err != nil {
+			rowErrs = append(rowErrs, RowError{Line: line, Raw: strings.Join(record, ","), Err: err})
+			continue
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	if len(rowErrs) > 0 {
+		return nil, &RecipientsError{Rows: rowErrs}
+	}
+	return recipients, nil
+}
+
+// ParseRecipientsJSONL 解析 JSONL 格式的接收者列表，每行一个 JSON 对象
+func ParseRecipientsJSONL(r io.Reader) ([]Recipient, error) {
+	var (
+		recipients []Recipient
+		rowErrs    []RowError
+	)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" || strings.HasPrefix(raw, "#") {
+			continue
+		}
+
+		var row struct {
+			Address string      `json:"address"`
+			Amount  json.Number `json:"amount"`
+			Memo    string      `json:"memo"`
+			Label   string      `json:"label"`
+		}
+		if err := json.Unmarshal([]byte(raw), &row); err != nil {
+			rowErrs = append(rowErrs, RowError{Line: line, Raw: raw, Err: err})
+			continue
+		}
+
+		recipient, err := newRecipient(row.Address, row.Amount.String(), row.Memo, row.Label)
+		if err != nil {
+			rowErrs = append(rowErrs, RowError{Line: line, Raw: raw, Err: err})
+			continue
+		}
+		recipients = append(recipients, recipient)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取文件出错: %w", err)
+	}
+
+	if len(rowErrs) > 0 {
+		return nil, &RecipientsError{Rows: rowErrs}
+	}
+	return recipients, nil
+}
+
+// CheckRecipients 校验调用方直接传入的接收者列表，行号按切片下标从 1 开始
+func CheckRecipients(recipients []Recipient) error {
+	var rowErrs []RowError
+	for i := range recipients {
+		if err := checkRecipient(&recipients[i]); err != nil {
+			rowErrs = append(rowErrs, RowError{Line: i + 1, Raw: recipients[i].Address, Err: err})
+		}
+	}
+	if len(rowErrs) > 0 {
+		return &RecipientsError{Rows: rowErrs}
+	}
+	return nil
+}
+
+// ResolveRecipients 合并 Recipients 与 Addresses，未指定数量（Amount 为 0）的接收者使用默认数量。
+// 列表中显式给出的数量在解析时必须为正数，因此 0 只表示未指定
+func ResolveRecipients(recipients []Recipient, addresses []string, defaultAmount float64) []Recipient {
+	if len(recipients) == 0 {
+		recipients = make([]Recipient, len(addresses))
+		for i := range addresses {
+			recipients[i].Address = addresses[i]
+		}
+	} else {
+		recipients = append([]Recipient(nil), recipients...)
+	}
+	for i := range recipients {
+		if recipients[i].Amount == 0 {
+			recipients[i].Amount = defaultAmount
+		}
+	}
+	return recipients
+}
+
+// ToBaseUnits 按精度将数量转换为最小单位（lamports 或代币原始数量），
+// 负数或超出 uint64 范围的数量返回错误
+func ToBaseUnits(amount float64, decimals uint8) (uint64, error) {
+	if amount < 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, fmt.Errorf("%w: %v", errInvalidAmount, amount)
+	}
+	units := decimal.NewFromFloat(amount).Shift(int32(decimals)).Truncate(0)
+	if units.GreaterThan(maxBaseUnits) {
+		return 0, fmt.Errorf("%w: %v", errAmountOverflow, amount)
+	}
+	return units.BigInt().Uint64(), nil
+}
+
+// recipientUnits 返回接收者按最小单位计的转账数量，已指定 Units 时直接使用
+func recipientUnits(r *Recipient, decimals uint8) (uint64, error) {
+	if r.Units > 0 {
+		return r.Units, nil
+	}
+	return ToBaseUnits(r.Amount, decimals)
+}
+
+func parseHeader(record []string) ([]string, error) {
+	columns := make([]string, len(record))
+	for i := range record {
+		name := strings.ToLower(strings.TrimSpace(record[i]))
+		known := false
+		for _, c := range recipientColumns {
+			if name == c {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("%w: %s", errUnknownColumn, record[i])
+		}
+		columns[i] = name
+	}
+	return columns, nil
+}
+
+func recordToRecipient(columns, record []string) (Recipient, error) {
+	if len(record) > len(columns) {
+		return Recipient{}, fmt.Errorf("%w: %d > %d", errTooManyColumns, len(record), len(columns))
+	}
+	fields := make(map[string]string, len(record))
+	for i := range record {
+		fields[columns[i]] = strings.TrimSpace(record[i])
+	}
+	return newRecipient(fields["address"], fields["amount"], fields["memo"], fields["label"])
+}
+
+func newRecipient(address, amount, memo, label string) (Recipient, error) {
+	r := Recipient{Address: strings.TrimSpace(address), Memo: memo, Label: label}
+	// 数量留空表示使用默认数量，显式给出的数量必须为正数，0 不会被当作未指定
+	if amount != "" {
+		v, err := strconv.ParseFloat(amount, 64)
+		if err != nil || v <= 0 {
+			return Recipient{}, fmt.Errorf("%w: %s", errInvalidAmount, amount)
+		}
+		r.Amount = v
+	}
+	if err := checkRecipient(&r); err != nil {
+		return Recipient{}, err
+	}
+	return r, nil
+}
+
+func checkRecipient(r *Recipient) error {
+	if r.Address == "" {
+		return errEmptyAddress
+	}
+	if _, err := solana.PublicKeyFromBase58(r.Address); err != nil {
+		return fmt.Errorf("无效地址 %s: %w", r.Address, err)
+	}
+	if r.Amount < 0 || math.IsNaN(r.Amount) || math.IsInf(r.Amount, 0) {
+		return fmt.Errorf("%w: %v", errInvalidAmount, r.Amount)
+	}
+	return nil
+}
+
+// isHeader 判断记录是否为表头，表头中必须包含 address 列
+func isHeader(record []string) bool {
+	for i := range record {
+		if strings.EqualFold(strings.TrimSpace(record[i]), "address") {
+			return true
+		}
+	}
+	return false
+}
+
+func isBlankRecord(record []string) bool {
+	for i := range record {
+		if strings.TrimSpace(record[i]) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,175 @@
+package forward
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAddress1 = "7mXrxq4A6LJKsdHDCvj4PCdF3tvQs8rdUKFWLdEicxXW"
+	testAddress2 = "9W8kkYe6wE9VWJRiwqQMwC3536mMei7KfG7oWiSNbNBb"
+)
+
+func TestParseRecipientsCSV(t *testing.T) {
+	t.Parallel()
+
+	recipients, err := ParseRecipientsCSV(strings.NewReader(testAddress1 + "\n\n" + testAddress2 + "\n"))
+	require.NoError(t, err, "ParseRecipientsCSV must not error on bare addresses")
+	require.Len(t, recipients, 2)
+	assert.Equal(t, testAddress1, recipients[0].Address)
+	assert.Zero(t, recipients[0].Amount, "amount should be left for the config default")
+
+	in := "address,amount,memo,label\n" +
+		"# comment line\n" +
+		testAddress1 + ",1.5,invoice-1,alice\n" +
+		testAddress2 + ",0.25\n"
+	recipients, err = ParseRecipientsCSV(strings.NewReader(in))
+	require.NoError(t, err, "ParseRecipientsCSV must not error with a header")
+	require.Len(t, recipients, 2)
+	assert.Equal(t, Recipient{Address: testAddress1, Amount: 1.5, Memo: "invoice-1", Label: "alice"}, recipients[0])
+	assert.Equal(t, 0.25, recipients[1].Amount)
+
+	recipients, err = ParseRecipientsCSV(strings.NewReader("label,address,amount\nbob," + testAddress2 + ",3\n"))
+	require.NoError(t, err, "ParseRecipientsCSV must not error with reordered columns")
+	require.Len(t, recipients, 1)
+	assert.Equal(t, Recipient{Address: testAddress2, Amount: 3, Label: "bob"}, recipients[0])
+
+	_, err = ParseRecipientsCSV(strings.NewReader("address,wallet\n"))
+	assert.ErrorIs(t, err, errUnknownColumn)
+
+	in = testAddress1 + ",1\n" +
+		"notbase58,1\n" +
+		testAddress2 + ",abc\n" +
+		testAddress2 + ",-1\n" +
+		testAddress2 + ",1,memo,label,extra\n" +
+		testAddress2 + ",0\n"
+	_, err = ParseRecipientsCSV(strings.NewReader(in))
+	require.ErrorIs(t, err, ErrMalformedRecipients)
+	var recipientsErr *RecipientsError
+	require.True(t, errors.As(err, &recipientsErr), "error must be a *RecipientsError")
+	require.Len(t, recipientsErr.Rows, 5)
+	assert.Equal(t, 2, recipientsErr.Rows[0].Line)
+	assert.ErrorIs(t, recipientsErr.Rows[1].Err, errInvalidAmount)
+	assert.ErrorIs(t, recipientsErr.Rows[2].Err, errInvalidAmount)
+	assert.ErrorIs(t, recipientsErr.Rows[3].Err, errTooManyColumns)
+	assert.ErrorIs(t, recipientsErr.Rows[4].Err, errInvalidAmount, "an explicit zero amount must not fall back to the default")
+
+	// 数量列留空时使用默认数量
+	recipients, err = ParseRecipientsCSV(strings.NewReader("address,amount,memo\n" + testAddress1 + ",,m\n"))
+	require.NoError(t, err, "ParseRecipientsCSV must not error on an empty amount")
+	require.Len(t, recipients, 1)
+	assert.Zero(t, recipients[0].Amount)
+}
+
+func TestParseRecipientsJSONL(t *testing.T) {
+	t.Parallel()
+
+	in := `{"address":"` + testAddress1 + `","amount":2.5,"memo":"m","label":"l"}` + "\n" +
+		"\n" +
+		`{"address":"` + testAddress2 + `","amount":"0.1"}` + "\n" +
+		`{"address":"` + testAddress2 + `"}` + "\n"
+	recipients, err := ParseRecipientsJSONL(strings.NewReader(in))
+	require.NoError(t, err, "ParseRecipientsJSONL must not error")
+	require.Len(t, recipients, 3)
+	assert.Equal(t, Recipient{Address: testAddress1, Amount: 2.5, Memo: "m", Label: "l"}, recipients[0])
+	assert.Equal(t, 0.1, recipients[1].Amount)
+	assert.Zero(t, recipients[2].Amount)
+
+	in = `{"address":"` + testAddress1 + `","amount":1}` + "\n" +
+		`{"address":` + "\n" +
+		`{"amount":1}` + "\n" +
+		`{"address":"` + testAddress2 + `","amount":0}` + "\n"
+	_, err = ParseRecipientsJSONL(strings.NewReader(in))
+	var recipientsErr *RecipientsError
+	require.True(t, errors.As(err, &recipientsErr), "error must be a *RecipientsError")
+	require.Len(t, recipientsErr.Rows, 3)
+	assert.Equal(t, 2, recipientsErr.Rows[0].Line)
+	assert.ErrorIs(t, recipientsErr.Rows[1].Err, errEmptyAddress)
+	assert.ErrorIs(t, recipientsErr.Rows[2].Err, errInvalidAmount, "an explicit zero amount must not fall back to the default")
+}
+
+func TestReadRecipientsFromFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	jsonl := filepath.Join(dir, "payout.jsonl")
+	require.NoError(t, os.WriteFile(jsonl, []byte(`{"address":"`+testAddress1+`","amount":1}`), 0o600))
+	recipients, err := ReadRecipientsFromFile(jsonl)
+	require.NoError(t, err, "ReadRecipientsFromFile must not error on JSONL")
+	assert.Len(t, recipients, 1)
+
+	csvFile := filepath.Join(dir, "payout.csv")
+	require.NoError(t, os.WriteFile(csvFile, []byte(testAddress1+",1\n"+testAddress2+",2\n"), 0o600))
+	recipients, err = ReadRecipientsFromFile(csvFile)
+	require.NoError(t, err, "ReadRecipientsFromFile must not error on CSV")
+	assert.Len(t, recipients, 2)
+
+	_, err = ReadRecipientsFromFile(filepath.Join(dir, "missing.csv"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCheckRecipients(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, CheckRecipients([]Recipient{{Address: testAddress1, Amount: 1}}))
+
+	err := CheckRecipients([]Recipient{{Address: testAddress1}, {Address: ""}, {Address: testAddress2, Amount: -2}})
+	var recipientsErr *RecipientsError
+	require.True(t, errors.As(err, &recipientsErr), "error must be a *RecipientsError")
+	require.Len(t, recipientsErr.Rows, 2)
+	assert.Equal(t, 2, recipientsErr.Rows[0].Line)
+	assert.Equal(t, 3, recipientsErr.Rows[1].Line)
+}
+
+func TestResolveRecipients(t *testing.T) {
+	t.Parallel()
+	recipients := ResolveRecipients(nil, []string{testAddress1, testAddress2}, 0.5)
+	require.Len(t, recipients, 2)
+	assert.Equal(t, 0.5, recipients[1].Amount)
+
+	in := []Recipient{{Address: testAddress1, Amount: 2}, {Address: testAddress2}}
+	recipients = ResolveRecipients(in, []string{testAddress1}, 0.5)
+	require.Len(t, recipients, 2)
+	assert.Equal(t, 2.0, recipients[0].Amount)
+	assert.Equal(t, 0.5, recipients[1].Amount)
+	assert.Zero(t, in[1].Amount, "ResolveRecipients must not mutate the caller's slice")
+}
+
+func TestToBaseUnits(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		amount   float64
+		decimals uint8
+		want     uint64
+	}{
+		{0.1, 9, 100000000},
+		{0.01, 9, 10000000},
+		{0.123456, 6, 123456},
+		{100, 0, 100},
+		{18446744073, 9, 18446744073000000000},
+	} {
+		units, err := ToBaseUnits(tc.amount, tc.decimals)
+		require.NoError(t, err, "ToBaseUnits must not error")
+		assert.Equal(t, tc.want, units)
+	}
+
+	_, err := ToBaseUnits(18446744074, 9)
+	assert.ErrorIs(t, err, errAmountOverflow, "amounts above the uint64 range must be rejected")
+	_, err = ToBaseUnits(1e30, 0)
+	assert.ErrorIs(t, err, errAmountOverflow)
+	_, err = ToBaseUnits(-1, 9)
+	assert.ErrorIs(t, err, errInvalidAmount)
+
+	// 指定最小单位数量时不经过浮点数换算
+	units, err := recipientUnits(&Recipient{Amount: FromBaseUnits(9007199254740993, 6), Units: 9007199254740993}, 6)
+	require.NoError(t, err, "recipientUnits must not error")
+	assert.Equal(t, uint64(9007199254740993), units, "Units must take precedence over Amount")
+	units, err = recipientUnits(&Recipient{Amount: 0.5}, 9)
+	require.NoError(t, err, "recipientUnits must not error")
+	assert.Equal(t, uint64(500000000), units)
+}
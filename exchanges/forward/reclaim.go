@@ -0,0 +1,222 @@
+package forward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"gocryptotrader/log"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	// tokenStateOffset 代币账户数据中状态字段的偏移
+	tokenStateOffset = 108
+	// tokenStateInitialized 代币账户已初始化且未冻结的状态值，冻结的账户无法关闭
+	tokenStateInitialized = 1
+	// tokenCloseAuthorityOffset 代币账户数据中关闭权限字段（COption<Pubkey>）的偏移
+	tokenCloseAuthorityOffset = 129
+)
+
+var errNoReclaimSources = errors.New("未指定需要回收租金的账户")
+
+// reclaimable 一个可以关闭的空代币账户
+type reclaimable struct {
+	account   solana.PublicKey
+	mint      solana.PublicKey
+	programID solana.PublicKey // 代币账户所属的代币程序
+	lamports  uint64           // 账户持有的租金
+}
+
+// Reclaim 关闭多个钱包名下余额为 0 的代币账户，租金退回各自的钱包
+// 使用 getTokenAccountsByOwner 分别查询 SPL Token 和 Token-2022 账户；每个钱包独立构建交易并签名，
+// 某个钱包失败时记录在其结果中，不影响其他钱包
+func (m *Manager) Reclaim(ctx context.Context, req *ReclaimRequest) (*ReclaimResult, error) {
+	if req.Signer == nil {
+		return nil, errNoSigner
+	}
+	if len(req.Sources) == 0 {
+		return nil, errNoReclaimSources
+	}
+	exclude := make(map[solana.PublicKey]struct{}, len(req.ExcludeMints))
+	for _, s := range req.ExcludeMints {
+		mint, err := solana.PublicKeyFromBase58(s)
+		if err != nil {
+			return nil, fmt.Errorf("无效的代币铸币地址 %s: %w", s, err)
+		}
+		exclude[mint] = struct{}{}
+	}
+	cfg := req.Config
+	if cfg.UseLookupTables {
+		// 每个钱包只有少量交易，回收租金不使用地址查找表
+		c := *cfg
+		c.UseLookupTables = false
+		cfg = &c
+	}
+
+	// 从 RPC 节点池获取客户端
+	rpcClient, err := m.client()
+	if err != nil {
+		return nil, err
+	}
+
+	// 逐个钱包查询代币账户并构建交易
+	result := &ReclaimResult{Sources: make([]*ReclaimSource, len(req.Sources))}
+	keys := make([]accountSigner, len(req.Sources))
+	for i, address := range req.Sources {
+		src := &ReclaimSource{Address: address}
+		result.Sources[i] = src
+		keys[i], src.Err = m.planReclaim(ctx, rpcClient, req, cfg, exclude, src)
+		if src.Err != nil {
+			log.Warnf(log.Global, "账户 %s 无法回收租金: %v", address, src.Err)
+		}
+	}
+
+	// 各钱包分别签名，并发发送或模拟
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, max(cfg.ConcurrentTxs, 1))
+	for i, src := range result.Sources {
+		if src.Err != nil || len(src.Batches) == 0 {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			if req.DryRun {
+				src.Simulation, src.Err = m.simulate(ctx, rpcClient, keys[i], src.Batches, cfg)
+				return
+			}
+			src.Err = m.execute(ctx, rpcClient, keys[i], src.Batches, cfg, nil)
+			src.settle()
+		}()
+	}
+	wg.Wait()
+	return result, ctx.Err()
+}
+
+// planReclaim 查询钱包名下可以关闭的代币账户并构建交易，返回钱包的签名者
+func (m *Manager) planReclaim(ctx context.Context, client *rpc.Client, req *ReclaimRequest, cfg *Config, exclude map[solana.PublicKey]struct{}, src *ReclaimSource) (accountSigner, error) {
+	signer, err := newAccountSigner(req.Signer, src.Address)
+	if err != nil {
+		return signer, err
+	}
+	owner := signer.PublicKey()
+
+	var accounts []reclaimable
+	for _, programID := range []solana.PublicKey{solana.TokenProgramID, solana.Token2022ProgramID} {
+		out, err := client.GetTokenAccountsByOwner(ctx, owner,
+			&rpc.GetTokenAccountsConfig{ProgramId: programID.ToPointer()},
+			&rpc.GetTokenAccountsOpts{Encoding: solana.EncodingBase64, Commitment: rpc.CommitmentConfirmed})
+		if err != nil {
+			return signer, fmt.Errorf("查询 %s 代币账户失败: %w", programID, err)
+		}
+		for _, ta := range out.Value {
+			if ta == nil {
+				continue
+			}
+			mint, ok := closableTokenAccount(ta.Account.Data.GetBinary(), owner)
+			if !ok {
+				continue
+			}
+			if _, skip := exclude[mint]; skip {
+				continue
+			}
+			accounts = append(accounts, reclaimable{
+				account:   ta.Pubkey,
+				mint:      mint,
+				programID: programID,
+				lamports:  ta.Account.Lamports,
+			})
+		}
+	}
+	if len(accounts) == 0 {
+		return signer, nil
+	}
+
+	balance, err := client.GetBalance(ctx, owner, rpc.CommitmentFinalized)
+	if err != nil {
+		return signer, fmt.Errorf("获取账户余额失败: %w", err)
+	}
+	price := cfg.computeUnitPrice(ctx, client, owner)
+	return signer, buildReclaim(src, cfg, owner, accounts, balance.Value, price)
+}
+
+// closableTokenAccount 判断代币账户能否由 owner 关闭：余额为 0、未冻结、
+// 没有已扣留的转账手续费，且未设置其他关闭权限；返回账户的铸币
+func closableTokenAccount(data []byte, owner solana.PublicKey) (solana.PublicKey, bool) {
+	amount, withheld, err := parseTokenAccount(data)
+	if err != nil || amount != 0 || withheld != 0 || len(data) < tokenCloseAuthorityOffset+4+32 {
+		return solana.PublicKey{}, false
+	}
+	if !solana.PublicKeyFromBytes(data[32:64]).Equals(owner) || data[tokenStateOffset] != tokenStateInitialized {
+		return solana.PublicKey{}, false
+	}
+	if data[tokenCloseAuthorityOffset] == 1 {
+		closeAuthority := solana.PublicKeyFromBytes(data[tokenCloseAuthorityOffset+4 : tokenCloseAuthorityOffset+4+32])
+		if !closeAuthority.Equals(owner) {
+			return solana.PublicKey{}, false
+		}
+	}
+	return solana.PublicKeyFromBytes(data[:32]), true
+}
+
+// buildReclaim 将 CloseAccount 指令装入批次，钱包余额必须足以支付交易费和优先费
+func buildReclaim(src *ReclaimSource, cfg *Config, owner solana.PublicKey, accounts []reclaimable, balance, price uint64) error {
+	var (
+		batch    *Batch
+		capacity *txCapacity
+	)
+	for _, a := range accounts {
+		if batch == nil || !capacity.reserve(reclaimLayout) {
+			batch = &Batch{Index: len(src.Batches), Status: StatusPending}
+			src.Batches = append(src.Batches, batch)
+			capacity = cfg.newTxCapacity()
+			capacity.reserve(reclaimLayout)
+		}
+		mint := &mintInfo{address: a.mint, programID: a.programID}
+		ix, err := mint.closeAccountInstruction(a.account, owner, owner)
+		if err != nil {
+			return fmt.Errorf("构建关闭代币账户指令失败: %w", err)
+		}
+		batch.add(Recipient{Address: a.account.String(), Amount: FromBaseUnits(a.lamports, 9), Label: a.mint.String()},
+			recipientCost{computeUnits: closeAccountUnits}, ix)
+		src.Accounts = append(src.Accounts, ClosedAccount{
+			Account:  a.account.String(),
+			Mint:     a.mint.String(),
+			Lamports: a.lamports,
+			Batch:    batch.Index,
+		})
+		src.Lamports += a.lamports
+	}
+
+	p := &Preflight{LamportBalance: balance}
+	for _, b := range src.Batches {
+		p.Fees += lamportsPerSignature
+		p.PriorityFees += priorityFee(price, cfg.computeUnitLimit(b))
+	}
+	src.Fees = p.Fees + p.PriorityFees
+	if !p.Sufficient() {
+		return &InsufficientFundsError{Preflight: p}
+	}
+	return nil
+}
+
+// settle 发送后只统计所在批次已确认的账户回收的租金
+func (s *ReclaimSource) settle() {
+	s.Lamports = 0
+	for i := range s.Accounts {
+		a := &s.Accounts[i]
+		a.Closed = s.Batches[a.Batch].Status == StatusConfirmed
+		if a.Closed {
+			s.Lamports += a.Lamports
+		}
+	}
+}
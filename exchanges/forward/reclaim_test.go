@@ -0,0 +1,111 @@
+package forward
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenAccountData 构造代币账户数据
+func tokenAccountData(mint, owner solana.PublicKey, amount uint64) []byte {
+	data := make([]byte, tokenAccountSize)
+	copy(data, mint[:])
+	copy(data[32:], owner[:])
+	binary.LittleEndian.PutUint64(data[tokenAmountOffset:], amount)
+	data[tokenStateOffset] = tokenStateInitialized
+	return data
+}
+
+func TestClosableTokenAccount(t *testing.T) {
+	t.Parallel()
+	owner := solana.NewWallet().PublicKey()
+	mint := solana.NewWallet().PublicKey()
+
+	got, ok := closableTokenAccount(tokenAccountData(mint, owner, 0), owner)
+	require.True(t, ok, "an empty account must be closable")
+	assert.Equal(t, mint, got)
+
+	_, ok = closableTokenAccount(tokenAccountData(mint, owner, 1), owner)
+	assert.False(t, ok, "an account with a balance must not be closed")
+
+	_, ok = closableTokenAccount(tokenAccountData(mint, solana.NewWallet().PublicKey(), 0), owner)
+	assert.False(t, ok, "an account of another owner must not be closed")
+
+	frozen := tokenAccountData(mint, owner, 0)
+	frozen[tokenStateOffset] = 2
+	_, ok = closableTokenAccount(frozen, owner)
+	assert.False(t, ok, "a frozen account cannot be closed")
+
+	other := solana.NewWallet().PublicKey()
+	delegated := tokenAccountData(mint, owner, 0)
+	delegated[tokenCloseAuthorityOffset] = 1
+	copy(delegated[tokenCloseAuthorityOffset+4:], other[:])
+	_, ok = closableTokenAccount(delegated, owner)
+	assert.False(t, ok, "an account with another close authority cannot be closed by the owner")
+	copy(delegated[tokenCloseAuthorityOffset+4:], owner[:])
+	_, ok = closableTokenAccount(delegated, owner)
+	assert.True(t, ok, "the owner as close authority may close the account")
+
+	// Token-2022 账户中仍有已扣留的转账手续费
+	withheld := append(tokenAccountData(mint, owner, 0), 2)
+	ext := make([]byte, 4+8)
+	binary.LittleEndian.PutUint16(ext, extensionTransferFeeAmount)
+	binary.LittleEndian.PutUint16(ext[2:], 8)
+	binary.LittleEndian.PutUint64(ext[4:], 5)
+	withheld = append(withheld, ext...)
+	_, ok = closableTokenAccount(withheld, owner)
+	assert.False(t, ok, "an account holding withheld fees cannot be closed")
+}
+
+func TestBuildReclaim(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	cfg.MaxInstructionsPerTx = 100
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	owner := key.PublicKey()
+
+	accounts := make([]reclaimable, 60)
+	for i := range accounts {
+		accounts[i] = reclaimable{
+			account:   solana.NewWallet().PublicKey(),
+			mint:      solana.NewWallet().PublicKey(),
+			programID: solana.TokenProgramID,
+			lamports:  2_039_280,
+		}
+		if i%2 == 1 {
+			accounts[i].programID = solana.Token2022ProgramID
+		}
+	}
+
+	src := &ReclaimSource{Address: owner.String()}
+	require.NoError(t, buildReclaim(src, cfg, owner, accounts, 1_000_000, 1000))
+	require.Greater(t, len(src.Batches), 1, "many accounts must be split across transactions")
+	for _, b := range src.Batches {
+		assert.LessOrEqual(t, transactionSize(t, cfg, b, key), maxTransactionSize, "batch %d must fit in a transaction", b.Index)
+	}
+	require.Len(t, src.Accounts, 60)
+	assert.Equal(t, uint64(60*2_039_280), src.Lamports)
+	assert.NotZero(t, src.Fees)
+
+	// 发送后只统计已确认的批次
+	src.Batches[0].Status = StatusConfirmed
+	src.settle()
+	var want uint64
+	for _, a := range src.Accounts {
+		if a.Batch == 0 {
+			assert.True(t, a.Closed)
+			want += a.Lamports
+		} else {
+			assert.False(t, a.Closed)
+		}
+	}
+	assert.Equal(t, want, src.Lamports)
+
+	src = &ReclaimSource{Address: owner.String()}
+	err = buildReclaim(src, cfg, owner, accounts, 0, 0)
+	assert.ErrorIs(t, err, ErrInsufficientFunds, "fees must be covered by the wallet balance")
+}
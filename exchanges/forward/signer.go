@@ -0,0 +1,62 @@
+package forward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	errNoSigner         = errors.New("未提供签名方")
+	errInvalidSignature = errors.New("签名方返回的签名无效")
+)
+
+// Signer 对交易消息签名。私钥只保存在签名方内部，forward 只传递账户公钥和待签名的消息，
+// 实现可以是进程内的密钥库，也可以是通过本地套接字访问、带有独立策略检查的签名服务
+type Signer interface {
+	// Sign 使用 account 的私钥对消息签名
+	Sign(ctx context.Context, account solana.PublicKey, message []byte) (solana.Signature, error)
+}
+
+// accountSigner 代表一个发送账户，交易由签名方使用该账户的私钥签名
+type accountSigner struct {
+	signer Signer
+	key    solana.PublicKey
+}
+
+// newAccountSigner 返回使用 signer 为 address 签名的发送账户
+func newAccountSigner(signer Signer, address string) (accountSigner, error) {
+	if signer == nil {
+		return accountSigner{}, errNoSigner
+	}
+	key, err := solana.PublicKeyFromBase58(address)
+	if err != nil {
+		return accountSigner{}, fmt.Errorf("无效的账户地址 %s: %w", address, err)
+	}
+	return accountSigner{signer: signer, key: key}, nil
+}
+
+// PublicKey 返回发送账户的公钥
+func (a accountSigner) PublicKey() solana.PublicKey {
+	return a.key
+}
+
+// signTransaction 对交易签名，forward 构建的交易只有发送账户一个签名者。
+// 返回的签名在使用前校验，签名方为其他账户签名或篡改消息时返回错误
+func (a accountSigner) signTransaction(ctx context.Context, tx *solana.Transaction) error {
+	message, err := tx.Message.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("编码交易消息失败: %w", err)
+	}
+	signature, err := a.signer.Sign(ctx, a.key, message)
+	if err != nil {
+		return err
+	}
+	if !signature.Verify(a.key, message) {
+		return fmt.Errorf("%w: %s", errInvalidSignature, a.key)
+	}
+	tx.Signatures = []solana.Signature{signature}
+	return nil
+}
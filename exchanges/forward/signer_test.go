@@ -0,0 +1,51 @@
+package forward
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keySigner 使用内存中的私钥签名
+type keySigner map[solana.PublicKey]solana.PrivateKey
+
+func (k keySigner) Sign(_ context.Context, account solana.PublicKey, message []byte) (solana.Signature, error) {
+	key, ok := k[account]
+	if !ok {
+		return solana.Signature{}, errNoSigner
+	}
+	return key.Sign(message)
+}
+
+// testSigner 返回使用 key 签名的发送账户
+func testSigner(key solana.PrivateKey) accountSigner {
+	return accountSigner{signer: keySigner{key.PublicKey(): key}, key: key.PublicKey()}
+}
+
+func TestSignTransaction(t *testing.T) {
+	t.Parallel()
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	b := instructionBatch(0, 0, system.NewTransferInstruction(1, key.PublicKey(), solana.NewWallet().PublicKey()).Build())
+
+	tx, err := buildTransaction(context.Background(), b, nil, solana.Hash{}, testSigner(key))
+	require.NoError(t, err)
+	require.Len(t, tx.Signatures, 1)
+	assert.NoError(t, tx.VerifySignatures())
+
+	// 签名方使用其他账户的私钥签名时必须被拒绝
+	other, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	wrong := accountSigner{signer: keySigner{key.PublicKey(): other}, key: key.PublicKey()}
+	_, err = buildTransaction(context.Background(), b, nil, solana.Hash{}, wrong)
+	assert.ErrorIs(t, err, errInvalidSignature)
+
+	_, err = newAccountSigner(nil, key.PublicKey().String())
+	assert.ErrorIs(t, err, errNoSigner)
+	_, err = newAccountSigner(keySigner{}, "not-an-address")
+	assert.Error(t, err)
+}
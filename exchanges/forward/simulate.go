@@ -0,0 +1,106 @@
+package forward
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"gocryptotrader/log"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	// tokenAccountSize SPL Token 代币账户的数据长度，用于估算创建 ATA 的租金
+	tokenAccountSize = 165
+	// lamportsPerSignature 无法查询费用时按每个签名估算的基础费用
+	lamportsPerSignature = 5000
+)
+
+// simulate 按真实运行的方式构建并签名每个批次，调用 simulateTransaction 估算费用、租金和计算单元
+// 不会发送任何交易，批次状态保持 pending
+func (m *Manager) simulate(ctx context.Context, client *rpc.Client, signer accountSigner, batches []*Batch, cfg *Config) (*SimulationReport, error) {
+	report := &SimulationReport{Transactions: len(batches)}
+	if len(batches) == 0 {
+		return report, nil
+	}
+
+	recent, err := client.GetLatestBlockhash(ctx, rpc.CommitmentFinalized)
+	if err != nil {
+		return nil, fmt.Errorf("获取最新 blockhash 失败: %w", err)
+	}
+
+	report.ComputeUnitPrice = cfg.computeUnitPrice(ctx, client, signer.PublicKey())
+
+	// 不同代币程序创建的账户长度不同，按账户长度查询租金
+	rent, err := rentExemptions(ctx, client, batches)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range batches {
+		sim := simulateBatch(ctx, client, signer, b, cfg.budgetInstructions(b, report.ComputeUnitPrice), recent.Value.Blockhash)
+		sim.EstimatedRent = rent[b.accountSize] * uint64(sim.ATACreations)
+		sim.ComputeUnitLimit = cfg.computeUnitLimit(b)
+
+		report.Instructions += sim.Instructions
+		report.ATACreations += sim.ATACreations
+		report.EstimatedFee += sim.EstimatedFee
+		report.EstimatedRent += sim.EstimatedRent
+		report.ComputeUnits += sim.ComputeUnits
+		if sim.Err != nil {
+			report.Failed++
+			log.Warnf(log.Global, "批次 %d 模拟失败: %v", b.Index, sim.Err)
+		}
+		report.Batches = append(report.Batches, sim)
+	}
+	return report, nil
+}
+
+// simulateBatch 构建并模拟单个批次
+func simulateBatch(ctx context.Context, client *rpc.Client, signer accountSigner, b *Batch, budget []solana.Instruction, blockhash solana.Hash) BatchSimulation {
+	sim := BatchSimulation{
+		Index:        b.Index,
+		Recipients:   b.Recipients,
+		Instructions: len(budget) + len(b.instructions),
+		ATACreations: b.ataCreations,
+	}
+
+	tx, err := buildTransaction(ctx, b, budget, blockhash, signer)
+	if err != nil {
+		sim.Err = err
+		return sim
+	}
+
+	sim.EstimatedFee = uint64(len(tx.Signatures)) * lamportsPerSignature
+	if msg, err := tx.Message.MarshalBinary(); err == nil {
+		fee, err := client.GetFeeForMessage(ctx, base64.StdEncoding.EncodeToString(msg), rpc.CommitmentFinalized)
+		if err == nil && fee.Value != nil {
+			sim.EstimatedFee = *fee.Value
+		}
+	}
+
+	if len(b.lookupTables) > 0 {
+		// 地址查找表在发送前才创建，无法模拟执行，计算单元使用估算值
+		sim.ComputeUnits = uint64(b.computeUnits)
+		return sim
+	}
+
+	out, err := client.SimulateTransactionWithOpts(ctx, tx, &rpc.SimulateTransactionOpts{
+		SigVerify:  true,
+		Commitment: rpc.CommitmentFinalized,
+	})
+	if err != nil {
+		sim.Err = fmt.Errorf("模拟交易失败: %w", err)
+		return sim
+	}
+	if out.Value.UnitsConsumed != nil {
+		sim.ComputeUnits = *out.Value.UnitsConsumed
+	}
+	sim.Logs = out.Value.Logs
+	if out.Value.Err != nil {
+		sim.Err = fmt.Errorf("模拟执行失败: %v", out.Value.Err)
+	}
+	return sim
+}
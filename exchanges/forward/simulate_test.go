@@ -0,0 +1,146 @@
+package forward
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transferBatch 返回从 from 向 n 个新地址转账的批次
+func transferBatch(index, n int, from solana.PublicKey) *Batch {
+	b := &Batch{Index: index, Status: StatusPending}
+	for range n {
+		to := solana.NewWallet().PublicKey()
+		b.add(Recipient{Address: to.String()}, recipientCost{computeUnits: systemTransferUnits, lamports: 1},
+			system.NewTransferInstruction(1, from, to).Build())
+	}
+	return b
+}
+
+// blockhashHandler 模拟 getLatestBlockhash
+func blockhashHandler(blockhash solana.Hash, lastValid uint64) rpcHandler {
+	return func([]json.RawMessage) (any, error) {
+		return rpcValue(map[string]any{"blockhash": blockhash.String(), "lastValidBlockHeight": lastValid}), nil
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	t.Parallel()
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	cfg := DefaultConfig()
+	cfg.PriorityFeePercentile = 0
+	cfg.ComputeUnitPrice = 1000
+
+	token := transferBatch(1, 2, key.PublicKey())
+	token.ataCreations, token.accountSize = 2, tokenAccountSize
+	batches := []*Batch{transferBatch(0, 3, key.PublicKey()), token}
+
+	var fees, simulations int32
+	server := rpcServer(t, map[string]rpcHandler{
+		"getLatestBlockhash": blockhashHandler(solana.Hash{1}, 200),
+		"getMinimumBalanceForRentExemption": func(params []json.RawMessage) (any, error) {
+			var size uint64
+			if err := json.Unmarshal(params[0], &size); err != nil || size != tokenAccountSize {
+				return nil, errors.New("unexpected account size")
+			}
+			return 2039280, nil
+		},
+		"getFeeForMessage": func([]json.RawMessage) (any, error) {
+			if atomic.AddInt32(&fees, 1) == 1 {
+				return rpcValue(7000), nil
+			}
+			// 节点无法计算费用时按签名数量估算
+			return rpcValue(nil), nil
+		},
+		"simulateTransaction": func([]json.RawMessage) (any, error) {
+			if atomic.AddInt32(&simulations, 1) == 1 {
+				return rpcValue(map[string]any{"err": nil, "logs": []string{"ok"}, "unitsConsumed": 450}), nil
+			}
+			return rpcValue(map[string]any{"err": map[string]any{"InstructionError": []any{2, "InsufficientFunds"}}, "logs": []string{}, "unitsConsumed": 300}), nil
+		},
+	})
+	defer server.Close()
+
+	report, err := (&Manager{}).simulate(context.Background(), rpc.New(server.URL), testSigner(key), batches, cfg)
+	require.NoError(t, err, "simulate must not error")
+	assert.Equal(t, 2, report.Transactions)
+	assert.Equal(t, uint64(1000), report.ComputeUnitPrice, "the configured unit price must be reported")
+	assert.Equal(t, 2+3+2+2, report.Instructions, "budget instructions must be counted")
+	assert.Equal(t, 2, report.ATACreations)
+	assert.Equal(t, uint64(7000+lamportsPerSignature), report.EstimatedFee)
+	assert.Equal(t, uint64(2*2039280), report.EstimatedRent)
+	assert.Equal(t, uint64(750), report.ComputeUnits)
+	assert.Equal(t, 1, report.Failed)
+
+	require.Len(t, report.Batches, 2)
+	assert.NoError(t, report.Batches[0].Err)
+	assert.Equal(t, []string{"ok"}, report.Batches[0].Logs)
+	assert.Equal(t, cfg.computeUnitLimit(batches[0]), report.Batches[0].ComputeUnitLimit)
+	assert.ErrorContains(t, report.Batches[1].Err, "模拟执行失败")
+	for _, b := range batches {
+		assert.Equal(t, StatusPending, b.Status, "simulation must not send any batch")
+		assert.Empty(t, b.Signature)
+	}
+
+	report, err = (&Manager{}).simulate(context.Background(), rpc.New(server.URL), testSigner(key), nil, cfg)
+	require.NoError(t, err, "simulate must not error without batches")
+	assert.Zero(t, report.Transactions)
+}
+
+func TestSimulateErrors(t *testing.T) {
+	t.Parallel()
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	cfg := DefaultConfig()
+	cfg.PriorityFeePercentile = 0
+
+	var simulations int32
+	server := rpcServer(t, map[string]rpcHandler{
+		"getLatestBlockhash": blockhashHandler(solana.Hash{1}, 200),
+		"getFeeForMessage": func([]json.RawMessage) (any, error) {
+			return nil, errors.New("fee unavailable")
+		},
+		"simulateTransaction": func([]json.RawMessage) (any, error) {
+			atomic.AddInt32(&simulations, 1)
+			return nil, errors.New("node is behind")
+		},
+	})
+	defer server.Close()
+	client := rpc.New(server.URL)
+
+	// 模拟请求失败时记录在批次结果中，不影响其他批次
+	report, err := (&Manager{}).simulate(context.Background(), client, testSigner(key), []*Batch{transferBatch(0, 1, key.PublicKey())}, cfg)
+	require.NoError(t, err, "a failed simulation must be reported on the batch")
+	assert.Equal(t, 1, report.Failed)
+	assert.ErrorContains(t, report.Batches[0].Err, "模拟交易失败")
+	assert.Equal(t, uint64(lamportsPerSignature), report.EstimatedFee, "the fee must fall back to the signature estimate")
+
+	// 引用地址查找表的批次不模拟执行，计算单元使用估算值
+	b := transferBatch(0, 2, key.PublicKey())
+	b.lookupTables = map[solana.PublicKey]solana.PublicKeySlice{solana.NewWallet().PublicKey(): {solana.SystemProgramID}}
+	sim := simulateBatch(context.Background(), client, testSigner(key), b, nil, solana.Hash{1})
+	assert.NoError(t, sim.Err)
+	assert.Equal(t, uint64(b.computeUnits), sim.ComputeUnits)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&simulations), "lookup table batches must not be simulated")
+
+	// 签名失败的批次不会请求节点
+	unknown := accountSigner{signer: keySigner{}, key: key.PublicKey()}
+	sim = simulateBatch(context.Background(), client, unknown, transferBatch(0, 1, key.PublicKey()), nil, solana.Hash{1})
+	assert.ErrorIs(t, sim.Err, errNoSigner, "a batch that cannot be signed must fail")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&simulations))
+
+	// 无法获取 blockhash 时不模拟任何批次
+	failing := rpcServer(t, map[string]rpcHandler{})
+	defer failing.Close()
+	_, err = (&Manager{}).simulate(context.Background(), rpc.New(failing.URL), testSigner(key), []*Batch{transferBatch(0, 1, key.PublicKey())}, cfg)
+	assert.ErrorContains(t, err, "blockhash")
+}
@@ -0,0 +1,364 @@
+package forward
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"gocryptotrader/log"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// extensionTransferFeeAmount Token-2022 代币账户中记录已扣留转账手续费的扩展
+const extensionTransferFeeAmount = 2
+
+var (
+	errNothingToSweep        = errors.New("未指定需要归集的 SOL 或代币")
+	errSourceIsDestination   = errors.New("源账户与归集目标地址相同")
+	errMalformedTokenAccount = errors.New("代币账户数据格式错误")
+)
+
+// sweepTarget 归集目标钱包及每个代币对应的目标代币账户
+type sweepTarget struct {
+	wallet   solana.PublicKey
+	mints    []*mintInfo
+	accounts []solana.PublicKey // 与 mints 一一对应的目标代币账户
+	existing *accountCache      // 目标代币账户是否存在
+	rent     map[uint64]uint64  // 按账户长度缓存的免租金额
+	reserve  uint64             // 归集 SOL 后源账户保留的 lamports
+}
+
+// sweepItem 源账户中一个待归集的代币账户
+type sweepItem struct {
+	mint        *mintInfo
+	account     solana.PublicKey // 源代币账户
+	destination solana.PublicKey // 目标代币账户
+	amount      uint64           // 代币余额（原始数量）
+	lamports    uint64           // 源代币账户持有的租金
+	create      bool             // 是否需要创建目标代币账户
+	close       bool             // 是否关闭源代币账户
+}
+
+// Sweep 将多个源账户中的 SOL 和代币归集到同一个目标地址
+// 每个源账户独立构建交易并签名，某个源账户失败时记录在其结果中，不影响其他源账户
+func (m *Manager) Sweep(ctx context.Context, req *SweepRequest) (*SweepResult, error) {
+	if req.Signer == nil {
+		return nil, errNoSigner
+	}
+	if !req.SweepSOL && len(req.TokenMints) == 0 {
+		return nil, errNothingToSweep
+	}
+	destination, err := solana.PublicKeyFromBase58(req.Destination)
+	if err != nil {
+		return nil, fmt.Errorf("无效的归集目标地址: %w", err)
+	}
+	if req.Config.UseLookupTables {
+		// 每个源账户只有少量交易，归集不使用地址查找表
+		cfg := *req.Config
+		cfg.UseLookupTables = false
+		r := *req
+		r.Config = &cfg
+		req = &r
+	}
+
+	// 从 RPC 节点池获取客户端
+	rpcClient, err := m.client()
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := m.sweepTarget(ctx, rpcClient, destination, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 逐个源账户查询余额并构建交易
+	result := &SweepResult{Sources: make([]*SweepSource, len(req.Sources))}
+	keys := make([]accountSigner, len(req.Sources))
+	prices := make([]uint64, len(req.Sources))
+	for i, address := range req.Sources {
+		src := &SweepSource{Address: address}
+		result.Sources[i] = src
+		keys[i], prices[i], src.Err = m.planSweep(ctx, rpcClient, req, target, src)
+		if src.Err != nil {
+			log.Warnf(log.Global, "源账户 %s 无法归集: %v", address, src.Err)
+		}
+	}
+
+	// 各源账户分别签名，并发发送或模拟
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, max(req.Config.ConcurrentTxs, 1))
+	for i, src := range result.Sources {
+		if src.Err != nil || len(src.Batches) == 0 {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			// SOL 归集金额已扣除按规划时单价计算的优先费，发送和重发时必须使用同一单价，
+			// 否则费用上涨会使源账户余额低于保留金额或免租金额，交易失败
+			cfg := req.Config.withComputeUnitPrice(prices[i])
+			if req.DryRun {
+				src.Simulation, src.Err = m.simulate(ctx, rpcClient, keys[i], src.Batches, cfg)
+				return
+			}
+			src.Err = m.execute(ctx, rpcClient, keys[i], src.Batches, cfg, nil)
+		}()
+	}
+	wg.Wait()
+	return result, ctx.Err()
+}
+
+// sweepTarget 查询需要归集的铸币、目标代币账户是否存在以及所需的免租金额
+func (m *Manager) sweepTarget(ctx context.Context, client *rpc.Client, destination solana.PublicKey, req *SweepRequest) (*sweepTarget, error) {
+	target := &sweepTarget{
+		wallet:   destination,
+		existing: newAccountCache(),
+		rent:     make(map[uint64]uint64),
+	}
+	for _, s := range req.TokenMints {
+		address, err := solana.PublicKeyFromBase58(s)
+		if err != nil {
+			return nil, fmt.Errorf("无效的代币铸币地址 %s: %w", s, err)
+		}
+		mint, err := fetchMint(ctx, client, address)
+		if err != nil {
+			return nil, err
+		}
+		account, err := mint.associatedTokenAddress(destination)
+		if err != nil {
+			return nil, fmt.Errorf("查找目标代币账户失败: %w", err)
+		}
+		target.mints = append(target.mints, mint)
+		target.accounts = append(target.accounts, account)
+	}
+	if err := target.existing.load(ctx, client, target.accounts); err != nil {
+		return nil, err
+	}
+
+	// 目标代币账户不存在时由源账户创建，需要预留租金
+	for i, mint := range target.mints {
+		size := mint.accountSize()
+		if _, ok := target.rent[size]; ok || target.existing.has(target.accounts[i]) {
+			continue
+		}
+		lamports, err := client.GetMinimumBalanceForRentExemption(ctx, size, rpc.CommitmentFinalized)
+		if err != nil {
+			return nil, fmt.Errorf("获取代币账户租金失败: %w", err)
+		}
+		target.rent[size] = lamports
+	}
+
+	// 源账户至少保留 0 字节账户的免租金额，余额低于免租金额的交易会被拒绝
+	if req.SweepSOL {
+		minimum, err := client.GetMinimumBalanceForRentExemption(ctx, 0, rpc.CommitmentFinalized)
+		if err != nil {
+			return nil, fmt.Errorf("获取账户免租金额失败: %w", err)
+		}
+		target.reserve = max(req.Reserve, minimum)
+	}
+	return target, nil
+}
+
+// planSweep 查询源账户的 SOL 和代币余额并构建归集交易，返回源账户的签名者和计算费用使用的优先费单价
+func (m *Manager) planSweep(ctx context.Context, client *rpc.Client, req *SweepRequest, target *sweepTarget, src *SweepSource) (accountSigner, uint64, error) {
+	signer, err := newAccountSigner(req.Signer, src.Address)
+	if err != nil {
+		return signer, 0, err
+	}
+	from := signer.PublicKey()
+	if from.Equals(target.wallet) {
+		return signer, 0, errSourceIsDestination
+	}
+
+	// 一次查询源钱包及其全部代币账户
+	accounts := make([]solana.PublicKey, 0, 1+len(target.mints))
+	accounts = append(accounts, from)
+	for _, mint := range target.mints {
+		account, err := mint.associatedTokenAddress(from)
+		if err != nil {
+			return signer, 0, fmt.Errorf("查找源代币账户失败: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+	infos, err := fetchAccounts(ctx, client, accounts)
+	if err != nil {
+		return signer, 0, err
+	}
+	var balance uint64
+	if infos[0] != nil {
+		balance = infos[0].Lamports
+	}
+
+	items := make([]sweepItem, 0, len(target.mints))
+	for i, mint := range target.mints {
+		info := infos[i+1]
+		if info == nil {
+			// 源账户没有该代币的账户
+			continue
+		}
+		amount, withheld, err := parseTokenAccount(info.Data.GetBinary())
+		if err != nil {
+			return signer, 0, fmt.Errorf("源代币账户 %s: %w", accounts[i+1], err)
+		}
+		item := sweepItem{
+			mint:        mint,
+			account:     accounts[i+1],
+			destination: target.accounts[i],
+			amount:      amount,
+			lamports:    info.Lamports,
+			// 账户中仍有已扣留的转账手续费时无法关闭
+			close: req.CloseTokenAccounts && withheld == 0,
+		}
+		if amount > 0 && !target.existing.has(item.destination) {
+			if !req.Config.CreateAccountIfNotExist {
+				log.Warnf(log.Global, "目标代币账户不存在且未配置自动创建: %s，已跳过源账户 %s 的该代币", mint.address, src.Address)
+				continue
+			}
+			item.create = true
+		}
+		if amount == 0 && !item.close {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	price := req.Config.computeUnitPrice(ctx, client, from)
+	return signer, price, buildSweep(src, req.Config, from, target, items, balance, price, req.SweepSOL)
+}
+
+// buildSweep 将 items 装入批次，归集 SOL 时将余额扣除交易费、优先费、租金和保留金额后的剩余部分转入目标地址
+// SOL 转账放在最后一个批次中，交易装满时单独使用一个批次
+func buildSweep(src *SweepSource, cfg *Config, from solana.PublicKey, target *sweepTarget, items []sweepItem, balance, price uint64, sweepSOL bool) error {
+	var (
+		batch    *Batch
+		capacity *txCapacity
+	)
+	nextBatch := func() *Batch {
+		b := &Batch{Index: len(src.Batches), Status: StatusPending}
+		capacity = cfg.newTxCapacity()
+		return b
+	}
+	for _, item := range items {
+		layout := sweepTokenLayout(item.amount > 0, item.create, item.close)
+		if batch == nil || !capacity.reserve(layout) {
+			batch = nextBatch()
+			src.Batches = append(src.Batches, batch)
+			capacity.reserve(layout)
+		}
+
+		var instructions []solana.Instruction
+		cost := recipientCost{tokens: item.amount, createsAccount: item.create}
+		if item.create {
+			ix, err := item.mint.createAccountInstruction(from, target.wallet)
+			if err != nil {
+				return fmt.Errorf("构建创建代币账户指令失败: %w", err)
+			}
+			instructions = append(instructions, ix)
+			cost.computeUnits += createATAUnits
+			batch.accountSize = item.mint.accountSize()
+			src.Rent += target.rent[item.mint.accountSize()]
+		}
+		if item.amount > 0 {
+			ix, err := item.mint.transferInstruction(item.amount, item.account, item.destination, from)
+			if err != nil {
+				return fmt.Errorf("构建转账指令失败: %w", err)
+			}
+			instructions = append(instructions, ix)
+			cost.computeUnits += tokenTransferUnits
+		}
+		if item.close {
+			ix, err := item.mint.closeAccountInstruction(item.account, target.wallet, from)
+			if err != nil {
+				return fmt.Errorf("构建关闭代币账户指令失败: %w", err)
+			}
+			instructions = append(instructions, ix)
+			cost.computeUnits += closeAccountUnits
+			src.ReclaimedRent += item.lamports
+		}
+
+		token := SweptToken{
+			Mint:    item.mint.address.String(),
+			Account: item.account.String(),
+			Amount:  FromBaseUnits(item.amount, item.mint.decimals),
+			Closed:  item.close,
+		}
+		src.Tokens = append(src.Tokens, token)
+		batch.add(Recipient{Address: target.wallet.String(), Amount: token.Amount, Label: token.Mint}, cost, instructions...)
+	}
+
+	fee := func(b *Batch, units uint32) uint64 {
+		return lamportsPerSignature + priorityFee(price, cfg.unitLimit(b.computeUnits+units))
+	}
+	if sweepSOL {
+		solBatch := batch
+		if batch == nil || !capacity.reserve(sweepSOLLayout) {
+			solBatch = nextBatch()
+		}
+		// 按加入 SOL 转账后的批次计算交易费
+		need := src.Rent + target.reserve + fee(solBatch, systemTransferUnits)
+		for _, b := range src.Batches {
+			if b != solBatch {
+				need += fee(b, 0)
+			}
+		}
+		if balance > need {
+			src.Lamports = balance - need
+			if solBatch != batch {
+				src.Batches = append(src.Batches, solBatch)
+			}
+			solBatch.add(Recipient{Address: target.wallet.String(), Amount: FromBaseUnits(src.Lamports, 9), Label: "SOL"},
+				recipientCost{computeUnits: systemTransferUnits, lamports: src.Lamports},
+				system.NewTransferInstruction(src.Lamports, from, target.wallet).Build())
+		}
+	}
+
+	// 源账户余额必须足以支付交易费、优先费和创建目标代币账户的租金
+	p := &Preflight{Transfers: src.Lamports, Rent: src.Rent, LamportBalance: balance}
+	for _, b := range src.Batches {
+		p.Fees += lamportsPerSignature
+		p.PriorityFees += fee(b, 0) - lamportsPerSignature
+	}
+	src.Fees = p.Fees + p.PriorityFees
+	if !p.Sufficient() {
+		return &InsufficientFundsError{Preflight: p}
+	}
+	return nil
+}
+
+// parseTokenAccount 解析代币账户的余额，以及 Token-2022 账户中已扣留、尚未提取的转账手续费
+func parseTokenAccount(data []byte) (amount, withheld uint64, err error) {
+	if len(data) < tokenAccountSize {
+		return 0, 0, errMalformedTokenAccount
+	}
+	amount = binary.LittleEndian.Uint64(data[tokenAmountOffset:])
+	if len(data) <= accountTypeOffset {
+		return amount, 0, nil
+	}
+	// Token-2022 扩展数据：账户类型之后为 TLV 格式
+	for tlv := data[accountTypeOffset+1:]; len(tlv) >= 4; {
+		extType := binary.LittleEndian.Uint16(tlv)
+		length := int(binary.LittleEndian.Uint16(tlv[2:]))
+		if extType == 0 {
+			break
+		}
+		if len(tlv) < 4+length {
+			return 0, 0, fmt.Errorf("%w: 扩展 %d 长度越界", errMalformedTokenAccount, extType)
+		}
+		if extType == extensionTransferFeeAmount && length >= 8 {
+			withheld = binary.LittleEndian.Uint64(tlv[4:])
+		}
+		tlv = tlv[4+length:]
+	}
+	return amount, withheld, nil
+}
@@ -0,0 +1,113 @@
+package forward
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sweepFixture 构造一个目标钱包和 n 个铸币，奇数序号的铸币交替使用 Token-2022
+func sweepFixture(t *testing.T, n int) (*sweepTarget, []sweepItem, solana.PrivateKey) {
+	t.Helper()
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	target := &sweepTarget{
+		wallet:   solana.NewWallet().PublicKey(),
+		existing: newAccountCache(),
+		rent:     map[uint64]uint64{tokenAccountSize: 2_039_280, tokenAccountSize + token2022AccountExtensionSize: 2_074_080},
+		reserve:  890_880,
+	}
+	var items []sweepItem
+	for i := range n {
+		mint := &mintInfo{address: solana.NewWallet().PublicKey(), programID: solana.TokenProgramID, decimals: 6}
+		if i%2 == 1 {
+			mint.programID = solana.Token2022ProgramID
+		}
+		source, err := mint.associatedTokenAddress(key.PublicKey())
+		require.NoError(t, err)
+		destination, err := mint.associatedTokenAddress(target.wallet)
+		require.NoError(t, err)
+		items = append(items, sweepItem{
+			mint:        mint,
+			account:     source,
+			destination: destination,
+			amount:      uint64(i+1) * 1_000_000,
+			lamports:    2_039_280,
+			create:      i%3 == 0,
+			close:       true,
+		})
+	}
+	return target, items, key
+}
+
+func TestBuildSweep(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	cfg.MaxInstructionsPerTx = 100
+	target, items, key := sweepFixture(t, 12)
+	from := key.PublicKey()
+
+	src := &SweepSource{Address: from.String()}
+	const balance = 1_000_000_000
+	require.NoError(t, buildSweep(src, cfg, from, target, items, balance, 1000, true))
+	require.Greater(t, len(src.Batches), 1, "many token accounts must be split across transactions")
+	for _, b := range src.Batches {
+		assert.LessOrEqual(t, transactionSize(t, cfg, b, key), maxTransactionSize, "batch %d must fit in a transaction", b.Index)
+	}
+	assert.Len(t, src.Tokens, 12)
+	assert.Equal(t, uint64(12*2_039_280), src.ReclaimedRent, "closed token accounts must return their rent")
+	assert.Equal(t, uint64(2*2_039_280+2*2_074_080), src.Rent, "rent must be paid for every destination account created")
+
+	// SOL 转账在最后一个批次，源账户保留免租金额
+	last := src.Batches[len(src.Batches)-1]
+	assert.Equal(t, "SOL", last.Recipients[len(last.Recipients)-1].Label)
+	assert.Equal(t, uint64(balance), src.Lamports+src.Fees+src.Rent+target.reserve, "the whole balance above the reserve must be swept")
+
+	// 余额不足以支付交易费和租金
+	src = &SweepSource{Address: from.String()}
+	err := buildSweep(src, cfg, from, target, items, 10_000, 0, true)
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	assert.Zero(t, src.Lamports)
+
+	// 只归集 SOL
+	src = &SweepSource{Address: from.String()}
+	require.NoError(t, buildSweep(src, cfg, from, target, nil, balance, 0, true))
+	require.Len(t, src.Batches, 1)
+	assert.Equal(t, uint64(balance-lamportsPerSignature-target.reserve), src.Lamports)
+
+	// 余额不超过保留金额时没有可归集的资产
+	src = &SweepSource{Address: from.String()}
+	require.NoError(t, buildSweep(src, cfg, from, target, nil, target.reserve, 0, true))
+	assert.Empty(t, src.Batches)
+}
+
+func TestParseTokenAccount(t *testing.T) {
+	t.Parallel()
+	_, _, err := parseTokenAccount(make([]byte, 10))
+	assert.ErrorIs(t, err, errMalformedTokenAccount)
+
+	data := make([]byte, tokenAccountSize)
+	binary.LittleEndian.PutUint64(data[tokenAmountOffset:], 42)
+	amount, withheld, err := parseTokenAccount(data)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), amount)
+	assert.Zero(t, withheld)
+
+	// Token-2022 账户：账户类型、ImmutableOwner 扩展和 TransferFeeAmount 扩展
+	data = append(data, 2)
+	data = binary.LittleEndian.AppendUint16(data, 7)
+	data = binary.LittleEndian.AppendUint16(data, 0)
+	data = binary.LittleEndian.AppendUint16(data, extensionTransferFeeAmount)
+	data = binary.LittleEndian.AppendUint16(data, 8)
+	data = binary.LittleEndian.AppendUint64(data, 5)
+	amount, withheld, err = parseTokenAccount(data)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), amount)
+	assert.Equal(t, uint64(5), withheld)
+
+	_, _, err = parseTokenAccount(data[:len(data)-4])
+	assert.ErrorIs(t, err, errMalformedTokenAccount)
+}
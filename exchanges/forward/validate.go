@@ -0,0 +1,221 @@
+package forward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrRecipientsRejected 接收者列表未通过校验
+	ErrRecipientsRejected = errors.New("接收者列表未通过校验")
+
+	errUnknownDuplicatePolicy = errors.New("未知的重复地址处理方式")
+)
+
+// DuplicatePolicy 表示重复地址的处理方式
+type DuplicatePolicy string
+
+// 重复地址处理方式
+const (
+	DuplicateReject DuplicatePolicy = "reject" // 列表中出现重复地址时拒绝整个列表，默认方式
+	DuplicateSum    DuplicatePolicy = "sum"    // 合并重复地址并累加数量，备注和标签使用第一次出现的行
+)
+
+// IssueKind 表示接收者校验发现的问题类型
+type IssueKind string
+
+// 接收者问题类型
+const (
+	IssueInvalid      IssueKind = "invalid"       // 地址不是有效的 base58 公钥
+	IssueDuplicate    IssueKind = "duplicate"     // 地址与之前的行重复
+	IssueDenylisted   IssueKind = "denylisted"    // 地址在禁止名单中
+	IssueOffCurve     IssueKind = "off_curve"     // 地址不在 ed25519 曲线上（PDA），没有对应的私钥
+	IssueExecutable   IssueKind = "executable"    // 地址是可执行的程序账户
+	IssueProgramOwned IssueKind = "program_owned" // 地址是由程序拥有的数据账户，例如代币账户
+)
+
+// ValidationOptions 接收者校验选项
+type ValidationOptions struct {
+	Duplicates    DuplicatePolicy // 重复地址的处理方式，为空时拒绝
+	DefaultAmount float64         // 合并重复地址时，数量为 0 的行按该数量累加
+	Denylist      []string        // 禁止转账的地址
+}
+
+// RecipientIssue 描述接收者列表中一行的问题
+type RecipientIssue struct {
+	Row      int       // 接收者在列表中的序号，从 1 开始
+	Address  string    // 接收者地址
+	Kind     IssueKind // 问题类型
+	Blocking bool      // 为 true 时拒绝整个列表，否则仅作提示
+	Detail   string    // 问题说明
+}
+
+// ValidationReport 接收者列表的校验结果，可以在用户确认转账前展示
+type ValidationReport struct {
+	Total      int              // 校验前的接收者数量
+	Merged     int              // 合并到之前行的重复行数
+	Recipients []Recipient      // 通过校验并合并重复地址后的接收者
+	Issues     []RecipientIssue // 发现的问题，按行排列
+
+	rows []int // 与 Recipients 一一对应，记录接收者第一次出现的行号
+}
+
+// Err 存在阻止转账的问题时返回错误，错误中列出所有这类问题
+func (r *ValidationReport) Err() error {
+	var (
+		sb    strings.Builder
+		count int
+	)
+	for i := range r.Issues {
+		if !r.Issues[i].Blocking {
+			continue
+		}
+		count++
+		fmt.Fprintf(&sb, "; 第 %d 行 %s %s: %s", r.Issues[i].Row, r.Issues[i].Address, r.Issues[i].Kind, r.Issues[i].Detail)
+	}
+	if count == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: 共 %d 个问题%s", ErrRecipientsRejected, count, sb.String())
+}
+
+// issue 记录一个问题
+func (r *ValidationReport) issue(row int, address string, kind IssueKind, blocking bool, detail string) {
+	r.Issues = append(r.Issues, RecipientIssue{Row: row, Address: address, Kind: kind, Blocking: blocking, Detail: detail})
+}
+
+// ScreenRecipients 在不访问链上的情况下校验接收者列表：解析地址、处理重复地址、检查禁止名单，
+// 并提示不在曲线上的地址。被阻止的接收者不会出现在结果的 Recipients 中
+func ScreenRecipients(recipients []Recipient, opts *ValidationOptions) (*ValidationReport, error) {
+	policy := opts.Duplicates
+	if policy == "" {
+		policy = DuplicateReject
+	}
+	if policy != DuplicateReject && policy != DuplicateSum {
+		return nil, fmt.Errorf("%w: %s", errUnknownDuplicatePolicy, policy)
+	}
+	denylist := make(map[solana.PublicKey]struct{}, len(opts.Denylist))
+	for _, s := range opts.Denylist {
+		key, err := solana.PublicKeyFromBase58(s)
+		if err != nil {
+			return nil, fmt.Errorf("禁止名单中的无效地址 %s: %w", s, err)
+		}
+		denylist[key] = struct{}{}
+	}
+
+	report := &ValidationReport{Total: len(recipients)}
+	// seen 记录地址第一次出现的行号和在 Recipients 中的位置
+	type first struct{ row, index int }
+	seen := make(map[solana.PublicKey]first, len(recipients))
+	for i := range recipients {
+		r := recipients[i]
+		row := i + 1
+		key, err := solana.PublicKeyFromBase58(strings.TrimSpace(r.Address))
+		if err != nil {
+			report.issue(row, r.Address, IssueInvalid, true, err.Error())
+			continue
+		}
+		r.Address = key.String()
+		if f, ok := seen[key]; ok {
+			if policy == DuplicateReject {
+				report.issue(row, r.Address, IssueDuplicate, true, fmt.Sprintf("与第 %d 行重复", f.row))
+				continue
+			}
+			report.issue(row, r.Address, IssueDuplicate, false, fmt.Sprintf("已合并到第 %d 行", f.row))
+			report.Merged++
+			if f.index >= 0 {
+				merged := &report.Recipients[f.index]
+				merged.Amount = decimal.NewFromFloat(merged.Amount).Add(decimal.NewFromFloat(amountOr(r.Amount, opts.DefaultAmount))).InexactFloat64()
+			}
+			continue
+		}
+		if _, ok := denylist[key]; ok {
+			seen[key] = first{row: row, index: -1}
+			report.issue(row, r.Address, IssueDenylisted, true, "地址在禁止名单中")
+			continue
+		}
+		if !key.IsOnCurve() {
+			report.issue(row, r.Address, IssueOffCurve, false, "地址不在曲线上，可能是程序派生地址（PDA），没有私钥可以直接使用其中的 SOL")
+		}
+		if policy == DuplicateSum {
+			// 合并后的行使用明确的数量，之后不再受默认数量影响
+			r.Amount = amountOr(r.Amount, opts.DefaultAmount)
+		}
+		seen[key] = first{row: row, index: len(report.Recipients)}
+		report.Recipients = append(report.Recipients, r)
+		report.rows = append(report.rows, row)
+	}
+	return report, nil
+}
+
+// ValidateRecipients 校验接收者列表，在 ScreenRecipients 的基础上查询链上账户，
+// 拒绝可执行的程序账户，并提示由程序拥有的数据账户
+func (m *Manager) ValidateRecipients(ctx context.Context, recipients []Recipient, opts *ValidationOptions) (*ValidationReport, error) {
+	report, err := ScreenRecipients(recipients, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(report.Recipients) == 0 {
+		return report, nil
+	}
+	rpcClient, err := m.client()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]solana.PublicKey, len(report.Recipients))
+	for i := range report.Recipients {
+		keys[i] = solana.MustPublicKeyFromBase58(report.Recipients[i].Address)
+	}
+	infos, err := fetchAccounts(ctx, rpcClient, keys)
+	if err != nil {
+		return nil, err
+	}
+	rows := report.rows
+	screened := report.Recipients
+	report.Recipients, report.rows = nil, nil
+	for i, info := range infos {
+		r, row := screened[i], rows[i]
+		switch {
+		case info == nil:
+		case info.Executable:
+			report.issue(row, r.Address, IssueExecutable, true, fmt.Sprintf("地址是由 %s 加载的程序", info.Owner))
+			continue
+		case !info.Owner.Equals(solana.SystemProgramID):
+			report.issue(row, r.Address, IssueProgramOwned, false, fmt.Sprintf("地址是由程序 %s 拥有的数据账户", info.Owner))
+		}
+		report.Recipients = append(report.Recipients, r)
+		report.rows = append(report.rows, row)
+	}
+	// 链上检查的问题追加在最后，按行号重新排列，同一行的问题保持发现的顺序
+	slices.SortStableFunc(report.Issues, func(a, b RecipientIssue) int { return a.Row - b.Row })
+	return report, nil
+}
+
+// ReadDenylistFromFile 读取禁止名单文件，每行一个地址，# 开头的行为注释
+func ReadDenylistFromFile(filePath string) ([]string, error) {
+	addresses, err := ReadAddressesFromFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	for _, address := range addresses {
+		if _, err := solana.PublicKeyFromBase58(address); err != nil {
+			return nil, fmt.Errorf("禁止名单中的无效地址 %s: %w", address, err)
+		}
+	}
+	return addresses, nil
+}
+
+// amountOr 数量为 0 时返回默认数量
+func amountOr(amount, defaultAmount float64) float64 {
+	if amount == 0 {
+		return defaultAmount
+	}
+	return amount
+}
@@ -0,0 +1,75 @@
+package forward
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScreenRecipients(t *testing.T) {
+	t.Parallel()
+	a := solana.NewWallet().PublicKey().String()
+	b := solana.NewWallet().PublicKey().String()
+	denied := solana.NewWallet().PublicKey().String()
+	pda, _, err := solana.FindProgramAddress([][]byte{[]byte("vault")}, solana.SystemProgramID)
+	require.NoError(t, err)
+
+	recipients := []Recipient{
+		{Address: a, Amount: 1, Memo: "first"},
+		{Address: "not-an-address"},
+		{Address: " " + a + " ", Memo: "second"},
+		{Address: denied, Amount: 3},
+		{Address: pda.String(), Amount: 4},
+		{Address: b, Amount: 5},
+	}
+
+	report, err := ScreenRecipients(recipients, &ValidationOptions{Denylist: []string{denied}})
+	require.NoError(t, err)
+	assert.Equal(t, 6, report.Total)
+	require.Len(t, report.Issues, 4)
+	assert.Equal(t, RecipientIssue{Row: 2, Address: "not-an-address", Kind: IssueInvalid, Blocking: true, Detail: report.Issues[0].Detail}, report.Issues[0])
+	assert.Equal(t, IssueDuplicate, report.Issues[1].Kind)
+	assert.True(t, report.Issues[1].Blocking, "duplicates must be rejected by default")
+	assert.Equal(t, IssueDenylisted, report.Issues[2].Kind)
+	assert.Equal(t, IssueOffCurve, report.Issues[3].Kind)
+	assert.False(t, report.Issues[3].Blocking, "off curve addresses are only reported")
+	assert.Len(t, report.Recipients, 3)
+	assert.ErrorIs(t, report.Err(), ErrRecipientsRejected)
+
+	report, err = ScreenRecipients(recipients[:3:3], &ValidationOptions{Duplicates: DuplicateSum, DefaultAmount: 0.5})
+	require.NoError(t, err)
+	require.Len(t, report.Recipients, 1, "duplicates must be merged")
+	assert.Equal(t, 1.5, report.Recipients[0].Amount, "amounts left empty must be summed as the default amount")
+	assert.Equal(t, "first", report.Recipients[0].Memo, "the merged recipient keeps the first row's memo")
+	assert.Equal(t, 1, report.Merged)
+	assert.ErrorIs(t, report.Err(), ErrRecipientsRejected, "invalid addresses must still be rejected")
+
+	report, err = ScreenRecipients([]Recipient{{Address: a}, {Address: b, Amount: 2}}, &ValidationOptions{Duplicates: DuplicateSum, DefaultAmount: 0.5})
+	require.NoError(t, err)
+	assert.NoError(t, report.Err())
+	assert.Equal(t, 0.5, report.Recipients[0].Amount)
+
+	_, err = ScreenRecipients(recipients, &ValidationOptions{Duplicates: "keep"})
+	assert.ErrorIs(t, err, errUnknownDuplicatePolicy)
+	_, err = ScreenRecipients(recipients, &ValidationOptions{Denylist: []string{"bad"}})
+	assert.Error(t, err, "an invalid denylist entry must error")
+}
+
+func TestReadDenylistFromFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, "denylist.txt")
+	require.NoError(t, os.WriteFile(file, []byte("# exchange hot wallets\n"+testAddress1+"\n\n  "+testAddress2+"  \n"), 0o600))
+	addresses, err := ReadDenylistFromFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, []string{testAddress1, testAddress2}, addresses)
+
+	require.NoError(t, os.WriteFile(file, []byte(testAddress1+"\nnot-an-address\n"), 0o600))
+	_, err = ReadDenylistFromFile(file)
+	assert.Error(t, err, "an invalid address must error")
+}
@@ -0,0 +1,188 @@
+package forward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gocryptotrader/log"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	// syncNativeUnits SyncNative 指令的计算单元估算值
+	syncNativeUnits = 3_000
+	// wrapSize 包装 SOL 的三条指令的字节数：创建 wSOL 账户、System Transfer 和 SyncNative
+	wrapSize = 11 + 17 + 5
+	// wrapUnits 包装 SOL 的计算单元估算值
+	wrapUnits = createATAUnits + systemTransferUnits + syncNativeUnits
+)
+
+var (
+	errInvalidWrapAmount = errors.New("包装的 SOL 数量必须大于 0")
+	errNoWrappedSOL      = errors.New("钱包没有 wSOL 账户")
+)
+
+// nativeMint 原生 SOL 的包装铸币（wSOL），属于 SPL Token 程序
+var nativeMint = &mintInfo{address: solana.WrappedSol, programID: solana.TokenProgramID, decimals: 9}
+
+// isNative 铸币是否为 wSOL
+func (m *mintInfo) isNative() bool {
+	return m.address.Equals(solana.WrappedSol)
+}
+
+// wrapInstructions 将 lamports 包装到 owner 的 wSOL 账户 account：账户不存在时创建，
+// 转入 lamports 后由 SyncNative 将账户余额同步为代币数量
+func wrapInstructions(owner, account solana.PublicKey, lamports uint64) ([]solana.Instruction, error) {
+	createIx, err := nativeMint.createAccountInstruction(owner, owner)
+	if err != nil {
+		return nil, err
+	}
+	return []solana.Instruction{
+		createIx,
+		system.NewTransferInstruction(lamports, owner, account).Build(),
+		token.NewSyncNativeInstruction(account).Build(),
+	}, nil
+}
+
+// reserveWrap 为交易开头包装 SOL 的指令预留空间，共用账户按 tokenCreateLayout 计入
+func (t *txCapacity) reserveWrap() {
+	if tokenCreateLayout.accounts > t.accounts {
+		t.bytes -= (tokenCreateLayout.accounts - t.accounts) * 32
+		t.keys -= tokenCreateLayout.accounts - t.accounts
+		t.accounts = tokenCreateLayout.accounts
+	}
+	t.bytes -= wrapSize
+	t.units -= wrapUnits
+}
+
+// wrapBatches 代币为 wSOL 时按批次顺序分配发送者现有的 wSOL 余额 balance，
+// 余额不足的批次在开头包装差额，使每个批次可以独立执行，并发发送或重发时不依赖其他批次
+func wrapBatches(batches []*Batch, owner, account solana.PublicKey, balance uint64) error {
+	for _, b := range batches {
+		var need uint64
+		for k := range b.costs {
+			need += b.costs[k].tokens
+		}
+		use := min(balance, need)
+		balance -= use
+		if use == need {
+			continue
+		}
+		instructions, err := wrapInstructions(owner, account, need-use)
+		if err != nil {
+			return fmt.Errorf("构建包装 SOL 指令失败: %w", err)
+		}
+		b.instructions = append(instructions, b.instructions...)
+		b.computeUnits += wrapUnits
+	}
+	return nil
+}
+
+// WrapSOL 将钱包中的 SOL 包装为 wSOL，存入钱包的 wSOL 关联代币账户，账户不存在时创建
+func (m *Manager) WrapSOL(ctx context.Context, req *WrapRequest) (*WrapResult, error) {
+	if req.Amount <= 0 {
+		return nil, errInvalidWrapAmount
+	}
+	lamports, err := ToBaseUnits(req.Amount, nativeMint.decimals)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := newAccountSigner(req.Signer, req.From)
+	if err != nil {
+		return nil, err
+	}
+	owner := signer.PublicKey()
+	account, err := nativeMint.associatedTokenAddress(owner)
+	if err != nil {
+		return nil, fmt.Errorf("查找 wSOL 账户失败: %w", err)
+	}
+	rpcClient, err := m.client()
+	if err != nil {
+		return nil, err
+	}
+
+	instructions, err := wrapInstructions(owner, account, lamports)
+	if err != nil {
+		return nil, fmt.Errorf("构建包装 SOL 指令失败: %w", err)
+	}
+	b := instructionBatch(0, wrapUnits, instructions...)
+	result := &WrapResult{Account: account.String(), Lamports: lamports, Batch: b}
+	cfg := managementConfig(req.Config)
+
+	// 余额必须足以支付包装数量、交易费、优先费，以及 wSOL 账户不存在时的租金
+	infos, err := fetchAccounts(ctx, rpcClient, []solana.PublicKey{owner, account})
+	if err != nil {
+		return nil, err
+	}
+	p := &Preflight{Transfers: lamports, Fees: lamportsPerSignature}
+	if infos[0] != nil {
+		p.LamportBalance = infos[0].Lamports
+	}
+	if infos[1] == nil {
+		if p.Rent, err = rpcClient.GetMinimumBalanceForRentExemption(ctx, tokenAccountSize, rpc.CommitmentFinalized); err != nil {
+			return nil, fmt.Errorf("获取代币账户租金失败: %w", err)
+		}
+	}
+	p.PriorityFees = priorityFee(cfg.computeUnitPrice(ctx, rpcClient, owner), cfg.computeUnitLimit(b))
+	result.Preflight = p
+
+	if req.DryRun {
+		result.Simulation, err = m.simulate(ctx, rpcClient, signer, []*Batch{b}, cfg)
+		return result, err
+	}
+	if !p.Sufficient() {
+		return result, &InsufficientFundsError{Preflight: p}
+	}
+	if err = m.executeConfirmed(ctx, rpcClient, signer, []*Batch{b}, cfg, "包装 SOL"); err != nil {
+		return result, err
+	}
+	log.Infof(log.Global, "已将 %s 的 %d lamports 包装为 wSOL", owner, lamports)
+	return result, nil
+}
+
+// UnwrapSOL 关闭钱包的 wSOL 关联代币账户，账户中的 wSOL 和租金全部以 SOL 退回钱包
+func (m *Manager) UnwrapSOL(ctx context.Context, req *WrapRequest) (*WrapResult, error) {
+	signer, err := newAccountSigner(req.Signer, req.From)
+	if err != nil {
+		return nil, err
+	}
+	owner := signer.PublicKey()
+	account, err := nativeMint.associatedTokenAddress(owner)
+	if err != nil {
+		return nil, fmt.Errorf("查找 wSOL 账户失败: %w", err)
+	}
+	rpcClient, err := m.client()
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := fetchAccounts(ctx, rpcClient, []solana.PublicKey{account})
+	if err != nil {
+		return nil, err
+	}
+	if infos[0] == nil {
+		return nil, fmt.Errorf("%w: %s", errNoWrappedSOL, owner)
+	}
+	ix, err := nativeMint.closeAccountInstruction(account, owner, owner)
+	if err != nil {
+		return nil, fmt.Errorf("构建关闭代币账户指令失败: %w", err)
+	}
+	b := instructionBatch(0, closeAccountUnits, ix)
+	result := &WrapResult{Account: account.String(), Lamports: infos[0].Lamports, Batch: b}
+	cfg := managementConfig(req.Config)
+
+	if req.DryRun {
+		result.Simulation, err = m.simulate(ctx, rpcClient, signer, []*Batch{b}, cfg)
+		return result, err
+	}
+	if err = m.executeConfirmed(ctx, rpcClient, signer, []*Batch{b}, cfg, "解除包装 SOL"); err != nil {
+		return result, err
+	}
+	log.Infof(log.Global, "已关闭 %s 的 wSOL 账户，退回 %d lamports", owner, result.Lamports)
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package forward
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapInstructions(t *testing.T) {
+	t.Parallel()
+	owner := solana.NewWallet().PublicKey()
+	account, err := nativeMint.associatedTokenAddress(owner)
+	require.NoError(t, err)
+
+	instructions, err := wrapInstructions(owner, account, 1_000)
+	require.NoError(t, err)
+	require.Len(t, instructions, 3)
+	assert.Equal(t, solana.SPLAssociatedTokenAccountProgramID, instructions[0].ProgramID())
+	assert.Equal(t, solana.SystemProgramID, instructions[1].ProgramID())
+	assert.Equal(t, account, instructions[1].Accounts()[1].PublicKey, "lamports must be sent to the wSOL account")
+	assert.Equal(t, solana.TokenProgramID, instructions[2].ProgramID())
+	assert.True(t, nativeMint.isNative())
+}
+
+func TestWrapBatchesFitTransaction(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	cfg.MaxInstructionsPerTx = 100
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	from := key.PublicKey()
+	account, err := nativeMint.associatedTokenAddress(from)
+	require.NoError(t, err)
+
+	capacity := cfg.newTxCapacity()
+	capacity.reserveWrap()
+	b := &Batch{}
+	for capacity.reserve(tokenCreateLayout) {
+		wallet := solana.NewWallet().PublicKey()
+		destination, err := nativeMint.associatedTokenAddress(wallet)
+		require.NoError(t, err)
+		createIx, err := nativeMint.createAccountInstruction(from, wallet)
+		require.NoError(t, err)
+		transferIx, err := nativeMint.transferInstruction(10, account, destination, from)
+		require.NoError(t, err)
+		b.add(Recipient{Address: wallet.String()}, recipientCost{computeUnits: createATAUnits + tokenTransferUnits, tokens: 10, createsAccount: true}, createIx, transferIx)
+	}
+	require.NoError(t, wrapBatches([]*Batch{b}, from, account, 0))
+	assert.LessOrEqual(t, transactionSize(t, cfg, b, key), maxTransactionSize, "wrapping batch must fit in a transaction")
+	assert.LessOrEqual(t, cfg.computeUnitLimit(b), uint32(maxComputeUnitLimit))
+}
+
+func TestWrapBatches(t *testing.T) {
+	t.Parallel()
+	from := solana.NewWallet().PublicKey()
+	account, err := nativeMint.associatedTokenAddress(from)
+	require.NoError(t, err)
+	batch := func(tokens ...uint64) *Batch {
+		b := &Batch{}
+		for _, amount := range tokens {
+			b.add(Recipient{}, recipientCost{computeUnits: tokenTransferUnits, tokens: amount}, nil)
+		}
+		return b
+	}
+
+	// 现有 wSOL 余额按批次顺序使用，不足的部分在批次中包装
+	batches := []*Batch{batch(30, 20), batch(40), batch(10)}
+	require.NoError(t, wrapBatches(batches, from, account, 60))
+	assert.Len(t, batches[0].instructions, 2, "a batch covered by the wSOL balance must not wrap")
+	require.Len(t, batches[1].instructions, 4)
+	assert.Equal(t, uint32(tokenTransferUnits+wrapUnits), batches[1].computeUnits)
+	data, err := batches[1].instructions[1].Data()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(30), uint64FromTransfer(t, data), "only the shortfall must be wrapped")
+	require.Len(t, batches[2].instructions, 4)
+	data, err = batches[2].instructions[1].Data()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), uint64FromTransfer(t, data))
+}
+
+// uint64FromTransfer 读取 System Transfer 指令数据中的 lamports
+func uint64FromTransfer(t *testing.T, data []byte) uint64 {
+	t.Helper()
+	require.Len(t, data, 12)
+	return binary.LittleEndian.Uint64(data[4:])
+}
+
+func TestAllocateFundsWrap(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	cfg.PartialPay = true
+
+	b := &Batch{}
+	b.add(Recipient{Address: "a"}, recipientCost{computeUnits: tokenTransferUnits, tokens: 50}, nil)
+	b.add(Recipient{Address: "b"}, recipientCost{computeUnits: tokenTransferUnits, tokens: 60}, nil)
+
+	// wSOL 余额不足的部分由 SOL 余额包装
+	result := &Result{Batches: []*Batch{b}}
+	p := &Preflight{LamportBalance: lamportsPerSignature + 10 + 30 + 5, TokenBalance: 20, Rent: 10, wrap: true}
+	allocateFunds(p, result, nil, 0, cfg)
+	require.Len(t, result.Unpaid, 1, "recipients beyond the SOL and wSOL balance must be left unpaid")
+	assert.Equal(t, "b", result.Unpaid[0].Address)
+	assert.Equal(t, uint64(30), p.Wrapped())
+	assert.Zero(t, p.TokenShortfall(), "wSOL shortfall is covered by wrapping")
+	assert.True(t, p.Sufficient())
+
+	p = &Preflight{LamportBalance: 10, TokenBalance: 20, Tokens: 50, wrap: true}
+	assert.Equal(t, uint64(20), p.LamportShortfall())
+	assert.Contains(t, (&InsufficientFundsError{Preflight: p}).Error(), "包装 wSOL 30")
+}
@@ -0,0 +1,318 @@
+package rpcpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gocryptotrader/config"
+	"gocryptotrader/exchanges/request"
+	"gocryptotrader/log"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// New returns a pool for the configured Solana RPC endpoints. Endpoints are
+// considered healthy until the first health check says otherwise.
+func New(cfg *config.SolanaConfig) (*Pool, error) {
+	if cfg == nil || len(cfg.Endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	p := &Pool{cfg: *cfg}
+	for i := range cfg.Endpoints {
+		e := &cfg.Endpoints[i]
+		if e.URL == "" {
+			return nil, fmt.Errorf("%w: endpoint %q has no url", ErrNoEndpoints, e.Name)
+		}
+		weight := e.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		p.endpoints = append(p.endpoints, &endpoint{
+			name:    e.Name,
+			url:     e.URL,
+			weight:  weight,
+			client:  jsonrpc.NewClient(e.URL),
+			limiter: request.NewRateLimitWithWeight(e.RateLimitInterval, e.RateLimit, 1),
+			healthy: true,
+		})
+	}
+	p.client = rpc.NewWithCustomRPCClient(p)
+	return p, nil
+}
+
+// Client returns a Solana RPC client whose calls are served by the pool
+func (p *Pool) Client() *rpc.Client {
+	return p.client
+}
+
+// Start starts the periodic health checks
+func (p *Pool) Start() error {
+	if p == nil {
+		return ErrNilPool
+	}
+	if !atomic.CompareAndSwapInt32(&p.started, 0, 1) {
+		return errAlreadyStarted
+	}
+	p.shutdown = make(chan struct{})
+	p.wg.Add(1)
+	go p.run()
+	return nil
+}
+
+// Stop stops the periodic health checks
+func (p *Pool) Stop() error {
+	if p == nil {
+		return ErrNilPool
+	}
+	if !atomic.CompareAndSwapInt32(&p.started, 1, 0) {
+		return errNotStarted
+	}
+	close(p.shutdown)
+	p.wg.Wait()
+	return nil
+}
+
+// IsRunning returns whether the health checks are running
+func (p *Pool) IsRunning() bool {
+	return p != nil && atomic.LoadInt32(&p.started) == 1
+}
+
+func (p *Pool) run() {
+	defer p.wg.Done()
+	interval := p.cfg.HealthCheckInterval
+	if interval <= 0 {
+		interval = config.DefaultSolanaHealthCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		p.CheckHealth(context.Background())
+		select {
+		case <-p.shutdown:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckHealth queries the current slot of every endpoint and marks endpoints
+// unhealthy when they fail, respond slower than MaxLatency or trail the
+// highest slot in the pool by more than MaxSlotLag
+func (p *Pool) CheckHealth(ctx context.Context) {
+	maxLatency := p.cfg.MaxLatency
+	if maxLatency <= 0 {
+		maxLatency = config.DefaultSolanaMaxLatency
+	}
+
+	type check struct {
+		slot    uint64
+		latency time.Duration
+		err     error
+	}
+	checks := make([]check, len(p.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range p.endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, maxLatency)
+			defer cancel()
+			if err := request.RateLimit(checkCtx, e.limiter); err != nil {
+				checks[i].err = err
+				return
+			}
+			start := time.Now()
+			checks[i].err = e.client.CallForInto(checkCtx, &checks[i].slot, "getSlot", nil)
+			checks[i].latency = time.Since(start)
+		}()
+	}
+	wg.Wait()
+
+	var highest uint64
+	for i := range checks {
+		if checks[i].err == nil && checks[i].slot > highest {
+			highest = checks[i].slot
+		}
+	}
+
+	now := time.Now()
+	for i, e := range p.endpoints {
+		c := checks[i]
+		if c.err == nil && c.latency > maxLatency {
+			c.err = fmt.Errorf("latency %s exceeds %s", c.latency, maxLatency)
+		}
+		var lag uint64
+		if c.err == nil {
+			lag = highest - c.slot
+			if lag > p.cfg.MaxSlotLag {
+				c.err = fmt.Errorf("slot %d is %d slots behind %d", c.slot, lag, highest)
+			}
+		}
+
+		e.m.Lock()
+		wasHealthy := e.healthy
+		e.healthy = c.err == nil
+		e.slot = c.slot
+		e.slotLag = lag
+		e.latency = c.latency
+		e.lastCheck = now
+		e.lastErr = c.err
+		e.m.Unlock()
+
+		switch {
+		case wasHealthy && c.err != nil:
+			log.Warnf(log.Global, "Solana RPC endpoint %s is unhealthy: %v", e.name, c.err)
+		case !wasHealthy && c.err == nil:
+			log.Infof(log.Global, "Solana RPC endpoint %s has recovered", e.name)
+		}
+	}
+}
+
+// Health returns a snapshot of the health of every endpoint in the pool
+func (p *Pool) Health() []EndpointHealth {
+	out := make([]EndpointHealth, len(p.endpoints))
+	for i, e := range p.endpoints {
+		e.m.RLock()
+		out[i] = EndpointHealth{
+			Name:      e.name,
+			URL:       e.url,
+			Weight:    e.weight,
+			Healthy:   e.healthy,
+			Slot:      e.slot,
+			SlotLag:   e.slotLag,
+			Latency:   e.latency,
+			LastCheck: e.lastCheck,
+			Requests:  atomic.LoadUint64(&e.requests),
+			Failures:  atomic.LoadUint64(&e.failures),
+		}
+		if e.lastErr != nil {
+			out[i].LastError = e.lastErr.Error()
+		}
+		e.m.RUnlock()
+	}
+	return out
+}
+
+// CallForInto implements rpc.JSONRPCClient
+func (p *Pool) CallForInto(ctx context.Context, out any, method string, params []any) error {
+	return p.do(ctx, method == sendTransactionMethod, func(c rpc.JSONRPCClient) error {
+		return c.CallForInto(ctx, out, method, params)
+	})
+}
+
+// CallWithCallback implements rpc.JSONRPCClient
+func (p *Pool) CallWithCallback(ctx context.Context, method string, params []any, callback func(*http.Request, *http.Response) error) error {
+	return p.do(ctx, method == sendTransactionMethod, func(c rpc.JSONRPCClient) error {
+		return c.CallWithCallback(ctx, method, params, callback)
+	})
+}
+
+// CallBatch implements rpc.JSONRPCClient
+func (p *Pool) CallBatch(ctx context.Context, requests jsonrpc.RPCRequests) (jsonrpc.RPCResponses, error) {
+	var responses jsonrpc.RPCResponses
+	var broadcast bool
+	for _, r := range requests {
+		if r.Method == sendTransactionMethod {
+			broadcast = true
+			break
+		}
+	}
+	err := p.do(ctx, broadcast, func(c rpc.JSONRPCClient) error {
+		var err error
+		responses, err = c.CallBatch(ctx, requests)
+		return err
+	})
+	return responses, err
+}
+
+// do runs fn against endpoints in order of preference until one of them
+// answers. Errors returned by the node itself are passed straight back to
+// the caller, except when the node reports that it is behind the cluster.
+// A broadcast that fails at the transport level is never retried, since the
+// node may already have forwarded the transaction to the cluster.
+func (p *Pool) do(ctx context.Context, broadcast bool, fn func(rpc.JSONRPCClient) error) error {
+	var errs error
+	for _, e := range p.order() {
+		if err := request.RateLimit(ctx, e.limiter); err != nil {
+			return err
+		}
+		atomic.AddUint64(&e.requests, 1)
+		err := fn(e.client)
+		if err == nil || !failover(err) {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return err
+		}
+		atomic.AddUint64(&e.failures, 1)
+		e.markUnhealthy(err)
+		if broadcast && transportError(err) {
+			return fmt.Errorf("%w: %s: %w", errBroadcastUnknown, e.name, err)
+		}
+		errs = errors.Join(errs, fmt.Errorf("%s: %w", e.name, err))
+	}
+	return fmt.Errorf("%w: %w", errAllEndpointsFailed, errs)
+}
+
+// failover returns whether an error warrants retrying on another endpoint
+func failover(err error) bool {
+	var rpcErr *jsonrpc.RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code == nodeUnhealthyCode
+	}
+	return true
+}
+
+// transportError returns whether an error happened before the node answered
+func transportError(err error) bool {
+	var rpcErr *jsonrpc.RPCError
+	return !errors.As(err, &rpcErr)
+}
+
+// order returns the endpoints shuffled by weight, healthy endpoints first
+func (p *Pool) order() []*endpoint {
+	type ranked struct {
+		e       *endpoint
+		healthy bool
+		key     float64
+	}
+	r := make([]ranked, len(p.endpoints))
+	for i, e := range p.endpoints {
+		e.m.RLock()
+		healthy := e.healthy
+		e.m.RUnlock()
+		// weighted random sampling without replacement, higher keys go first
+		r[i] = ranked{e: e, healthy: healthy, key: math.Pow(rand.Float64(), 1/float64(e.weight))} //nolint:gosec // not used for security
+	}
+	sort.SliceStable(r, func(i, j int) bool {
+		if r[i].healthy != r[j].healthy {
+			return r[i].healthy
+		}
+		return r[i].key > r[j].key
+	})
+	out := make([]*endpoint, len(r))
+	for i := range r {
+		out[i] = r[i].e
+	}
+	return out
+}
+
+func (e *endpoint) markUnhealthy(err error) {
+	e.m.Lock()
+	wasHealthy := e.healthy
+	e.healthy = false
+	e.lastErr = err
+	e.m.Unlock()
+	if wasHealthy {
+		log.Warnf(log.Global, "Solana RPC endpoint %s failed, failing over: %v", e.name, err)
+	}
+}
@@ -0,0 +1,259 @@
+package rpcpool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gocryptotrader/config"
+
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slotServer answers getSlot with slot, or with rpcErr when it is set
+func slotServer(t *testing.T, slot uint64, rpcErr *jsonrpc.RPCError, calls *int32) *httptest.Server {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		atomic.AddInt32(calls, 1)
+		resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		if rpcErr != nil {
+			resp["error"] = rpcErr
+		} else {
+			resp["result"] = slot
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// downServer is an endpoint that fails at the transport level
+func downServer(t *testing.T, calls *int32) *httptest.Server {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(calls, 1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	_, err := New(nil)
+	assert.ErrorIs(t, err, ErrNoEndpoints)
+	_, err = New(&config.SolanaConfig{})
+	assert.ErrorIs(t, err, ErrNoEndpoints)
+	_, err = New(&config.SolanaConfig{Endpoints: []config.SolanaRPCEndpoint{{Name: "a"}}})
+	assert.ErrorIs(t, err, ErrNoEndpoints)
+
+	p, err := New(&config.SolanaConfig{Endpoints: []config.SolanaRPCEndpoint{{Name: "a", URL: "http://localhost"}}})
+	require.NoError(t, err)
+	assert.NotNil(t, p.Client())
+	h := p.Health()
+	require.Len(t, h, 1)
+	assert.True(t, h[0].Healthy, "endpoints must be healthy before the first check")
+	assert.Equal(t, 1, h[0].Weight, "weight must default to 1")
+}
+
+func TestStartStop(t *testing.T) {
+	t.Parallel()
+	var calls int32
+	s := slotServer(t, 1, nil, &calls)
+	p, err := New(&config.SolanaConfig{
+		Endpoints:           []config.SolanaRPCEndpoint{{Name: "a", URL: s.URL}},
+		HealthCheckInterval: time.Hour,
+	})
+	require.NoError(t, err)
+	assert.ErrorIs(t, p.Stop(), errNotStarted)
+	require.NoError(t, p.Start())
+	assert.True(t, p.IsRunning())
+	assert.ErrorIs(t, p.Start(), errAlreadyStarted)
+	require.NoError(t, p.Stop())
+	assert.False(t, p.IsRunning())
+
+	var nilPool *Pool
+	assert.ErrorIs(t, nilPool.Start(), ErrNilPool)
+	assert.False(t, nilPool.IsRunning())
+}
+
+func TestOrder(t *testing.T) {
+	t.Parallel()
+	p, err := New(&config.SolanaConfig{Endpoints: []config.SolanaRPCEndpoint{
+		{Name: "heavy", URL: "http://heavy", Weight: 9},
+		{Name: "light", URL: "http://light", Weight: 1},
+		{Name: "down", URL: "http://down", Weight: 100},
+	}})
+	require.NoError(t, err)
+	p.endpoints[2].markUnhealthy(assert.AnError)
+
+	first := make(map[string]int)
+	for range 2000 {
+		order := p.order()
+		require.Len(t, order, 3)
+		assert.Equal(t, "down", order[2].name, "unhealthy endpoints must be tried last")
+		first[order[0].name]++
+	}
+	assert.Greater(t, first["heavy"], 1600, "endpoints must be preferred in proportion to their weight")
+	assert.Positive(t, first["light"], "lighter endpoints must still be used")
+}
+
+func TestFailover(t *testing.T) {
+	t.Parallel()
+	var downCalls, behindCalls, okCalls int32
+	down := downServer(t, &downCalls)
+	behind := slotServer(t, 0, &jsonrpc.RPCError{Code: nodeUnhealthyCode, Message: "Node is behind"}, &behindCalls)
+	ok := slotServer(t, 42, nil, &okCalls)
+	p, err := New(&config.SolanaConfig{Endpoints: []config.SolanaRPCEndpoint{
+		{Name: "down", URL: down.URL, Weight: 1000},
+		{Name: "behind", URL: behind.URL, Weight: 1000},
+		{Name: "ok", URL: ok.URL, Weight: 1},
+	}})
+	require.NoError(t, err)
+
+	slot, err := p.Client().GetSlot(context.Background(), "")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), slot)
+
+	// 失败的节点被标记为不健康，后续请求直接发往健康节点
+	slot, err = p.Client().GetSlot(context.Background(), "")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), slot)
+	assert.LessOrEqual(t, atomic.LoadInt32(&downCalls), int32(1))
+	assert.LessOrEqual(t, atomic.LoadInt32(&behindCalls), int32(1))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&okCalls))
+
+	for _, h := range p.Health() {
+		assert.Equal(t, h.Name == "ok", h.Healthy, "endpoint %s health", h.Name)
+	}
+}
+
+func TestRPCErrorsPassThrough(t *testing.T) {
+	t.Parallel()
+	var badCalls, okCalls int32
+	bad := slotServer(t, 0, &jsonrpc.RPCError{Code: -32602, Message: "invalid params"}, &badCalls)
+	ok := slotServer(t, 1, nil, &okCalls)
+	p, err := New(&config.SolanaConfig{Endpoints: []config.SolanaRPCEndpoint{
+		{Name: "bad", URL: bad.URL},
+		{Name: "ok", URL: ok.URL},
+	}})
+	require.NoError(t, err)
+	p.endpoints[1].markUnhealthy(assert.AnError)
+
+	_, err = p.Client().GetSlot(context.Background(), "")
+	var rpcErr *jsonrpc.RPCError
+	require.ErrorAs(t, err, &rpcErr, "errors returned by the node must not be wrapped")
+	assert.Equal(t, -32602, rpcErr.Code)
+	assert.Zero(t, atomic.LoadInt32(&okCalls), "request errors must not fail over")
+
+	var downCalls int32
+	down := downServer(t, &downCalls)
+	p, err = New(&config.SolanaConfig{Endpoints: []config.SolanaRPCEndpoint{{Name: "down", URL: down.URL}}})
+	require.NoError(t, err)
+	_, err = p.Client().GetSlot(context.Background(), "")
+	assert.ErrorIs(t, err, errAllEndpointsFailed)
+}
+
+func TestCheckHealth(t *testing.T) {
+	t.Parallel()
+	var calls int32
+	tip := slotServer(t, 1000, nil, &calls)
+	near := slotServer(t, 990, nil, &calls)
+	lagging := slotServer(t, 900, nil, &calls)
+	down := downServer(t, &calls)
+	p, err := New(&config.SolanaConfig{
+		Endpoints: []config.SolanaRPCEndpoint{
+			{Name: "tip", URL: tip.URL},
+			{Name: "near", URL: near.URL},
+			{Name: "lagging", URL: lagging.URL},
+			{Name: "down", URL: down.URL},
+		},
+		MaxSlotLag: 50,
+		MaxLatency: time.Second,
+	})
+	require.NoError(t, err)
+
+	p.CheckHealth(context.Background())
+	h := p.Health()
+	require.Len(t, h, 4)
+	assert.True(t, h[0].Healthy)
+	assert.Equal(t, uint64(1000), h[0].Slot)
+	assert.True(t, h[1].Healthy)
+	assert.Equal(t, uint64(10), h[1].SlotLag)
+	assert.False(t, h[2].Healthy, "endpoints beyond the slot lag must be unhealthy")
+	assert.Equal(t, uint64(100), h[2].SlotLag)
+	assert.NotEmpty(t, h[2].LastError)
+	assert.False(t, h[3].Healthy, "unreachable endpoints must be unhealthy")
+	assert.NotEmpty(t, h[3].LastError)
+	assert.False(t, h[0].LastCheck.IsZero())
+}
+
+func TestBroadcastNoFailover(t *testing.T) {
+	t.Parallel()
+	var slowCalls, rejectCalls int32
+	slow := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&slowCalls, 1)
+		_, _ = io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+	t.Cleanup(slow.Close)
+	reject := slotServer(t, 0, &jsonrpc.RPCError{Code: -32002, Message: "Transaction simulation failed: This transaction has already been processed"}, &rejectCalls)
+	p, err := New(&config.SolanaConfig{Endpoints: []config.SolanaRPCEndpoint{
+		{Name: "slow", URL: slow.URL},
+		{Name: "reject", URL: reject.URL},
+	}})
+	require.NoError(t, err)
+	p.endpoints[0].client = jsonrpc.NewClientWithOpts(slow.URL, &jsonrpc.RPCClientOpts{HTTPClient: &http.Client{Timeout: 50 * time.Millisecond}})
+	p.endpoints[1].markUnhealthy(assert.AnError)
+
+	// 节点超时后交易可能已经广播，不得再发往其他节点
+	var sig string
+	err = p.CallForInto(context.Background(), &sig, sendTransactionMethod, []any{"tx"})
+	assert.ErrorIs(t, err, errBroadcastUnknown)
+	var rpcErr *jsonrpc.RPCError
+	assert.False(t, errors.As(err, &rpcErr), "a broadcast with an unknown outcome must not look like a rejection")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&slowCalls))
+	assert.Zero(t, atomic.LoadInt32(&rejectCalls), "sendTransaction must not fail over after a transport error")
+
+	_, err = p.CallBatch(context.Background(), jsonrpc.RPCRequests{jsonrpc.NewRequest(sendTransactionMethod, "tx")})
+	assert.ErrorIs(t, err, errBroadcastUnknown, "batches containing sendTransaction must not fail over")
+	assert.Zero(t, atomic.LoadInt32(&rejectCalls))
+
+	// 其他请求仍然切换到下一个节点
+	p.endpoints[0].weight = 1000
+	_, err = p.Client().GetSlot(context.Background(), "")
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, -32002, rpcErr.Code)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&rejectCalls))
+
+	// 节点报告落后时交易未被处理，可以切换节点
+	var behindCalls, okCalls int32
+	behind := slotServer(t, 0, &jsonrpc.RPCError{Code: nodeUnhealthyCode, Message: "Node is behind"}, &behindCalls)
+	ok := slotServer(t, 0, nil, &okCalls)
+	p, err = New(&config.SolanaConfig{Endpoints: []config.SolanaRPCEndpoint{
+		{Name: "behind", URL: behind.URL},
+		{Name: "ok", URL: ok.URL},
+	}})
+	require.NoError(t, err)
+	p.endpoints[1].markUnhealthy(assert.AnError)
+	var out any
+	require.NoError(t, p.CallForInto(context.Background(), &out, sendTransactionMethod, []any{"tx"}))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&behindCalls))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&okCalls))
+}
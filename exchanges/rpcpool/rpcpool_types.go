@@ -0,0 +1,82 @@
+package rpcpool
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"gocryptotrader/config"
+	"gocryptotrader/exchanges/request"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// nodeUnhealthyCode is the JSON-RPC error code a Solana node returns when it
+// is behind the cluster, the request is retried on another endpoint
+const nodeUnhealthyCode = -32005
+
+// sendTransactionMethod broadcasts a transaction and must not be sent twice
+// when the outcome of the first attempt is unknown
+const sendTransactionMethod = "sendTransaction"
+
+var (
+	// ErrNoEndpoints is returned when the pool is configured without endpoints
+	ErrNoEndpoints = errors.New("no solana rpc endpoints configured")
+	// ErrNilPool is returned when a nil pool is used
+	ErrNilPool = errors.New("solana rpc pool is nil")
+
+	errAllEndpointsFailed = errors.New("all solana rpc endpoints failed")
+	errBroadcastUnknown   = errors.New("solana rpc endpoint failed during broadcast, transaction may have been sent")
+	errAlreadyStarted     = errors.New("solana rpc pool already started")
+	errNotStarted         = errors.New("solana rpc pool not started")
+)
+
+// Pool distributes Solana JSON-RPC calls across a set of weighted endpoints.
+// Healthy endpoints are preferred and a call that fails at the transport
+// level, or on a node that reports itself behind, is retried on the next
+// endpoint. sendTransaction is only retried when the node reports itself
+// behind, as a transport failure leaves it unknown whether the transaction
+// was broadcast. Pool implements rpc.JSONRPCClient so it can back an rpc.Client.
+type Pool struct {
+	started   int32
+	cfg       config.SolanaConfig
+	endpoints []*endpoint
+	client    *rpc.Client
+	shutdown  chan struct{}
+	wg        sync.WaitGroup
+}
+
+// endpoint is a single RPC node in the pool
+type endpoint struct {
+	name    string
+	url     string
+	weight  int
+	client  rpc.JSONRPCClient
+	limiter *request.RateLimiterWithWeight
+
+	requests uint64
+	failures uint64
+
+	m         sync.RWMutex
+	healthy   bool
+	slot      uint64
+	slotLag   uint64
+	latency   time.Duration
+	lastCheck time.Time
+	lastErr   error
+}
+
+// EndpointHealth is a snapshot of the health of a pool endpoint
+type EndpointHealth struct {
+	Name      string
+	URL       string
+	Weight    int
+	Healthy   bool
+	Slot      uint64
+	SlotLag   uint64
+	Latency   time.Duration
+	LastCheck time.Time
+	LastError string
+	Requests  uint64
+	Failures  uint64
+}
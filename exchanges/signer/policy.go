@@ -0,0 +1,144 @@
+package signer
+
+import (
+	"fmt"
+	"math"
+
+	"gocryptotrader/config"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/programs/system"
+)
+
+// addressLookupTableProgramID 地址查找表程序
+var addressLookupTableProgramID = solana.MustPublicKeyFromBase58("AddressLookupTab1e1111111111111111111111111")
+
+// DefaultPrograms 未配置允许的程序时，交易只能调用转账使用的程序
+var DefaultPrograms = []solana.PublicKey{
+	solana.SystemProgramID,
+	solana.TokenProgramID,
+	solana.Token2022ProgramID,
+	solana.SPLAssociatedTokenAccountProgramID,
+	computebudget.ProgramID,
+	solana.MemoProgramID,
+	addressLookupTableProgramID,
+}
+
+// NewPolicy 根据配置创建签名策略
+func NewPolicy(cfg *config.SignerConfig) (Policy, error) {
+	p := Policy{
+		AllowedPrograms: make(map[solana.PublicKey]struct{}),
+		MaxLamports:     cfg.MaxLamportsPerTransaction,
+	}
+	if len(cfg.AllowedAccounts) > 0 {
+		p.AllowedAccounts = make(map[solana.PublicKey]struct{}, len(cfg.AllowedAccounts))
+		for _, address := range cfg.AllowedAccounts {
+			key, err := solana.PublicKeyFromBase58(address)
+			if err != nil {
+				return Policy{}, fmt.Errorf("无效的允许账户 %s: %w", address, err)
+			}
+			p.AllowedAccounts[key] = struct{}{}
+		}
+	}
+	programs := DefaultPrograms
+	if len(cfg.AllowedPrograms) > 0 {
+		programs = make([]solana.PublicKey, 0, len(cfg.AllowedPrograms))
+		for _, address := range cfg.AllowedPrograms {
+			key, err := solana.PublicKeyFromBase58(address)
+			if err != nil {
+				return Policy{}, fmt.Errorf("无效的允许程序 %s: %w", address, err)
+			}
+			programs = append(programs, key)
+		}
+	}
+	for _, program := range programs {
+		p.AllowedPrograms[program] = struct{}{}
+	}
+	return p, nil
+}
+
+// Check 检查是否可以使用 account 的私钥对消息签名：账户必须被允许且是交易要求的签名者，
+// 交易只能调用允许的程序，从账户转出的 SOL 不能超过上限
+func (p *Policy) Check(account solana.PublicKey, message []byte) error {
+	if p.AllowedAccounts != nil {
+		if _, ok := p.AllowedAccounts[account]; !ok {
+			return fmt.Errorf("%w: 不允许为账户 %s 签名", ErrPolicyViolation, account)
+		}
+	}
+	var msg solana.Message
+	if err := msg.UnmarshalWithDecoder(bin.NewBinDecoder(message)); err != nil {
+		return fmt.Errorf("%w: %w", errMalformedRequest, err)
+	}
+	if !msg.Signers().Contains(account) {
+		return fmt.Errorf("%w: 账户 %s 不是交易的签名者", ErrPolicyViolation, account)
+	}
+
+	var lamports uint64
+	for i, inst := range msg.Instructions {
+		// 程序不能从地址查找表加载，只会出现在静态账户中
+		if int(inst.ProgramIDIndex) >= len(msg.AccountKeys) {
+			return fmt.Errorf("%w: 指令 %d 的程序索引越界", errMalformedRequest, i)
+		}
+		program := msg.AccountKeys[inst.ProgramIDIndex]
+		if _, ok := p.AllowedPrograms[program]; !ok {
+			return fmt.Errorf("%w: 指令 %d 调用了不允许的程序 %s", ErrPolicyViolation, i, program)
+		}
+		if !program.Equals(solana.SystemProgramID) {
+			continue
+		}
+		amount, err := systemLamports(&msg, inst, account)
+		if err != nil {
+			return fmt.Errorf("指令 %d: %w", i, err)
+		}
+		if amount > math.MaxUint64-lamports {
+			return fmt.Errorf("%w: 转出的 SOL 溢出", ErrPolicyViolation)
+		}
+		lamports += amount
+	}
+	if p.MaxLamports > 0 && lamports > p.MaxLamports {
+		return fmt.Errorf("%w: 交易从 %s 转出 %d lamports，超过上限 %d", ErrPolicyViolation, account, lamports, p.MaxLamports)
+	}
+	return nil
+}
+
+// systemLamports 返回系统程序指令从 account 转出的 lamports：以 account 为资金来源的转账和创建账户，
+// 以及由 account 授权的种子账户转账和 nonce 账户提取。无法计算转出金额的系统程序指令被拒绝
+func systemLamports(msg *solana.Message, inst solana.CompiledInstruction, account solana.PublicKey) (uint64, error) {
+	accounts := make([]*solana.AccountMeta, len(inst.Accounts))
+	for i, index := range inst.Accounts {
+		// 从地址查找表加载的账户不可能是签名者，不会是 account
+		if int(index) < len(msg.AccountKeys) {
+			accounts[i] = &solana.AccountMeta{PublicKey: msg.AccountKeys[index]}
+		}
+	}
+	decoded, err := system.DecodeInstruction(accounts, inst.Data)
+	if err != nil {
+		return 0, fmt.Errorf("%w: 无法解析系统程序指令: %w", errMalformedRequest, err)
+	}
+	var from *solana.AccountMeta
+	var lamports *uint64
+	switch ix := decoded.Impl.(type) {
+	case *system.Transfer:
+		from, lamports = ix.GetFundingAccount(), ix.Lamports
+	case *system.CreateAccount:
+		from, lamports = ix.GetFundingAccount(), ix.Lamports
+	case *system.CreateAccountWithSeed:
+		from, lamports = ix.GetFundingAccount(), ix.Lamports
+	case *system.TransferWithSeed:
+		// 种子账户由 base 账户签名授权转出
+		from, lamports = ix.GetBaseForFundingAccount(), ix.Lamports
+	case *system.WithdrawNonceAccount:
+		// nonce 账户由权限账户签名授权提取
+		from, lamports = ix.GetNonceAuthorityAccount(), ix.Lamports
+	case *system.AdvanceNonceAccount, *system.InitializeNonceAccount, *system.Allocate, *system.AllocateWithSeed:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("%w: 不支持的系统程序指令 %d", ErrPolicyViolation, decoded.TypeID.Uint32())
+	}
+	if from == nil || lamports == nil || !from.PublicKey.Equals(account) {
+		return 0, nil
+	}
+	return *lamports, nil
+}
@@ -0,0 +1,79 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+
+	"gocryptotrader/config"
+	"gocryptotrader/exchanges/forward"
+	"gocryptotrader/gctrpc"
+	"gocryptotrader/log"
+
+	"github.com/gagliardetto/solana-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewServer 创建签名服务，通过策略检查的请求由 keystore 签名
+func NewServer(keystore forward.Signer, cfg *config.SignerConfig) (*Server, error) {
+	if keystore == nil {
+		return nil, errNoKeystore
+	}
+	policy, err := NewPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{keystore: keystore, policy: policy, srv: grpc.NewServer()}
+	gctrpc.RegisterSignerServiceServer(s.srv, s)
+	return s, nil
+}
+
+// Sign 检查策略后对消息签名，违反策略的请求返回 PermissionDenied
+func (s *Server) Sign(ctx context.Context, req *gctrpc.SignRequest) (*gctrpc.SignResponse, error) {
+	account, err := solana.PublicKeyFromBase58(req.Account)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v: %v", errMalformedRequest, err)
+	}
+	if err = s.policy.Check(account, req.Message); err != nil {
+		log.Warnf(log.Global, "拒绝为 %s 签名: %v", account, err)
+		if errors.Is(err, ErrPolicyViolation) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	signature, err := s.keystore.Sign(ctx, account, req.Message)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &gctrpc.SignResponse{Signature: signature[:]}, nil
+}
+
+// Serve 在 socket 上提供签名服务直到 Stop 被调用。套接字只允许当前用户访问，
+// 上次运行遗留的套接字文件会被删除
+func (s *Server) Serve(socket string) error {
+	if socket == "" {
+		return errNoSocket
+	}
+	if err := os.Remove(socket); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("删除旧的签名服务套接字失败: %w", err)
+	}
+	lis, err := net.Listen("unix", socket)
+	if err != nil {
+		return fmt.Errorf("监听签名服务套接字 %s 失败: %w", socket, err)
+	}
+	if err = os.Chmod(socket, 0o600); err != nil {
+		return errors.Join(fmt.Errorf("设置签名服务套接字权限失败: %w", err), lis.Close())
+	}
+	log.Infof(log.Global, "签名服务监听 %s", socket)
+	return s.srv.Serve(lis)
+}
+
+// Stop 等待进行中的签名完成后停止签名服务
+func (s *Server) Stop() {
+	s.srv.GracefulStop()
+}
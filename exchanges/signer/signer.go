@@ -0,0 +1,57 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+
+	"gocryptotrader/config"
+	"gocryptotrader/exchanges/account"
+	"gocryptotrader/exchanges/forward"
+	"gocryptotrader/gctrpc"
+
+	"github.com/gagliardetto/solana-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// New 根据配置返回签名方：配置了套接字时使用独立的签名进程，
+// 否则以 accounts 表作为进程内的密钥库
+func New(cfg *config.Config) (forward.Signer, error) {
+	if cfg.Signer.Socket != "" {
+		return NewRemote(cfg.Signer.Socket)
+	}
+	return account.New(cfg), nil
+}
+
+// NewRemote 返回通过 socket 访问签名进程的签名方，连接在第一次签名时建立
+func NewRemote(socket string) (*Remote, error) {
+	if socket == "" {
+		return nil, errNoSocket
+	}
+	// 套接字只在本机可访问，由文件权限限制访问者，不需要 TLS
+	conn, err := grpc.NewClient("unix:"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("连接签名服务 %s 失败: %w", socket, err)
+	}
+	return &Remote{conn: conn, client: gctrpc.NewSignerServiceClient(conn)}, nil
+}
+
+// Sign 请求签名进程使用 account 的私钥对消息签名
+func (r *Remote) Sign(ctx context.Context, account solana.PublicKey, message []byte) (solana.Signature, error) {
+	resp, err := r.client.Sign(ctx, &gctrpc.SignRequest{
+		Account: account.String(),
+		Message: message,
+	})
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("签名服务拒绝为 %s 签名: %w", account, err)
+	}
+	if len(resp.Signature) != solana.SignatureLength {
+		return solana.Signature{}, errMalformedResponse
+	}
+	return solana.SignatureFromBytes(resp.Signature), nil
+}
+
+// Close 关闭与签名进程的连接
+func (r *Remote) Close() error {
+	return r.conn.Close()
+}
@@ -0,0 +1,132 @@
+package signer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gocryptotrader/config"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// keySigner 使用内存中的私钥签名
+type keySigner map[solana.PublicKey]solana.PrivateKey
+
+func (k keySigner) Sign(_ context.Context, account solana.PublicKey, message []byte) (solana.Signature, error) {
+	return k[account].Sign(message)
+}
+
+func transferMessage(t *testing.T, from solana.PublicKey, lamports uint64, extra ...solana.Instruction) []byte {
+	t.Helper()
+	instructions := append([]solana.Instruction{
+		system.NewTransferInstruction(lamports, from, solana.NewWallet().PublicKey()).Build(),
+	}, extra...)
+	return compileMessage(t, from, instructions...)
+}
+
+func compileMessage(t *testing.T, payer solana.PublicKey, instructions ...solana.Instruction) []byte {
+	t.Helper()
+	tx, err := solana.NewTransaction(instructions, solana.Hash{}, solana.TransactionPayer(payer))
+	require.NoError(t, err)
+	message, err := tx.Message.MarshalBinary()
+	require.NoError(t, err)
+	return message
+}
+
+func TestPolicyCheck(t *testing.T) {
+	t.Parallel()
+	key := solana.NewWallet().PrivateKey
+	other := solana.NewWallet().PublicKey()
+
+	p, err := NewPolicy(&config.SignerConfig{MaxLamportsPerTransaction: 1000})
+	require.NoError(t, err)
+	assert.NoError(t, p.Check(key.PublicKey(), transferMessage(t, key.PublicKey(), 1000)))
+	assert.ErrorIs(t, p.Check(key.PublicKey(), transferMessage(t, key.PublicKey(), 1001)), ErrPolicyViolation)
+	assert.ErrorIs(t, p.Check(other, transferMessage(t, key.PublicKey(), 1)), ErrPolicyViolation, "the account must be a signer of the transaction")
+
+	unknown := solana.NewInstruction(solana.NewWallet().PublicKey(), solana.AccountMetaSlice{}, []byte{1})
+	assert.ErrorIs(t, p.Check(key.PublicKey(), transferMessage(t, key.PublicKey(), 1, unknown)), ErrPolicyViolation)
+	assert.ErrorIs(t, p.Check(key.PublicKey(), []byte{1, 2, 3}), errMalformedRequest)
+
+	p, err = NewPolicy(&config.SignerConfig{AllowedAccounts: []string{other.String()}})
+	require.NoError(t, err)
+	assert.ErrorIs(t, p.Check(key.PublicKey(), transferMessage(t, key.PublicKey(), 1)), ErrPolicyViolation)
+
+	_, err = NewPolicy(&config.SignerConfig{AllowedPrograms: []string{"invalid"}})
+	assert.Error(t, err)
+}
+
+func TestPolicyCheckSystemInstructions(t *testing.T) {
+	t.Parallel()
+	key := solana.NewWallet().PublicKey()
+	recipient := solana.NewWallet().PublicKey()
+	nonce := solana.NewWallet().PublicKey()
+	seeded, err := solana.CreateWithSeed(key, "seed", solana.SystemProgramID)
+	require.NoError(t, err)
+
+	p, err := NewPolicy(&config.SignerConfig{MaxLamportsPerTransaction: 1000})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name        string
+		instruction func(lamports uint64) solana.Instruction
+	}{
+		{"CreateAccountWithSeed", func(lamports uint64) solana.Instruction {
+			return system.NewCreateAccountWithSeedInstruction(key, "seed", lamports, 80, solana.SystemProgramID, key, seeded, key).Build()
+		}},
+		{"TransferWithSeed", func(lamports uint64) solana.Instruction {
+			return system.NewTransferWithSeedInstruction(lamports, "seed", solana.SystemProgramID, seeded, key, recipient).Build()
+		}},
+		{"WithdrawNonceAccount", func(lamports uint64) solana.Instruction {
+			return system.NewWithdrawNonceAccountInstruction(lamports, nonce, recipient, solana.SysVarRecentBlockHashesPubkey, solana.SysVarRentPubkey, key).Build()
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.NoError(t, p.Check(key, compileMessage(t, key, tc.instruction(1000))))
+			assert.ErrorIs(t, p.Check(key, compileMessage(t, key, tc.instruction(1001))), ErrPolicyViolation)
+			assert.ErrorIs(t, p.Check(key, transferMessage(t, key, 500, tc.instruction(501))), ErrPolicyViolation, "amounts must add up with other instructions")
+		})
+	}
+
+	assign := system.NewAssignInstruction(solana.TokenProgramID, key).Build()
+	assert.ErrorIs(t, p.Check(key, compileMessage(t, key, assign)), ErrPolicyViolation, "system instructions the policy cannot account for must be rejected")
+}
+
+func TestRemote(t *testing.T) {
+	t.Parallel()
+	key := solana.NewWallet().PrivateKey
+	srv, err := NewServer(keySigner{key.PublicKey(): key}, &config.SignerConfig{MaxLamportsPerTransaction: 1000})
+	require.NoError(t, err)
+	socket := filepath.Join(t.TempDir(), "signer.sock")
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(socket) }()
+	t.Cleanup(func() {
+		srv.Stop()
+		<-served
+	})
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(socket)
+		return err == nil
+	}, time.Second, time.Millisecond)
+
+	remote, err := NewRemote(socket)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, remote.Close()) })
+
+	message := transferMessage(t, key.PublicKey(), 1000)
+	signature, err := remote.Sign(context.Background(), key.PublicKey(), message)
+	require.NoError(t, err)
+	assert.True(t, signature.Verify(key.PublicKey(), message))
+
+	_, err = remote.Sign(context.Background(), key.PublicKey(), transferMessage(t, key.PublicKey(), 1001))
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,46 @@
+package signer
+
+import (
+	"errors"
+
+	"gocryptotrader/exchanges/forward"
+	"gocryptotrader/gctrpc"
+
+	"github.com/gagliardetto/solana-go"
+	"google.golang.org/grpc"
+)
+
+var (
+	// ErrPolicyViolation 签名请求不符合签名服务的策略
+	ErrPolicyViolation = errors.New("签名请求违反策略")
+
+	errNoSocket          = errors.New("未配置签名服务套接字")
+	errNoKeystore        = errors.New("未提供密钥库")
+	errMalformedRequest  = errors.New("签名请求格式错误")
+	errMalformedResponse = errors.New("签名服务返回的签名格式错误")
+)
+
+// Remote 通过本地 Unix 套接字请求独立的签名进程签名，引擎进程不接触私钥
+type Remote struct {
+	conn   *grpc.ClientConn
+	client gctrpc.SignerServiceClient
+}
+
+// Policy 签名服务在签名前检查的策略
+type Policy struct {
+	// AllowedAccounts 允许签名的账户，为空时允许密钥库中的所有账户
+	AllowedAccounts map[solana.PublicKey]struct{}
+	// AllowedPrograms 交易可以调用的程序
+	AllowedPrograms map[solana.PublicKey]struct{}
+	// MaxLamports 单笔交易从签名账户转出的 SOL 上限，为 0 时不限制
+	MaxLamports uint64
+}
+
+// Server 签名服务，检查策略后使用密钥库签名
+type Server struct {
+	gctrpc.UnimplementedSignerServiceServer
+
+	keystore forward.Signer
+	policy   Policy
+	srv      *grpc.Server
+}
@@ -0,0 +1,310 @@
+package vesting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gocryptotrader/common"
+	"gocryptotrader/config"
+	"gocryptotrader/database/repository/vestingplan"
+	"gocryptotrader/exchanges/account"
+	"gocryptotrader/exchanges/forward"
+	"gocryptotrader/exchanges/rpcpool"
+	"gocryptotrader/log"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/shopspring/decimal"
+)
+
+// New 创建一个新的归属计划管理器，释放转账通过 RPC 节点池发送，由 signer 签名
+func New(cfg *config.Config, pool *rpcpool.Pool, signer forward.Signer) *Manager {
+	return &Manager{
+		forward:  forward.New(cfg, pool),
+		accounts: account.New(cfg),
+		signer:   signer,
+	}
+}
+
+// CreatePlan 校验并保存新的归属计划，total 为代币数量，按铸币精度换算为最小单位后保存；
+// StartAt 为零值时从当前时间开始归属
+func (m *Manager) CreatePlan(ctx context.Context, p *vestingplan.Plan, total float64) error {
+	decimals, err := m.forward.TokenDecimals(ctx, p.TokenMint)
+	if err != nil {
+		return err
+	}
+	units, err := forward.ToBaseUnits(total, decimals)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errInvalidTotal, err)
+	}
+	p.Decimals, p.TotalAmount = decimals, units
+	if err := validatePlan(p); err != nil {
+		return err
+	}
+	if _, err := m.accounts.GetAccountByID(p.AccountID); err != nil {
+		return fmt.Errorf("受益账户 %d: %w", p.AccountID, err)
+	}
+	if p.StartAt.IsZero() {
+		p.StartAt = time.Now().UTC()
+	}
+	p.ReleasedAmount = 0
+	p.Status = vestingplan.StatusActive
+	return vestingplan.InsertPlan(p)
+}
+
+// Vested 返回计划在 t 时已归属的数量（最小单位）：悬崖期结束前为 0，此后按已经过的
+// 完整周期线性归属并向下取整，最后一个周期结束时归属全部数量
+func Vested(p *vestingplan.Plan, t time.Time) uint64 {
+	if p.Period <= 0 || p.Periods <= 0 || t.Before(p.StartAt.Add(p.Cliff)) || t.Before(p.StartAt) {
+		return 0
+	}
+	elapsed := int64(t.Sub(p.StartAt) / p.Period)
+	if elapsed >= int64(p.Periods) {
+		return p.TotalAmount
+	}
+	vested, _ := decimal.NewFromUint64(p.TotalAmount).Mul(decimal.NewFromInt(elapsed)).QuoRem(decimal.NewFromInt(int64(p.Periods)), 0)
+	return vested.BigInt().Uint64()
+}
+
+// NextVestingAt 返回 t 之后下一次有新数量归属的时间，全部归属后返回零值
+func NextVestingAt(p *vestingplan.Plan, t time.Time) time.Time {
+	if p.Period <= 0 || p.Periods <= 0 || !t.Before(p.StartAt.Add(p.Period*time.Duration(p.Periods))) {
+		return time.Time{}
+	}
+	next := int64(1)
+	if !t.Before(p.StartAt) {
+		next = int64(t.Sub(p.StartAt)/p.Period) + 1
+	}
+	at := p.StartAt.Add(p.Period * time.Duration(next))
+	if cliffEnd := p.StartAt.Add(p.Cliff); at.Before(cliffEnd) {
+		// 悬崖期内到期的周期在悬崖期结束时一并归属
+		return cliffEnd
+	}
+	return at
+}
+
+// GetProgress 返回计划在 t 时的归属进度
+func GetProgress(p *vestingplan.Plan, t time.Time) Progress {
+	progress := Progress{Vested: Vested(p, t), NextVestingAt: NextVestingAt(p, t)}
+	if progress.Vested > p.ReleasedAmount {
+		progress.Releasable = progress.Vested - p.ReleasedAmount
+	}
+	return progress
+}
+
+// Release 释放计划当前已归属但尚未释放的数量：先查询上一次释放的链上结果，
+// 再将应释放的最小单位数量转给受益账户。释放记录在签名后、发送前保存签名，
+// 确认后在同一事务中累加计划的已释放数量，进程中断也不会重复支付
+func (m *Manager) Release(ctx context.Context, id string, cfg *forward.Config) (*vestingplan.Release, error) {
+	if err := lock(id); err != nil {
+		return nil, err
+	}
+	defer unlock(id)
+
+	plan, err := vestingplan.GetPlan(id)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Status != vestingplan.StatusActive {
+		return nil, fmt.Errorf("%w: %s", ErrPlanInactive, id)
+	}
+	if err = m.reconcile(ctx, plan, cfg); err != nil {
+		return nil, err
+	}
+
+	due := GetProgress(plan, time.Now()).Releasable
+	if due == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNothingDue, id)
+	}
+
+	beneficiary, err := m.accounts.GetAccountByID(plan.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("获取受益账户 %d 失败: %w", plan.AccountID, err)
+	}
+	release := &vestingplan.Release{
+		PlanID:   plan.ID,
+		Amount:   due,
+		Decimals: plan.Decimals,
+		Status:   string(forward.StatusPending),
+	}
+	if err = vestingplan.InsertRelease(release); err != nil {
+		return nil, err
+	}
+	observer := &releaseObserver{plan: plan, release: release}
+	result, err := m.forward.TransferToken(ctx, &forward.TokenForwardRequest{
+		From:      plan.SourceAddress,
+		Signer:    m.signer,
+		TokenMint: plan.TokenMint,
+		Recipients: []forward.Recipient{{
+			Address: beneficiary.Address,
+			Amount:  forward.FromBaseUnits(due, plan.Decimals),
+			Units:   due,
+			Label:   "vesting " + plan.ID,
+		}},
+		Config:   cfg,
+		Observer: observer,
+	})
+	if err == nil && len(result.Skipped) > 0 {
+		err = fmt.Errorf("受益账户地址无效: %s", beneficiary.Address)
+	}
+	if err != nil {
+		if release.Status == string(forward.StatusPending) {
+			// 交易未签名发送，资金未转出
+			release.Status, release.Error = string(forward.StatusFailed), err.Error()
+			if uErr := vestingplan.UpdateRelease(release); uErr != nil {
+				log.Errorf(log.Global, "记录归属计划 %s 的释放失败: %v", plan.ID, uErr)
+			}
+		}
+		return release, err
+	}
+	return release, nil
+}
+
+// ReleaseDue 释放所有进行中且有可释放数量的计划，单个计划失败不影响其他计划
+func (m *Manager) ReleaseDue(ctx context.Context, cfg *forward.Config) ([]*vestingplan.Release, error) {
+	plans, err := vestingplan.ListPlans(0, vestingplan.StatusActive)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var releases []*vestingplan.Release
+	var errs error
+	for i := range plans {
+		// 已发送但未结算的释放不计入已释放数量，这类计划的可释放数量仍大于 0，会在这里完成结算
+		if GetProgress(&plans[i], now).Releasable == 0 {
+			continue
+		}
+		release, err := m.Release(ctx, plans[i].ID, cfg)
+		if release != nil {
+			releases = append(releases, release)
+		}
+		if err != nil && !errors.Is(err, ErrNothingDue) {
+			errs = common.AppendError(errs, fmt.Errorf("归属计划 %s: %w", plans[i].ID, err))
+		}
+	}
+	return releases, errs
+}
+
+// reconcile 结算计划中已发送的释放：查询链上结果，确认的累加到已释放数量，
+// 失败或过期的标记为失败；仍未确定结果时返回 ErrReleaseInFlight。
+// 未签名就中断的释放没有发送交易，直接标记为失败
+func (m *Manager) reconcile(ctx context.Context, plan *vestingplan.Plan, cfg *forward.Config) error {
+	for i := range plan.Releases {
+		r := &plan.Releases[i]
+		switch forward.Status(r.Status) {
+		case forward.StatusPending:
+			r.Status, r.Error = string(forward.StatusFailed), "释放在签名前中断"
+			if err := vestingplan.UpdateRelease(r); err != nil {
+				return err
+			}
+		case forward.StatusSent:
+			b := &forward.Batch{
+				Signature:            r.Signature,
+				LastValidBlockHeight: r.LastValidBlockHeight,
+				Status:               forward.StatusSent,
+			}
+			if err := m.forward.Reconcile(ctx, cfg, []*forward.Batch{b}); err != nil {
+				return err
+			}
+			observer := &releaseObserver{plan: plan, release: r}
+			if err := observer.record(b); err != nil {
+				return err
+			}
+			if b.Status == forward.StatusSent {
+				return fmt.Errorf("%w: %s", ErrReleaseInFlight, r.Signature)
+			}
+		}
+	}
+	return nil
+}
+
+// releaseObserver 将释放交易的签名和链上状态保存到释放记录
+type releaseObserver struct {
+	plan    *vestingplan.Plan
+	release *vestingplan.Release
+}
+
+// BatchSigned 在交易发送前保存签名
+func (o *releaseObserver) BatchSigned(b *forward.Batch) error {
+	return o.record(b)
+}
+
+// BatchUpdated 保存交易的发送或确认结果
+func (o *releaseObserver) BatchUpdated(b *forward.Batch) {
+	if err := o.record(b); err != nil {
+		log.Errorf(log.Global, "记录归属计划 %s 的释放 %d 失败: %v", o.plan.ID, o.release.ID, err)
+	}
+}
+
+// record 保存批次状态，确认时在同一事务中累加计划的已释放数量，全部释放后结束计划
+func (o *releaseObserver) record(b *forward.Batch) error {
+	r := o.release
+	r.Signature = b.Signature
+	r.LastValidBlockHeight = b.LastValidBlockHeight
+	r.Status = string(b.Status)
+	r.Error = ""
+	if b.Err != nil {
+		r.Error = b.Err.Error()
+	}
+	if b.Status != forward.StatusConfirmed {
+		return vestingplan.UpdateRelease(r)
+	}
+
+	released := o.plan.ReleasedAmount + r.Amount
+	status := vestingplan.StatusActive
+	if released >= o.plan.TotalAmount {
+		status = vestingplan.StatusCompleted
+	}
+	if err := vestingplan.SettleRelease(r, released, status); err != nil {
+		return err
+	}
+	o.plan.ReleasedAmount, o.plan.Status = released, status
+	return nil
+}
+
+// validatePlan 校验计划的地址和归属参数
+func validatePlan(p *vestingplan.Plan) error {
+	if p.SourceAddress == "" {
+		return errSourceUnset
+	}
+	if _, err := solana.PublicKeyFromBase58(p.SourceAddress); err != nil {
+		return fmt.Errorf("无效的发放地址: %w", err)
+	}
+	if _, err := solana.PublicKeyFromBase58(p.TokenMint); err != nil {
+		return fmt.Errorf("无效的代币铸币地址: %w", err)
+	}
+	if p.TotalAmount == 0 {
+		return errInvalidTotal
+	}
+	if p.TotalAmount > math.MaxInt64 {
+		return errTotalTooLarge
+	}
+	if p.Period < time.Second {
+		return errInvalidPeriod
+	}
+	if p.Periods <= 0 {
+		return errInvalidPeriods
+	}
+	if p.Cliff < 0 {
+		return errInvalidCliff
+	}
+	return nil
+}
+
+func lock(id string) error {
+	releasing.Lock()
+	defer releasing.Unlock()
+	if _, ok := releasing.ids[id]; ok {
+		return fmt.Errorf("%w: %s", ErrPlanReleasing, id)
+	}
+	releasing.ids[id] = struct{}{}
+	return nil
+}
+
+func unlock(id string) {
+	releasing.Lock()
+	delete(releasing.ids, id)
+	releasing.Unlock()
+}
@@ -0,0 +1,112 @@
+package vesting
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gocryptotrader/database/repository/vestingplan"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testSource = "7mXrxq4A6LJKsdHDCvj4PCdF3tvQs8rdUKFWLdEicxXW"
+	testMint   = "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"
+)
+
+var testStart = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func testPlan() *vestingplan.Plan {
+	return &vestingplan.Plan{
+		SourceAddress: testSource,
+		TokenMint:     testMint,
+		Decimals:      6,
+		TotalAmount:   1000000000,
+		StartAt:       testStart,
+		Cliff:         3 * time.Hour,
+		Period:        time.Hour,
+		Periods:       3,
+	}
+}
+
+func TestVested(t *testing.T) {
+	t.Parallel()
+
+	p := testPlan()
+	p.Cliff = 90 * time.Minute
+	assert.Zero(t, Vested(p, testStart.Add(-time.Hour)), "nothing should vest before the start")
+	assert.Zero(t, Vested(p, testStart.Add(89*time.Minute)), "nothing should vest during the cliff")
+	assert.Equal(t, uint64(333333333), Vested(p, testStart.Add(90*time.Minute)), "periods completed during the cliff should vest when it ends")
+	assert.Equal(t, uint64(666666666), Vested(p, testStart.Add(2*time.Hour)), "vested base units should be rounded down")
+	assert.Equal(t, uint64(1000000000), Vested(p, testStart.Add(3*time.Hour)), "the final period should vest the exact total")
+	assert.Equal(t, uint64(1000000000), Vested(p, testStart.Add(100*time.Hour)), "vesting should not exceed the total")
+
+	// 超出 float64 精度的总量也按最小单位精确归属
+	p.TotalAmount = math.MaxInt64
+	assert.Equal(t, uint64(math.MaxInt64/3), Vested(p, testStart.Add(90*time.Minute)))
+	assert.Equal(t, uint64(math.MaxInt64), Vested(p, testStart.Add(3*time.Hour)))
+
+	p.Period = 0
+	assert.Zero(t, Vested(p, testStart.Add(time.Hour)), "an invalid period should vest nothing")
+}
+
+func TestNextVestingAt(t *testing.T) {
+	t.Parallel()
+
+	p := testPlan()
+	p.Cliff = 90 * time.Minute
+	assert.Equal(t, testStart.Add(90*time.Minute), NextVestingAt(p, testStart.Add(-time.Hour)), "the first vesting should be at the end of the cliff")
+	assert.Equal(t, testStart.Add(2*time.Hour), NextVestingAt(p, testStart.Add(90*time.Minute)))
+	assert.Equal(t, testStart.Add(3*time.Hour), NextVestingAt(p, testStart.Add(2*time.Hour)), "a vesting boundary should move to the next period")
+	assert.True(t, NextVestingAt(p, testStart.Add(3*time.Hour)).IsZero(), "a fully vested plan has no next vesting")
+
+	p.Cliff = 0
+	assert.Equal(t, testStart.Add(time.Hour), NextVestingAt(p, testStart), "without a cliff the first vesting is after one period")
+}
+
+func TestGetProgress(t *testing.T) {
+	t.Parallel()
+
+	p := testPlan()
+	p.Cliff = 0
+	p.ReleasedAmount = 333330000
+	progress := GetProgress(p, testStart.Add(90*time.Minute))
+	assert.Equal(t, uint64(333333333), progress.Vested)
+	assert.Equal(t, uint64(3333), progress.Releasable)
+	assert.Equal(t, testStart.Add(2*time.Hour), progress.NextVestingAt)
+
+	p.ReleasedAmount = 1000000000
+	progress = GetProgress(p, testStart.Add(time.Hour))
+	assert.Zero(t, progress.Releasable, "releasable should never be negative")
+}
+
+func TestValidatePlan(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, validatePlan(testPlan()))
+
+	for _, tc := range []struct {
+		name   string
+		modify func(*vestingplan.Plan)
+		err    error
+	}{
+		{"source unset", func(p *vestingplan.Plan) { p.SourceAddress = "" }, errSourceUnset},
+		{"zero total", func(p *vestingplan.Plan) { p.TotalAmount = 0 }, errInvalidTotal},
+		{"total too large", func(p *vestingplan.Plan) { p.TotalAmount = math.MaxInt64 + 1 }, errTotalTooLarge},
+		{"short period", func(p *vestingplan.Plan) { p.Period = time.Millisecond }, errInvalidPeriod},
+		{"no periods", func(p *vestingplan.Plan) { p.Periods = 0 }, errInvalidPeriods},
+		{"negative cliff", func(p *vestingplan.Plan) { p.Cliff = -time.Second }, errInvalidCliff},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			p := testPlan()
+			tc.modify(p)
+			assert.ErrorIs(t, validatePlan(p), tc.err)
+		})
+	}
+
+	p := testPlan()
+	p.TokenMint = "not-a-mint"
+	assert.Error(t, validatePlan(p), "validatePlan should reject an invalid mint")
+}
@@ -0,0 +1,48 @@
+package vesting
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"gocryptotrader/exchanges/account"
+	"gocryptotrader/exchanges/forward"
+)
+
+var (
+	// ErrNothingDue 计划当前没有已归属但尚未释放的数量
+	ErrNothingDue = errors.New("当前没有可释放的数量")
+	// ErrPlanInactive 计划已全部释放，不能再释放
+	ErrPlanInactive = errors.New("归属计划已结束")
+	// ErrReleaseInFlight 上一次释放的交易已发送但链上结果未确定，需要稍后重试
+	ErrReleaseInFlight = errors.New("上一次释放的交易结果尚未确定")
+	// ErrPlanReleasing 计划正在被另一个请求释放
+	ErrPlanReleasing = errors.New("归属计划正在释放")
+
+	errInvalidTotal   = errors.New("归属总量必须大于 0")
+	errTotalTooLarge  = errors.New("归属总量超出可保存的范围")
+	errInvalidPeriod  = errors.New("释放周期必须至少为一秒")
+	errInvalidPeriods = errors.New("释放周期数必须大于 0")
+	errInvalidCliff   = errors.New("悬崖期不能为负数")
+	errSourceUnset    = errors.New("未设置发放地址")
+)
+
+// Manager 计算归属计划的应释放数量，并通过代币转发释放给受益账户
+type Manager struct {
+	forward  *forward.Manager
+	accounts *account.Manager
+	signer   forward.Signer
+}
+
+// Progress 计划在某一时刻的归属进度，数量均为代币最小单位
+type Progress struct {
+	Vested        uint64    // 已归属的数量
+	Releasable    uint64    // 已归属但尚未释放的数量
+	NextVestingAt time.Time // 下一次有新数量归属的时间，全部归属后为零值
+}
+
+// releasing 记录正在释放的计划 ID，同一计划同时只允许一个释放，避免重复支付
+var releasing = struct {
+	sync.Mutex
+	ids map[string]struct{}
+}{ids: make(map[string]struct{})}
@@ -19,13 +19,48 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	GoCryptoTraderService_GetInfo_FullMethodName         = "/gctrpc.GoCryptoTraderService/GetInfo"
-	GoCryptoTraderService_GetRPCEndpoints_FullMethodName = "/gctrpc.GoCryptoTraderService/GetRPCEndpoints"
-	GoCryptoTraderService_GetAccounts_FullMethodName     = "/gctrpc.GoCryptoTraderService/GetAccounts"
-	GoCryptoTraderService_GetTokenPrice_FullMethodName   = "/gctrpc.GoCryptoTraderService/GetTokenPrice"
-	GoCryptoTraderService_Crypto_FullMethodName          = "/gctrpc.GoCryptoTraderService/Crypto"
-	GoCryptoTraderService_TransferSOL_FullMethodName     = "/gctrpc.GoCryptoTraderService/TransferSOL"
-	GoCryptoTraderService_TransferToken_FullMethodName   = "/gctrpc.GoCryptoTraderService/TransferToken"
+	GoCryptoTraderService_GetInfo_FullMethodName                  = "/gctrpc.GoCryptoTraderService/GetInfo"
+	GoCryptoTraderService_GetRPCEndpoints_FullMethodName          = "/gctrpc.GoCryptoTraderService/GetRPCEndpoints"
+	GoCryptoTraderService_GetSolanaRPCHealth_FullMethodName       = "/gctrpc.GoCryptoTraderService/GetSolanaRPCHealth"
+	GoCryptoTraderService_GetAccounts_FullMethodName              = "/gctrpc.GoCryptoTraderService/GetAccounts"
+	GoCryptoTraderService_CreateAccounts_FullMethodName           = "/gctrpc.GoCryptoTraderService/CreateAccounts"
+	GoCryptoTraderService_ImportAccount_FullMethodName            = "/gctrpc.GoCryptoTraderService/ImportAccount"
+	GoCryptoTraderService_GenerateMnemonic_FullMethodName         = "/gctrpc.GoCryptoTraderService/GenerateMnemonic"
+	GoCryptoTraderService_GetAccountTree_FullMethodName           = "/gctrpc.GoCryptoTraderService/GetAccountTree"
+	GoCryptoTraderService_SetAccountParent_FullMethodName         = "/gctrpc.GoCryptoTraderService/SetAccountParent"
+	GoCryptoTraderService_FundChildren_FullMethodName             = "/gctrpc.GoCryptoTraderService/FundChildren"
+	GoCryptoTraderService_SweepLeaves_FullMethodName              = "/gctrpc.GoCryptoTraderService/SweepLeaves"
+	GoCryptoTraderService_GetTokenPrice_FullMethodName            = "/gctrpc.GoCryptoTraderService/GetTokenPrice"
+	GoCryptoTraderService_Crypto_FullMethodName                   = "/gctrpc.GoCryptoTraderService/Crypto"
+	GoCryptoTraderService_MigrateAccountCiphers_FullMethodName    = "/gctrpc.GoCryptoTraderService/MigrateAccountCiphers"
+	GoCryptoTraderService_ValidateRecipients_FullMethodName       = "/gctrpc.GoCryptoTraderService/ValidateRecipients"
+	GoCryptoTraderService_TransferSOL_FullMethodName              = "/gctrpc.GoCryptoTraderService/TransferSOL"
+	GoCryptoTraderService_TransferToken_FullMethodName            = "/gctrpc.GoCryptoTraderService/TransferToken"
+	GoCryptoTraderService_TransferSOLStream_FullMethodName        = "/gctrpc.GoCryptoTraderService/TransferSOLStream"
+	GoCryptoTraderService_TransferTokenStream_FullMethodName      = "/gctrpc.GoCryptoTraderService/TransferTokenStream"
+	GoCryptoTraderService_ListTransferJobs_FullMethodName         = "/gctrpc.GoCryptoTraderService/ListTransferJobs"
+	GoCryptoTraderService_GetTransferJob_FullMethodName           = "/gctrpc.GoCryptoTraderService/GetTransferJob"
+	GoCryptoTraderService_ResumeTransferJob_FullMethodName        = "/gctrpc.GoCryptoTraderService/ResumeTransferJob"
+	GoCryptoTraderService_FindTransfers_FullMethodName            = "/gctrpc.GoCryptoTraderService/FindTransfers"
+	GoCryptoTraderService_SweepAccounts_FullMethodName            = "/gctrpc.GoCryptoTraderService/SweepAccounts"
+	GoCryptoTraderService_ReclaimRent_FullMethodName              = "/gctrpc.GoCryptoTraderService/ReclaimRent"
+	GoCryptoTraderService_CreateNonceAccounts_FullMethodName      = "/gctrpc.GoCryptoTraderService/CreateNonceAccounts"
+	GoCryptoTraderService_ListNonceAccounts_FullMethodName        = "/gctrpc.GoCryptoTraderService/ListNonceAccounts"
+	GoCryptoTraderService_CloseNonceAccounts_FullMethodName       = "/gctrpc.GoCryptoTraderService/CloseNonceAccounts"
+	GoCryptoTraderService_SubmitSignedTransactions_FullMethodName = "/gctrpc.GoCryptoTraderService/SubmitSignedTransactions"
+	GoCryptoTraderService_CreateTransferSchedule_FullMethodName   = "/gctrpc.GoCryptoTraderService/CreateTransferSchedule"
+	GoCryptoTraderService_UpdateTransferSchedule_FullMethodName   = "/gctrpc.GoCryptoTraderService/UpdateTransferSchedule"
+	GoCryptoTraderService_GetTransferSchedule_FullMethodName      = "/gctrpc.GoCryptoTraderService/GetTransferSchedule"
+	GoCryptoTraderService_ListTransferSchedules_FullMethodName    = "/gctrpc.GoCryptoTraderService/ListTransferSchedules"
+	GoCryptoTraderService_DeleteTransferSchedule_FullMethodName   = "/gctrpc.GoCryptoTraderService/DeleteTransferSchedule"
+	GoCryptoTraderService_PauseTransferSchedule_FullMethodName    = "/gctrpc.GoCryptoTraderService/PauseTransferSchedule"
+	GoCryptoTraderService_ResumeTransferSchedule_FullMethodName   = "/gctrpc.GoCryptoTraderService/ResumeTransferSchedule"
+	GoCryptoTraderService_CreateVestingPlan_FullMethodName        = "/gctrpc.GoCryptoTraderService/CreateVestingPlan"
+	GoCryptoTraderService_GetVestingPlan_FullMethodName           = "/gctrpc.GoCryptoTraderService/GetVestingPlan"
+	GoCryptoTraderService_ListVestingPlans_FullMethodName         = "/gctrpc.GoCryptoTraderService/ListVestingPlans"
+	GoCryptoTraderService_ReleaseVestingPlan_FullMethodName       = "/gctrpc.GoCryptoTraderService/ReleaseVestingPlan"
+	GoCryptoTraderService_WrapSOL_FullMethodName                  = "/gctrpc.GoCryptoTraderService/WrapSOL"
+	GoCryptoTraderService_UnwrapSOL_FullMethodName                = "/gctrpc.GoCryptoTraderService/UnwrapSOL"
 )
 
 // GoCryptoTraderServiceClient is the client API for GoCryptoTraderService service.
@@ -34,11 +69,46 @@ const (
 type GoCryptoTraderServiceClient interface {
 	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
 	GetRPCEndpoints(ctx context.Context, in *GetRPCEndpointsRequest, opts ...grpc.CallOption) (*GetRPCEndpointsResponse, error)
+	GetSolanaRPCHealth(ctx context.Context, in *GetSolanaRPCHealthRequest, opts ...grpc.CallOption) (*GetSolanaRPCHealthResponse, error)
 	GetAccounts(ctx context.Context, in *GetAccountsRequest, opts ...grpc.CallOption) (*GetAccountsResponse, error)
+	CreateAccounts(ctx context.Context, in *CreateAccountsRequest, opts ...grpc.CallOption) (*CreateAccountsResponse, error)
+	ImportAccount(ctx context.Context, in *ImportAccountRequest, opts ...grpc.CallOption) (*CreatedAccount, error)
+	GenerateMnemonic(ctx context.Context, in *GenerateMnemonicRequest, opts ...grpc.CallOption) (*GenerateMnemonicResponse, error)
+	GetAccountTree(ctx context.Context, in *GetAccountTreeRequest, opts ...grpc.CallOption) (*GetAccountTreeResponse, error)
+	SetAccountParent(ctx context.Context, in *SetAccountParentRequest, opts ...grpc.CallOption) (*Account, error)
+	FundChildren(ctx context.Context, in *FundChildrenRequest, opts ...grpc.CallOption) (*TransferSOLResponse, error)
+	SweepLeaves(ctx context.Context, in *SweepLeavesRequest, opts ...grpc.CallOption) (*SweepLeavesResponse, error)
 	GetTokenPrice(ctx context.Context, in *GetTokenPriceRequest, opts ...grpc.CallOption) (*GetTokenPriceResponse, error)
 	Crypto(ctx context.Context, in *CryptoRequest, opts ...grpc.CallOption) (*CryptoResponse, error)
+	MigrateAccountCiphers(ctx context.Context, in *MigrateAccountCiphersRequest, opts ...grpc.CallOption) (*MigrateAccountCiphersResponse, error)
+	ValidateRecipients(ctx context.Context, in *ValidateRecipientsRequest, opts ...grpc.CallOption) (*RecipientValidation, error)
 	TransferSOL(ctx context.Context, in *TransferSOLRequest, opts ...grpc.CallOption) (*TransferSOLResponse, error)
 	TransferToken(ctx context.Context, in *TransferTokenRequest, opts ...grpc.CallOption) (*TransferTokenResponse, error)
+	TransferSOLStream(ctx context.Context, in *TransferSOLRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TransferProgress], error)
+	TransferTokenStream(ctx context.Context, in *TransferTokenRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TransferProgress], error)
+	ListTransferJobs(ctx context.Context, in *ListTransferJobsRequest, opts ...grpc.CallOption) (*ListTransferJobsResponse, error)
+	GetTransferJob(ctx context.Context, in *GetTransferJobRequest, opts ...grpc.CallOption) (*GetTransferJobResponse, error)
+	ResumeTransferJob(ctx context.Context, in *ResumeTransferJobRequest, opts ...grpc.CallOption) (*ResumeTransferJobResponse, error)
+	FindTransfers(ctx context.Context, in *FindTransfersRequest, opts ...grpc.CallOption) (*FindTransfersResponse, error)
+	SweepAccounts(ctx context.Context, in *SweepAccountsRequest, opts ...grpc.CallOption) (*SweepAccountsResponse, error)
+	ReclaimRent(ctx context.Context, in *ReclaimRentRequest, opts ...grpc.CallOption) (*ReclaimRentResponse, error)
+	CreateNonceAccounts(ctx context.Context, in *CreateNonceAccountsRequest, opts ...grpc.CallOption) (*NonceAccountsResponse, error)
+	ListNonceAccounts(ctx context.Context, in *ListNonceAccountsRequest, opts ...grpc.CallOption) (*NonceAccountsResponse, error)
+	CloseNonceAccounts(ctx context.Context, in *CloseNonceAccountsRequest, opts ...grpc.CallOption) (*NonceAccountsResponse, error)
+	SubmitSignedTransactions(ctx context.Context, in *SubmitSignedTransactionsRequest, opts ...grpc.CallOption) (*SubmitSignedTransactionsResponse, error)
+	CreateTransferSchedule(ctx context.Context, in *CreateTransferScheduleRequest, opts ...grpc.CallOption) (*TransferScheduleResponse, error)
+	UpdateTransferSchedule(ctx context.Context, in *UpdateTransferScheduleRequest, opts ...grpc.CallOption) (*TransferScheduleResponse, error)
+	GetTransferSchedule(ctx context.Context, in *GetTransferScheduleRequest, opts ...grpc.CallOption) (*TransferScheduleResponse, error)
+	ListTransferSchedules(ctx context.Context, in *ListTransferSchedulesRequest, opts ...grpc.CallOption) (*ListTransferSchedulesResponse, error)
+	DeleteTransferSchedule(ctx context.Context, in *DeleteTransferScheduleRequest, opts ...grpc.CallOption) (*DeleteTransferScheduleResponse, error)
+	PauseTransferSchedule(ctx context.Context, in *PauseTransferScheduleRequest, opts ...grpc.CallOption) (*TransferScheduleResponse, error)
+	ResumeTransferSchedule(ctx context.Context, in *ResumeTransferScheduleRequest, opts ...grpc.CallOption) (*TransferScheduleResponse, error)
+	CreateVestingPlan(ctx context.Context, in *CreateVestingPlanRequest, opts ...grpc.CallOption) (*VestingPlanResponse, error)
+	GetVestingPlan(ctx context.Context, in *GetVestingPlanRequest, opts ...grpc.CallOption) (*VestingPlanResponse, error)
+	ListVestingPlans(ctx context.Context, in *ListVestingPlansRequest, opts ...grpc.CallOption) (*ListVestingPlansResponse, error)
+	ReleaseVestingPlan(ctx context.Context, in *ReleaseVestingPlanRequest, opts ...grpc.CallOption) (*ReleaseVestingPlanResponse, error)
+	WrapSOL(ctx context.Context, in *WrapSOLRequest, opts ...grpc.CallOption) (*WrappedSOLResponse, error)
+	UnwrapSOL(ctx context.Context, in *UnwrapSOLRequest, opts ...grpc.CallOption) (*WrappedSOLResponse, error)
 }
 
 type goCryptoTraderServiceClient struct {
@@ -69,6 +139,16 @@ func (c *goCryptoTraderServiceClient) GetRPCEndpoints(ctx context.Context, in *G
 	return out, nil
 }
 
+func (c *goCryptoTraderServiceClient) GetSolanaRPCHealth(ctx context.Context, in *GetSolanaRPCHealthRequest, opts ...grpc.CallOption) (*GetSolanaRPCHealthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSolanaRPCHealthResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_GetSolanaRPCHealth_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *goCryptoTraderServiceClient) GetAccounts(ctx context.Context, in *GetAccountsRequest, opts ...grpc.CallOption) (*GetAccountsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetAccountsResponse)
@@ -79,6 +159,76 @@ func (c *goCryptoTraderServiceClient) GetAccounts(ctx context.Context, in *GetAc
 	return out, nil
 }
 
+func (c *goCryptoTraderServiceClient) CreateAccounts(ctx context.Context, in *CreateAccountsRequest, opts ...grpc.CallOption) (*CreateAccountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateAccountsResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_CreateAccounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) ImportAccount(ctx context.Context, in *ImportAccountRequest, opts ...grpc.CallOption) (*CreatedAccount, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreatedAccount)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_ImportAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) GenerateMnemonic(ctx context.Context, in *GenerateMnemonicRequest, opts ...grpc.CallOption) (*GenerateMnemonicResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerateMnemonicResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_GenerateMnemonic_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) GetAccountTree(ctx context.Context, in *GetAccountTreeRequest, opts ...grpc.CallOption) (*GetAccountTreeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAccountTreeResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_GetAccountTree_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) SetAccountParent(ctx context.Context, in *SetAccountParentRequest, opts ...grpc.CallOption) (*Account, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Account)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_SetAccountParent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) FundChildren(ctx context.Context, in *FundChildrenRequest, opts ...grpc.CallOption) (*TransferSOLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferSOLResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_FundChildren_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) SweepLeaves(ctx context.Context, in *SweepLeavesRequest, opts ...grpc.CallOption) (*SweepLeavesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SweepLeavesResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_SweepLeaves_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *goCryptoTraderServiceClient) GetTokenPrice(ctx context.Context, in *GetTokenPriceRequest, opts ...grpc.CallOption) (*GetTokenPriceResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetTokenPriceResponse)
@@ -99,6 +249,26 @@ func (c *goCryptoTraderServiceClient) Crypto(ctx context.Context, in *CryptoRequ
 	return out, nil
 }
 
+func (c *goCryptoTraderServiceClient) MigrateAccountCiphers(ctx context.Context, in *MigrateAccountCiphersRequest, opts ...grpc.CallOption) (*MigrateAccountCiphersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MigrateAccountCiphersResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_MigrateAccountCiphers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) ValidateRecipients(ctx context.Context, in *ValidateRecipientsRequest, opts ...grpc.CallOption) (*RecipientValidation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecipientValidation)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_ValidateRecipients_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *goCryptoTraderServiceClient) TransferSOL(ctx context.Context, in *TransferSOLRequest, opts ...grpc.CallOption) (*TransferSOLResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(TransferSOLResponse)
@@ -119,17 +289,320 @@ func (c *goCryptoTraderServiceClient) TransferToken(ctx context.Context, in *Tra
 	return out, nil
 }
 
+func (c *goCryptoTraderServiceClient) TransferSOLStream(ctx context.Context, in *TransferSOLRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TransferProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GoCryptoTraderService_ServiceDesc.Streams[0], GoCryptoTraderService_TransferSOLStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TransferSOLRequest, TransferProgress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GoCryptoTraderService_TransferSOLStreamClient = grpc.ServerStreamingClient[TransferProgress]
+
+func (c *goCryptoTraderServiceClient) TransferTokenStream(ctx context.Context, in *TransferTokenRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TransferProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GoCryptoTraderService_ServiceDesc.Streams[1], GoCryptoTraderService_TransferTokenStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TransferTokenRequest, TransferProgress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GoCryptoTraderService_TransferTokenStreamClient = grpc.ServerStreamingClient[TransferProgress]
+
+func (c *goCryptoTraderServiceClient) ListTransferJobs(ctx context.Context, in *ListTransferJobsRequest, opts ...grpc.CallOption) (*ListTransferJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTransferJobsResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_ListTransferJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) GetTransferJob(ctx context.Context, in *GetTransferJobRequest, opts ...grpc.CallOption) (*GetTransferJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTransferJobResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_GetTransferJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) ResumeTransferJob(ctx context.Context, in *ResumeTransferJobRequest, opts ...grpc.CallOption) (*ResumeTransferJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResumeTransferJobResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_ResumeTransferJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) FindTransfers(ctx context.Context, in *FindTransfersRequest, opts ...grpc.CallOption) (*FindTransfersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FindTransfersResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_FindTransfers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) SweepAccounts(ctx context.Context, in *SweepAccountsRequest, opts ...grpc.CallOption) (*SweepAccountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SweepAccountsResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_SweepAccounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) ReclaimRent(ctx context.Context, in *ReclaimRentRequest, opts ...grpc.CallOption) (*ReclaimRentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReclaimRentResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_ReclaimRent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) CreateNonceAccounts(ctx context.Context, in *CreateNonceAccountsRequest, opts ...grpc.CallOption) (*NonceAccountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NonceAccountsResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_CreateNonceAccounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) ListNonceAccounts(ctx context.Context, in *ListNonceAccountsRequest, opts ...grpc.CallOption) (*NonceAccountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NonceAccountsResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_ListNonceAccounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) CloseNonceAccounts(ctx context.Context, in *CloseNonceAccountsRequest, opts ...grpc.CallOption) (*NonceAccountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NonceAccountsResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_CloseNonceAccounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) SubmitSignedTransactions(ctx context.Context, in *SubmitSignedTransactionsRequest, opts ...grpc.CallOption) (*SubmitSignedTransactionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitSignedTransactionsResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_SubmitSignedTransactions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) CreateTransferSchedule(ctx context.Context, in *CreateTransferScheduleRequest, opts ...grpc.CallOption) (*TransferScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferScheduleResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_CreateTransferSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) UpdateTransferSchedule(ctx context.Context, in *UpdateTransferScheduleRequest, opts ...grpc.CallOption) (*TransferScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferScheduleResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_UpdateTransferSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) GetTransferSchedule(ctx context.Context, in *GetTransferScheduleRequest, opts ...grpc.CallOption) (*TransferScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferScheduleResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_GetTransferSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) ListTransferSchedules(ctx context.Context, in *ListTransferSchedulesRequest, opts ...grpc.CallOption) (*ListTransferSchedulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTransferSchedulesResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_ListTransferSchedules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) DeleteTransferSchedule(ctx context.Context, in *DeleteTransferScheduleRequest, opts ...grpc.CallOption) (*DeleteTransferScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteTransferScheduleResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_DeleteTransferSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) PauseTransferSchedule(ctx context.Context, in *PauseTransferScheduleRequest, opts ...grpc.CallOption) (*TransferScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferScheduleResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_PauseTransferSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) ResumeTransferSchedule(ctx context.Context, in *ResumeTransferScheduleRequest, opts ...grpc.CallOption) (*TransferScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferScheduleResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_ResumeTransferSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) CreateVestingPlan(ctx context.Context, in *CreateVestingPlanRequest, opts ...grpc.CallOption) (*VestingPlanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VestingPlanResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_CreateVestingPlan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) GetVestingPlan(ctx context.Context, in *GetVestingPlanRequest, opts ...grpc.CallOption) (*VestingPlanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VestingPlanResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_GetVestingPlan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) ListVestingPlans(ctx context.Context, in *ListVestingPlansRequest, opts ...grpc.CallOption) (*ListVestingPlansResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVestingPlansResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_ListVestingPlans_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) ReleaseVestingPlan(ctx context.Context, in *ReleaseVestingPlanRequest, opts ...grpc.CallOption) (*ReleaseVestingPlanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReleaseVestingPlanResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_ReleaseVestingPlan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) WrapSOL(ctx context.Context, in *WrapSOLRequest, opts ...grpc.CallOption) (*WrappedSOLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WrappedSOLResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_WrapSOL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goCryptoTraderServiceClient) UnwrapSOL(ctx context.Context, in *UnwrapSOLRequest, opts ...grpc.CallOption) (*WrappedSOLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WrappedSOLResponse)
+	err := c.cc.Invoke(ctx, GoCryptoTraderService_UnwrapSOL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GoCryptoTraderServiceServer is the server API for GoCryptoTraderService service.
 // All implementations must embed UnimplementedGoCryptoTraderServiceServer
 // for forward compatibility.
 type GoCryptoTraderServiceServer interface {
 	GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error)
 	GetRPCEndpoints(context.Context, *GetRPCEndpointsRequest) (*GetRPCEndpointsResponse, error)
+	GetSolanaRPCHealth(context.Context, *GetSolanaRPCHealthRequest) (*GetSolanaRPCHealthResponse, error)
 	GetAccounts(context.Context, *GetAccountsRequest) (*GetAccountsResponse, error)
+	CreateAccounts(context.Context, *CreateAccountsRequest) (*CreateAccountsResponse, error)
+	ImportAccount(context.Context, *ImportAccountRequest) (*CreatedAccount, error)
+	GenerateMnemonic(context.Context, *GenerateMnemonicRequest) (*GenerateMnemonicResponse, error)
+	GetAccountTree(context.Context, *GetAccountTreeRequest) (*GetAccountTreeResponse, error)
+	SetAccountParent(context.Context, *SetAccountParentRequest) (*Account, error)
+	FundChildren(context.Context, *FundChildrenRequest) (*TransferSOLResponse, error)
+	SweepLeaves(context.Context, *SweepLeavesRequest) (*SweepLeavesResponse, error)
 	GetTokenPrice(context.Context, *GetTokenPriceRequest) (*GetTokenPriceResponse, error)
 	Crypto(context.Context, *CryptoRequest) (*CryptoResponse, error)
+	MigrateAccountCiphers(context.Context, *MigrateAccountCiphersRequest) (*MigrateAccountCiphersResponse, error)
+	ValidateRecipients(context.Context, *ValidateRecipientsRequest) (*RecipientValidation, error)
 	TransferSOL(context.Context, *TransferSOLRequest) (*TransferSOLResponse, error)
 	TransferToken(context.Context, *TransferTokenRequest) (*TransferTokenResponse, error)
+	TransferSOLStream(*TransferSOLRequest, grpc.ServerStreamingServer[TransferProgress]) error
+	TransferTokenStream(*TransferTokenRequest, grpc.ServerStreamingServer[TransferProgress]) error
+	ListTransferJobs(context.Context, *ListTransferJobsRequest) (*ListTransferJobsResponse, error)
+	GetTransferJob(context.Context, *GetTransferJobRequest) (*GetTransferJobResponse, error)
+	ResumeTransferJob(context.Context, *ResumeTransferJobRequest) (*ResumeTransferJobResponse, error)
+	FindTransfers(context.Context, *FindTransfersRequest) (*FindTransfersResponse, error)
+	SweepAccounts(context.Context, *SweepAccountsRequest) (*SweepAccountsResponse, error)
+	ReclaimRent(context.Context, *ReclaimRentRequest) (*ReclaimRentResponse, error)
+	CreateNonceAccounts(context.Context, *CreateNonceAccountsRequest) (*NonceAccountsResponse, error)
+	ListNonceAccounts(context.Context, *ListNonceAccountsRequest) (*NonceAccountsResponse, error)
+	CloseNonceAccounts(context.Context, *CloseNonceAccountsRequest) (*NonceAccountsResponse, error)
+	SubmitSignedTransactions(context.Context, *SubmitSignedTransactionsRequest) (*SubmitSignedTransactionsResponse, error)
+	CreateTransferSchedule(context.Context, *CreateTransferScheduleRequest) (*TransferScheduleResponse, error)
+	UpdateTransferSchedule(context.Context, *UpdateTransferScheduleRequest) (*TransferScheduleResponse, error)
+	GetTransferSchedule(context.Context, *GetTransferScheduleRequest) (*TransferScheduleResponse, error)
+	ListTransferSchedules(context.Context, *ListTransferSchedulesRequest) (*ListTransferSchedulesResponse, error)
+	DeleteTransferSchedule(context.Context, *DeleteTransferScheduleRequest) (*DeleteTransferScheduleResponse, error)
+	PauseTransferSchedule(context.Context, *PauseTransferScheduleRequest) (*TransferScheduleResponse, error)
+	ResumeTransferSchedule(context.Context, *ResumeTransferScheduleRequest) (*TransferScheduleResponse, error)
+	CreateVestingPlan(context.Context, *CreateVestingPlanRequest) (*VestingPlanResponse, error)
+	GetVestingPlan(context.Context, *GetVestingPlanRequest) (*VestingPlanResponse, error)
+	ListVestingPlans(context.Context, *ListVestingPlansRequest) (*ListVestingPlansResponse, error)
+	ReleaseVestingPlan(context.Context, *ReleaseVestingPlanRequest) (*ReleaseVestingPlanResponse, error)
+	WrapSOL(context.Context, *WrapSOLRequest) (*WrappedSOLResponse, error)
+	UnwrapSOL(context.Context, *UnwrapSOLRequest) (*WrappedSOLResponse, error)
 	mustEmbedUnimplementedGoCryptoTraderServiceServer()
 }
 
@@ -146,164 +619,885 @@ func (UnimplementedGoCryptoTraderServiceServer) GetInfo(context.Context, *GetInf
 func (UnimplementedGoCryptoTraderServiceServer) GetRPCEndpoints(context.Context, *GetRPCEndpointsRequest) (*GetRPCEndpointsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRPCEndpoints not implemented")
 }
+func (UnimplementedGoCryptoTraderServiceServer) GetSolanaRPCHealth(context.Context, *GetSolanaRPCHealthRequest) (*GetSolanaRPCHealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSolanaRPCHealth not implemented")
+}
 func (UnimplementedGoCryptoTraderServiceServer) GetAccounts(context.Context, *GetAccountsRequest) (*GetAccountsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAccounts not implemented")
 }
-func (UnimplementedGoCryptoTraderServiceServer) GetTokenPrice(context.Context, *GetTokenPriceRequest) (*GetTokenPriceResponse, error) {
+func (UnimplementedGoCryptoTraderServiceServer) CreateAccounts(context.Context, *CreateAccountsRequest) (*CreateAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAccounts not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) ImportAccount(context.Context, *ImportAccountRequest) (*CreatedAccount, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportAccount not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) GenerateMnemonic(context.Context, *GenerateMnemonicRequest) (*GenerateMnemonicResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateMnemonic not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) GetAccountTree(context.Context, *GetAccountTreeRequest) (*GetAccountTreeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountTree not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) SetAccountParent(context.Context, *SetAccountParentRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAccountParent not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) FundChildren(context.Context, *FundChildrenRequest) (*TransferSOLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FundChildren not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) SweepLeaves(context.Context, *SweepLeavesRequest) (*SweepLeavesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SweepLeaves not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) GetTokenPrice(context.Context, *GetTokenPriceRequest) (*GetTokenPriceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetTokenPrice not implemented")
 }
-func (UnimplementedGoCryptoTraderServiceServer) Crypto(context.Context, *CryptoRequest) (*CryptoResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Crypto not implemented")
+func (UnimplementedGoCryptoTraderServiceServer) Crypto(context.Context, *CryptoRequest) (*CryptoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Crypto not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) MigrateAccountCiphers(context.Context, *MigrateAccountCiphersRequest) (*MigrateAccountCiphersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MigrateAccountCiphers not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) ValidateRecipients(context.Context, *ValidateRecipientsRequest) (*RecipientValidation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateRecipients not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) TransferSOL(context.Context, *TransferSOLRequest) (*TransferSOLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferSOL not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) TransferToken(context.Context, *TransferTokenRequest) (*TransferTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferToken not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) TransferSOLStream(*TransferSOLRequest, grpc.ServerStreamingServer[TransferProgress]) error {
+	return status.Errorf(codes.Unimplemented, "method TransferSOLStream not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) TransferTokenStream(*TransferTokenRequest, grpc.ServerStreamingServer[TransferProgress]) error {
+	return status.Errorf(codes.Unimplemented, "method TransferTokenStream not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) ListTransferJobs(context.Context, *ListTransferJobsRequest) (*ListTransferJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTransferJobs not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) GetTransferJob(context.Context, *GetTransferJobRequest) (*GetTransferJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransferJob not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) ResumeTransferJob(context.Context, *ResumeTransferJobRequest) (*ResumeTransferJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeTransferJob not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) FindTransfers(context.Context, *FindTransfersRequest) (*FindTransfersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindTransfers not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) SweepAccounts(context.Context, *SweepAccountsRequest) (*SweepAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SweepAccounts not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) ReclaimRent(context.Context, *ReclaimRentRequest) (*ReclaimRentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReclaimRent not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) CreateNonceAccounts(context.Context, *CreateNonceAccountsRequest) (*NonceAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateNonceAccounts not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) ListNonceAccounts(context.Context, *ListNonceAccountsRequest) (*NonceAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNonceAccounts not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) CloseNonceAccounts(context.Context, *CloseNonceAccountsRequest) (*NonceAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseNonceAccounts not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) SubmitSignedTransactions(context.Context, *SubmitSignedTransactionsRequest) (*SubmitSignedTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitSignedTransactions not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) CreateTransferSchedule(context.Context, *CreateTransferScheduleRequest) (*TransferScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTransferSchedule not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) UpdateTransferSchedule(context.Context, *UpdateTransferScheduleRequest) (*TransferScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTransferSchedule not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) GetTransferSchedule(context.Context, *GetTransferScheduleRequest) (*TransferScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransferSchedule not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) ListTransferSchedules(context.Context, *ListTransferSchedulesRequest) (*ListTransferSchedulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTransferSchedules not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) DeleteTransferSchedule(context.Context, *DeleteTransferScheduleRequest) (*DeleteTransferScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTransferSchedule not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) PauseTransferSchedule(context.Context, *PauseTransferScheduleRequest) (*TransferScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseTransferSchedule not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) ResumeTransferSchedule(context.Context, *ResumeTransferScheduleRequest) (*TransferScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeTransferSchedule not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) CreateVestingPlan(context.Context, *CreateVestingPlanRequest) (*VestingPlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateVestingPlan not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) GetVestingPlan(context.Context, *GetVestingPlanRequest) (*VestingPlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVestingPlan not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) ListVestingPlans(context.Context, *ListVestingPlansRequest) (*ListVestingPlansResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVestingPlans not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) ReleaseVestingPlan(context.Context, *ReleaseVestingPlanRequest) (*ReleaseVestingPlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseVestingPlan not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) WrapSOL(context.Context, *WrapSOLRequest) (*WrappedSOLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WrapSOL not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) UnwrapSOL(context.Context, *UnwrapSOLRequest) (*WrappedSOLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnwrapSOL not implemented")
+}
+func (UnimplementedGoCryptoTraderServiceServer) mustEmbedUnimplementedGoCryptoTraderServiceServer() {}
+func (UnimplementedGoCryptoTraderServiceServer) testEmbeddedByValue()                               {}
+
+// UnsafeGoCryptoTraderServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GoCryptoTraderServiceServer will
+// result in compilation errors.
+type UnsafeGoCryptoTraderServiceServer interface {
+	mustEmbedUnimplementedGoCryptoTraderServiceServer()
+}
+
+func RegisterGoCryptoTraderServiceServer(s grpc.ServiceRegistrar, srv GoCryptoTraderServiceServer) {
+	// If the following call pancis, it indicates UnimplementedGoCryptoTraderServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&GoCryptoTraderService_ServiceDesc, srv)
+}
+
+func _GoCryptoTraderService_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).GetInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_GetInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).GetInfo(ctx, req.(*GetInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_GetRPCEndpoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRPCEndpointsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).GetRPCEndpoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_GetRPCEndpoints_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).GetRPCEndpoints(ctx, req.(*GetRPCEndpointsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_GetSolanaRPCHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSolanaRPCHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).GetSolanaRPCHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_GetSolanaRPCHealth_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).GetSolanaRPCHealth(ctx, req.(*GetSolanaRPCHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_GetAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).GetAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_GetAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).GetAccounts(ctx, req.(*GetAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_CreateAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).CreateAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_CreateAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).CreateAccounts(ctx, req.(*CreateAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_ImportAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).ImportAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_ImportAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).ImportAccount(ctx, req.(*ImportAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_GenerateMnemonic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateMnemonicRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).GenerateMnemonic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_GenerateMnemonic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).GenerateMnemonic(ctx, req.(*GenerateMnemonicRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_GetAccountTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountTreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).GetAccountTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_GetAccountTree_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).GetAccountTree(ctx, req.(*GetAccountTreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_SetAccountParent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAccountParentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).SetAccountParent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_SetAccountParent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).SetAccountParent(ctx, req.(*SetAccountParentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_FundChildren_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FundChildrenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).FundChildren(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_FundChildren_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).FundChildren(ctx, req.(*FundChildrenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_SweepLeaves_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SweepLeavesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).SweepLeaves(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_SweepLeaves_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).SweepLeaves(ctx, req.(*SweepLeavesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_GetTokenPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTokenPriceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).GetTokenPrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_GetTokenPrice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).GetTokenPrice(ctx, req.(*GetTokenPriceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_Crypto_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CryptoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).Crypto(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_Crypto_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).Crypto(ctx, req.(*CryptoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_MigrateAccountCiphers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MigrateAccountCiphersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).MigrateAccountCiphers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_MigrateAccountCiphers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).MigrateAccountCiphers(ctx, req.(*MigrateAccountCiphersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_ValidateRecipients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateRecipientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).ValidateRecipients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_ValidateRecipients_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).ValidateRecipients(ctx, req.(*ValidateRecipientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_TransferSOL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferSOLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).TransferSOL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_TransferSOL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).TransferSOL(ctx, req.(*TransferSOLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_TransferToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).TransferToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_TransferToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).TransferToken(ctx, req.(*TransferTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_TransferSOLStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TransferSOLRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GoCryptoTraderServiceServer).TransferSOLStream(m, &grpc.GenericServerStream[TransferSOLRequest, TransferProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GoCryptoTraderService_TransferSOLStreamServer = grpc.ServerStreamingServer[TransferProgress]
+
+func _GoCryptoTraderService_TransferTokenStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TransferTokenRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GoCryptoTraderServiceServer).TransferTokenStream(m, &grpc.GenericServerStream[TransferTokenRequest, TransferProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GoCryptoTraderService_TransferTokenStreamServer = grpc.ServerStreamingServer[TransferProgress]
+
+func _GoCryptoTraderService_ListTransferJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTransferJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).ListTransferJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_ListTransferJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).ListTransferJobs(ctx, req.(*ListTransferJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_GetTransferJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransferJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).GetTransferJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_GetTransferJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).GetTransferJob(ctx, req.(*GetTransferJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_ResumeTransferJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeTransferJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).ResumeTransferJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_ResumeTransferJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).ResumeTransferJob(ctx, req.(*ResumeTransferJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_FindTransfers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindTransfersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).FindTransfers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_FindTransfers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).FindTransfers(ctx, req.(*FindTransfersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_SweepAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SweepAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).SweepAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_SweepAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).SweepAccounts(ctx, req.(*SweepAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_ReclaimRent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReclaimRentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).ReclaimRent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_ReclaimRent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).ReclaimRent(ctx, req.(*ReclaimRentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_CreateNonceAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNonceAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).CreateNonceAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_CreateNonceAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).CreateNonceAccounts(ctx, req.(*CreateNonceAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_ListNonceAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNonceAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).ListNonceAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_ListNonceAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).ListNonceAccounts(ctx, req.(*ListNonceAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_CloseNonceAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseNonceAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).CloseNonceAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_CloseNonceAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).CloseNonceAccounts(ctx, req.(*CloseNonceAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_SubmitSignedTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitSignedTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).SubmitSignedTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_SubmitSignedTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).SubmitSignedTransactions(ctx, req.(*SubmitSignedTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_CreateTransferSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTransferScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).CreateTransferSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_CreateTransferSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).CreateTransferSchedule(ctx, req.(*CreateTransferScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoCryptoTraderService_UpdateTransferSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTransferScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).UpdateTransferSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_UpdateTransferSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).UpdateTransferSchedule(ctx, req.(*UpdateTransferScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedGoCryptoTraderServiceServer) TransferSOL(context.Context, *TransferSOLRequest) (*TransferSOLResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method TransferSOL not implemented")
+
+func _GoCryptoTraderService_GetTransferSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransferScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).GetTransferSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_GetTransferSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).GetTransferSchedule(ctx, req.(*GetTransferScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedGoCryptoTraderServiceServer) TransferToken(context.Context, *TransferTokenRequest) (*TransferTokenResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method TransferToken not implemented")
+
+func _GoCryptoTraderService_ListTransferSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTransferSchedulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).ListTransferSchedules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_ListTransferSchedules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).ListTransferSchedules(ctx, req.(*ListTransferSchedulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedGoCryptoTraderServiceServer) mustEmbedUnimplementedGoCryptoTraderServiceServer() {}
-func (UnimplementedGoCryptoTraderServiceServer) testEmbeddedByValue()                               {}
 
-// UnsafeGoCryptoTraderServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to GoCryptoTraderServiceServer will
-// result in compilation errors.
-type UnsafeGoCryptoTraderServiceServer interface {
-	mustEmbedUnimplementedGoCryptoTraderServiceServer()
+func _GoCryptoTraderService_DeleteTransferSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTransferScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).DeleteTransferSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_DeleteTransferSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).DeleteTransferSchedule(ctx, req.(*DeleteTransferScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterGoCryptoTraderServiceServer(s grpc.ServiceRegistrar, srv GoCryptoTraderServiceServer) {
-	// If the following call pancis, it indicates UnimplementedGoCryptoTraderServiceServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+func _GoCryptoTraderService_PauseTransferSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseTransferScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	s.RegisterService(&GoCryptoTraderService_ServiceDesc, srv)
+	if interceptor == nil {
+		return srv.(GoCryptoTraderServiceServer).PauseTransferSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoCryptoTraderService_PauseTransferSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoCryptoTraderServiceServer).PauseTransferSchedule(ctx, req.(*PauseTransferScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _GoCryptoTraderService_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetInfoRequest)
+func _GoCryptoTraderService_ResumeTransferSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeTransferScheduleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GoCryptoTraderServiceServer).GetInfo(ctx, in)
+		return srv.(GoCryptoTraderServiceServer).ResumeTransferSchedule(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: GoCryptoTraderService_GetInfo_FullMethodName,
+		FullMethod: GoCryptoTraderService_ResumeTransferSchedule_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GoCryptoTraderServiceServer).GetInfo(ctx, req.(*GetInfoRequest))
+		return srv.(GoCryptoTraderServiceServer).ResumeTransferSchedule(ctx, req.(*ResumeTransferScheduleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _GoCryptoTraderService_GetRPCEndpoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetRPCEndpointsRequest)
+func _GoCryptoTraderService_CreateVestingPlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVestingPlanRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GoCryptoTraderServiceServer).GetRPCEndpoints(ctx, in)
+		return srv.(GoCryptoTraderServiceServer).CreateVestingPlan(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: GoCryptoTraderService_GetRPCEndpoints_FullMethodName,
+		FullMethod: GoCryptoTraderService_CreateVestingPlan_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GoCryptoTraderServiceServer).GetRPCEndpoints(ctx, req.(*GetRPCEndpointsRequest))
+		return srv.(GoCryptoTraderServiceServer).CreateVestingPlan(ctx, req.(*CreateVestingPlanRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _GoCryptoTraderService_GetAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetAccountsRequest)
+func _GoCryptoTraderService_GetVestingPlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVestingPlanRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GoCryptoTraderServiceServer).GetAccounts(ctx, in)
+		return srv.(GoCryptoTraderServiceServer).GetVestingPlan(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: GoCryptoTraderService_GetAccounts_FullMethodName,
+		FullMethod: GoCryptoTraderService_GetVestingPlan_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GoCryptoTraderServiceServer).GetAccounts(ctx, req.(*GetAccountsRequest))
+		return srv.(GoCryptoTraderServiceServer).GetVestingPlan(ctx, req.(*GetVestingPlanRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _GoCryptoTraderService_GetTokenPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetTokenPriceRequest)
+func _GoCryptoTraderService_ListVestingPlans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVestingPlansRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GoCryptoTraderServiceServer).GetTokenPrice(ctx, in)
+		return srv.(GoCryptoTraderServiceServer).ListVestingPlans(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: GoCryptoTraderService_GetTokenPrice_FullMethodName,
+		FullMethod: GoCryptoTraderService_ListVestingPlans_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GoCryptoTraderServiceServer).GetTokenPrice(ctx, req.(*GetTokenPriceRequest))
+		return srv.(GoCryptoTraderServiceServer).ListVestingPlans(ctx, req.(*ListVestingPlansRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _GoCryptoTraderService_Crypto_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CryptoRequest)
+func _GoCryptoTraderService_ReleaseVestingPlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseVestingPlanRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GoCryptoTraderServiceServer).Crypto(ctx, in)
+		return srv.(GoCryptoTraderServiceServer).ReleaseVestingPlan(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: GoCryptoTraderService_Crypto_FullMethodName,
+		FullMethod: GoCryptoTraderService_ReleaseVestingPlan_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GoCryptoTraderServiceServer).Crypto(ctx, req.(*CryptoRequest))
+		return srv.(GoCryptoTraderServiceServer).ReleaseVestingPlan(ctx, req.(*ReleaseVestingPlanRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _GoCryptoTraderService_TransferSOL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TransferSOLRequest)
+func _GoCryptoTraderService_WrapSOL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WrapSOLRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GoCryptoTraderServiceServer).TransferSOL(ctx, in)
+		return srv.(GoCryptoTraderServiceServer).WrapSOL(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: GoCryptoTraderService_TransferSOL_FullMethodName,
+		FullMethod: GoCryptoTraderService_WrapSOL_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GoCryptoTraderServiceServer).TransferSOL(ctx, req.(*TransferSOLRequest))
+		return srv.(GoCryptoTraderServiceServer).WrapSOL(ctx, req.(*WrapSOLRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _GoCryptoTraderService_TransferToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TransferTokenRequest)
+func _GoCryptoTraderService_UnwrapSOL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnwrapSOLRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GoCryptoTraderServiceServer).TransferToken(ctx, in)
+		return srv.(GoCryptoTraderServiceServer).UnwrapSOL(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: GoCryptoTraderService_TransferToken_FullMethodName,
+		FullMethod: GoCryptoTraderService_UnwrapSOL_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GoCryptoTraderServiceServer).TransferToken(ctx, req.(*TransferTokenRequest))
+		return srv.(GoCryptoTraderServiceServer).UnwrapSOL(ctx, req.(*UnwrapSOLRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -323,10 +1517,42 @@ var GoCryptoTraderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetRPCEndpoints",
 			Handler:    _GoCryptoTraderService_GetRPCEndpoints_Handler,
 		},
+		{
+			MethodName: "GetSolanaRPCHealth",
+			Handler:    _GoCryptoTraderService_GetSolanaRPCHealth_Handler,
+		},
 		{
 			MethodName: "GetAccounts",
 			Handler:    _GoCryptoTraderService_GetAccounts_Handler,
 		},
+		{
+			MethodName: "CreateAccounts",
+			Handler:    _GoCryptoTraderService_CreateAccounts_Handler,
+		},
+		{
+			MethodName: "ImportAccount",
+			Handler:    _GoCryptoTraderService_ImportAccount_Handler,
+		},
+		{
+			MethodName: "GenerateMnemonic",
+			Handler:    _GoCryptoTraderService_GenerateMnemonic_Handler,
+		},
+		{
+			MethodName: "GetAccountTree",
+			Handler:    _GoCryptoTraderService_GetAccountTree_Handler,
+		},
+		{
+			MethodName: "SetAccountParent",
+			Handler:    _GoCryptoTraderService_SetAccountParent_Handler,
+		},
+		{
+			MethodName: "FundChildren",
+			Handler:    _GoCryptoTraderService_FundChildren_Handler,
+		},
+		{
+			MethodName: "SweepLeaves",
+			Handler:    _GoCryptoTraderService_SweepLeaves_Handler,
+		},
 		{
 			MethodName: "GetTokenPrice",
 			Handler:    _GoCryptoTraderService_GetTokenPrice_Handler,
@@ -335,6 +1561,14 @@ var GoCryptoTraderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Crypto",
 			Handler:    _GoCryptoTraderService_Crypto_Handler,
 		},
+		{
+			MethodName: "MigrateAccountCiphers",
+			Handler:    _GoCryptoTraderService_MigrateAccountCiphers_Handler,
+		},
+		{
+			MethodName: "ValidateRecipients",
+			Handler:    _GoCryptoTraderService_ValidateRecipients_Handler,
+		},
 		{
 			MethodName: "TransferSOL",
 			Handler:    _GoCryptoTraderService_TransferSOL_Handler,
@@ -343,6 +1577,211 @@ var GoCryptoTraderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "TransferToken",
 			Handler:    _GoCryptoTraderService_TransferToken_Handler,
 		},
+		{
+			MethodName: "ListTransferJobs",
+			Handler:    _GoCryptoTraderService_ListTransferJobs_Handler,
+		},
+		{
+			MethodName: "GetTransferJob",
+			Handler:    _GoCryptoTraderService_GetTransferJob_Handler,
+		},
+		{
+			MethodName: "ResumeTransferJob",
+			Handler:    _GoCryptoTraderService_ResumeTransferJob_Handler,
+		},
+		{
+			MethodName: "FindTransfers",
+			Handler:    _GoCryptoTraderService_FindTransfers_Handler,
+		},
+		{
+			MethodName: "SweepAccounts",
+			Handler:    _GoCryptoTraderService_SweepAccounts_Handler,
+		},
+		{
+			MethodName: "ReclaimRent",
+			Handler:    _GoCryptoTraderService_ReclaimRent_Handler,
+		},
+		{
+			MethodName: "CreateNonceAccounts",
+			Handler:    _GoCryptoTraderService_CreateNonceAccounts_Handler,
+		},
+		{
+			MethodName: "ListNonceAccounts",
+			Handler:    _GoCryptoTraderService_ListNonceAccounts_Handler,
+		},
+		{
+			MethodName: "CloseNonceAccounts",
+			Handler:    _GoCryptoTraderService_CloseNonceAccounts_Handler,
+		},
+		{
+			MethodName: "SubmitSignedTransactions",
+			Handler:    _GoCryptoTraderService_SubmitSignedTransactions_Handler,
+		},
+		{
+			MethodName: "CreateTransferSchedule",
+			Handler:    _GoCryptoTraderService_CreateTransferSchedule_Handler,
+		},
+		{
+			MethodName: "UpdateTransferSchedule",
+			Handler:    _GoCryptoTraderService_UpdateTransferSchedule_Handler,
+		},
+		{
+			MethodName: "GetTransferSchedule",
+			Handler:    _GoCryptoTraderService_GetTransferSchedule_Handler,
+		},
+		{
+			MethodName: "ListTransferSchedules",
+			Handler:    _GoCryptoTraderService_ListTransferSchedules_Handler,
+		},
+		{
+			MethodName: "DeleteTransferSchedule",
+			Handler:    _GoCryptoTraderService_DeleteTransferSchedule_Handler,
+		},
+		{
+			MethodName: "PauseTransferSchedule",
+			Handler:    _GoCryptoTraderService_PauseTransferSchedule_Handler,
+		},
+		{
+			MethodName: "ResumeTransferSchedule",
+			Handler:    _GoCryptoTraderService_ResumeTransferSchedule_Handler,
+		},
+		{
+			MethodName: "CreateVestingPlan",
+			Handler:    _GoCryptoTraderService_CreateVestingPlan_Handler,
+		},
+		{
+			MethodName: "GetVestingPlan",
+			Handler:    _GoCryptoTraderService_GetVestingPlan_Handler,
+		},
+		{
+			MethodName: "ListVestingPlans",
+			Handler:    _GoCryptoTraderService_ListVestingPlans_Handler,
+		},
+		{
+			MethodName: "ReleaseVestingPlan",
+			Handler:    _GoCryptoTraderService_ReleaseVestingPlan_Handler,
+		},
+		{
+			MethodName: "WrapSOL",
+			Handler:    _GoCryptoTraderService_WrapSOL_Handler,
+		},
+		{
+			MethodName: "UnwrapSOL",
+			Handler:    _GoCryptoTraderService_UnwrapSOL_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "TransferSOLStream",
+			Handler:       _GoCryptoTraderService_TransferSOLStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "TransferTokenStream",
+			Handler:       _GoCryptoTraderService_TransferTokenStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "rpc.proto",
+}
+
+const (
+	SignerService_Sign_FullMethodName = "/gctrpc.SignerService/Sign"
+)
+
+// SignerServiceClient is the client API for SignerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SignerServiceClient interface {
+	Sign(ctx context.Context, in *SignRequest, opts ...grpc.CallOption) (*SignResponse, error)
+}
+
+type signerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSignerServiceClient(cc grpc.ClientConnInterface) SignerServiceClient {
+	return &signerServiceClient{cc}
+}
+
+func (c *signerServiceClient) Sign(ctx context.Context, in *SignRequest, opts ...grpc.CallOption) (*SignResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SignResponse)
+	err := c.cc.Invoke(ctx, SignerService_Sign_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SignerServiceServer is the server API for SignerService service.
+// All implementations must embed UnimplementedSignerServiceServer
+// for forward compatibility.
+type SignerServiceServer interface {
+	Sign(context.Context, *SignRequest) (*SignResponse, error)
+	mustEmbedUnimplementedSignerServiceServer()
+}
+
+// UnimplementedSignerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSignerServiceServer struct{}
+
+func (UnimplementedSignerServiceServer) Sign(context.Context, *SignRequest) (*SignResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sign not implemented")
+}
+func (UnimplementedSignerServiceServer) mustEmbedUnimplementedSignerServiceServer() {}
+func (UnimplementedSignerServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeSignerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SignerServiceServer will
+// result in compilation errors.
+type UnsafeSignerServiceServer interface {
+	mustEmbedUnimplementedSignerServiceServer()
+}
+
+func RegisterSignerServiceServer(s grpc.ServiceRegistrar, srv SignerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedSignerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SignerService_ServiceDesc, srv)
+}
+
+func _SignerService_Sign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServiceServer).Sign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SignerService_Sign_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServiceServer).Sign(ctx, req.(*SignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SignerService_ServiceDesc is the grpc.ServiceDesc for SignerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SignerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gctrpc.SignerService",
+	HandlerType: (*SignerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Sign",
+			Handler:    _SignerService_Sign_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "rpc.proto",
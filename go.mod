@@ -4,6 +4,7 @@ go 1.23.0
 
 require (
 	github.com/buger/jsonparser v1.1.1
+	github.com/gagliardetto/binary v0.8.0
 	github.com/gofrs/uuid v4.4.0+incompatible
 	github.com/golang/protobuf v1.5.4
 	github.com/grpc-ecosystem/go-grpc-middleware v1.4.0
@@ -15,6 +16,7 @@ require (
 	github.com/stretchr/testify v1.10.0
 	github.com/thrasher-corp/goose v2.7.0-rc4.0.20191002032028-0f2c2a27abdb+incompatible
 	github.com/thrasher-corp/sqlboiler v1.0.1-0.20191001234224-71e17f37a85e
+	github.com/tyler-smith/go-bip39 v1.1.0
 	github.com/urfave/cli/v2 v2.27.5
 	golang.org/x/crypto v0.36.0
 	golang.org/x/term v0.30.0
@@ -32,7 +34,6 @@ require (
 	github.com/blocto/solana-go-sdk v1.30.0 // indirect
 	github.com/donutnomad/solana-web3 v0.0.0-20250313072913-99732fd085a1 // indirect
 	github.com/fatih/color v1.18.0 // indirect
-	github.com/gagliardetto/binary v0.8.0 // indirect
 	github.com/gagliardetto/treeout v0.1.4 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/gorilla/rpc v1.2.0 // indirect
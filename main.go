@@ -54,6 +54,8 @@ func main() {
 	flag.BoolVar(&settings.EnableNTPClient, "ntpclient", true, "enables the NTP client to check system clock drift")
 	flag.BoolVar(&settings.EnableDispatcher, "dispatch", true, "enables the dispatch system")
 	flag.BoolVar(&settings.EnableCurrencyStateManager, "currencystatemanager", true, "enables the currency state manager")
+	flag.BoolVar(&settings.EnableTransferScheduleManager, "transferschedulemanager", true, "enables the transfer schedule manager, which starts scheduled and recurring transfer jobs when they are due")
+	flag.DurationVar(&settings.TransferScheduleManagerDelay, "transferschedulemanagerdelay", 0, "sets the transfer schedule managers sleep delay between checking for due schedules")
 
 	// Exchange syncer settings
 	flag.BoolVar(&settings.EnableTickerSyncing, "tickersync", false, "enables ticker syncing for all enabled exchanges, overriding false config value")